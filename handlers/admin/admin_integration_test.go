@@ -0,0 +1,153 @@
+package admin_test
+
+import (
+	"context"
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/jwtauth"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func signup(t *testing.T, r http.Handler, email string) string {
+	t.Helper()
+	rr := testutil.DoJSON(t, r, http.MethodPost, "/api/v1/auth/signup", dto.SignupRequest{
+		FirstName: "Test",
+		LastName:  "User",
+		Email:     email,
+		Password:  "password123",
+	}, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	env := testutil.DecodeJSON[dto.EnvelopeAny](t, rr)
+	data := env.Data.(map[string]any)
+	return data["access_token"].(string)
+}
+
+func login(t *testing.T, r http.Handler, email, password string) int {
+	t.Helper()
+	rr := testutil.DoJSON(t, r, http.MethodPost, "/api/v1/auth/login", dto.LoginRequest{
+		Email:    email,
+		Password: password,
+	}, nil)
+	return rr.Code
+}
+
+func TestAdminUserManagement_SQLite(t *testing.T) {
+	sqlDB := testutil.NewSQLiteTestDB(t)
+	uow, err := repositories.NewUnitOfWork("sqlite", sqlDB)
+	require.NoError(t, err)
+	r := testutil.NewTestRouter(t, uow, "test-secret")
+	ctx := context.Background()
+
+	adminToken := signup(t, r, "admin@example.com")
+	signup(t, r, "member@example.com")
+
+	// Standard users are rejected by the admin guard.
+	rr := testutil.DoJSON(t, r, http.MethodGet, "/api/v1/admin/users", nil, map[string]string{
+		"Authorization": "Bearer " + adminToken,
+	})
+	require.Equal(t, http.StatusForbidden, rr.Code)
+
+	admin, err := uow.Users().GetUserByEmail(ctx, "admin@example.com")
+	require.NoError(t, err)
+	require.NoError(t, uow.Users().UpdateUserType(ctx, admin.ID, models.AdminUser))
+	member, err := uow.Users().GetUserByEmail(ctx, "member@example.com")
+	require.NoError(t, err)
+
+	auth := map[string]string{"Authorization": "Bearer " + adminToken}
+
+	rr = testutil.DoJSON(t, r, http.MethodGet, "/api/v1/admin/users?q=member", nil, auth)
+	require.Equal(t, http.StatusOK, rr.Code)
+	list := testutil.DecodeJSON[dto.AdminUsersEnvelope](t, rr)
+	require.Equal(t, 1, list.Data.Total)
+	require.Equal(t, member.ID, list.Data.Users[0].ID)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantLogin  int
+	}{
+		{"disable blocks login", "/disable", http.StatusOK, http.StatusUnauthorized},
+		{"enable restores login", "/enable", http.StatusOK, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := testutil.DoJSON(t, r, http.MethodPost, "/api/v1/admin/users/"+member.ID.String()+tt.path, nil, auth)
+			require.Equal(t, tt.wantStatus, rr.Code)
+			require.Equal(t, tt.wantLogin, login(t, r, "member@example.com", "password123"))
+		})
+	}
+
+	// Admins cannot lock themselves out.
+	rr = testutil.DoJSON(t, r, http.MethodPost, "/api/v1/admin/users/"+admin.ID.String()+"/disable", nil, auth)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// Forced reset blocks login until the token is redeemed.
+	rr = testutil.DoJSON(t, r, http.MethodPost, "/api/v1/admin/users/"+member.ID.String()+"/password-reset", nil, auth)
+	require.Equal(t, http.StatusOK, rr.Code)
+	reset := testutil.DecodeJSON[dto.AdminPasswordResetEnvelope](t, rr)
+	require.Equal(t, http.StatusUnauthorized, login(t, r, "member@example.com", "password123"))
+
+	rr = testutil.DoJSON(t, r, http.MethodPost, "/api/v1/auth/password/reset", dto.PasswordResetRequest{
+		Token:    reset.Data.Token,
+		Password: "new-password-456",
+	}, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, http.StatusOK, login(t, r, "member@example.com", "new-password-456"))
+
+	rr = testutil.DoJSON(t, r, http.MethodGet, "/api/v1/admin/audit?target_user_id="+member.ID.String(), nil, auth)
+	require.Equal(t, http.StatusOK, rr.Code)
+	audit := testutil.DecodeJSON[dto.AdminAuditEnvelope](t, rr)
+	require.Equal(t, 3, audit.Data.Total)
+	require.Equal(t, models.AdminActionPasswordReset, audit.Data.Entries[0].Action)
+
+	// Without the password, a disabled account looks like any failed login.
+	rr = testutil.DoJSON(t, r, http.MethodPost, "/api/v1/admin/users/"+member.ID.String()+"/disable", nil, auth)
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr = testutil.DoJSON(t, r, http.MethodPost, "/api/v1/auth/login", dto.LoginRequest{Email: "member@example.com", Password: "wrong-password"}, nil)
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	require.NotContains(t, rr.Body.String(), jwtauth.ErrAccountDisabled.Error())
+	rr = testutil.DoJSON(t, r, http.MethodPost, "/api/v1/auth/login", dto.LoginRequest{Email: "member@example.com", Password: "new-password-456"}, nil)
+	require.Contains(t, rr.Body.String(), jwtauth.ErrAccountDisabled.Error())
+}
+
+func TestAdminImpersonation_SQLite(t *testing.T) {
+	sqlDB := testutil.NewSQLiteTestDB(t)
+	uow, err := repositories.NewUnitOfWork("sqlite", sqlDB)
+	require.NoError(t, err)
+	r := testutil.NewTestRouter(t, uow, "test-secret")
+	ctx := context.Background()
+
+	adminToken := signup(t, r, "admin@example.com")
+	signup(t, r, "member@example.com")
+	admin, err := uow.Users().GetUserByEmail(ctx, "admin@example.com")
+	require.NoError(t, err)
+	require.NoError(t, uow.Users().UpdateUserType(ctx, admin.ID, models.AdminUser))
+	member, err := uow.Users().GetUserByEmail(ctx, "member@example.com")
+	require.NoError(t, err)
+
+	rr := testutil.DoJSON(t, r, http.MethodPost, "/api/v1/admin/users/"+member.ID.String()+"/impersonate", nil, map[string]string{
+		"Authorization": "Bearer " + adminToken,
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	imp := testutil.DecodeJSON[dto.ImpersonationEnvelope](t, rr)
+	require.Equal(t, admin.ID.String(), imp.Data.ActorID)
+
+	impAuth := map[string]string{"Authorization": "Bearer " + imp.Data.AccessToken}
+
+	rr = testutil.DoJSON(t, r, http.MethodGet, "/api/v1/me", nil, impAuth)
+	require.Equal(t, http.StatusOK, rr.Code)
+	me := testutil.DecodeJSON[dto.MeEnvelope](t, rr)
+	require.Equal(t, member.ID.String(), me.Data.UserID)
+	require.Equal(t, admin.ID.String(), me.Data.ImpersonatedBy)
+
+	rr = testutil.DoJSON(t, r, http.MethodGet, "/api/v1/admin/users", nil, impAuth)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	denied := testutil.DecodeJSON[dto.ErrorEnvelope](t, rr)
+	require.Equal(t, dto.CodeImpersonationDenied, denied.Data.Code)
+}
@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"net/http"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListAuditLogs godoc
+// @Summary List admin audit trail
+// @Description Returns the actions taken through the admin API, newest first.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param actor_id query string false "Filter by acting administrator"
+// @Param target_user_id query string false "Filter by target user"
+// @Param action query string false "Filter by action (e.g. user.disable)"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param offset query int false "Number of entries to skip"
+// @Success 200 {object} dto.AdminAuditEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /admin/audit [get]
+func (h *Handler) ListAuditLogs(c *gin.Context) {
+	limit, offset, ok := parsePaging(c)
+	if !ok {
+		return
+	}
+	filter := models.AdminAuditFilter{
+		Action: models.AdminAction(strings.TrimSpace(c.Query("action"))),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if raw := strings.TrimSpace(c.Query("actor_id")); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			dto.BadRequest(dto.CodeInvalidRequest, "invalid actor_id", nil).Send(c)
+			return
+		}
+		filter.ActorID = &id
+	}
+	if raw := strings.TrimSpace(c.Query("target_user_id")); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			dto.BadRequest(dto.CodeInvalidRequest, "invalid target_user_id", nil).Send(c)
+			return
+		}
+		filter.TargetUserID = &id
+	}
+
+	entries, total, err := h.uow.Audit().ListAdminAuditLogs(c.Request.Context(), filter)
+	if err != nil {
+		dto.RepoError(err, "audit entry").Send(c)
+		return
+	}
+	if entries == nil {
+		entries = []*models.AdminAuditLog{}
+	}
+	dto.OK(c, http.StatusOK, dto.AdminAuditListResponse{
+		Entries: entries,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
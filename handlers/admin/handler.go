@@ -0,0 +1,144 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/jwtauth"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v3"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+type Handler struct {
+	uow repositories.UnitOfWork
+	mw  *jwt.GinJWTMiddleware
+}
+
+func NewHandler(uow repositories.UnitOfWork, mw *jwt.GinJWTMiddleware) *Handler {
+	return &Handler{uow: uow, mw: mw}
+}
+
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup, AuthMiddleware gin.HandlerFunc) {
+	rg.Use(AuthMiddleware, jwtauth.DenyImpersonation(), jwtauth.RequireAdmin(h.uow.Users()))
+
+	rg.GET("/users", h.ListUsers)
+	rg.GET("/users/:id", h.GetUser)
+	rg.DELETE("/users/:id", h.DeleteUser)
+	rg.POST("/users/:id/disable", h.DisableUser)
+	rg.POST("/users/:id/enable", h.EnableUser)
+	rg.POST("/users/:id/promote", h.PromoteUser)
+	rg.POST("/users/:id/demote", h.DemoteUser)
+	rg.POST("/users/:id/password-reset", h.ForcePasswordReset)
+	rg.POST("/users/:id/impersonate", h.Impersonate)
+
+	rg.GET("/audit", h.ListAuditLogs)
+}
+
+// adminAction is the body of a state-changing admin endpoint. It runs inside the same
+// transaction as the audit entry and returns extra details to store with it.
+type adminAction func(ctx context.Context, r repositories.Repos, target *models.User) (map[string]any, error)
+
+// runAction loads the target user, executes fn and records the audit entry atomically.
+// It returns the (re-read) target user, or nil if a response has already been sent.
+func (h *Handler) runAction(c *gin.Context, action models.AdminAction, allowSelf bool, fn adminAction) *models.User {
+	actorID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.Unauthorized(dto.CodeUnauthorized, "unauthorized", nil).Send(c)
+		return nil
+	}
+	target, ok := h.loadTarget(c)
+	if !ok {
+		return nil
+	}
+	if !allowSelf && target.ID == actorID {
+		dto.BadRequest(dto.CodeInvalidRequest, "administrators cannot perform this action on their own account", nil).Send(c)
+		return nil
+	}
+
+	err = h.uow.WithTransaction(c.Request.Context(), func(ctx context.Context, r repositories.Repos) error {
+		details, err := fn(ctx, r, target)
+		if err != nil {
+			return err
+		}
+		targetID := target.ID
+		return r.Audit.CreateAdminAuditLog(ctx, &models.AdminAuditLog{
+			ID:           uuid.New(),
+			ActorID:      &actorID,
+			Action:       action,
+			TargetUserID: &targetID,
+			Details:      details,
+			TraceID:      trace.Get(c),
+			CreatedAt:    time.Now(),
+		})
+	})
+	if err != nil {
+		dto.RepoError(err, "user").Send(c)
+		return nil
+	}
+	trace.Log(c, "admin_action", "action="+string(action)+" actor_id="+actorID.String()+" target_user_id="+target.ID.String())
+
+	if action == models.AdminActionDeleteUser {
+		return target
+	}
+	updated, err := h.uow.Users().GetUserByID(c.Request.Context(), target.ID)
+	if err != nil {
+		// The action itself succeeded; fall back to the pre-action snapshot.
+		return target
+	}
+	return updated
+}
+
+func (h *Handler) loadTarget(c *gin.Context) (*models.User, bool) {
+	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id", nil).Send(c)
+		return nil, false
+	}
+	u, err := h.uow.Users().GetUserByID(c.Request.Context(), id)
+	if err != nil {
+		dto.RepoError(err, "user").Send(c)
+		return nil, false
+	}
+	return u, true
+}
+
+func parsePaging(c *gin.Context) (limit int, offset int, ok bool) {
+	limit, offset = defaultPageSize, 0
+	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 || v > maxPageSize {
+			dto.BadRequest(dto.CodeInvalidRequest, "limit must be between 1 and "+strconv.Itoa(maxPageSize), nil).Send(c)
+			return 0, 0, false
+		}
+		limit = v
+	}
+	if raw := strings.TrimSpace(c.Query("offset")); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			dto.BadRequest(dto.CodeInvalidRequest, "offset must be a non-negative integer", nil).Send(c)
+			return 0, 0, false
+		}
+		offset = v
+	}
+	return limit, offset, true
+}
+
+func sendUser(c *gin.Context, u *models.User) {
+	if u == nil {
+		return
+	}
+	dto.OK(c, http.StatusOK, u)
+}
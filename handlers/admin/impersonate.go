@@ -0,0 +1,85 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/jwtauth"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Impersonate godoc
+// @Summary Impersonate a user
+// @Description Issues a short-lived access token for the target user that also carries an "act" claim
+// @Description identifying the administrator. No refresh token or cookie is issued, and sensitive
+// @Description endpoints refuse impersonation tokens.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.ImpersonationEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /admin/users/{id}/impersonate [post]
+func (h *Handler) Impersonate(c *gin.Context) {
+	if h.mw == nil {
+		dto.Internal(dto.CodeInternalError, "auth middleware not initialized", "nil middleware", nil).Send(c)
+		return
+	}
+	target, ok := h.loadTarget(c)
+	if !ok {
+		return
+	}
+	if target.IsDisabled() {
+		dto.BadRequest(dto.CodeInvalidRequest, "cannot impersonate a disabled user", nil).Send(c)
+		return
+	}
+	if target.UserType == models.AdminUser {
+		dto.Forbidden(dto.CodeForbidden, "cannot impersonate another administrator", nil).Send(c)
+		return
+	}
+
+	expiresAt := time.Now().Add(jwtauth.ImpersonationTTL)
+	u := h.runAction(c, models.AdminActionImpersonate, false, func(ctx context.Context, r repositories.Repos, target *models.User) (map[string]any, error) {
+		return map[string]any{"expires_at": expiresAt}, nil
+	})
+	if u == nil {
+		return
+	}
+	actorID, _ := jwtauth.CurrentUserID(c)
+
+	identity := &jwtauth.UserIdentity{
+		ID:        u.ID.String(),
+		Email:     u.Email,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Provider:  models.LocalProvider,
+		UserType:  u.UserType,
+		ActorID:   actorID.String(),
+	}
+	token, err := h.mw.TokenGenerator(c.Request.Context(), identity)
+	if err != nil {
+		dto.Internal(dto.CodeInternalError, "could not generate token", err.Error(), nil).Send(c)
+		return
+	}
+	// Impersonation must not be extendable: drop the refresh token right away.
+	if h.mw.RefreshTokenStore != nil {
+		_ = h.mw.RefreshTokenStore.Delete(c.Request.Context(), token.RefreshToken)
+	}
+	trace.Log(c, "impersonation_start", "actor_id="+identity.ActorID+" user_id="+identity.ID)
+
+	dto.OK(c, http.StatusOK, dto.ImpersonationResponse{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		ExpiresAt:   token.ExpiresAt,
+		UserID:      identity.ID,
+		ActorID:     identity.ActorID,
+	})
+}
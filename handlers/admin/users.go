@@ -0,0 +1,259 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/jwtauth"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/listspec"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListUsers godoc
+// @Summary List users
+// @Description List and search users. Requires a system administrator.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Search in email, first name and last name"
+// @Param user_type query string false "Filter by user type (standard|admin)"
+// @Param disabled query bool false "Filter by disabled state"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param offset query int false "Number of users to skip"
+// @Success 200 {object} dto.AdminUsersEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /admin/users [get]
+func (h *Handler) ListUsers(c *gin.Context) {
+	limit, offset, ok := parsePaging(c)
+	if !ok {
+		return
+	}
+	filter := models.UserListFilter{
+		Query:  c.Query("q"),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if raw := strings.TrimSpace(c.Query("user_type")); raw != "" {
+		ut := models.UserType(raw)
+		if !ut.IsValid() {
+			dto.BadRequest(dto.CodeInvalidRequest, "invalid user_type", nil).Send(c)
+			return
+		}
+		filter.UserType = ut
+	}
+	if raw := strings.TrimSpace(c.Query("disabled")); raw != "" {
+		disabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			dto.BadRequest(dto.CodeInvalidRequest, "invalid disabled flag", nil).Send(c)
+			return
+		}
+		filter.Disabled = &disabled
+	}
+
+	users, total, err := h.uow.Users().ListUsers(c.Request.Context(), filter)
+	if err != nil {
+		dto.RepoError(err, "user").Send(c)
+		return
+	}
+	if users == nil {
+		users = []*models.User{}
+	}
+	dto.OK(c, http.StatusOK, dto.AdminUserListResponse{
+		Users:  users,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// GetUser godoc
+// @Summary Get user details
+// @Description Returns the user together with their teams and linked identities.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.AdminUserDetailEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /admin/users/{id} [get]
+func (h *Handler) GetUser(c *gin.Context) {
+	u, ok := h.loadTarget(c)
+	if !ok {
+		return
+	}
+	// The detail view shows the first page; the user's own /team endpoint pages through the rest.
+	teams, err := h.uow.Teams().GetTeamsByUserID(c.Request.Context(), u.ID, true, listquery.Default(listspec.Teams))
+	if err != nil {
+		dto.RepoError(err, "user").Send(c)
+		return
+	}
+	identities, err := h.uow.Users().ListAuthProvidersByUserID(c.Request.Context(), u.ID)
+	if err != nil {
+		dto.RepoError(err, "user").Send(c)
+		return
+	}
+	if identities == nil {
+		identities = []models.AuthProvider{}
+	}
+	dto.OK(c, http.StatusOK, dto.AdminUserDetailResponse{
+		User:       u,
+		Teams:      teams.Items,
+		Identities: identities,
+	})
+}
+
+// DisableUser godoc
+// @Summary Disable a user
+// @Description Disabled users can no longer log in with a password or OAuth provider.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.AdminUserEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /admin/users/{id}/disable [post]
+func (h *Handler) DisableUser(c *gin.Context) {
+	sendUser(c, h.runAction(c, models.AdminActionDisableUser, false, func(ctx context.Context, r repositories.Repos, target *models.User) (map[string]any, error) {
+		return nil, r.Users.SetUserDisabled(ctx, target.ID, true)
+	}))
+}
+
+// EnableUser godoc
+// @Summary Enable a user
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.AdminUserEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /admin/users/{id}/enable [post]
+func (h *Handler) EnableUser(c *gin.Context) {
+	sendUser(c, h.runAction(c, models.AdminActionEnableUser, false, func(ctx context.Context, r repositories.Repos, target *models.User) (map[string]any, error) {
+		return nil, r.Users.SetUserDisabled(ctx, target.ID, false)
+	}))
+}
+
+// PromoteUser godoc
+// @Summary Promote a user to system administrator
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.AdminUserEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /admin/users/{id}/promote [post]
+func (h *Handler) PromoteUser(c *gin.Context) {
+	sendUser(c, h.runAction(c, models.AdminActionPromoteUser, false, func(ctx context.Context, r repositories.Repos, target *models.User) (map[string]any, error) {
+		details := map[string]any{"from": target.UserType, "to": models.AdminUser}
+		return details, r.Users.UpdateUserType(ctx, target.ID, models.AdminUser)
+	}))
+}
+
+// DemoteUser godoc
+// @Summary Demote a system administrator to a standard user
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.AdminUserEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /admin/users/{id}/demote [post]
+func (h *Handler) DemoteUser(c *gin.Context) {
+	sendUser(c, h.runAction(c, models.AdminActionDemoteUser, false, func(ctx context.Context, r repositories.Repos, target *models.User) (map[string]any, error) {
+		details := map[string]any{"from": target.UserType, "to": models.StandardUser}
+		return details, r.Users.UpdateUserType(ctx, target.ID, models.StandardUser)
+	}))
+}
+
+// ForcePasswordReset godoc
+// @Summary Force a password reset
+// @Description Blocks password login until the user sets a new password with the returned one-time token (POST /auth/password/reset).
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.AdminPasswordResetEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /admin/users/{id}/password-reset [post]
+func (h *Handler) ForcePasswordReset(c *gin.Context) {
+	token, hash, err := jwtauth.NewPasswordResetToken()
+	if err != nil {
+		dto.Internal(dto.CodeInternalError, "could not generate reset token", err.Error(), nil).Send(c)
+		return
+	}
+	now := time.Now()
+	expiresAt := now.Add(jwtauth.PasswordResetTTL)
+
+	u := h.runAction(c, models.AdminActionPasswordReset, true, func(ctx context.Context, r repositories.Repos, target *models.User) (map[string]any, error) {
+		// Only one outstanding token per user.
+		if err := r.Users.DeletePasswordResetTokensByUserID(ctx, target.ID); err != nil {
+			return nil, err
+		}
+		if err := r.Users.CreatePasswordResetToken(ctx, &models.PasswordResetToken{
+			ID:        uuid.New(),
+			UserID:    target.ID,
+			TokenHash: hash,
+			ExpiresAt: expiresAt,
+			CreatedAt: now,
+		}); err != nil {
+			return nil, err
+		}
+		return map[string]any{"expires_at": expiresAt}, r.Users.SetPasswordResetRequired(ctx, target.ID, true)
+	})
+	if u == nil {
+		return
+	}
+	dto.OK(c, http.StatusOK, dto.AdminPasswordResetResponse{Token: token, ExpiresAt: expiresAt})
+}
+
+// DeleteUser godoc
+// @Summary Delete a user
+// @Description Permanently deletes the user, their credentials and team memberships.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /admin/users/{id} [delete]
+func (h *Handler) DeleteUser(c *gin.Context) {
+	u := h.runAction(c, models.AdminActionDeleteUser, false, func(ctx context.Context, r repositories.Repos, target *models.User) (map[string]any, error) {
+		details := map[string]any{"email": target.Email}
+		return details, r.Users.DeleteUser(ctx, target.ID)
+	})
+	if u == nil {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -61,3 +61,79 @@ func TestMe_Unauthorized(t *testing.T) {
 	meEnv := testutil.DecodeJSON[dto.EnvelopeAny](t, meResp)
 	require.False(t, meEnv.Success)
 }
+
+func TestCSRF_CookieAuth(t *testing.T) {
+	repo := fakes.NewUserRepo()
+	uow := fakes.NewUnitOfWork(repo, nil)
+	r := testutil.NewTestRouter(t, uow, "test-secret")
+
+	signupResp := testutil.DoJSON(t, r, http.MethodPost, "/api/v1/auth/signup", dto.SignupRequest{
+		FirstName: "Bishoy",
+		LastName:  "Raafat",
+		Email:     "bishoy@example.com",
+		Password:  "password123",
+	}, nil)
+	require.Equal(t, http.StatusOK, signupResp.Code)
+
+	loginResp := testutil.DoJSON(t, r, http.MethodPost, "/api/v1/auth/login", dto.LoginRequest{
+		Email:    "bishoy@example.com",
+		Password: "password123",
+	}, nil)
+	require.Equal(t, http.StatusOK, loginResp.Code)
+	var jwtCookie string
+	for _, ck := range loginResp.Result().Cookies() {
+		if ck.Name == "jwt" {
+			jwtCookie = ck.Value
+		}
+	}
+	require.NotEmpty(t, jwtCookie)
+	loginEnv := testutil.DecodeJSON[dto.EnvelopeAny](t, loginResp)
+	accessToken, _ := loginEnv.Data.(map[string]any)["access_token"].(string)
+
+	csrfResp := testutil.DoJSON(t, r, http.MethodGet, "/api/v1/auth/csrf", nil, map[string]string{
+		"Cookie": "jwt=" + jwtCookie,
+	})
+	require.Equal(t, http.StatusOK, csrfResp.Code)
+	csrfEnv := testutil.DecodeJSON[dto.Envelope[dto.CSRFTokenResponse]](t, csrfResp)
+	csrfToken := csrfEnv.Data.Token
+	require.NotEmpty(t, csrfToken)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"cookie without token", map[string]string{"Cookie": "jwt=" + jwtCookie}, http.StatusForbidden},
+		{"header without cookie", map[string]string{
+			"Cookie":       "jwt=" + jwtCookie,
+			"X-CSRF-Token": csrfToken,
+		}, http.StatusForbidden},
+		{"forged token", map[string]string{
+			"Cookie":       "jwt=" + jwtCookie + "; csrf_token=abc.def",
+			"X-CSRF-Token": "abc.def",
+		}, http.StatusForbidden},
+		{"bearer is exempt", map[string]string{"Authorization": "Bearer " + accessToken}, http.StatusOK},
+		{"bearer with cookie is exempt", map[string]string{
+			"Authorization": "Bearer " + accessToken,
+			"Cookie":        "jwt=" + jwtCookie,
+		}, http.StatusOK},
+		{"basic authorization with cookie", map[string]string{
+			"Authorization": "Basic x",
+			"Cookie":        "jwt=" + jwtCookie,
+		}, http.StatusForbidden},
+		{"invalid bearer with cookie", map[string]string{
+			"Authorization": "Bearer not-a-token",
+			"Cookie":        "jwt=" + jwtCookie,
+		}, http.StatusForbidden},
+		{"valid double submit", map[string]string{
+			"Cookie":       "jwt=" + jwtCookie + "; csrf_token=" + csrfToken,
+			"X-CSRF-Token": csrfToken,
+		}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := testutil.DoJSON(t, r, http.MethodPost, "/api/v1/logout", nil, tt.headers)
+			require.Equal(t, tt.want, resp.Code)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 	"task_manager/public/dto"
@@ -29,6 +31,7 @@ func NewHandler(uow repositories.UnitOfWork, mw *jwt.GinJWTMiddleware) *Handler
 
 func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.POST("/signup", h.Signup)
+	rg.POST("/password/reset", h.ResetPassword)
 }
 
 // Signup godoc
@@ -61,13 +64,13 @@ func (h *Handler) Signup(c *gin.Context) {
 		return
 	}
 
-	existing, err := h.users.GetUserByEmail(c.Request.Context(), req.Email)
-	if err != nil {
-		dto.Internal(dto.CodeDatabaseError, "database error", err.Error(), nil).Send(c)
+	_, err := h.users.GetUserByEmail(c.Request.Context(), req.Email)
+	if err == nil {
+		dto.Conflict(dto.CodeConflict, "email already in use", nil).Send(c)
 		return
 	}
-	if existing != nil {
-		dto.Conflict(dto.CodeConflict, "email already in use", nil).Send(c)
+	if !errors.Is(err, repositories.ErrNotFound) {
+		dto.RepoError(err, "user").Send(c)
 		return
 	}
 
@@ -96,22 +99,22 @@ func (h *Handler) Signup(c *gin.Context) {
 	defer tx.Stop()
 
 	if err := tx.Users().CreateUser(c.Request.Context(), u); err != nil {
-		// if the DB enforces uniqueness, this also covers race conditions
-		if looksLikeUniqueViolation(err) {
+		// the unique index on email also covers concurrent signups
+		if errors.Is(err, repositories.ErrConflict) {
 			dto.Conflict(dto.CodeConflict, "email already in use", nil).Send(c)
 			return
 		}
-		dto.Internal(dto.CodeDatabaseError, "could not create user", err.Error(), nil).Send(c)
+		dto.RepoError(err, "user").Send(c)
 		return
 	}
 
 	if err := tx.Users().UpsertPassword(c.Request.Context(), u.ID, string(hash)); err != nil {
-		dto.Internal(dto.CodeDatabaseError, "could not set password", err.Error(), nil).Send(c)
+		dto.RepoError(err, "password").Send(c)
 		return
 	}
 
 	if err := tx.Commit(); err != nil {
-		dto.Internal(dto.CodeDatabaseError, "could not complete signup", err.Error(), nil).Send(c)
+		dto.RepoError(err, "user").Send(c)
 		return
 	}
 	trace.Log(c, "signup", "user_id="+u.ID.String()+" email="+u.Email)
@@ -151,10 +154,61 @@ func (h *Handler) Signup(c *gin.Context) {
 	})
 }
 
-func looksLikeUniqueViolation(err error) bool {
-	if err == nil {
-		return false
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password using a reset token issued by an administrator.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.PasswordResetRequest true "Password reset request"
+// @Success 200 {object} dto.MessageEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 500 {object} dto.ErrorEnvelope
+// @Router /auth/password/reset [post]
+func (h *Handler) ResetPassword(c *gin.Context) {
+	req := dto.PasswordResetRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	req.Token = strings.TrimSpace(req.Token)
+
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
 	}
-	s := strings.ToLower(err.Error())
-	return strings.Contains(s, "unique") || strings.Contains(s, "duplicate")
+
+	resetToken, err := h.users.GetPasswordResetTokenByHash(c.Request.Context(), jwtauth.HashPasswordResetToken(req.Token))
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		dto.RepoError(err, "reset token").Send(c)
+		return
+	}
+	if err != nil || time.Now().After(resetToken.ExpiresAt) {
+		dto.BadRequest(dto.CodeInvalidToken, "invalid or expired reset token", nil).Send(c)
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), 14)
+	if err != nil {
+		dto.Internal(dto.CodeInternalError, "could not hash password", err.Error(), nil).Send(c)
+		return
+	}
+
+	err = h.uow.WithTransaction(c.Request.Context(), func(ctx context.Context, r repositories.Repos) error {
+		if err := r.Users.UpsertPassword(ctx, resetToken.UserID, string(hash)); err != nil {
+			return err
+		}
+		if err := r.Users.SetPasswordResetRequired(ctx, resetToken.UserID, false); err != nil {
+			return err
+		}
+		return r.Users.DeletePasswordResetTokensByUserID(ctx, resetToken.UserID)
+	})
+	if err != nil {
+		dto.RepoError(err, "password").Send(c)
+		return
+	}
+	trace.Log(c, "password_reset", "user_id="+resetToken.UserID.String())
+
+	dto.OK(c, http.StatusOK, dto.MessageResponse{Message: "password updated"})
 }
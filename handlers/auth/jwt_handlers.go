@@ -1,7 +1,9 @@
 package auth
 
 import (
+	"net/http"
 	"task_manager/public/dto"
+	"task_manager/public/jwtauth"
 
 	jwt "github.com/appleboy/gin-jwt/v3"
 	"github.com/gin-gonic/gin"
@@ -18,6 +20,7 @@ func RegisterRoutes(rg *gin.RouterGroup) {
 	rg.POST("/login", Login)
 	rg.POST("/refresh", Refresh)
 	rg.POST("/logout", mw.MiddlewareFunc(), Logout)
+	rg.GET("/csrf", mw.MiddlewareFunc(), CSRFToken)
 }
 
 // Login godoc
@@ -72,3 +75,32 @@ func Logout(c *gin.Context) {
 	}
 	mw.LogoutHandler(c)
 }
+
+// CSRFToken godoc
+// @Summary Get CSRF token
+// @Description Issues a CSRF token and sets it in the csrf_token cookie. Browser clients that
+// @Description authenticate with the jwt cookie must echo it in the X-CSRF-Token header on
+// @Description POST/PUT/PATCH/DELETE requests. Clients using a Bearer header do not need it.
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.CSRFTokenEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Router /auth/csrf [get]
+func CSRFToken(c *gin.Context) {
+	if mw == nil {
+		dto.Internal(dto.CodeInternalError, "auth middleware not initialized", "nil middleware", nil).Send(c)
+		return
+	}
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.Unauthorized(dto.CodeUnauthorized, "unauthorized", nil).Send(c)
+		return
+	}
+	token, err := jwtauth.IssueCSRFToken(c, mw, userID.String())
+	if err != nil {
+		dto.Internal(dto.CodeInternalError, "could not generate CSRF token", err.Error(), nil).Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, dto.CSRFTokenResponse{Token: token, HeaderName: jwtauth.CSRFHeaderKey})
+}
@@ -46,10 +46,10 @@ func New(uow repositories.UnitOfWork, mw *jwt.GinJWTMiddleware) *Handler {
 func (h *Handler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.GET("/google/login", h.GoogleLogin)
 	rg.GET("/google/callback", h.GoogleCallback)
-	rg.GET("/google/link", h.mw.MiddlewareFunc(), h.GoogleLink)
+	rg.GET("/google/link", h.mw.MiddlewareFunc(), jwtauth.DenyImpersonation(), h.GoogleLink)
 	rg.GET("/github/login", h.GitHubLogin)
 	rg.GET("/github/callback", h.GitHubCallback)
-	rg.GET("/github/link", h.mw.MiddlewareFunc(), h.GitHubLink)
+	rg.GET("/github/link", h.mw.MiddlewareFunc(), jwtauth.DenyImpersonation(), h.GitHubLink)
 }
 
 func NewWithConfig(uow repositories.UnitOfWork, mw *jwt.GinJWTMiddleware, cfg config.Config) *Handler {
@@ -323,11 +323,18 @@ func (h *Handler) handleProviderCallback(c *gin.Context, stateData StateData, p
 func (h *Handler) handleLoginWithProvider(c *gin.Context, platform string, p providerProfile) {
 	// 1) If provider is already linked, login.
 	existingByProvider, err := h.users.GetUserByAuthProvider(c.Request.Context(), p.Provider, p.ProviderUserID)
-	if err != nil {
-		dto.Internal(dto.CodeDatabaseError, "database error", err.Error(), nil).Send(c)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		dto.RepoError(err, "user").Send(c)
 		return
 	}
-	if existingByProvider != nil {
+	if err == nil {
+		if existingByProvider.IsDisabled() {
+			trace.Log(c, "oauth_login_disabled",
+				"provider="+string(p.Provider)+" user_id="+existingByProvider.ID.String(),
+			)
+			dto.Forbidden(dto.CodeForbidden, jwtauth.ErrAccountDisabled.Error(), nil).Send(c)
+			return
+		}
 		trace.Log(c, "oauth_login",
 			"provider="+string(p.Provider)+
 				" provider_user_id="+p.ProviderUserID+
@@ -348,12 +355,12 @@ func (h *Handler) handleLoginWithProvider(c *gin.Context, platform string, p pro
 
 	// 2) If email matches an existing user but provider isn't linked yet, forbid.
 	if strings.TrimSpace(p.Email) != "" {
-		existingByEmail, err := h.users.GetUserByEmail(c.Request.Context(), p.Email)
-		if err != nil {
-			dto.Internal(dto.CodeDatabaseError, "database error", err.Error(), nil).Send(c)
+		_, err := h.users.GetUserByEmail(c.Request.Context(), p.Email)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			dto.RepoError(err, "user").Send(c)
 			return
 		}
-		if existingByEmail != nil {
+		if err == nil {
 			trace.Log(c, "oauth_login_forbidden_not_linked",
 				"provider="+string(p.Provider)+" provider_user_id="+p.ProviderUserID+" email="+p.Email,
 			)
@@ -401,15 +408,15 @@ func (h *Handler) handleLoginWithProvider(c *gin.Context, platform string, p pro
 	defer tx.Stop()
 
 	if err := tx.Users().CreateUser(c.Request.Context(), u); err != nil {
-		dto.Internal(dto.CodeDatabaseError, "could not create user", err.Error(), nil).Send(c)
+		dto.RepoError(err, "user").Send(c)
 		return
 	}
 	if err := tx.Users().CreateAuthProvider(c.Request.Context(), ap); err != nil {
-		dto.Internal(dto.CodeDatabaseError, "could not link provider", err.Error(), nil).Send(c)
+		dto.RepoError(err, "provider link").Send(c)
 		return
 	}
 	if err := tx.Commit(); err != nil {
-		dto.Internal(dto.CodeDatabaseError, "could not complete signup", err.Error(), nil).Send(c)
+		dto.RepoError(err, "user").Send(c)
 		return
 	}
 	trace.Log(c, "oauth_signup",
@@ -437,37 +444,42 @@ func (h *Handler) handleLinkProvider(c *gin.Context, platform string, userIDRaw
 	}
 
 	u, err := h.users.GetUserByID(c.Request.Context(), userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		dto.BadRequest(dto.CodeInvalidRequest, "user not found", nil).Send(c)
+		return
+	}
 	if err != nil {
-		dto.Internal(dto.CodeDatabaseError, "database error", err.Error(), nil).Send(c)
+		dto.RepoError(err, "user").Send(c)
 		return
 	}
-	if u == nil {
-		dto.BadRequest(dto.CodeInvalidRequest, "user not found", nil).Send(c)
+	if u.IsDisabled() {
+		dto.Forbidden(dto.CodeForbidden, jwtauth.ErrAccountDisabled.Error(), nil).Send(c)
 		return
 	}
 
 	// If provider_user_id already linked to another user -> conflict.
 	existingByProvider, err := h.users.GetUserByAuthProvider(c.Request.Context(), p.Provider, p.ProviderUserID)
-	if err != nil {
-		dto.Internal(dto.CodeDatabaseError, "database error", err.Error(), nil).Send(c)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		dto.RepoError(err, "user").Send(c)
 		return
 	}
-	if existingByProvider != nil && existingByProvider.ID != userID {
+	if err == nil && existingByProvider.ID != userID {
 		dto.Conflict(dto.CodeConflict, "provider account already linked to another user", nil).Send(c)
 		return
 	}
 
 	// If user already has this provider linked, it must match the same provider_user_id.
 	existingLink, err := h.users.GetAuthProviderByUserAndProvider(c.Request.Context(), userID, p.Provider)
-	if err != nil {
-		dto.Internal(dto.CodeDatabaseError, "database error", err.Error(), nil).Send(c)
+	linked := err == nil
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		dto.RepoError(err, "provider link").Send(c)
 		return
 	}
-	if existingLink != nil && existingLink.ProviderUserID != p.ProviderUserID {
+	if linked && existingLink.ProviderUserID != p.ProviderUserID {
 		dto.Conflict(dto.CodeConflict, "this provider is already linked to a different provider account", nil).Send(c)
 		return
 	}
-	if existingLink == nil {
+	if !linked {
 		now := time.Now()
 		ap := &models.AuthProvider{
 			ID:             uuid.New(),
@@ -482,7 +494,7 @@ func (h *Handler) handleLinkProvider(c *gin.Context, platform string, userIDRaw
 			UpdatedAt:      now,
 		}
 		if err := h.users.CreateAuthProvider(c.Request.Context(), ap); err != nil {
-			dto.Internal(dto.CodeDatabaseError, "could not link provider", err.Error(), nil).Send(c)
+			dto.RepoError(err, "provider link").Send(c)
 			return
 		}
 		trace.Log(c, "oauth_link",
@@ -0,0 +1,96 @@
+package team
+
+import (
+	"task_manager/public/dto"
+	"task_manager/public/jwtauth"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/listspec"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TeamGetTaskActivity godoc
+// @Summary List the activity of a task
+// @Description Who changed what on the task and when, newest first: creation, field edits with their old and new
+// @Description values, state transitions, assignee and label changes, comments and attachments. Changes made by the
+// @Description server, such as recurring tasks it creates, have no actor.
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from meta.next_cursor of the previous page"
+// @Param sort query string false "created_at or -created_at"
+// @Param include_total query bool false "Include meta.total"
+// @Param action query string false "Only this action, e.g. task.updated; action[in]=a,b for several"
+// @Param actor_id query string false "Only changes made by this user"
+// @Success 200 {object} dto.TaskActivitiesEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/activity [get]
+func (r *TeamsHandler) TeamGetTaskActivity(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	q, err := listquery.Parse(c.Request.URL.Query(), listspec.TaskActivity)
+	if err != nil {
+		dto.ListQueryError(err).Send(c)
+		return
+	}
+	activity, err := r.uow.Activity().ListTaskActivity(c.Request.Context(), task.ID, q)
+	if err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	dto.OKPage(c, activity)
+}
+
+// recordActivity adds an entry to the activity of task within tx, made by the caller.
+// It sends the error response and returns false when the entry cannot be written, so
+// the change it records is rolled back with it.
+func recordActivity(c *gin.Context, tx repositories.Transaction, task *models.Task, action models.TaskActivityAction, changes []models.FieldChange, details map[string]any) bool {
+	var actorID *uuid.UUID
+	if id, err := jwtauth.CurrentUserID(c); err == nil {
+		actorID = &id
+	}
+	err := tx.Activity().CreateTaskActivity(c.Request.Context(), &models.TaskActivity{
+		ID:        uuid.New(),
+		TeamID:    task.TeamID,
+		TaskID:    task.ID,
+		ActorID:   actorID,
+		Action:    action,
+		Changes:   changes,
+		Details:   details,
+		TraceID:   trace.Get(c),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		dto.RepoError(err, "task activity").Send(c)
+		return false
+	}
+	return true
+}
+
+// recordTasksActivity adds the same entry to the activity of each of the tasks, for
+// changes made to many tasks of the team at once.
+func recordTasksActivity(c *gin.Context, tx repositories.Transaction, teamID uuid.UUID, taskIDs []uuid.UUID, action models.TaskActivityAction, changes []models.FieldChange, details map[string]any) bool {
+	for _, id := range taskIDs {
+		if !recordActivity(c, tx, &models.Task{ID: id, TeamID: teamID}, action, changes, details) {
+			return false
+		}
+	}
+	return true
+}
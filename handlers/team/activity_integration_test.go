@@ -0,0 +1,104 @@
+package team_test
+
+import (
+	"net/http"
+	"net/url"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskActivity_SQLite(t *testing.T) {
+	f := newFixture(t)
+	teamPath := "/api/v1/team/" + f.teamID.String()
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Launch", AssigneeIDs: []uuid.UUID{f.memberID}}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	task := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data
+	taskPath := f.tasksPath() + "/" + task.ID.String()
+
+	title, description := "Ship", ""
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, taskPath, dto.TaskUpdateRequest{Title: &title, Description: &description}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	// Unchanged values are not recorded.
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, taskPath, dto.TaskUpdateRequest{Title: &title}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/labels", dto.LabelRequest{Name: "bug", Color: "#d73a4a"}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	label := testutil.DecodeJSON[dto.LabelEnvelope](t, rr).Data
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, taskPath+"/labels", dto.TaskLabelsRequest{LabelIDs: []uuid.UUID{label.ID}}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, taskPath+"/assignees", dto.TaskAssigneesRequest{UserIDs: []uuid.UUID{f.memberID}}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/team/"+f.teamID.String()+"/workflow", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var done *models.WorkflowState
+	for _, s := range testutil.DecodeJSON[dto.WorkflowEnvelope](t, rr).Data.States {
+		if s.Category == models.CategoryDone {
+			done = &s
+		}
+	}
+	require.NotNil(t, done)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, taskPath+"/state", dto.TaskStateRequest{StateID: done.ID}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, taskPath+"/comments", dto.CommentRequest{Body: "done"}, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	comment := testutil.DecodeJSON[dto.CommentEnvelope](t, rr).Data
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, taskPath+"/activity", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	activity := testutil.DecodeJSON[dto.TaskActivitiesEnvelope](t, rr).Data
+	actions := make([]models.TaskActivityAction, len(activity))
+	for i, a := range activity {
+		actions[i] = a.Action
+	}
+	require.Equal(t, []models.TaskActivityAction{
+		models.ActivityCommentAdded,
+		models.ActivityStateChanged,
+		models.ActivityAssigneesChanged,
+		models.ActivityLabelsChanged,
+		models.ActivityTaskUpdated,
+		models.ActivityTaskCreated,
+	}, actions)
+
+	require.Equal(t, comment.ID.String(), activity[0].Details["comment_id"])
+	require.Equal(t, "state_id", activity[1].Changes[0].Field)
+	require.Equal(t, done.ID.String(), activity[1].Changes[0].New)
+	require.Equal(t, models.FieldChange{Field: "assignee_ids", Old: []any{f.memberID.String()}, New: []any{}}, activity[2].Changes[0])
+	require.Equal(t, f.memberID, *activity[4].ActorID)
+	require.Equal(t, []models.FieldChange{{Field: "title", Old: "Launch", New: "Ship"}}, activity[4].Changes)
+	created := map[string]any{}
+	for _, c := range activity[5].Changes {
+		created[c.Field] = c.New
+	}
+	require.Equal(t, "Launch", created["title"])
+	require.Equal(t, []any{f.memberID.String()}, created["assignee_ids"])
+	require.Contains(t, created, "state_id")
+
+	// Filters and pages.
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, taskPath+"/activity?actor_id="+f.memberID.String()+"&sort=created_at&limit=2", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	page := testutil.DecodeJSON[dto.TaskActivitiesEnvelope](t, rr)
+	require.Len(t, page.Data, 2)
+	require.Equal(t, models.ActivityTaskUpdated, page.Data[0].Action)
+	require.True(t, page.Meta.HasMore)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, taskPath+"/activity?actor_id="+f.memberID.String()+"&sort=created_at&limit=2&cursor="+url.QueryEscape(page.Meta.NextCursor), nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	page = testutil.DecodeJSON[dto.TaskActivitiesEnvelope](t, rr)
+	require.Len(t, page.Data, 2)
+	require.Equal(t, models.ActivityCommentAdded, page.Data[1].Action)
+	require.False(t, page.Meta.HasMore)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, taskPath+"/activity?action=task.archived", nil, f.member)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, taskPath+"/activity?include_total=true", nil, f.member)
+	require.Equal(t, 6, *testutil.DecodeJSON[dto.TaskActivitiesEnvelope](t, rr).Meta.Total)
+}
@@ -0,0 +1,406 @@
+package team
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"task_manager/public/blobstore"
+	"task_manager/public/dto"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// multipartOverhead is the room left in the request body for the multipart framing and
+// the other form fields on top of the file itself.
+const multipartOverhead = 1 << 20
+
+// TeamGetAttachments godoc
+// @Summary List the attachments of a task
+// @Description Attachments of the task and of its comments, oldest first.
+// @Tags attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} dto.AttachmentsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/attachments [get]
+func (r *TeamsHandler) TeamGetAttachments(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	attachments, err := r.uow.Attachments().ListTaskAttachments(c.Request.Context(), task.ID)
+	if err != nil {
+		dto.RepoError(err, "attachment").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, attachments)
+}
+
+// TeamPostAttachment godoc
+// @Summary Upload an attachment
+// @Description Uploads a file to the task, or to one of its comments with comment_id (comment authors only).
+// @Description The content type is detected from the file content, not taken from the client. Files larger
+// @Description than the per-file limit, or that would take the team over its storage quota, are refused
+// @Description with 413.
+// @Tags attachments
+// @Accept mpfd
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param file formData file true "File"
+// @Param comment_id formData string false "Comment of the task to attach the file to"
+// @Success 201 {object} dto.AttachmentEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 413 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/attachments [post]
+func (r *TeamsHandler) TeamPostAttachment(c *gin.Context) {
+	teamID, userID, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, r.maxAttachmentBytes+multipartOverhead)
+	fh, err := c.FormFile("file")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		r.fileTooLarge(c)
+		return
+	}
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "a file is required in the \"file\" form field", nil).Send(c)
+		return
+	}
+	if fh.Size > r.maxAttachmentBytes {
+		r.fileTooLarge(c)
+		return
+	}
+
+	var commentID *uuid.UUID
+	if raw := strings.TrimSpace(c.PostForm("comment_id")); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			dto.BadRequest(dto.CodeInvalidRequest, "invalid comment id", nil).Send(c)
+			return
+		}
+		comment, err := r.uow.Comments().GetComment(c.Request.Context(), task.ID, id)
+		if err == nil && comment.IsDeleted() {
+			err = repositories.ErrNotFound
+		}
+		if err != nil {
+			dto.RepoError(err, "comment").Send(c)
+			return
+		}
+		if comment.AuthorID == nil || *comment.AuthorID != userID {
+			dto.Forbidden(dto.CodeForbidden, "only the author can attach files to a comment", nil).Send(c)
+			return
+		}
+		commentID = &id
+	}
+	if !r.checkStorageQuota(c, r.uow.Attachments(), teamID, fh.Size) {
+		return
+	}
+
+	file, err := fh.Open()
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid file", nil).Send(c)
+		return
+	}
+	defer file.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid file", nil).Send(c)
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		dto.Internal(dto.CodeInternalError, "could not read the file", err.Error(), nil).Send(c)
+		return
+	}
+
+	attachment := &models.Attachment{
+		ID:          uuid.New(),
+		TeamID:      teamID,
+		TaskID:      task.ID,
+		CommentID:   commentID,
+		UploadedBy:  &userID,
+		Filename:    attachmentFilename(fh.Filename),
+		ContentType: http.DetectContentType(head[:n]),
+		SizeBytes:   fh.Size,
+	}
+	attachment.StorageKey = teamID.String() + "/" + task.ID.String() + "/" + attachment.ID.String()
+	if err := r.blobs.Put(c.Request.Context(), attachment.StorageKey, file, fh.Size, attachment.ContentType); err != nil {
+		dto.Internal(dto.CodeInternalError, "could not store the file", err.Error(), nil).Send(c)
+		return
+	}
+	// The blob is not referenced by any row yet, so it is removed here if the row
+	// cannot be written.
+	stored := false
+	defer func() {
+		if !stored {
+			_ = r.blobs.Delete(c.Request.Context(), attachment.StorageKey)
+		}
+	}()
+
+	tx, err := r.uow.Begin(c.Request.Context())
+	if err != nil {
+		dto.Internal(dto.CodeDatabaseError, "database error", err.Error(), nil).Send(c)
+		return
+	}
+	defer tx.Stop()
+
+	// Checked again inside the transaction, as other uploads may have finished meanwhile;
+	// the lock keeps concurrent uploads from passing the check together.
+	if err := tx.Attachments().LockTeamStorage(c.Request.Context(), teamID); err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	// The storage lock is taken first: it is the stronger of the two on the team row.
+	if !lockTeamWritable(c, tx, teamID) {
+		return
+	}
+	if !r.checkStorageQuota(c, tx.Attachments(), teamID, fh.Size) {
+		return
+	}
+	if err := tx.Attachments().CreateAttachment(c.Request.Context(), attachment); err != nil {
+		dto.RepoError(err, "attachment").Send(c)
+		return
+	}
+	details := map[string]any{"attachment_id": attachment.ID, "filename": attachment.Filename, "size_bytes": attachment.SizeBytes}
+	if !recordActivity(c, tx, task, models.ActivityAttachmentAdded, nil, details) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "attachment").Send(c)
+		return
+	}
+	stored = true
+	trace.Log(c, "attachment_created", "task_id="+task.ID.String()+" attachment_id="+attachment.ID.String()+" size="+strconv.FormatInt(attachment.SizeBytes, 10))
+
+	dto.OK(c, http.StatusCreated, attachment)
+}
+
+// TeamDownloadAttachment godoc
+// @Summary Download an attachment
+// @Description Streams the file. A single byte range ("Range: bytes=start-end") is answered with 206.
+// @Tags attachments
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 416 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/attachments/{attachment_id}/download [get]
+func (r *TeamsHandler) TeamDownloadAttachment(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	attachment, ok := r.loadAttachment(c, task.ID)
+	if !ok {
+		return
+	}
+
+	offset, length, partial, ok := parseRange(c.GetHeader("Range"), attachment.SizeBytes)
+	if !ok {
+		c.Header("Content-Range", "bytes */"+strconv.FormatInt(attachment.SizeBytes, 10))
+		dto.Fail(c, http.StatusRequestedRangeNotSatisfiable, dto.CodeRangeNotSatisfiable, "range not satisfiable", "", nil)
+		return
+	}
+	body, err := r.blobs.Open(c.Request.Context(), attachment.StorageKey, offset, length)
+	if errors.Is(err, blobstore.ErrNotFound) {
+		dto.NotFound(dto.CodeNotFound, "attachment content not found", err.Error(), nil).Send(c)
+		return
+	}
+	if err != nil {
+		dto.Internal(dto.CodeInternalError, "could not read the file", err.Error(), nil).Send(c)
+		return
+	}
+	defer body.Close()
+
+	h := c.Writer.Header()
+	h.Set("Content-Type", attachment.ContentType)
+	h.Set("Content-Length", strconv.FormatInt(length, 10))
+	h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	h.Set("X-Content-Type-Options", "nosniff")
+	h.Set("Accept-Ranges", "bytes")
+	status := http.StatusOK
+	if partial {
+		status = http.StatusPartialContent
+		h.Set("Content-Range", "bytes "+strconv.FormatInt(offset, 10)+"-"+strconv.FormatInt(offset+length-1, 10)+"/"+strconv.FormatInt(attachment.SizeBytes, 10))
+	}
+	c.Status(status)
+	if _, err := io.Copy(c.Writer, body); err != nil {
+		trace.Log(c, "attachment_download_failed", "attachment_id="+attachment.ID.String()+" err="+err.Error())
+	}
+}
+
+// TeamDeleteAttachment godoc
+// @Summary Delete an attachment
+// @Description Only the uploader or a team admin/founder can delete an attachment. The file is removed from
+// @Description storage in the background.
+// @Tags attachments
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 204
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/attachments/{attachment_id} [delete]
+func (r *TeamsHandler) TeamDeleteAttachment(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	attachment, ok := r.loadAttachment(c, task.ID)
+	if !ok {
+		return
+	}
+	isUploader := attachment.UploadedBy != nil && *attachment.UploadedBy == userID
+	if !isUploader && role != models.AdminUserRole && role != models.FounderUserRole {
+		dto.Forbidden(dto.CodeForbidden, "only the uploader or a team admin can delete the attachment", nil).Send(c)
+		return
+	}
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+	if err := tx.Attachments().DeleteAttachment(c.Request.Context(), task.ID, attachment.ID); err != nil {
+		dto.RepoError(err, "attachment").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "attachment").Send(c)
+		return
+	}
+	trace.Log(c, "attachment_deleted", "task_id="+task.ID.String()+" attachment_id="+attachment.ID.String())
+	c.Status(http.StatusNoContent)
+}
+
+func (r *TeamsHandler) loadAttachment(c *gin.Context, taskID uuid.UUID) (*models.Attachment, bool) {
+	attachmentID, err := uuid.Parse(c.Param("attachment_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid attachment id", nil).Send(c)
+		return nil, false
+	}
+	attachment, err := r.uow.Attachments().GetAttachment(c.Request.Context(), taskID, attachmentID)
+	if err != nil {
+		dto.RepoError(err, "attachment").Send(c)
+		return nil, false
+	}
+	return attachment, true
+}
+
+func (r *TeamsHandler) fileTooLarge(c *gin.Context) {
+	dto.TooLarge(dto.CodeFileTooLarge, "the file is too large", map[string]any{"max_bytes": r.maxAttachmentBytes}).Send(c)
+}
+
+// checkStorageQuota makes sure the team has room for size more bytes. It sends the
+// error response and returns false otherwise.
+func (r *TeamsHandler) checkStorageQuota(c *gin.Context, attachments repositories.AttachmentRepository, teamID uuid.UUID, size int64) bool {
+	used, err := attachments.TeamStorageUsed(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "attachment").Send(c)
+		return false
+	}
+	if used+size > r.storageQuotaBytes {
+		dto.TooLarge(dto.CodeStorageQuotaExceeded, "the team storage quota is exceeded", map[string]any{
+			"used_bytes":  used,
+			"quota_bytes": r.storageQuotaBytes,
+		}).Send(c)
+		return false
+	}
+	return true
+}
+
+// attachmentFilename keeps the base name of a client supplied file name.
+func attachmentFilename(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "." || name == "/" || name == "" || !utf8.ValidString(name) {
+		return "file"
+	}
+	if len(name) > 255 {
+		name = strings.ToValidUTF8(name[:255], "")
+	}
+	return name
+}
+
+// parseRange interprets a Range header for a blob of size bytes. Only single byte
+// ranges are honoured; anything else is answered with the whole blob, as RFC 9110
+// allows. ok is false when the range cannot be satisfied.
+func parseRange(header string, size int64) (offset int64, length int64, partial bool, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if header == "" || !found || strings.Contains(spec, ",") {
+		return 0, size, false, true
+	}
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false, false
+	}
+	if first == "" {
+		// Suffix range: the last n bytes.
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 || size == 0 {
+			return 0, 0, false, false
+		}
+		n = min(n, size)
+		return size - n, n, true, true
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return 0, 0, false, false
+	}
+	end := size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, false, false
+		}
+		end = min(end, size-1)
+	}
+	return start, end - start + 1, true, true
+}
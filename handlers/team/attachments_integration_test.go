@@ -0,0 +1,156 @@
+package team_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"task_manager/public/blobstore"
+	"task_manager/public/config"
+	"task_manager/public/dto"
+	"task_manager/public/jobs"
+	"task_manager/public/testutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+func upload(t *testing.T, r http.Handler, path string, content []byte, commentID *uuid.UUID, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if commentID != nil {
+		require.NoError(t, w.WriteField("comment_id", commentID.String()))
+	}
+	part, err := w.CreateFormFile("file", "../diagram.png")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	req := httptest.NewRequest(http.MethodPost, path, &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestAttachments_SQLite(t *testing.T) {
+	cfg := config.Load()
+	cfg.AttachmentMaxBytes = 1024
+	cfg.TeamStorageQuotaBytes = 2048
+	blobs := blobstore.NewLocal(t.TempDir())
+	f := newFixtureWithConfig(t, cfg, blobs)
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Design"}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	task := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data
+	attachments := f.tasksPath() + "/" + task.ID.String() + "/attachments"
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath()+"/"+task.ID.String()+"/comments", dto.CommentRequest{Body: "see attached"}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	comment := testutil.DecodeJSON[dto.CommentEnvelope](t, rr).Data
+
+	png := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{0}, 592)...)
+	rr = upload(t, f.r, attachments, png, &comment.ID, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	attachment := testutil.DecodeJSON[dto.AttachmentEnvelope](t, rr).Data
+	require.Equal(t, "image/png", attachment.ContentType)
+	require.Equal(t, "diagram.png", attachment.Filename)
+	require.Equal(t, int64(600), attachment.SizeBytes)
+	require.Equal(t, comment.ID, *attachment.CommentID)
+
+	tests := []struct {
+		name       string
+		content    []byte
+		commentID  *uuid.UUID
+		auth       map[string]string
+		wantStatus int
+		wantCode   dto.ErrorCode
+	}{
+		{"non-members cannot upload", []byte("x"), nil, signup(t, f.r, "stranger@example.com"), http.StatusForbidden, dto.CodeForbidden},
+		{"only the comment author attaches to a comment", []byte("x"), &comment.ID, f.member, http.StatusForbidden, dto.CodeForbidden},
+		{"file over the size limit", bytes.Repeat([]byte("a"), 1025), nil, f.member, http.StatusRequestEntityTooLarge, dto.CodeFileTooLarge},
+		{"member uploads a text file", bytes.Repeat([]byte("a"), 1000), nil, f.member, http.StatusCreated, ""},
+		{"upload over the team quota", bytes.Repeat([]byte("a"), 500), nil, f.member, http.StatusRequestEntityTooLarge, dto.CodeStorageQuotaExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := upload(t, f.r, attachments, tt.content, tt.commentID, tt.auth)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantCode != "" {
+				require.Equal(t, tt.wantCode, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+			}
+		})
+	}
+
+	download := attachments + "/" + attachment.ID.String() + "/download"
+	downloads := []struct {
+		name         string
+		rangeHeader  string
+		wantStatus   int
+		wantBody     []byte
+		wantRangeHdr string
+	}{
+		{"whole file", "", http.StatusOK, png, ""},
+		{"byte range", "bytes=0-7", http.StatusPartialContent, pngHeader, "bytes 0-7/600"},
+		{"suffix range", "bytes=-2", http.StatusPartialContent, []byte{0, 0}, "bytes 598-599/600"},
+		{"range past the end", "bytes=600-", http.StatusRequestedRangeNotSatisfiable, nil, "bytes */600"},
+	}
+	for _, tt := range downloads {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{"Range": tt.rangeHeader}
+			for k, v := range f.member {
+				headers[k] = v
+			}
+			rr := testutil.DoJSON(t, f.r, http.MethodGet, download, nil, headers)
+			require.Equal(t, tt.wantStatus, rr.Code)
+			require.Equal(t, tt.wantRangeHdr, rr.Header().Get("Content-Range"))
+			if tt.wantBody != nil {
+				require.Equal(t, tt.wantBody, rr.Body.Bytes())
+				require.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+				require.Equal(t, `attachment; filename=diagram.png`, rr.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, attachments+"/"+attachment.ID.String(), nil, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+
+	// Purging the deleted task from the trash queues its blobs; the cleanup job removes them.
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, attachments, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, testutil.DecodeJSON[dto.AttachmentsEnvelope](t, rr).Data, 2)
+	key := f.teamID.String() + "/" + task.ID.String() + "/" + attachment.ID.String()
+	rc, err := blobs.Open(context.Background(), key, 0, -1)
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, rc)
+	require.NoError(t, rc.Close())
+
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, f.tasksPath()+"/"+task.ID.String(), nil, f.founder)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	pending, err := f.uow.Attachments().ListBlobDeletions(context.Background(), 10)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+	_, err = f.uow.Trash().PurgeTrash(context.Background(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	pending, err = f.uow.Attachments().ListBlobDeletions(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+
+	require.NoError(t, jobs.NewBlobCleanup(f.uow, blobs).Run(context.Background()))
+	_, err = blobs.Open(context.Background(), key, 0, -1)
+	require.ErrorIs(t, err, blobstore.ErrNotFound)
+	pending, err = f.uow.Attachments().ListBlobDeletions(context.Background(), 10)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
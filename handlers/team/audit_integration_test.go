@@ -0,0 +1,104 @@
+package team_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"task_manager/public/dto"
+	"task_manager/public/jobs"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories/listspec"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamAudit_SQLite(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	teamPath := "/api/v1/team/" + f.teamID.String()
+	founder, err := f.uow.Users().GetUserByEmail(ctx, "founder@example.com")
+	require.NoError(t, err)
+
+	headers := map[string]string{"User-Agent": "audit-test/1.0"}
+	for k, v := range f.founder {
+		headers[k] = v
+	}
+	rr := testutil.DoJSON(t, f.r, http.MethodPut, teamPath, dto.TeamCreationRequest{TeamName: "Platform"}, headers)
+	require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+	// Saving the same name again is not an action worth logging.
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, teamPath, dto.TeamCreationRequest{TeamName: "Platform"}, f.founder)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/projects", dto.ProjectRequest{Name: "Secret", Restricted: true}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	project := testutil.DecodeJSON[dto.ProjectEnvelope](t, rr).Data
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, teamPath+"/projects/"+project.ID.String()+"/members", dto.ProjectMembersRequest{UserIDs: []uuid.UUID{f.memberID}}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/audit", nil, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/audit", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	entries := testutil.DecodeJSON[dto.TeamAuditEnvelope](t, rr).Data
+	actions := make([]models.TeamAuditAction, len(entries))
+	for i, e := range entries {
+		actions[i] = e.Action
+		require.Equal(t, founder.ID, *e.ActorID)
+	}
+	require.Equal(t, []models.TeamAuditAction{
+		models.TeamActionProjectAccess,
+		models.TeamActionProjectAccess,
+		models.TeamActionRename,
+		models.TeamActionCreate,
+	}, actions)
+	require.Equal(t, []any{f.memberID.String()}, entries[0].Details["added"])
+	rename := entries[2]
+	require.Equal(t, map[string]any{"old_name": "Ops", "new_name": "Platform"}, rename.Details)
+	require.Equal(t, "audit-test/1.0", rename.UserAgent)
+	require.NotEmpty(t, rename.IP)
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"?action=team.rename", 1},
+		{"?action[in]=team.create,team.rename", 2},
+		{"?actor_id=" + f.memberID.String(), 0},
+		{"?created_at[gte]=" + url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339)), 0},
+	}
+	for _, tt := range tests {
+		rr = testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/audit"+tt.query, nil, f.founder)
+		require.Equal(t, http.StatusOK, rr.Code, tt.query)
+		require.Len(t, testutil.DecodeJSON[dto.TeamAuditEnvelope](t, rr).Data, tt.want, tt.query)
+	}
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/audit?action=team.freeze", nil, f.founder)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// The retention job only deletes entries older than the retention period.
+	require.NoError(t, f.uow.Audit().CreateTeamAuditLog(ctx, &models.TeamAuditLog{
+		ID:        uuid.New(),
+		TeamID:    f.teamID,
+		Action:    models.TeamActionRename,
+		CreatedAt: time.Now().AddDate(0, 0, -400),
+	}))
+	require.NoError(t, jobs.NewAuditRetention(f.uow, 365).Run(ctx))
+	q, err := listquery.Parse(url.Values{"include_total": {"true"}}, listspec.TeamAudit)
+	require.NoError(t, err)
+	res, err := f.uow.Audit().ListTeamAuditLogs(ctx, f.teamID, q)
+	require.NoError(t, err)
+	require.Equal(t, 4, *res.Total)
+
+	// Entries outlive the team.
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, teamPath, nil, f.founder)
+	require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+	res, err = f.uow.Audit().ListTeamAuditLogs(ctx, f.teamID, q)
+	require.NoError(t, err)
+	require.Equal(t, 5, *res.Total)
+	require.Equal(t, models.TeamActionDelete, res.Items[0].Action)
+}
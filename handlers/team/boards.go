@@ -0,0 +1,750 @@
+package team
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/rank"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultBoardCards = 100
+	maxBoardCards     = 500
+)
+
+// TeamGetBoards godoc
+// @Summary List the boards of a team
+// @Tags boards
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} dto.BoardsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/boards [get]
+func (r *TeamsHandler) TeamGetBoards(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	boards, err := r.uow.Boards().ListBoards(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, boards)
+}
+
+// TeamPostBoard godoc
+// @Summary Create a board
+// @Description Only team admins and founders can manage boards. Without columns, the board gets one column per
+// @Description workflow state. Swimlanes by custom field need a single-select field, e.g. a priority field.
+// @Tags boards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body dto.BoardRequest true "Board"
+// @Success 201 {object} dto.BoardEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/boards [post]
+func (r *TeamsHandler) TeamPostBoard(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	if role != models.AdminUserRole && role != models.FounderUserRole {
+		dto.Forbidden(dto.CodeForbidden, "only admin or founder can manage boards", nil).Send(c)
+		return
+	}
+
+	req := dto.BoardRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+	if req.SwimlaneBy == "" {
+		req.SwimlaneBy = models.SwimlaneNone
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	fieldID, ok := checkSwimlane(c, tx.CustomFields(), teamID, req.SwimlaneBy, req.SwimlaneFieldID)
+	if !ok {
+		return
+	}
+	w, err := tx.Workflows().GetWorkflow(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "workflow").Send(c)
+		return
+	}
+	columns, ok := buildBoardColumns(c, w, req.Columns)
+	if !ok {
+		return
+	}
+	board := &models.Board{
+		ID:              uuid.New(),
+		TeamID:          teamID,
+		Name:            req.Name,
+		SwimlaneBy:      req.SwimlaneBy,
+		SwimlaneFieldID: fieldID,
+		Columns:         columns,
+		CreatedBy:       &userID,
+	}
+	if err := tx.Boards().CreateBoard(c.Request.Context(), board); err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return
+	}
+	trace.Log(c, "board_created", "team_id="+teamID.String()+" board_id="+board.ID.String())
+	dto.OK(c, http.StatusCreated, board)
+}
+
+// TeamGetBoard godoc
+// @Summary Get a board with its cards
+// @Description Each column holds the tasks in its workflow state in card order, split into swimlanes. A task
+// @Description with several assignees or labels shows in the lane of each. The empty swimlane key holds the
+// @Description cards without one.
+// @Tags boards
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param board_id path string true "Board ID"
+// @Param limit query int false "Most cards per column (default 100, max 500)"
+// @Success 200 {object} dto.BoardViewEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/boards/{board_id} [get]
+func (r *TeamsHandler) TeamGetBoard(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	limit := defaultBoardCards
+	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 || v > maxBoardCards {
+			dto.BadRequest(dto.CodeInvalidRequest, "limit must be between 1 and "+strconv.Itoa(maxBoardCards), nil).Send(c)
+			return
+		}
+		limit = v
+	}
+
+	board, ok := r.loadBoard(c, r.uow.Boards(), teamID)
+	if !ok {
+		return
+	}
+	lanes, ok := r.swimlanes(c, board)
+	if !ok {
+		return
+	}
+	view := &models.BoardView{Board: board, Columns: make([]models.BoardColumnView, 0, len(board.Columns))}
+	tasks := make([][]*models.Task, len(board.Columns))
+	for i, col := range board.Columns {
+		var count int
+		var err error
+		tasks[i], count, err = r.uow.Boards().ListColumnTasks(c.Request.Context(), board, col.StateID, limit)
+		if err != nil {
+			dto.RepoError(err, "board").Send(c)
+			return
+		}
+		view.Columns = append(view.Columns, models.BoardColumnView{BoardColumn: col, TaskCount: count, OverLimit: col.OverLimit(count)})
+		for _, t := range tasks[i] {
+			lanes.add(t)
+		}
+	}
+	view.Swimlanes = lanes.keys()
+	for i := range view.Columns {
+		view.Columns[i].Lanes = lanes.split(view.Swimlanes, tasks[i])
+	}
+	dto.OK(c, http.StatusOK, view)
+}
+
+// TeamPatchBoard godoc
+// @Summary Edit a board
+// @Description Only team admins and founders can manage boards.
+// @Tags boards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param board_id path string true "Board ID"
+// @Param request body dto.BoardUpdateRequest true "Fields to change"
+// @Success 200 {object} dto.BoardEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/boards/{board_id} [patch]
+func (r *TeamsHandler) TeamPatchBoard(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage boards")
+	if !ok {
+		return
+	}
+
+	req := dto.BoardUpdateRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if req.Name != nil {
+		*req.Name = strings.TrimSpace(*req.Name)
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	board, ok := r.loadBoard(c, tx.Boards(), teamID)
+	if !ok {
+		return
+	}
+	if req.Name != nil {
+		board.Name = *req.Name
+	}
+	if req.SwimlaneBy != nil || req.SwimlaneFieldID != nil {
+		by, fieldID := board.SwimlaneBy, req.SwimlaneFieldID
+		if req.SwimlaneBy != nil {
+			by = *req.SwimlaneBy
+		}
+		if fieldID == nil && by == models.SwimlaneCustomField {
+			fieldID = board.SwimlaneFieldID
+		}
+		if board.SwimlaneFieldID, ok = checkSwimlane(c, tx.CustomFields(), teamID, by, fieldID); !ok {
+			return
+		}
+		board.SwimlaneBy = by
+	}
+	if err := tx.Boards().UpdateBoard(c.Request.Context(), board); err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return
+	}
+	trace.Log(c, "board_updated", "team_id="+teamID.String()+" board_id="+board.ID.String())
+	dto.OK(c, http.StatusOK, board)
+}
+
+// TeamPutBoardColumns godoc
+// @Summary Replace the columns of a board
+// @Description Only team admins and founders can manage boards. Each workflow state can have at most one column.
+// @Description Card order is kept per board, so it survives changing the columns.
+// @Tags boards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param board_id path string true "Board ID"
+// @Param request body dto.BoardColumnsRequest true "Columns in display order"
+// @Success 200 {object} dto.BoardEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/boards/{board_id}/columns [put]
+func (r *TeamsHandler) TeamPutBoardColumns(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage boards")
+	if !ok {
+		return
+	}
+
+	req := dto.BoardColumnsRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	board, ok := r.loadBoard(c, tx.Boards(), teamID)
+	if !ok {
+		return
+	}
+	w, err := tx.Workflows().GetWorkflow(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "workflow").Send(c)
+		return
+	}
+	if board.Columns, ok = buildBoardColumns(c, w, req.Columns); !ok {
+		return
+	}
+	if err := tx.Boards().SetBoardColumns(c.Request.Context(), board.ID, board.Columns); err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return
+	}
+	trace.Log(c, "board_columns_updated", "team_id="+teamID.String()+" board_id="+board.ID.String()+" columns="+strconv.Itoa(len(board.Columns)))
+	dto.OK(c, http.StatusOK, board)
+}
+
+// TeamDeleteBoard godoc
+// @Summary Delete a board
+// @Description Only team admins and founders can manage boards. The tasks are not affected.
+// @Tags boards
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param board_id path string true "Board ID"
+// @Success 204
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/boards/{board_id} [delete]
+func (r *TeamsHandler) TeamDeleteBoard(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage boards")
+	if !ok {
+		return
+	}
+	boardID, err := uuid.Parse(c.Param("board_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid board id", nil).Send(c)
+		return
+	}
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+	if err := tx.Boards().DeleteBoard(c.Request.Context(), teamID, boardID); err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return
+	}
+	trace.Log(c, "board_deleted", "team_id="+teamID.String()+" board_id="+boardID.String())
+	c.Status(http.StatusNoContent)
+}
+
+// TeamMoveBoardCard godoc
+// @Summary Move a card on a board
+// @Description Moves the task into the state of the column and between after_id and before_id, in one
+// @Description transaction. The state change follows the same rules as POST /team/{id}/tasks/{task_id}/state.
+// @Description Moving into a column at its WIP limit is refused in enforce mode and returns a warning in warn
+// @Description mode. Only the moved card gets a new rank; other cards keep theirs.
+// @Tags boards
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param board_id path string true "Board ID"
+// @Param request body dto.BoardMoveRequest true "Target column and position"
+// @Success 200 {object} dto.BoardMoveEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/boards/{board_id}/move [post]
+func (r *TeamsHandler) TeamMoveBoardCard(c *gin.Context) {
+	teamID, _, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.BoardMoveRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+	for _, id := range []*uuid.UUID{req.AfterID, req.BeforeID} {
+		if id != nil && *id == req.TaskID {
+			dto.BadRequest(dto.CodeInvalidBoard, "a card cannot be placed next to itself", nil).Send(c)
+			return
+		}
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	boardID, err := uuid.Parse(c.Param("board_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid board id", nil).Send(c)
+		return
+	}
+	if err := tx.Boards().LockBoard(c.Request.Context(), teamID, boardID); err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return
+	}
+	board, err := tx.Boards().GetBoard(c.Request.Context(), teamID, boardID)
+	if err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return
+	}
+	col := board.Column(req.ColumnID)
+	if col == nil {
+		dto.BadRequest(dto.CodeInvalidBoard, "unknown column", map[string]any{"column_id": req.ColumnID}).Send(c)
+		return
+	}
+	task, err := tx.Tasks().GetTaskByID(c.Request.Context(), teamID, req.TaskID)
+	if err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	w, err := tx.Workflows().GetWorkflow(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "workflow").Send(c)
+		return
+	}
+	to, ok := checkTransition(c, w, task, col.StateID, role)
+	if !ok {
+		return
+	}
+
+	move := &models.BoardMove{Task: task, ColumnID: col.ID, Warnings: []string{}}
+	moved := task.StateID == nil || *task.StateID != to.ID
+	if moved {
+		count, err := tx.Boards().CountStateTasks(c.Request.Context(), teamID, to.ID)
+		if err != nil {
+			dto.RepoError(err, "board").Send(c)
+			return
+		}
+		if col.OverLimit(count + 1) {
+			if col.WIPMode == models.WIPEnforce {
+				dto.Conflict(dto.CodeWIPLimitExceeded, "the column is at its WIP limit", map[string]any{
+					"column_id":  col.ID,
+					"wip_limit":  *col.WIPLimit,
+					"task_count": count,
+				}).Send(c)
+				return
+			}
+			move.Warnings = append(move.Warnings, fmt.Sprintf("column %q is over its WIP limit of %d", col.Name, *col.WIPLimit))
+		}
+		if !moveTaskState(c, tx, task, to, req.Force) {
+			return
+		}
+	}
+	if move.Rank, ok = placeCard(c, tx.Boards(), board, col.StateID, task.ID, req.AfterID, req.BeforeID); !ok {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return
+	}
+	trace.Log(c, "board_card_moved", "board_id="+board.ID.String()+" task_id="+task.ID.String()+" column_id="+col.ID.String()+" state_changed="+strconv.FormatBool(moved))
+	dto.OK(c, http.StatusOK, move)
+}
+
+func (r *TeamsHandler) loadBoard(c *gin.Context, boards repositories.BoardRepository, teamID uuid.UUID) (*models.Board, bool) {
+	boardID, err := uuid.Parse(c.Param("board_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid board id", nil).Send(c)
+		return nil, false
+	}
+	board, err := boards.GetBoard(c.Request.Context(), teamID, boardID)
+	if err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return nil, false
+	}
+	return board, true
+}
+
+// checkSwimlane validates the swimlanes of a board and returns the field to store with
+// them: custom_field swimlanes need a single-select field of the team, the others none.
+// It sends the error response and returns ok=false on failure.
+func checkSwimlane(c *gin.Context, fields repositories.CustomFieldRepository, teamID uuid.UUID, by models.SwimlaneBy, fieldID *uuid.UUID) (*uuid.UUID, bool) {
+	if !by.IsValid() {
+		dto.BadRequest(dto.CodeInvalidBoard, "swimlane_by must be none, assignee, label or custom_field", nil).Send(c)
+		return nil, false
+	}
+	if by != models.SwimlaneCustomField {
+		return nil, true
+	}
+	if fieldID == nil {
+		dto.BadRequest(dto.CodeInvalidBoard, "custom_field swimlanes need swimlane_field_id", nil).Send(c)
+		return nil, false
+	}
+	field, err := fields.GetCustomField(c.Request.Context(), teamID, *fieldID)
+	if err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return nil, false
+	}
+	if field.Type != models.FieldSingleSelect {
+		dto.BadRequest(dto.CodeInvalidBoard, "swimlanes need a single_select field", map[string]any{"type": field.Type}).Send(c)
+		return nil, false
+	}
+	return fieldID, true
+}
+
+// buildBoardColumns turns the requested columns into board columns, or into one column
+// per state of w when there are none. It sends the error response and returns
+// ok=false when a state is unknown or has two columns.
+func buildBoardColumns(c *gin.Context, w *models.Workflow, reqs []dto.BoardColumnRequest) ([]models.BoardColumn, bool) {
+	if len(reqs) == 0 {
+		columns := make([]models.BoardColumn, 0, len(w.States))
+		for _, s := range w.States {
+			columns = append(columns, models.BoardColumn{ID: uuid.New(), StateID: s.ID, Name: s.Name, WIPMode: models.WIPWarn})
+		}
+		return columns, true
+	}
+	columns := make([]models.BoardColumn, 0, len(reqs))
+	seen := map[uuid.UUID]bool{}
+	for _, req := range reqs {
+		state := w.State(req.StateID)
+		if state == nil {
+			dto.BadRequest(dto.CodeInvalidBoard, "unknown workflow state", map[string]any{"state_id": req.StateID}).Send(c)
+			return nil, false
+		}
+		if seen[state.ID] {
+			dto.BadRequest(dto.CodeInvalidBoard, "a workflow state can only have one column", map[string]any{"state_id": state.ID}).Send(c)
+			return nil, false
+		}
+		seen[state.ID] = true
+		mode := req.WIPMode
+		if mode == "" {
+			mode = models.WIPWarn
+		}
+		if !mode.IsValid() {
+			dto.BadRequest(dto.CodeInvalidBoard, "wip_mode must be warn or enforce", nil).Send(c)
+			return nil, false
+		}
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			name = state.Name
+		}
+		columns = append(columns, models.BoardColumn{ID: uuid.New(), StateID: state.ID, Name: name, WIPLimit: req.WIPLimit, WIPMode: mode})
+	}
+	return columns, true
+}
+
+// placeCard ranks the task on board between afterID and beforeID among the tasks in
+// stateID, or at the bottom without either, and returns its rank. Cards that were never
+// moved on the board are ranked first, in the order they are shown. It sends the error
+// response and returns ok=false on failure.
+func placeCard(c *gin.Context, boards repositories.BoardRepository, board *models.Board, stateID uuid.UUID, taskID uuid.UUID, afterID *uuid.UUID, beforeID *uuid.UUID) (string, bool) {
+	ctx := c.Request.Context()
+	all, err := boards.ListColumnCards(ctx, board, stateID)
+	if err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return "", false
+	}
+	cards := make([]models.BoardCard, 0, len(all))
+	prev := ""
+	for _, card := range all {
+		if card.TaskID == taskID {
+			continue
+		}
+		if card.Rank == "" {
+			if card.Rank, err = rank.Between(prev, ""); err != nil {
+				dto.Internal(dto.CodeInternalError, "invalid card order", err.Error(), nil).Send(c)
+				return "", false
+			}
+			if err := boards.SetCardRank(ctx, board.ID, card.TaskID, card.Rank); err != nil {
+				dto.RepoError(err, "board").Send(c)
+				return "", false
+			}
+		}
+		prev = card.Rank
+		cards = append(cards, card)
+	}
+
+	index := func(id uuid.UUID) int {
+		for i, card := range cards {
+			if card.TaskID == id {
+				return i
+			}
+		}
+		return -1
+	}
+	// The card goes in front of cards[pos].
+	pos := len(cards)
+	if afterID != nil {
+		i := index(*afterID)
+		if i < 0 {
+			dto.BadRequest(dto.CodeInvalidBoard, "after_id is not a card of the column", nil).Send(c)
+			return "", false
+		}
+		pos = i + 1
+	}
+	if beforeID != nil {
+		i := index(*beforeID)
+		if i < 0 {
+			dto.BadRequest(dto.CodeInvalidBoard, "before_id is not a card of the column", nil).Send(c)
+			return "", false
+		}
+		if afterID != nil && i != pos {
+			dto.Conflict(dto.CodeConflict, "after_id and before_id are no longer adjacent", nil).Send(c)
+			return "", false
+		}
+		pos = i
+	}
+	lo, hi := "", ""
+	if pos > 0 {
+		lo = cards[pos-1].Rank
+	}
+	if pos < len(cards) {
+		hi = cards[pos].Rank
+	}
+	key, err := rank.Between(lo, hi)
+	if err != nil {
+		dto.Internal(dto.CodeInternalError, "invalid card order", err.Error(), nil).Send(c)
+		return "", false
+	}
+	if err := boards.SetCardRank(ctx, board.ID, taskID, key); err != nil {
+		dto.RepoError(err, "board").Send(c)
+		return "", false
+	}
+	return key, true
+}
+
+// swimlaneSplitter sorts the cards of a board into its swimlanes.
+type swimlaneSplitter struct {
+	// laneKeys returns the swimlanes of a task; nil puts it into the empty lane.
+	laneKeys func(t *models.Task) []string
+	// order of the known keys; keys found on cards are appended.
+	order []string
+	seen  map[string]bool
+}
+
+// swimlanes prepares the swimlanes of board. Labels show in label order and field
+// options in option order; assignees in the order they first appear on the board.
+func (r *TeamsHandler) swimlanes(c *gin.Context, board *models.Board) (*swimlaneSplitter, bool) {
+	s := &swimlaneSplitter{laneKeys: func(*models.Task) []string { return nil }, seen: map[string]bool{}}
+	switch board.SwimlaneBy {
+	case models.SwimlaneAssignee:
+		s.laneKeys = func(t *models.Task) []string { return uuidKeys(t.AssigneeIDs) }
+	case models.SwimlaneLabel:
+		labels, err := r.uow.Labels().ListLabels(c.Request.Context(), board.TeamID)
+		if err != nil {
+			dto.RepoError(err, "label").Send(c)
+			return nil, false
+		}
+		for _, l := range labels {
+			s.order = append(s.order, l.ID.String())
+		}
+		s.laneKeys = func(t *models.Task) []string { return uuidKeys(t.LabelIDs) }
+	case models.SwimlaneCustomField:
+		// The field was deleted; the board shows a single lane.
+		if board.SwimlaneFieldID == nil {
+			break
+		}
+		field, err := r.uow.CustomFields().GetCustomField(c.Request.Context(), board.TeamID, *board.SwimlaneFieldID)
+		if err != nil {
+			dto.RepoError(err, "custom field").Send(c)
+			return nil, false
+		}
+		s.order = append(s.order, field.Options...)
+		s.laneKeys = func(t *models.Task) []string {
+			if v, ok := t.CustomFields[field.Key].(string); ok {
+				return []string{v}
+			}
+			return nil
+		}
+	}
+	return s, true
+}
+
+func uuidKeys(ids []uuid.UUID) []string {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, id.String())
+	}
+	return keys
+}
+
+func (s *swimlaneSplitter) lanesOf(t *models.Task) []string {
+	if keys := s.laneKeys(t); len(keys) > 0 {
+		return keys
+	}
+	return []string{""}
+}
+
+// add records the swimlanes of a card.
+func (s *swimlaneSplitter) add(t *models.Task) {
+	for _, k := range s.lanesOf(t) {
+		if !s.seen[k] && k != "" && !slices.Contains(s.order, k) {
+			s.order = append(s.order, k)
+		}
+		s.seen[k] = true
+	}
+}
+
+// keys returns the swimlanes holding at least one card, the empty one last.
+func (s *swimlaneSplitter) keys() []string {
+	keys := []string{}
+	for _, k := range s.order {
+		if s.seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	if s.seen[""] || len(keys) == 0 {
+		keys = append(keys, "")
+	}
+	return keys
+}
+
+// split sorts the cards of a column into the swimlanes keys, keeping their order.
+func (s *swimlaneSplitter) split(keys []string, tasks []*models.Task) []models.BoardLane {
+	lanes := make([]models.BoardLane, len(keys))
+	index := make(map[string]int, len(keys))
+	for i, k := range keys {
+		lanes[i] = models.BoardLane{Key: k, Tasks: []*models.Task{}}
+		index[k] = i
+	}
+	for _, t := range tasks {
+		for _, k := range s.lanesOf(t) {
+			lanes[index[k]].Tasks = append(lanes[index[k]].Tasks, t)
+		}
+	}
+	return lanes
+}
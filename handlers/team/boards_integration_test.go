@@ -0,0 +1,160 @@
+package team_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoards_SQLite(t *testing.T) {
+	f := newFixture(t)
+	boardsPath := "/api/v1/team/" + f.teamID.String() + "/boards"
+	fieldsPath := "/api/v1/team/" + f.teamID.String() + "/fields"
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, fieldsPath, dto.CustomFieldRequest{Key: "priority", Name: "Priority", Type: models.FieldSingleSelect, Options: []string{"high", "low"}}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	priority := testutil.DecodeJSON[dto.CustomFieldEnvelope](t, rr).Data.ID
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, fieldsPath, dto.CustomFieldRequest{Key: "notes", Name: "Notes", Type: models.FieldText}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	notes := testutil.DecodeJSON[dto.CustomFieldEnvelope](t, rr).Data.ID
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, boardsPath, dto.BoardRequest{Name: "Delivery"}, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, boardsPath, dto.BoardRequest{Name: "Delivery", SwimlaneBy: models.SwimlaneCustomField, SwimlaneFieldID: &notes}, f.founder)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, boardsPath, dto.BoardRequest{Name: "Delivery", SwimlaneBy: models.SwimlaneCustomField, SwimlaneFieldID: &priority}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	board := testutil.DecodeJSON[dto.BoardEnvelope](t, rr).Data
+	require.Len(t, board.Columns, 3)
+	todo, doing, done := board.Columns[0], board.Columns[1], board.Columns[2]
+	require.Equal(t, "To Do", todo.Name)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, boardsPath, dto.BoardRequest{Name: "Delivery"}, f.founder)
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	one := 1
+	boardPath := boardsPath + "/" + board.ID.String()
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, boardPath+"/columns", dto.BoardColumnsRequest{Columns: []dto.BoardColumnRequest{
+		{StateID: todo.StateID}, {StateID: todo.StateID},
+	}}, f.founder)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, boardPath+"/columns", dto.BoardColumnsRequest{Columns: []dto.BoardColumnRequest{
+		{StateID: todo.StateID, Name: "Backlog"},
+		{StateID: doing.StateID, WIPLimit: &one, WIPMode: models.WIPEnforce},
+		{StateID: done.StateID, WIPLimit: &one},
+	}}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	board = testutil.DecodeJSON[dto.BoardEnvelope](t, rr).Data
+	todo, doing, done = board.Columns[0], board.Columns[1], board.Columns[2]
+	require.Equal(t, "Backlog", todo.Name)
+	require.Equal(t, models.WIPWarn, done.WIPMode)
+
+	create := func(title string, fields map[string]any) uuid.UUID {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: title, CustomFields: fields}, f.member)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.ID
+	}
+	a := create("Draft spec", nil)
+	b := create("Review spec", map[string]any{"priority": "low"})
+	c := create("Publish spec", map[string]any{"priority": "high"})
+
+	view := func() models.BoardView {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodGet, boardPath, nil, f.member)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.BoardViewEnvelope](t, rr).Data
+	}
+	cards := func(col models.BoardColumnView) []uuid.UUID {
+		ids := []uuid.UUID{}
+		for _, lane := range col.Lanes {
+			for _, task := range lane.Tasks {
+				ids = append(ids, task.ID)
+			}
+		}
+		return ids
+	}
+	// Lanes follow the option order; the cards without a priority come last.
+	v := view()
+	require.Equal(t, []string{"high", "low", ""}, v.Swimlanes)
+	require.Equal(t, 3, v.Columns[0].TaskCount)
+	require.Equal(t, []uuid.UUID{c, b, a}, cards(v.Columns[0]))
+
+	move := func(req dto.BoardMoveRequest) *httptest.ResponseRecorder {
+		t.Helper()
+		return testutil.DoJSON(t, f.r, http.MethodPost, boardPath+"/move", req, f.member)
+	}
+	// Dropping swimlanes makes the column order easy to read.
+	none := models.SwimlaneNone
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, boardPath, dto.BoardUpdateRequest{SwimlaneBy: &none}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Nil(t, testutil.DecodeJSON[dto.BoardEnvelope](t, rr).Data.SwimlaneFieldID)
+	require.Equal(t, []uuid.UUID{a, b, c}, cards(view().Columns[0]))
+
+	rr = move(dto.BoardMoveRequest{TaskID: c, ColumnID: todo.ID, BeforeID: &a})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, []uuid.UUID{c, a, b}, cards(view().Columns[0]))
+	rr = move(dto.BoardMoveRequest{TaskID: b, ColumnID: todo.ID, AfterID: &c, BeforeID: &a})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, []uuid.UUID{c, b, a}, cards(view().Columns[0]))
+
+	tests := []struct {
+		name       string
+		req        dto.BoardMoveRequest
+		wantStatus int
+	}{
+		{"unknown column", dto.BoardMoveRequest{TaskID: a, ColumnID: uuid.New()}, http.StatusBadRequest},
+		{"neighbour in another column", dto.BoardMoveRequest{TaskID: a, ColumnID: doing.ID, AfterID: &c}, http.StatusBadRequest},
+		{"next to itself", dto.BoardMoveRequest{TaskID: a, ColumnID: todo.ID, AfterID: &a}, http.StatusBadRequest},
+		{"neighbours no longer adjacent", dto.BoardMoveRequest{TaskID: a, ColumnID: todo.ID, AfterID: &b, BeforeID: &c}, http.StatusConflict},
+		{"unknown task", dto.BoardMoveRequest{TaskID: uuid.New(), ColumnID: todo.ID}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := move(tt.req)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+		})
+	}
+	require.Equal(t, []uuid.UUID{c, b, a}, cards(view().Columns[0]))
+
+	// Moving across columns changes the state in the same transaction.
+	rr = move(dto.BoardMoveRequest{TaskID: a, ColumnID: doing.ID})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	moved := testutil.DecodeJSON[dto.BoardMoveEnvelope](t, rr).Data
+	require.Equal(t, doing.StateID, *moved.Task.StateID)
+	require.Empty(t, moved.Warnings)
+
+	// The enforced limit refuses the move and leaves the task where it was.
+	rr = move(dto.BoardMoveRequest{TaskID: b, ColumnID: doing.ID})
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.Equal(t, dto.CodeWIPLimitExceeded, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+	v = view()
+	require.Equal(t, []uuid.UUID{c, b}, cards(v.Columns[0]))
+	require.Equal(t, []uuid.UUID{a}, cards(v.Columns[1]))
+
+	rr = move(dto.BoardMoveRequest{TaskID: a, ColumnID: done.ID})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = move(dto.BoardMoveRequest{TaskID: c, ColumnID: done.ID, BeforeID: &a})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Len(t, testutil.DecodeJSON[dto.BoardMoveEnvelope](t, rr).Data.Warnings, 1)
+	v = view()
+	require.Equal(t, []uuid.UUID{c, a}, cards(v.Columns[2]))
+	require.True(t, v.Columns[2].OverLimit)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, boardPath+"?limit=1", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	v = testutil.DecodeJSON[dto.BoardViewEnvelope](t, rr).Data
+	require.Equal(t, []uuid.UUID{c}, cards(v.Columns[2]))
+	require.Equal(t, 2, v.Columns[2].TaskCount)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, boardPath, nil, f.founder)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, boardsPath, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Empty(t, testutil.DecodeJSON[dto.BoardsEnvelope](t, rr).Data)
+}
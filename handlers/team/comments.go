@@ -0,0 +1,431 @@
+package team
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/listquery"
+	"task_manager/public/markdown"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/listspec"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TeamGetComments godoc
+// @Summary List the comments of a task
+// @Description Top-level comments and replies in creation order. Deleted comments are listed without their body.
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from meta.next_cursor of the previous page"
+// @Param sort query string false "created_at or -created_at"
+// @Param include_total query bool false "Include meta.total"
+// @Param parent_id query string false "Replies of a comment; parent_id[isnull]=true for top-level comments"
+// @Success 200 {object} dto.CommentsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/comments [get]
+func (r *TeamsHandler) TeamGetComments(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	q, err := listquery.Parse(c.Request.URL.Query(), listspec.Comments)
+	if err != nil {
+		dto.ListQueryError(err).Send(c)
+		return
+	}
+	comments, err := r.uow.Comments().ListTaskComments(c.Request.Context(), task.ID, q)
+	if err != nil {
+		dto.RepoError(err, "comment").Send(c)
+		return
+	}
+	for _, cm := range comments.Items {
+		cm.Redact()
+	}
+	dto.OKPage(c, comments)
+}
+
+// TeamPostComment godoc
+// @Summary Comment on a task
+// @Description The body is Markdown; the server stores a sanitized HTML rendering next to it. Mentioned
+// @Description team members ("@firstname.lastname" or "@<user id>") get a notification. With parent_id the
+// @Description comment is a reply to a top-level comment.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.CommentRequest true "Comment"
+// @Success 201 {object} dto.CommentEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/comments [post]
+func (r *TeamsHandler) TeamPostComment(c *gin.Context) {
+	teamID, userID, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.CommentRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	task, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	if req.ParentID != nil {
+		parent, err := tx.Comments().GetComment(c.Request.Context(), task.ID, *req.ParentID)
+		if err != nil {
+			dto.RepoError(err, "parent comment").Send(c)
+			return
+		}
+		if parent.ParentID != nil {
+			dto.BadRequest(dto.CodeInvalidRequest, "replies cannot be replied to", nil).Send(c)
+			return
+		}
+		if parent.IsDeleted() {
+			dto.BadRequest(dto.CodeInvalidRequest, "deleted comments cannot be replied to", nil).Send(c)
+			return
+		}
+	}
+	mentions, err := resolveMentions(c.Request.Context(), tx.Teams(), tx.Users(), teamID, req.Body)
+	if err != nil {
+		dto.RepoError(err, "team member").Send(c)
+		return
+	}
+
+	comment := &models.Comment{
+		ID:         uuid.New(),
+		TeamID:     teamID,
+		TaskID:     task.ID,
+		ParentID:   req.ParentID,
+		AuthorID:   &userID,
+		Body:       req.Body,
+		BodyHTML:   markdown.Render(req.Body),
+		MentionIDs: mentions,
+	}
+	if err := tx.Comments().CreateComment(c.Request.Context(), comment); err != nil {
+		dto.RepoError(err, "comment").Send(c)
+		return
+	}
+	if err := notifyMentions(c.Request.Context(), tx.Notifications(), comment, mentions, userID); err != nil {
+		dto.RepoError(err, "notification").Send(c)
+		return
+	}
+	if !recordActivity(c, tx, task, models.ActivityCommentAdded, nil, map[string]any{"comment_id": comment.ID}) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "comment").Send(c)
+		return
+	}
+	trace.Log(c, "comment_created", "task_id="+task.ID.String()+" comment_id="+comment.ID.String()+" mentions="+strconv.Itoa(len(mentions)))
+
+	dto.OK(c, http.StatusCreated, comment)
+}
+
+// TeamPatchComment godoc
+// @Summary Edit a comment
+// @Description Only the author or a team admin/founder can edit a comment. The previous body is kept in the
+// @Description comment history, and users that are newly mentioned get a notification.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param comment_id path string true "Comment ID"
+// @Param request body dto.CommentUpdateRequest true "New body"
+// @Success 200 {object} dto.CommentEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/comments/{comment_id} [patch]
+func (r *TeamsHandler) TeamPatchComment(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.CommentUpdateRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	task, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	comment, ok := r.loadComment(c, tx.Comments(), task.ID, userID, role)
+	if !ok {
+		return
+	}
+	mentions, err := resolveMentions(c.Request.Context(), tx.Teams(), tx.Users(), teamID, req.Body)
+	if err != nil {
+		dto.RepoError(err, "team member").Send(c)
+		return
+	}
+	known := make(map[uuid.UUID]bool, len(comment.MentionIDs))
+	for _, id := range comment.MentionIDs {
+		known[id] = true
+	}
+	var added []uuid.UUID
+	for _, id := range mentions {
+		if !known[id] {
+			added = append(added, id)
+		}
+	}
+
+	comment.Body = req.Body
+	comment.BodyHTML = markdown.Render(req.Body)
+	comment.MentionIDs = mentions
+	if err := tx.Comments().UpdateComment(c.Request.Context(), comment, userID); err != nil {
+		dto.RepoError(err, "comment").Send(c)
+		return
+	}
+	if err := notifyMentions(c.Request.Context(), tx.Notifications(), comment, added, userID); err != nil {
+		dto.RepoError(err, "notification").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "comment").Send(c)
+		return
+	}
+	trace.Log(c, "comment_edited", "task_id="+task.ID.String()+" comment_id="+comment.ID.String())
+
+	dto.OK(c, http.StatusOK, comment)
+}
+
+// TeamDeleteComment godoc
+// @Summary Delete a comment
+// @Description Only the author or a team admin/founder can delete a comment. The comment stays in the thread without its body
+// @Description and can be restored from the trash until it is purged.
+// @Tags comments
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param comment_id path string true "Comment ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/comments/{comment_id} [delete]
+func (r *TeamsHandler) TeamDeleteComment(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	comment, ok := r.loadComment(c, r.uow.Comments(), task.ID, userID, role)
+	if !ok {
+		return
+	}
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+	if err := tx.Comments().SoftDeleteComment(c.Request.Context(), task.ID, comment.ID); err != nil {
+		dto.RepoError(err, "comment").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "comment").Send(c)
+		return
+	}
+	trace.Log(c, "comment_deleted", "task_id="+task.ID.String()+" comment_id="+comment.ID.String())
+	c.Status(http.StatusNoContent)
+}
+
+// TeamGetCommentHistory godoc
+// @Summary Get the edit history of a comment
+// @Description Previous bodies of the comment, oldest first.
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param comment_id path string true "Comment ID"
+// @Success 200 {object} dto.CommentRevisionsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/comments/{comment_id}/history [get]
+func (r *TeamsHandler) TeamGetCommentHistory(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	commentID, err := uuid.Parse(c.Param("comment_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid comment id", nil).Send(c)
+		return
+	}
+	comment, err := r.uow.Comments().GetComment(c.Request.Context(), task.ID, commentID)
+	if err == nil && comment.IsDeleted() {
+		err = repositories.ErrNotFound
+	}
+	if err != nil {
+		dto.RepoError(err, "comment").Send(c)
+		return
+	}
+	revisions, err := r.uow.Comments().ListCommentRevisions(c.Request.Context(), comment.ID)
+	if err != nil {
+		dto.RepoError(err, "comment").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, revisions)
+}
+
+// loadComment resolves the :comment_id parameter within the task and checks that the
+// caller may change it: the author or a team admin/founder. Deleted comments are not found.
+// It sends the error response and returns ok=false on failure.
+func (r *TeamsHandler) loadComment(c *gin.Context, comments repositories.CommentRepository, taskID uuid.UUID, userID uuid.UUID, role models.TeamUserRole) (*models.Comment, bool) {
+	commentID, err := uuid.Parse(c.Param("comment_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid comment id", nil).Send(c)
+		return nil, false
+	}
+	comment, err := comments.GetComment(c.Request.Context(), taskID, commentID)
+	if err == nil && comment.IsDeleted() {
+		err = repositories.ErrNotFound
+	}
+	if err != nil {
+		dto.RepoError(err, "comment").Send(c)
+		return nil, false
+	}
+	isAuthor := comment.AuthorID != nil && *comment.AuthorID == userID
+	if !isAuthor && role != models.AdminUserRole && role != models.FounderUserRole {
+		dto.Forbidden(dto.CodeForbidden, "only the author or a team admin can change the comment", nil).Send(c)
+		return nil, false
+	}
+	return comment, true
+}
+
+// mentionRe matches "@token" when the @ does not follow a word character, so email
+// addresses are not mistaken for mentions.
+var mentionRe = regexp.MustCompile(`(?:^|[^\w.@])@(\w(?:[\w.-]*\w)?)`)
+
+// resolveMentions returns the members of the team mentioned in body, either as
+// "@firstname.lastname" (case-insensitive, spaces removed) or "@<user id>".
+// Tokens that match no member are ignored.
+func resolveMentions(ctx context.Context, teams repositories.TeamRepository, users repositories.UserRepository, teamID uuid.UUID, body string) ([]uuid.UUID, error) {
+	tokens := map[string]bool{}
+	for _, m := range mentionRe.FindAllStringSubmatch(body, -1) {
+		tokens[strings.ToLower(m[1])] = true
+	}
+	mentions := []uuid.UUID{}
+	if len(tokens) == 0 {
+		return mentions, nil
+	}
+
+	values := url.Values{listquery.ParamLimit: {"200"}}
+	for {
+		q, err := listquery.Parse(values, listspec.TeamMembers)
+		if err != nil {
+			return nil, err
+		}
+		page, err := teams.GetTeamsMembers(ctx, teamID, q)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range page.Items {
+			if tokens[m.UserID.String()] {
+				mentions = append(mentions, m.UserID)
+				continue
+			}
+			u, err := users.GetUserByID(ctx, m.UserID)
+			if err != nil {
+				return nil, err
+			}
+			name := strings.ToLower(strings.ReplaceAll(u.FirstName, " ", "") + "." + strings.ReplaceAll(u.LastName, " ", ""))
+			if tokens[name] {
+				mentions = append(mentions, m.UserID)
+			}
+		}
+		if !page.HasMore {
+			return mentions, nil
+		}
+		values.Set(listquery.ParamCursor, page.NextCursor)
+	}
+}
+
+// notifyMentions notifies the mentioned users of comment, except its author.
+func notifyMentions(ctx context.Context, notifications repositories.NotificationRepository, comment *models.Comment, userIDs []uuid.UUID, actorID uuid.UUID) error {
+	var batch []*models.Notification
+	for _, id := range userIDs {
+		if id == actorID {
+			continue
+		}
+		batch = append(batch, &models.Notification{
+			ID:        uuid.New(),
+			UserID:    id,
+			TeamID:    comment.TeamID,
+			Kind:      models.NotificationMention,
+			TaskID:    &comment.TaskID,
+			CommentID: &comment.ID,
+			ActorID:   &actorID,
+		})
+	}
+	return notifications.CreateNotifications(ctx, batch)
+}
@@ -0,0 +1,84 @@
+package team_test
+
+import (
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/testutil"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComments_SQLite(t *testing.T) {
+	f := newFixture(t)
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Launch"}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	task := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data
+	comments := f.tasksPath() + "/" + task.ID.String() + "/comments"
+
+	// The outsider is not a member, so only the member is mentioned.
+	body := "Ping @" + f.memberID.String() + " and @" + f.outsiderID.String() + " <script>x</script>"
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, comments, dto.CommentRequest{Body: body}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	top := testutil.DecodeJSON[dto.CommentEnvelope](t, rr).Data
+	require.Equal(t, []uuid.UUID{f.memberID}, top.MentionIDs)
+	require.NotContains(t, top.BodyHTML, "<script>")
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/user/me/notifications?read_at[isnull]=true", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	notifications := testutil.DecodeJSON[dto.NotificationsEnvelope](t, rr).Data
+	require.Len(t, notifications, 1)
+	require.Equal(t, top.ID, *notifications[0].CommentID)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, "/api/v1/user/me/notifications/"+notifications[0].ID.String()+"/read", nil, f.member)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, "/api/v1/user/me/notifications/"+notifications[0].ID.String()+"/read", nil, f.founder)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, comments, dto.CommentRequest{Body: "**agreed**", ParentID: &top.ID}, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	reply := testutil.DecodeJSON[dto.CommentEnvelope](t, rr).Data
+	require.Equal(t, "<p><strong>agreed</strong></p>\n", reply.BodyHTML)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, comments, dto.CommentRequest{Body: "nested", ParentID: &reply.ID}, f.founder)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	tests := []struct {
+		name       string
+		method     string
+		comment    uuid.UUID
+		auth       map[string]string
+		wantStatus int
+	}{
+		{"member cannot edit the founder's comment", http.MethodPatch, top.ID, f.member, http.StatusForbidden},
+		{"author edits", http.MethodPatch, reply.ID, f.member, http.StatusOK},
+		{"founder edits any comment", http.MethodPatch, reply.ID, f.founder, http.StatusOK},
+		{"member cannot delete the founder's comment", http.MethodDelete, top.ID, f.member, http.StatusForbidden},
+		{"author deletes", http.MethodDelete, top.ID, f.founder, http.StatusNoContent},
+		{"deleted comments cannot be edited", http.MethodPatch, top.ID, f.founder, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := testutil.DoJSON(t, f.r, tt.method, comments+"/"+tt.comment.String(), dto.CommentUpdateRequest{Body: tt.name}, tt.auth)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+		})
+	}
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, comments+"/"+reply.ID.String()+"/history", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	history := testutil.DecodeJSON[dto.CommentRevisionsEnvelope](t, rr).Data
+	require.Len(t, history, 2)
+	require.Equal(t, "**agreed**", history[0].Body)
+
+	// The deleted comment keeps its place in the thread without its body.
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, comments, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	list := testutil.DecodeJSON[dto.CommentsEnvelope](t, rr).Data
+	require.Len(t, list, 2)
+	require.Equal(t, top.ID, list[0].ID)
+	require.NotNil(t, list[0].DeletedAt)
+	require.Empty(t, list[0].Body)
+	require.Equal(t, "founder edits any comment", list[1].Body)
+}
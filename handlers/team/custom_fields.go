@@ -0,0 +1,330 @@
+package team
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxCustomFields bounds the fields of a team; each one adds a subquery per filter or
+// sort key to the task list.
+const maxCustomFields = 50
+
+// TeamGetCustomFields godoc
+// @Summary List the custom fields of a team
+// @Tags custom fields
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} dto.CustomFieldsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/fields [get]
+func (r *TeamsHandler) TeamGetCustomFields(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	fields, err := r.uow.CustomFields().ListCustomFields(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, fields)
+}
+
+// TeamPostCustomField godoc
+// @Summary Create a custom field
+// @Description Only team admins and founders can manage custom fields. Keys are unique within the team.
+// @Description Tasks carry the value under custom_fields.<key> and can be filtered and sorted by cf.<key>.
+// @Tags custom fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body dto.CustomFieldRequest true "Custom field"
+// @Success 201 {object} dto.CustomFieldEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/fields [post]
+func (r *TeamsHandler) TeamPostCustomField(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage custom fields")
+	if !ok {
+		return
+	}
+
+	req := dto.CustomFieldRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	req.Key = strings.TrimSpace(req.Key)
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	field := &models.CustomField{
+		ID:       uuid.New(),
+		TeamID:   teamID,
+		Key:      req.Key,
+		Name:     req.Name,
+		Type:     req.Type,
+		Required: req.Required,
+		Min:      req.Min,
+		Max:      req.Max,
+		Pattern:  req.Pattern,
+		Options:  trimOptions(req.Options),
+	}
+	if err := field.Check(); err != nil {
+		dto.BadRequest(dto.CodeInvalidCustomField, err.Error(), nil).Send(c)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	existing, err := tx.CustomFields().ListCustomFields(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return
+	}
+	if len(existing) >= maxCustomFields {
+		dto.Conflict(dto.CodeConflict, "the team already has the maximum number of custom fields", map[string]any{"max": maxCustomFields}).Send(c)
+		return
+	}
+	field.Position = len(existing)
+	if req.Position != nil {
+		field.Position = *req.Position
+	}
+	if err := tx.CustomFields().CreateCustomField(c.Request.Context(), field); err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return
+	}
+	trace.Log(c, "custom_field_created", "team_id="+teamID.String()+" field_id="+field.ID.String())
+	dto.OK(c, http.StatusCreated, field)
+}
+
+// TeamPatchCustomField godoc
+// @Summary Edit a custom field
+// @Description Only team admins and founders can manage custom fields. The key and the type cannot be changed.
+// @Description Removing an option clears it from every task; making a field required only applies to later writes.
+// @Tags custom fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param field_id path string true "Custom field ID"
+// @Param request body dto.CustomFieldUpdateRequest true "Fields to change"
+// @Success 200 {object} dto.CustomFieldEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/fields/{field_id} [patch]
+func (r *TeamsHandler) TeamPatchCustomField(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage custom fields")
+	if !ok {
+		return
+	}
+
+	req := dto.CustomFieldUpdateRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if req.Name != nil {
+		*req.Name = strings.TrimSpace(*req.Name)
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	field, ok := r.loadCustomField(c, tx.CustomFields(), teamID)
+	if !ok {
+		return
+	}
+	if req.Name != nil {
+		field.Name = *req.Name
+	}
+	if req.Required != nil {
+		field.Required = *req.Required
+	}
+	if req.ClearBounds {
+		field.Min, field.Max = nil, nil
+	} else {
+		if req.Min != nil {
+			field.Min = req.Min
+		}
+		if req.Max != nil {
+			field.Max = req.Max
+		}
+	}
+	if req.Pattern != nil {
+		field.Pattern = *req.Pattern
+	}
+	if req.Options != nil {
+		field.Options = trimOptions(req.Options)
+	}
+	if req.Position != nil {
+		field.Position = *req.Position
+	}
+	if err := field.Check(); err != nil {
+		dto.BadRequest(dto.CodeInvalidCustomField, err.Error(), nil).Send(c)
+		return
+	}
+	if err := tx.CustomFields().UpdateCustomField(c.Request.Context(), field); err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return
+	}
+	trace.Log(c, "custom_field_updated", "team_id="+teamID.String()+" field_id="+field.ID.String())
+	dto.OK(c, http.StatusOK, field)
+}
+
+// TeamDeleteCustomField godoc
+// @Summary Delete a custom field
+// @Description Only team admins and founders can manage custom fields. The values of the field are removed from every task.
+// @Tags custom fields
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param field_id path string true "Custom field ID"
+// @Success 204
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/fields/{field_id} [delete]
+func (r *TeamsHandler) TeamDeleteCustomField(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage custom fields")
+	if !ok {
+		return
+	}
+	fieldID, err := uuid.Parse(c.Param("field_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid custom field id", nil).Send(c)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	if err := tx.CustomFields().DeleteCustomField(c.Request.Context(), teamID, fieldID); err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return
+	}
+	trace.Log(c, "custom_field_deleted", "team_id="+teamID.String()+" field_id="+fieldID.String())
+	c.Status(http.StatusNoContent)
+}
+
+func (r *TeamsHandler) loadCustomField(c *gin.Context, fields repositories.CustomFieldRepository, teamID uuid.UUID) (*models.CustomField, bool) {
+	fieldID, err := uuid.Parse(c.Param("field_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid custom field id", nil).Send(c)
+		return nil, false
+	}
+	field, err := fields.GetCustomField(c.Request.Context(), teamID, fieldID)
+	if err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return nil, false
+	}
+	return field, true
+}
+
+func trimOptions(options []string) []string {
+	out := make([]string, len(options))
+	for i, o := range options {
+		out[i] = strings.TrimSpace(o)
+	}
+	return out
+}
+
+// checkCustomFields validates the custom field values of a task write against the team's
+// fields and normalizes them. With creating set, every required field must have a value.
+// User fields only accept members of the team. It sends the error response and returns
+// ok=false on failure.
+func (r *TeamsHandler) checkCustomFields(c *gin.Context, tx repositories.Transaction, teamID uuid.UUID, values map[string]any, creating bool) ([]models.CustomFieldValue, bool) {
+	out := []models.CustomFieldValue{}
+	if len(values) == 0 && !creating {
+		return out, true
+	}
+	fields, err := tx.CustomFields().ListCustomFields(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return nil, false
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !slices.ContainsFunc(fields, func(f *models.CustomField) bool { return f.Key == key }) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) > 0 {
+		slices.Sort(keys)
+		dto.BadRequest(dto.CodeInvalidCustomField, "unknown custom field", map[string]any{"fields": keys}).Send(c)
+		return nil, false
+	}
+
+	for _, f := range fields {
+		raw, present := values[f.Key]
+		if !present && !(creating && f.Required) {
+			continue
+		}
+		v, err := f.Normalize(raw)
+		if err != nil {
+			dto.BadRequest(dto.CodeInvalidCustomField, f.Key+" "+err.Error(), map[string]any{"field": f.Key}).Send(c)
+			return nil, false
+		}
+		if userID, ok := v.(uuid.UUID); ok {
+			_, notMembers, err := teamMembersOnly(c.Request.Context(), tx.Teams(), teamID, []uuid.UUID{userID})
+			if err != nil {
+				dto.RepoError(err, "team member").Send(c)
+				return nil, false
+			}
+			if len(notMembers) > 0 {
+				dto.BadRequest(dto.CodeNotTeamMember, f.Key+" must be a member of the team", map[string]any{"field": f.Key, "user_ids": notMembers}).Send(c)
+				return nil, false
+			}
+		}
+		out = append(out, models.CustomFieldValue{Field: f, Value: v})
+	}
+	return out, true
+}
@@ -0,0 +1,166 @@
+package team_test
+
+import (
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomFields_SQLite(t *testing.T) {
+	f := newFixture(t)
+	fieldsPath := "/api/v1/team/" + f.teamID.String() + "/fields"
+	float := func(v float64) *float64 { return &v }
+
+	newField := func(req dto.CustomFieldRequest) uuid.UUID {
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, fieldsPath, req, f.founder)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.CustomFieldEnvelope](t, rr).Data.ID
+	}
+	points := newField(dto.CustomFieldRequest{Key: "points", Name: "Points", Type: models.FieldNumber, Min: float(0), Max: float(100)})
+	newField(dto.CustomFieldRequest{Key: "severity", Name: "Severity", Type: models.FieldSingleSelect, Required: true, Options: []string{"low", "high"}})
+	platform := newField(dto.CustomFieldRequest{Key: "platform", Name: "Platform", Type: models.FieldMultiSelect, Options: []string{"ios", "android", "web"}})
+	newField(dto.CustomFieldRequest{Key: "due", Name: "Due", Type: models.FieldDate})
+	newField(dto.CustomFieldRequest{Key: "owner", Name: "Owner", Type: models.FieldUser})
+	newField(dto.CustomFieldRequest{Key: "flagged", Name: "Flagged", Type: models.FieldCheckbox})
+	newField(dto.CustomFieldRequest{Key: "ref", Name: "Ticket", Type: models.FieldText, Pattern: `^[A-Z]+-[0-9]+$`})
+
+	definitions := []struct {
+		name string
+		req  dto.CustomFieldRequest
+		user map[string]string
+		code int
+	}{
+		{"members cannot manage fields", dto.CustomFieldRequest{Key: "size", Name: "Size", Type: models.FieldText}, f.member, http.StatusForbidden},
+		{"duplicate key", dto.CustomFieldRequest{Key: "points", Name: "Points", Type: models.FieldNumber}, f.founder, http.StatusConflict},
+		{"invalid key", dto.CustomFieldRequest{Key: "Story Points", Name: "Points", Type: models.FieldNumber}, f.founder, http.StatusBadRequest},
+		{"select without options", dto.CustomFieldRequest{Key: "size", Name: "Size", Type: models.FieldSingleSelect}, f.founder, http.StatusBadRequest},
+		{"min above max", dto.CustomFieldRequest{Key: "size", Name: "Size", Type: models.FieldNumber, Min: float(5), Max: float(1)}, f.founder, http.StatusBadRequest},
+		{"invalid pattern", dto.CustomFieldRequest{Key: "size", Name: "Size", Type: models.FieldText, Pattern: "("}, f.founder, http.StatusBadRequest},
+	}
+	for _, tt := range definitions {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := testutil.DoJSON(t, f.r, http.MethodPost, fieldsPath, tt.req, tt.user)
+			require.Equal(t, tt.code, rr.Code, rr.Body.String())
+		})
+	}
+
+	newTask := func(title string, values map[string]any) models.Task {
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: title, CustomFields: values}, f.member)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data
+	}
+	t1 := newTask("one", map[string]any{"severity": "high", "points": 5, "platform": []string{"web", "ios", "web"}, "due": "2026-03-01", "ref": "APP-12"})
+	require.Equal(t, []any{"ios", "web"}, t1.CustomFields["platform"])
+	require.Equal(t, "2026-03-01", t1.CustomFields["due"])
+	t2 := newTask("two", map[string]any{"severity": "low", "points": 2, "flagged": true, "owner": f.memberID.String()})
+	t3 := newTask("three", map[string]any{"severity": "low"})
+
+	rr := testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+t1.ID.String(), nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, map[string]any{"severity": "high", "points": float64(5), "platform": []any{"ios", "web"}, "due": "2026-03-01", "ref": "APP-12"},
+		testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.CustomFields)
+
+	invalid := []struct {
+		name   string
+		values map[string]any
+		code   dto.ErrorCode
+	}{
+		{"required field missing", map[string]any{"points": 1}, dto.CodeInvalidCustomField},
+		{"unknown field", map[string]any{"severity": "low", "nope": 1}, dto.CodeInvalidCustomField},
+		{"number out of range", map[string]any{"severity": "low", "points": 500}, dto.CodeInvalidCustomField},
+		{"wrong type", map[string]any{"severity": "low", "points": "5"}, dto.CodeInvalidCustomField},
+		{"unknown option", map[string]any{"severity": "urgent"}, dto.CodeInvalidCustomField},
+		{"invalid date", map[string]any{"severity": "low", "due": "March 1st"}, dto.CodeInvalidCustomField},
+		{"pattern mismatch", map[string]any{"severity": "low", "ref": "app 12"}, dto.CodeInvalidCustomField},
+		{"user outside the team", map[string]any{"severity": "low", "owner": f.outsiderID.String()}, dto.CodeNotTeamMember},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "x", CustomFields: tt.values}, f.member)
+			require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+			require.Equal(t, tt.code, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+		})
+	}
+
+	listIDs := func(t *testing.T, query string) []uuid.UUID {
+		rr := testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?"+query, nil, f.member)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		got := []uuid.UUID{}
+		for _, task := range testutil.DecodeJSON[dto.TasksEnvelope](t, rr).Data {
+			got = append(got, task.ID)
+		}
+		return got
+	}
+	filters := []struct {
+		name  string
+		query string
+		want  []uuid.UUID
+	}{
+		{"number comparison", "cf.points[gte]=3", []uuid.UUID{t1.ID}},
+		{"number unset", "cf.points[isnull]=true", []uuid.UUID{t3.ID}},
+		{"single select", "cf.severity=low", []uuid.UUID{t2.ID, t3.ID}},
+		{"multi select any", "cf.platform[any]=web,android", []uuid.UUID{t1.ID}},
+		{"multi select all", "cf.platform[all]=web,android", []uuid.UUID{}},
+		{"date", "cf.due[lt]=2026-04-01", []uuid.UUID{t1.ID}},
+		{"user", "cf.owner=" + f.memberID.String(), []uuid.UUID{t2.ID}},
+		{"checkbox", "cf.flagged=true", []uuid.UUID{t2.ID}},
+		{"text contains", "cf.ref[contains]=app", []uuid.UUID{t1.ID}},
+	}
+	for _, tt := range filters {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ElementsMatch(t, tt.want, listIDs(t, tt.query))
+		})
+	}
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?cf.severity=urgent", nil, f.member)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?sort=cf.platform", nil, f.member)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// Sorting by a custom field pages through the tasks with unset values last.
+	var order []uuid.UUID
+	query := "sort=-cf.points&limit=1"
+	for {
+		rr := testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?"+query, nil, f.member)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		page := testutil.DecodeJSON[dto.TasksEnvelope](t, rr)
+		for _, task := range page.Data {
+			order = append(order, task.ID)
+		}
+		if !page.Meta.HasMore {
+			break
+		}
+		query = "sort=-cf.points&limit=1&cursor=" + page.Meta.NextCursor
+	}
+	require.Equal(t, []uuid.UUID{t1.ID, t2.ID, t3.ID}, order)
+
+	// Patching only touches the listed fields; null clears one unless it is required.
+	taskPath := f.tasksPath() + "/" + t1.ID.String()
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, taskPath, dto.TaskUpdateRequest{CustomFields: map[string]any{"points": nil, "flagged": false}}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, map[string]any{"severity": "high", "flagged": false, "platform": []any{"ios", "web"}, "due": "2026-03-01", "ref": "APP-12"},
+		testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.CustomFields)
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, taskPath, dto.TaskUpdateRequest{CustomFields: map[string]any{"severity": nil}}, f.member)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.ElementsMatch(t, []uuid.UUID{t1.ID, t3.ID}, listIDs(t, "cf.points[isnull]=true"))
+
+	// Removing an option clears it from the tasks.
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, fieldsPath+"/"+platform.String(), dto.CustomFieldUpdateRequest{Options: []string{"ios", "android"}}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, taskPath, nil, f.member)
+	require.Equal(t, []any{"ios"}, testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.CustomFields["platform"])
+
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, fieldsPath+"/"+points.String(), nil, f.founder)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+t2.ID.String(), nil, f.member)
+	require.NotContains(t, testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.CustomFields, "points")
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?cf.points[gte]=1", nil, f.member)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, fieldsPath, nil, f.member)
+	require.Len(t, testutil.DecodeJSON[dto.CustomFieldsEnvelope](t, rr).Data, 6)
+}
@@ -1 +1,45 @@
 package team
+
+import (
+	"task_manager/public/dto"
+	"task_manager/public/jwtauth"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories/listspec"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TeamGetInvitations godoc
+// @Summary List my invitations
+// @Description Invitations sent to the current user, newest first.
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from meta.next_cursor of the previous page"
+// @Param sort query string false "created_at or -created_at"
+// @Param include_total query bool false "Include meta.total"
+// @Param team_id query string false "Filter by team; also team_id[in]"
+// @Param accepted query bool false "Filter by answer; accepted[isnull]=true for pending invitations"
+// @Success 200 {object} dto.TeamsInvitationsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Router /team/invitations [get]
+func (r *TeamsHandler) TeamGetInvitations(c *gin.Context) {
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return
+	}
+	q, err := listquery.Parse(c.Request.URL.Query(), listspec.Invitations)
+	if err != nil {
+		dto.ListQueryError(err).Send(c)
+		return
+	}
+	invitations, err := r.uow.Teams().GetUserInvitations(c.Request.Context(), userID, q)
+	if err != nil {
+		dto.RepoError(err, "invitation").Send(c)
+		return
+	}
+	dto.OKPage(c, invitations)
+}
@@ -0,0 +1,437 @@
+package team
+
+import (
+	"net/http"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TeamGetLabels godoc
+// @Summary List the labels of a team
+// @Tags labels
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} dto.LabelsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/labels [get]
+func (r *TeamsHandler) TeamGetLabels(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	labels, err := r.uow.Labels().ListLabels(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "label").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, labels)
+}
+
+// TeamPostLabel godoc
+// @Summary Create a label
+// @Description Only team admins and founders can manage labels. Names are unique within the team, ignoring case.
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body dto.LabelRequest true "Label"
+// @Success 201 {object} dto.LabelEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/labels [post]
+func (r *TeamsHandler) TeamPostLabel(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage labels")
+	if !ok {
+		return
+	}
+
+	req := dto.LabelRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.Description = strings.TrimSpace(req.Description)
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	label := &models.Label{
+		ID:          uuid.New(),
+		TeamID:      teamID,
+		Name:        req.Name,
+		Color:       strings.ToLower(req.Color),
+		Description: req.Description,
+	}
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+	if err := tx.Labels().CreateLabel(c.Request.Context(), label); err != nil {
+		dto.RepoError(err, "label").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "label").Send(c)
+		return
+	}
+	trace.Log(c, "label_created", "team_id="+teamID.String()+" label_id="+label.ID.String())
+	dto.OK(c, http.StatusCreated, label)
+}
+
+// TeamPatchLabel godoc
+// @Summary Edit a label
+// @Description Only team admins and founders can manage labels. Renaming a label updates every task carrying it.
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param label_id path string true "Label ID"
+// @Param request body dto.LabelUpdateRequest true "Fields to change"
+// @Success 200 {object} dto.LabelEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/labels/{label_id} [patch]
+func (r *TeamsHandler) TeamPatchLabel(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage labels")
+	if !ok {
+		return
+	}
+
+	req := dto.LabelUpdateRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if req.Name != nil {
+		*req.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		*req.Description = strings.TrimSpace(*req.Description)
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	label, ok := r.loadLabel(c, tx.Labels(), teamID, c.Param("label_id"))
+	if !ok {
+		return
+	}
+	if req.Name != nil {
+		label.Name = *req.Name
+	}
+	if req.Color != nil {
+		label.Color = strings.ToLower(*req.Color)
+	}
+	if req.Description != nil {
+		label.Description = *req.Description
+	}
+	if err := tx.Labels().UpdateLabel(c.Request.Context(), label); err != nil {
+		dto.RepoError(err, "label").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "label").Send(c)
+		return
+	}
+	trace.Log(c, "label_updated", "team_id="+teamID.String()+" label_id="+label.ID.String())
+	dto.OK(c, http.StatusOK, label)
+}
+
+// TeamDeleteLabel godoc
+// @Summary Delete a label
+// @Description Only team admins and founders can manage labels. The label is removed from every task.
+// @Tags labels
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param label_id path string true "Label ID"
+// @Success 204
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/labels/{label_id} [delete]
+func (r *TeamsHandler) TeamDeleteLabel(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage labels")
+	if !ok {
+		return
+	}
+	labelID, err := uuid.Parse(c.Param("label_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid label id", nil).Send(c)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	if err := tx.Labels().DeleteLabel(c.Request.Context(), teamID, labelID); err != nil {
+		dto.RepoError(err, "label").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "label").Send(c)
+		return
+	}
+	trace.Log(c, "label_deleted", "team_id="+teamID.String()+" label_id="+labelID.String())
+	c.Status(http.StatusNoContent)
+}
+
+// TeamMergeLabel godoc
+// @Summary Merge a label into another
+// @Description Only team admins and founders can manage labels. Every task carrying the label gets target_id
+// @Description instead, and the label is deleted, all in one transaction.
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param label_id path string true "Label to merge and delete"
+// @Param request body dto.LabelMergeRequest true "Label to keep"
+// @Success 200 {object} dto.LabelEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/labels/{label_id}/merge [post]
+func (r *TeamsHandler) TeamMergeLabel(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage labels")
+	if !ok {
+		return
+	}
+
+	req := dto.LabelMergeRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	source, ok := r.loadLabel(c, tx.Labels(), teamID, c.Param("label_id"))
+	if !ok {
+		return
+	}
+	if source.ID == req.TargetID {
+		dto.BadRequest(dto.CodeInvalidRequest, "a label cannot be merged into itself", nil).Send(c)
+		return
+	}
+	target, err := tx.Labels().GetLabel(c.Request.Context(), teamID, req.TargetID)
+	if err != nil {
+		dto.RepoError(err, "target label").Send(c)
+		return
+	}
+	if err := tx.Labels().MergeLabels(c.Request.Context(), teamID, source.ID, target.ID); err != nil {
+		dto.RepoError(err, "label").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "label").Send(c)
+		return
+	}
+	trace.Log(c, "label_merged", "team_id="+teamID.String()+" label_id="+source.ID.String()+" target_id="+target.ID.String())
+	dto.OK(c, http.StatusOK, target)
+}
+
+// TeamAddTaskLabels godoc
+// @Summary Add labels to a task
+// @Description Labels the task already carries are skipped. Every label must belong to the team.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.TaskLabelsRequest true "Labels to add"
+// @Success 200 {object} dto.TeamsTaskEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/labels [post]
+func (r *TeamsHandler) TeamAddTaskLabels(c *gin.Context) {
+	r.changeTaskLabels(c, true)
+}
+
+// TeamRemoveTaskLabels godoc
+// @Summary Remove labels from a task
+// @Description Labels the task does not carry are ignored.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.TaskLabelsRequest true "Labels to remove"
+// @Success 200 {object} dto.TeamsTaskEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/labels [delete]
+func (r *TeamsHandler) TeamRemoveTaskLabels(c *gin.Context) {
+	r.changeTaskLabels(c, false)
+}
+
+func (r *TeamsHandler) changeTaskLabels(c *gin.Context, add bool) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.TaskLabelsRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	task, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	var err error
+	if add {
+		labels, ok := r.checkLabels(c, tx.Labels(), teamID, req.LabelIDs)
+		if !ok {
+			return
+		}
+		err = tx.Labels().AddTaskLabels(c.Request.Context(), task.ID, labels)
+	} else {
+		err = tx.Labels().RemoveTaskLabels(c.Request.Context(), task.ID, req.LabelIDs)
+	}
+	if err != nil {
+		dto.RepoError(err, "label").Send(c)
+		return
+	}
+
+	before := task.LabelIDs
+	task, ok = r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	if change, changed := models.IDsChange("label_ids", before, task.LabelIDs); changed {
+		if !recordActivity(c, tx, task, models.ActivityLabelsChanged, []models.FieldChange{change}, nil) {
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, task)
+}
+
+// requireTeamAdmin is requireMember for endpoints limited to team admins and founders,
+// such as label and custom field management. action completes the error message.
+func (r *TeamsHandler) requireTeamAdmin(c *gin.Context, action string) (uuid.UUID, bool) {
+	teamID, _, role, ok := r.requireMember(c)
+	if !ok {
+		return uuid.Nil, false
+	}
+	if role != models.AdminUserRole && role != models.FounderUserRole {
+		dto.Forbidden(dto.CodeForbidden, "only admin or founder can "+action, nil).Send(c)
+		return uuid.Nil, false
+	}
+	return teamID, true
+}
+
+func (r *TeamsHandler) loadLabel(c *gin.Context, labels repositories.LabelRepository, teamID uuid.UUID, rawID string) (*models.Label, bool) {
+	labelID, err := uuid.Parse(rawID)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid label id", nil).Send(c)
+		return nil, false
+	}
+	label, err := labels.GetLabel(c.Request.Context(), teamID, labelID)
+	if err != nil {
+		dto.RepoError(err, "label").Send(c)
+		return nil, false
+	}
+	return label, true
+}
+
+// checkLabels deduplicates ids and makes sure every label belongs to the team. It sends
+// the error response and returns ok=false on failure.
+func (r *TeamsHandler) checkLabels(c *gin.Context, labels repositories.LabelRepository, teamID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, bool) {
+	out := []uuid.UUID{}
+	if len(ids) == 0 {
+		return out, true
+	}
+	teamLabels, err := labels.ListLabels(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "label").Send(c)
+		return nil, false
+	}
+	known := make(map[uuid.UUID]bool, len(teamLabels))
+	for _, l := range teamLabels {
+		known[l.ID] = true
+	}
+	seen := make(map[uuid.UUID]bool, len(ids))
+	var unknown []uuid.UUID
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if !known[id] {
+			unknown = append(unknown, id)
+			continue
+		}
+		out = append(out, id)
+	}
+	if len(unknown) > 0 {
+		dto.BadRequest(dto.CodeInvalidReference, "labels must belong to the team", map[string]any{"label_ids": unknown}).Send(c)
+		return nil, false
+	}
+	return out, true
+}
@@ -0,0 +1,96 @@
+package team_test
+
+import (
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/testutil"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabels_SQLite(t *testing.T) {
+	f := newFixture(t)
+	labelsPath := "/api/v1/team/" + f.teamID.String() + "/labels"
+
+	newLabel := func(name string, color string) uuid.UUID {
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, labelsPath, dto.LabelRequest{Name: name, Color: color}, f.founder)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.LabelEnvelope](t, rr).Data.ID
+	}
+	bug := newLabel("bug", "#D73A4A")
+	ui := newLabel("ui", "#0075ca")
+	defect := newLabel("defect", "#ff0000")
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, labelsPath, dto.LabelRequest{Name: "docs", Color: "#ffffff"}, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, labelsPath, dto.LabelRequest{Name: "BUG", Color: "#ffffff"}, f.founder)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, labelsPath, dto.LabelRequest{Name: "docs", Color: "blue"}, f.founder)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	newTask := func(title string, labels ...uuid.UUID) uuid.UUID {
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: title, LabelIDs: labels}, f.member)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.ID
+	}
+	both := newTask("both", bug, ui, bug)
+	uiOnly := newTask("ui only", ui)
+	other := newTask("other")
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "x", LabelIDs: []uuid.UUID{uuid.New()}}, f.member)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Equal(t, dto.CodeInvalidReference, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+
+	// Members label tasks; the defect label is merged into bug below.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath()+"/"+other.String()+"/labels", dto.TaskLabelsRequest{LabelIDs: []uuid.UUID{defect}}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath()+"/"+both.String()+"/labels", dto.TaskLabelsRequest{LabelIDs: []uuid.UUID{defect}}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.ElementsMatch(t, []uuid.UUID{bug, ui, defect}, testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.LabelIDs)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, labelsPath+"/"+defect.String()+"/merge", dto.LabelMergeRequest{TargetID: bug}, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, labelsPath+"/"+defect.String()+"/merge", dto.LabelMergeRequest{TargetID: bug}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, labelsPath, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, testutil.DecodeJSON[dto.LabelsEnvelope](t, rr).Data, 2)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []uuid.UUID
+	}{
+		{"any label", "label_id[any]=" + bug.String() + "," + ui.String(), []uuid.UUID{both, uiOnly, other}},
+		{"all labels", "label_id[all]=" + bug.String() + "," + ui.String(), []uuid.UUID{both}},
+		{"merged label carried over", "label_id=" + bug.String(), []uuid.UUID{both, other}},
+		{"repeated values count once", "label_id[all]=" + ui.String() + "," + ui.String(), []uuid.UUID{both, uiOnly}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?"+tt.query, nil, f.member)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+			var got []uuid.UUID
+			for _, task := range testutil.DecodeJSON[dto.TasksEnvelope](t, rr).Data {
+				got = append(got, task.ID)
+			}
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+other.String(), nil, f.member)
+	before := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.UpdatedAt
+	name := "Bug report"
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, labelsPath+"/"+bug.String(), dto.LabelUpdateRequest{Name: &name}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, "Bug report", testutil.DecodeJSON[dto.LabelEnvelope](t, rr).Data.Name)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+other.String(), nil, f.member)
+	require.True(t, testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.UpdatedAt.After(before))
+
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, f.tasksPath()+"/"+both.String()+"/labels", dto.TaskLabelsRequest{LabelIDs: []uuid.UUID{ui}}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, []uuid.UUID{bug}, testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.LabelIDs)
+}
@@ -0,0 +1,224 @@
+package team
+
+import (
+	"errors"
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TeamGetTaskLinks godoc
+// @Summary List the links of a task
+// @Description Links from and to the task. A "blocks" link means from_task_id has to be done before to_task_id.
+// @Tags task links
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} dto.TaskLinksEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/links [get]
+func (r *TeamsHandler) TeamGetTaskLinks(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	links, err := r.uow.TaskLinks().ListTaskLinks(c.Request.Context(), task.ID)
+	if err != nil {
+		dto.RepoError(err, "task link").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, links)
+}
+
+// TeamPostTaskLink godoc
+// @Summary Link two tasks
+// @Description Links the task to another task of the same team. Blocking links that would create a cycle are refused.
+// @Tags task links
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.TaskLinkRequest true "Link"
+// @Success 201 {object} dto.TaskLinkEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/links [post]
+func (r *TeamsHandler) TeamPostTaskLink(c *gin.Context) {
+	teamID, userID, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.TaskLinkRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	task, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	if req.TaskID == task.ID {
+		dto.BadRequest(dto.CodeInvalidRequest, "a task cannot be linked to itself", nil).Send(c)
+		return
+	}
+	if _, err := tx.Tasks().GetTaskByID(c.Request.Context(), teamID, req.TaskID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			dto.BadRequest(dto.CodeInvalidRequest, "linked task not found in this team", nil).Send(c)
+			return
+		}
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+
+	link := &models.TaskLink{
+		ID:         uuid.New(),
+		TeamID:     teamID,
+		FromTaskID: task.ID,
+		ToTaskID:   req.TaskID,
+		Kind:       models.LinkBlocks,
+		CreatedBy:  &userID,
+	}
+	switch req.Type {
+	case "blocked_by":
+		link.FromTaskID, link.ToTaskID = req.TaskID, task.ID
+	case "relates_to":
+		// Related links have no direction; storing them in a canonical order lets the
+		// unique index catch the same link made from the other side.
+		link.Kind = models.LinkRelatesTo
+		if link.ToTaskID.String() < link.FromTaskID.String() {
+			link.FromTaskID, link.ToTaskID = link.ToTaskID, link.FromTaskID
+		}
+	}
+	if link.Kind == models.LinkBlocks {
+		cycle, err := tx.TaskLinks().HasBlockingPath(c.Request.Context(), link.ToTaskID, link.FromTaskID)
+		if err != nil {
+			dto.RepoError(err, "task link").Send(c)
+			return
+		}
+		if cycle {
+			dto.Conflict(dto.CodeDependencyCycle, "the link would create a dependency cycle", nil).Send(c)
+			return
+		}
+	}
+	if err := tx.TaskLinks().CreateTaskLink(c.Request.Context(), link); err != nil {
+		dto.RepoError(err, "task link").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "task link").Send(c)
+		return
+	}
+	trace.Log(c, "task_link_created", "from_task_id="+link.FromTaskID.String()+" to_task_id="+link.ToTaskID.String()+" kind="+string(link.Kind))
+
+	dto.OK(c, http.StatusCreated, link)
+}
+
+// TeamDeleteTaskLink godoc
+// @Summary Remove a link
+// @Tags task links
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param link_id path string true "Link ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/links/{link_id} [delete]
+func (r *TeamsHandler) TeamDeleteTaskLink(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	linkID, err := uuid.Parse(c.Param("link_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid link id", nil).Send(c)
+		return
+	}
+	link, err := r.uow.TaskLinks().GetTaskLink(c.Request.Context(), teamID, linkID)
+	if err != nil {
+		dto.RepoError(err, "task link").Send(c)
+		return
+	}
+	if link.FromTaskID != task.ID && link.ToTaskID != task.ID {
+		dto.RepoError(repositories.ErrNotFound, "task link").Send(c)
+		return
+	}
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+	if err := tx.TaskLinks().DeleteTaskLink(c.Request.Context(), teamID, link.ID); err != nil {
+		dto.RepoError(err, "task link").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "task link").Send(c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// TeamGetDependencies godoc
+// @Summary Get the dependency graph of the team
+// @Description Every task that takes part in a link, the links, and the critical path: the chain of blocking
+// @Description tasks with the largest remaining estimate. Equal chains are ordered by the due date of their last task.
+// @Tags task links
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} dto.DependencyGraphEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/dependencies [get]
+func (r *TeamsHandler) TeamGetDependencies(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	g, err := r.uow.TaskLinks().GetDependencyGraph(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "task link").Send(c)
+		return
+	}
+	g.CriticalPath = models.ComputeCriticalPath(g.Nodes, g.Links)
+	dto.OK(c, http.StatusOK, g)
+}
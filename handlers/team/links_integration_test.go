@@ -0,0 +1,89 @@
+package team_test
+
+import (
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskLinks_SQLite(t *testing.T) {
+	f := newFixture(t)
+
+	create := func(title string, estimate float64) uuid.UUID {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: title, Estimate: &estimate}, f.founder)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.ID
+	}
+	design := create("design", 3)
+	build := create("build", 5)
+	docs := create("docs", 1)
+	release := create("release", 1)
+
+	tests := []struct {
+		name       string
+		from       uuid.UUID
+		typ        string
+		to         uuid.UUID
+		wantStatus int
+	}{
+		{"design blocks build", design, "blocks", build, http.StatusCreated},
+		{"release blocked by build", release, "blocked_by", build, http.StatusCreated},
+		{"docs block release", docs, "blocks", release, http.StatusCreated},
+		{"duplicate", build, "blocked_by", design, http.StatusConflict},
+		{"direct cycle", build, "blocks", design, http.StatusConflict},
+		{"transitive cycle", release, "blocks", design, http.StatusConflict},
+		{"self link", design, "relates_to", design, http.StatusBadRequest},
+		{"related", docs, "relates_to", design, http.StatusCreated},
+		{"related from the other side", design, "relates_to", docs, http.StatusConflict},
+		{"related tasks may also block", design, "blocks", docs, http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := f.tasksPath() + "/" + tt.from.String() + "/links"
+			rr := testutil.DoJSON(t, f.r, http.MethodPost, path, dto.TaskLinkRequest{TaskID: tt.to, Type: tt.typ}, f.member)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+		})
+	}
+
+	// Release cannot be done while build and docs are open.
+	rr := testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/team/"+f.teamID.String()+"/workflow", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var done uuid.UUID
+	for _, s := range testutil.DecodeJSON[dto.WorkflowEnvelope](t, rr).Data.States {
+		if s.Category == models.CategoryDone {
+			done = s.ID
+		}
+	}
+	state := f.tasksPath() + "/" + release.String() + "/state"
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, state, dto.TaskStateRequest{StateID: done}, f.member)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.Equal(t, dto.CodeTaskBlocked, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+
+	// The critical path goes through the heaviest open chain: design (3) -> build (5) -> release (1).
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/team/"+f.teamID.String()+"/dependencies", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	g := testutil.DecodeJSON[dto.DependencyGraphEnvelope](t, rr).Data
+	require.Len(t, g.Nodes, 4)
+	require.Len(t, g.Links, 5)
+	require.Equal(t, []uuid.UUID{design, build, release}, g.CriticalPath.TaskIDs)
+	require.InDelta(t, 9, g.CriticalPath.Estimate, 0.001)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, state, dto.TaskStateRequest{StateID: done, Force: true}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	// Removing a link only works through one of its tasks.
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+docs.String()+"/links", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	links := testutil.DecodeJSON[dto.TaskLinksEnvelope](t, rr).Data
+	require.Len(t, links, 3)
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, f.tasksPath()+"/"+build.String()+"/links/"+links[0].ID.String(), nil, f.member)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, f.tasksPath()+"/"+docs.String()+"/links/"+links[0].ID.String(), nil, f.member)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+}
@@ -1 +1,127 @@
 package team
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/jwtauth"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/listspec"
+	"task_manager/public/repositories/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// requireMember resolves the :id team parameter and the caller's role in it.
+// It sends the error response and returns ok=false when the caller is not a member,
+// or when the request would change an archived team.
+func (r *TeamsHandler) requireMember(c *gin.Context) (teamID uuid.UUID, userID uuid.UUID, role models.TeamUserRole, ok bool) {
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+	teamID, err = uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid team id", nil).Send(c)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+	memberRole, err := r.uow.Teams().GetMemberRole(c.Request.Context(), teamID, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		dto.Forbidden(dto.CodeForbidden, "only team members can access the team", nil).Send(c)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead && !r.checkTeamNotArchived(c, teamID) {
+		return uuid.Nil, uuid.Nil, "", false
+	}
+	return teamID, userID, *memberRole, true
+}
+
+// checkTeamNotArchived refuses changes to an archived team, which is read-only for
+// every member. It sends the error response and returns false when it is archived.
+func (r *TeamsHandler) checkTeamNotArchived(c *gin.Context, teamID uuid.UUID) bool {
+	team, err := r.uow.Teams().GetTeamByID(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return false
+	}
+	if team.Archived() {
+		dto.Conflict(dto.CodeTeamArchived, "the team is archived and read-only", map[string]any{"team_id": teamID}).Send(c)
+		return false
+	}
+	return true
+}
+
+// beginTeamWrite starts the transaction of a change to the team and checks again, with
+// the team locked, that it is not archived; requireMember checked before the request
+// body was read, and the team may have been archived since. It sends the error response
+// and returns false when the change cannot go ahead.
+func (r *TeamsHandler) beginTeamWrite(c *gin.Context, teamID uuid.UUID) (repositories.Transaction, bool) {
+	tx, err := r.uow.Begin(c.Request.Context())
+	if err != nil {
+		dto.Internal(dto.CodeDatabaseError, "database error", err.Error(), nil).Send(c)
+		return nil, false
+	}
+	if !lockTeamWritable(c, tx, teamID) {
+		tx.Stop()
+		return nil, false
+	}
+	return tx, true
+}
+
+// lockTeamWritable locks the team within tx and refuses the change when the team is
+// archived. It sends the error response and returns false in that case.
+func lockTeamWritable(c *gin.Context, tx repositories.Transaction, teamID uuid.UUID) bool {
+	team, err := tx.Teams().LockTeam(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return false
+	}
+	if team.Archived() {
+		dto.Conflict(dto.CodeTeamArchived, "the team is archived and read-only", map[string]any{"team_id": teamID}).Send(c)
+		return false
+	}
+	return true
+}
+
+// TeamGetMembers godoc
+// @Summary List team members
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from meta.next_cursor of the previous page"
+// @Param sort query string false "Comma separated, prefix with - for descending: role, joined_at"
+// @Param include_total query bool false "Include meta.total"
+// @Param role query string false "Filter by role; also role[ne], role[in]=admin,founder"
+// @Param user_id query string false "Filter by user id; also user_id[in]"
+// @Success 200 {object} dto.TeamMembersEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/members [get]
+func (r *TeamsHandler) TeamGetMembers(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	q, err := listquery.Parse(c.Request.URL.Query(), listspec.TeamMembers)
+	if err != nil {
+		dto.ListQueryError(err).Send(c)
+		return
+	}
+	members, err := r.uow.Teams().GetTeamsMembers(c.Request.Context(), teamID, q)
+	if err != nil {
+		dto.RepoError(err, "team member").Send(c)
+		return
+	}
+	dto.OKPage(c, members)
+}
@@ -0,0 +1,449 @@
+package team
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TeamGetMilestones godoc
+// @Summary List the milestones of a team
+// @Description Milestones are ordered by due date. Each comes with the counts and shares of its tasks by workflow
+// @Description category and its health: on_track, at_risk, overdue or completed. A milestone with open tasks is at
+// @Description risk while the elapsed share of its time runs more than 25 points ahead of its share of done tasks.
+// @Tags milestones
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param project_id query string false "Only milestones of this project"
+// @Param completed query bool false "Include completed milestones"
+// @Success 200 {object} dto.MilestonesEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/milestones [get]
+func (r *TeamsHandler) TeamGetMilestones(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	var projectID *uuid.UUID
+	if raw := strings.TrimSpace(c.Query("project_id")); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			dto.BadRequest(dto.CodeInvalidRequest, "invalid project id", nil).Send(c)
+			return
+		}
+		projectID = &id
+	}
+	completed := false
+	if raw := strings.TrimSpace(c.Query("completed")); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			dto.BadRequest(dto.CodeInvalidRequest, "completed must be true or false", nil).Send(c)
+			return
+		}
+		completed = v
+	}
+	milestones, err := r.uow.Milestones().ListMilestones(c.Request.Context(), teamID, projectID, completed)
+	if err != nil {
+		dto.RepoError(err, "milestone").Send(c)
+		return
+	}
+	now := time.Now()
+	for _, m := range milestones {
+		m.Assess(now)
+	}
+	dto.OK(c, http.StatusOK, milestones)
+}
+
+// TeamPostMilestone godoc
+// @Summary Create a milestone
+// @Description Only team admins and founders can manage milestones. With project_id only tasks of that project can
+// @Description be attached to the milestone.
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body dto.MilestoneRequest true "Milestone"
+// @Success 201 {object} dto.MilestoneEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/milestones [post]
+func (r *TeamsHandler) TeamPostMilestone(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	if role != models.AdminUserRole && role != models.FounderUserRole {
+		dto.Forbidden(dto.CodeForbidden, "only admin or founder can manage milestones", nil).Send(c)
+		return
+	}
+
+	req := dto.MilestoneRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.Description = strings.TrimSpace(req.Description)
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	milestone := &models.Milestone{
+		ID:          uuid.New(),
+		TeamID:      teamID,
+		ProjectID:   req.ProjectID,
+		Name:        req.Name,
+		Description: req.Description,
+		DueAt:       req.DueAt,
+		CreatedBy:   &userID,
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	if milestone.ProjectID != nil && !r.checkScopeProject(c, tx.Projects(), teamID, *milestone.ProjectID) {
+		return
+	}
+	if err := tx.Milestones().CreateMilestone(c.Request.Context(), milestone); err != nil {
+		dto.RepoError(err, "milestone").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "milestone").Send(c)
+		return
+	}
+	milestone.Assess(time.Now())
+	trace.Log(c, "milestone_created", "team_id="+teamID.String()+" milestone_id="+milestone.ID.String())
+	dto.OK(c, http.StatusCreated, milestone)
+}
+
+// TeamGetMilestone godoc
+// @Summary Get a milestone
+// @Tags milestones
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param milestone_id path string true "Milestone ID"
+// @Success 200 {object} dto.MilestoneEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/milestones/{milestone_id} [get]
+func (r *TeamsHandler) TeamGetMilestone(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	milestone, ok := r.loadMilestone(c, r.uow.Milestones(), teamID)
+	if !ok {
+		return
+	}
+	milestone.Assess(time.Now())
+	dto.OK(c, http.StatusOK, milestone)
+}
+
+// TeamPatchMilestone godoc
+// @Summary Edit a milestone
+// @Description Only team admins and founders can manage milestones. Completed milestones must be reopened first.
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param milestone_id path string true "Milestone ID"
+// @Param request body dto.MilestoneUpdateRequest true "Fields to change"
+// @Success 200 {object} dto.MilestoneEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/milestones/{milestone_id} [patch]
+func (r *TeamsHandler) TeamPatchMilestone(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage milestones")
+	if !ok {
+		return
+	}
+
+	req := dto.MilestoneUpdateRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if req.Name != nil {
+		*req.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		*req.Description = strings.TrimSpace(*req.Description)
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	milestone, ok := r.loadMilestone(c, tx.Milestones(), teamID)
+	if !ok {
+		return
+	}
+	if milestone.Completed() {
+		dto.Conflict(dto.CodeMilestoneCompleted, "the milestone is completed", map[string]any{"milestone_id": milestone.ID}).Send(c)
+		return
+	}
+	if req.Name != nil {
+		milestone.Name = *req.Name
+	}
+	if req.Description != nil {
+		milestone.Description = *req.Description
+	}
+	if req.ClearProject {
+		milestone.ProjectID = nil
+	} else if req.ProjectID != nil {
+		if !r.checkScopeProject(c, tx.Projects(), teamID, *req.ProjectID) {
+			return
+		}
+		milestone.ProjectID = req.ProjectID
+	}
+	if req.DueAt != nil {
+		milestone.DueAt = *req.DueAt
+	}
+	if err := tx.Milestones().UpdateMilestone(c.Request.Context(), milestone); err != nil {
+		dto.RepoError(err, "milestone").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "milestone").Send(c)
+		return
+	}
+	milestone.Assess(time.Now())
+	trace.Log(c, "milestone_updated", "team_id="+teamID.String()+" milestone_id="+milestone.ID.String())
+	dto.OK(c, http.StatusOK, milestone)
+}
+
+// TeamCompleteMilestone godoc
+// @Summary Complete a milestone
+// @Description Only team admins and founders can manage milestones. A milestone with open tasks is only completed
+// @Description with force, and the response then warns about the open tasks. Completed milestones take no new tasks.
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param milestone_id path string true "Milestone ID"
+// @Param request body dto.MilestoneCompleteRequest true "Options"
+// @Success 200 {object} dto.MilestoneCompletionEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/milestones/{milestone_id}/complete [post]
+func (r *TeamsHandler) TeamCompleteMilestone(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage milestones")
+	if !ok {
+		return
+	}
+
+	req := dto.MilestoneCompleteRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	milestone, ok := r.loadMilestone(c, tx.Milestones(), teamID)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	result := models.MilestoneCompletion{Milestone: milestone, Warnings: []string{}}
+	if milestone.Completed() {
+		milestone.Assess(now)
+		dto.OK(c, http.StatusOK, result)
+		return
+	}
+	if open := milestone.OpenTasks(); open > 0 {
+		if !req.Force {
+			dto.Conflict(dto.CodeMilestoneOpenTasks, "the milestone still has open tasks", map[string]any{"open_tasks": open}).Send(c)
+			return
+		}
+		result.Warnings = append(result.Warnings, "completed with "+strconv.Itoa(open)+" open tasks")
+	}
+	milestone.CompletedAt = &now
+	if err := tx.Milestones().UpdateMilestone(c.Request.Context(), milestone); err != nil {
+		dto.RepoError(err, "milestone").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "milestone").Send(c)
+		return
+	}
+	milestone.Assess(now)
+	trace.Log(c, "milestone_completed", "team_id="+teamID.String()+" milestone_id="+milestone.ID.String()+" open_tasks="+strconv.Itoa(milestone.OpenTasks()))
+	dto.OK(c, http.StatusOK, result)
+}
+
+// TeamReopenMilestone godoc
+// @Summary Reopen a completed milestone
+// @Description Only team admins and founders can manage milestones.
+// @Tags milestones
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param milestone_id path string true "Milestone ID"
+// @Success 200 {object} dto.MilestoneEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/milestones/{milestone_id}/reopen [post]
+func (r *TeamsHandler) TeamReopenMilestone(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage milestones")
+	if !ok {
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	milestone, ok := r.loadMilestone(c, tx.Milestones(), teamID)
+	if !ok {
+		return
+	}
+	if milestone.Completed() {
+		milestone.CompletedAt = nil
+		if err := tx.Milestones().UpdateMilestone(c.Request.Context(), milestone); err != nil {
+			dto.RepoError(err, "milestone").Send(c)
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			dto.RepoError(err, "milestone").Send(c)
+			return
+		}
+		trace.Log(c, "milestone_reopened", "team_id="+teamID.String()+" milestone_id="+milestone.ID.String())
+	}
+	milestone.Assess(time.Now())
+	dto.OK(c, http.StatusOK, milestone)
+}
+
+// TeamDeleteMilestone godoc
+// @Summary Delete a milestone
+// @Description Only team admins and founders can manage milestones. The tasks of the milestone are kept.
+// @Tags milestones
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param milestone_id path string true "Milestone ID"
+// @Success 204
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/milestones/{milestone_id} [delete]
+func (r *TeamsHandler) TeamDeleteMilestone(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage milestones")
+	if !ok {
+		return
+	}
+	milestoneID, err := uuid.Parse(c.Param("milestone_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid milestone id", nil).Send(c)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	taskIDs, err := tx.Milestones().DeleteMilestone(c.Request.Context(), teamID, milestoneID)
+	if err != nil {
+		dto.RepoError(err, "milestone").Send(c)
+		return
+	}
+	change := models.IDChange("milestone_id", &milestoneID, nil)
+	if !recordTasksActivity(c, tx, teamID, taskIDs, models.ActivityTaskUpdated, []models.FieldChange{change}, nil) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "milestone").Send(c)
+		return
+	}
+	trace.Log(c, "milestone_deleted", "team_id="+teamID.String()+" milestone_id="+milestoneID.String())
+	c.Status(http.StatusNoContent)
+}
+
+func (r *TeamsHandler) loadMilestone(c *gin.Context, milestones repositories.MilestoneRepository, teamID uuid.UUID) (*models.Milestone, bool) {
+	milestoneID, err := uuid.Parse(c.Param("milestone_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid milestone id", nil).Send(c)
+		return nil, false
+	}
+	milestone, err := milestones.GetMilestone(c.Request.Context(), teamID, milestoneID)
+	if err != nil {
+		dto.RepoError(err, "milestone").Send(c)
+		return nil, false
+	}
+	return milestone, true
+}
+
+// checkTaskMilestone makes sure a task of project projectID can be attached to
+// milestone milestoneID: the milestone belongs to the team, is not completed and, for
+// project milestones, is a milestone of the same project. It sends the error response
+// and returns false otherwise.
+func (r *TeamsHandler) checkTaskMilestone(c *gin.Context, milestones repositories.MilestoneRepository, teamID uuid.UUID, milestoneID uuid.UUID, projectID *uuid.UUID) bool {
+	milestone, err := milestones.GetMilestone(c.Request.Context(), teamID, milestoneID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		dto.BadRequest(dto.CodeInvalidMilestone, "unknown milestone", map[string]any{"milestone_id": milestoneID}).Send(c)
+		return false
+	}
+	if err != nil {
+		dto.RepoError(err, "milestone").Send(c)
+		return false
+	}
+	if milestone.Completed() {
+		dto.Conflict(dto.CodeMilestoneCompleted, "the milestone is completed", map[string]any{"milestone_id": milestoneID}).Send(c)
+		return false
+	}
+	if milestone.ProjectID != nil && (projectID == nil || *projectID != *milestone.ProjectID) {
+		dto.BadRequest(dto.CodeInvalidMilestone, "the milestone only takes tasks of its project", map[string]any{"milestone_id": milestoneID, "project_id": *milestone.ProjectID}).Send(c)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package team_test
+
+import (
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMilestones_SQLite(t *testing.T) {
+	f := newFixture(t)
+	milestonesPath := "/api/v1/team/" + f.teamID.String() + "/milestones"
+	due := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, milestonesPath, dto.MilestoneRequest{Name: "Beta", DueAt: due}, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, milestonesPath, dto.MilestoneRequest{Name: "Beta", DueAt: due}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	beta := testutil.DecodeJSON[dto.MilestoneEnvelope](t, rr).Data
+	require.Equal(t, models.MilestoneOnTrack, beta.Health)
+	betaPath := milestonesPath + "/" + beta.ID.String()
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, milestonesPath, dto.MilestoneRequest{Name: "Alpha", DueAt: time.Now().Add(-time.Hour)}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	alpha := testutil.DecodeJSON[dto.MilestoneEnvelope](t, rr).Data
+
+	create := func(title string, milestoneID uuid.UUID) uuid.UUID {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: title, MilestoneID: &milestoneID}, f.member)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.ID
+	}
+	a := create("Invite testers", beta.ID)
+	create("Crash reporting", beta.ID)
+	create("Feedback form", beta.ID)
+	b := create("Release notes", beta.ID)
+	create("Old promise", alpha.ID)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Lost", MilestoneID: &[]uuid.UUID{uuid.New()}[0]}, f.member)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/team/"+f.teamID.String()+"/workflow", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	states := map[models.WorkflowCategory]uuid.UUID{}
+	for _, s := range testutil.DecodeJSON[dto.WorkflowEnvelope](t, rr).Data.States {
+		states[s.Category] = s.ID
+	}
+	for task, category := range map[uuid.UUID]models.WorkflowCategory{a: models.CategoryDone, b: models.CategoryActive} {
+		rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath()+"/"+task.String()+"/state", dto.TaskStateRequest{StateID: states[category]}, f.member)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, betaPath, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	progress := testutil.DecodeJSON[dto.MilestoneEnvelope](t, rr).Data.Progress
+	require.Equal(t, models.MilestoneProgress{
+		ProjectProgress:   models.ProjectProgress{Total: 4, NotStarted: 2, Active: 1, Done: 1, Percent: 25},
+		NotStartedPercent: 50,
+		ActivePercent:     25,
+	}, progress)
+
+	// The list is ordered by due date; the past milestone with open work is overdue.
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, milestonesPath, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	list := testutil.DecodeJSON[dto.MilestonesEnvelope](t, rr).Data
+	require.Len(t, list, 2)
+	require.Equal(t, alpha.ID, list[0].ID)
+	require.Equal(t, models.MilestoneOverdue, list[0].Health)
+	require.Equal(t, models.MilestoneOnTrack, list[1].Health)
+
+	// Completing is refused while tasks are open, unless forced.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, betaPath+"/complete", dto.MilestoneCompleteRequest{}, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, betaPath+"/complete", dto.MilestoneCompleteRequest{}, f.founder)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.Equal(t, dto.CodeMilestoneOpenTasks, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, betaPath+"/complete", dto.MilestoneCompleteRequest{Force: true}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	completion := testutil.DecodeJSON[dto.MilestoneCompletionEnvelope](t, rr).Data
+	require.Equal(t, models.MilestoneCompleted, completion.Milestone.Health)
+	require.Len(t, completion.Warnings, 1)
+
+	// Completed milestones take no new tasks, are not edited and leave the default list.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Late", MilestoneID: &beta.ID}, f.member)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	name := "Public beta"
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, betaPath, dto.MilestoneUpdateRequest{Name: &name}, f.founder)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, milestonesPath, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, testutil.DecodeJSON[dto.MilestonesEnvelope](t, rr).Data, 1)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, milestonesPath+"?completed=true", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, testutil.DecodeJSON[dto.MilestonesEnvelope](t, rr).Data, 2)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, betaPath+"/reopen", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Nil(t, testutil.DecodeJSON[dto.MilestoneEnvelope](t, rr).Data.CompletedAt)
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, f.tasksPath()+"/"+b.String(), dto.TaskUpdateRequest{ClearMilestone: true}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Nil(t, testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.MilestoneID)
+
+	// Project milestones only take tasks of their project.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, "/api/v1/team/"+f.teamID.String()+"/projects", dto.ProjectRequest{Name: "Web"}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	project := testutil.DecodeJSON[dto.ProjectEnvelope](t, rr).Data
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, milestonesPath, dto.MilestoneRequest{Name: "Web launch", ProjectID: &project.ID, DueAt: due}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	web := testutil.DecodeJSON[dto.MilestoneEnvelope](t, rr).Data
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, f.tasksPath()+"/"+b.String(), dto.TaskUpdateRequest{MilestoneID: &web.ID}, f.member)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, f.tasksPath()+"/"+b.String(), dto.TaskUpdateRequest{ProjectID: &project.ID, MilestoneID: &web.ID}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, milestonesPath+"?project_id="+project.ID.String(), nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, testutil.DecodeJSON[dto.MilestonesEnvelope](t, rr).Data, 1)
+
+	// Deleting a milestone keeps its tasks.
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, betaPath, nil, f.founder)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?milestone_id[isnull]=true", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Len(t, testutil.DecodeJSON[dto.TasksEnvelope](t, rr).Data, 3)
+	require.Equal(t, []models.FieldChange{{Field: "milestone_id", Old: beta.ID.String(), New: nil}}, f.fieldChanges(t, a, "milestone_id"))
+}
@@ -0,0 +1,657 @@
+package team
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TeamGetProjects godoc
+// @Summary List the projects of a team
+// @Description Projects come with their progress, rolled up from the workflow states of their tasks.
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param archived query bool false "Include archived projects"
+// @Success 200 {object} dto.ProjectsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/projects [get]
+func (r *TeamsHandler) TeamGetProjects(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	archived := false
+	if raw := strings.TrimSpace(c.Query("archived")); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			dto.BadRequest(dto.CodeInvalidRequest, "archived must be true or false", nil).Send(c)
+			return
+		}
+		archived = v
+	}
+	projects, err := r.uow.Projects().ListProjects(c.Request.Context(), teamID, archived)
+	if err != nil {
+		dto.RepoError(err, "project").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, projects)
+}
+
+// TeamPostProject godoc
+// @Summary Create a project
+// @Description Only team admins and founders can create projects. The lead and the members must be members of the team.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body dto.ProjectRequest true "Project"
+// @Success 201 {object} dto.ProjectEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/projects [post]
+func (r *TeamsHandler) TeamPostProject(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	if role != models.AdminUserRole && role != models.FounderUserRole {
+		dto.Forbidden(dto.CodeForbidden, "only admin or founder can create projects", nil).Send(c)
+		return
+	}
+
+	req := dto.ProjectRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.Description = strings.TrimSpace(req.Description)
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+	if req.Status == "" {
+		req.Status = models.ProjectPlanned
+	}
+
+	project := &models.Project{
+		ID:          uuid.New(),
+		TeamID:      teamID,
+		Name:        req.Name,
+		Description: req.Description,
+		LeadID:      req.LeadID,
+		Status:      req.Status,
+		StartDate:   req.StartDate,
+		TargetDate:  req.TargetDate,
+		Restricted:  req.Restricted,
+		CreatedBy:   &userID,
+	}
+	if !checkProjectFields(c, project) {
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	if project.LeadID != nil && !r.checkLead(c, tx.Teams(), teamID, *project.LeadID) {
+		return
+	}
+	if project.MemberIDs, ok = r.checkProjectMembers(c, tx.Teams(), teamID, req.MemberIDs); !ok {
+		return
+	}
+	if err := tx.Projects().CreateProject(c.Request.Context(), project); err != nil {
+		dto.RepoError(err, "project").Send(c)
+		return
+	}
+	if project.Restricted {
+		details := map[string]any{"project_id": project.ID, "name": project.Name, "restricted": true, "added": project.MemberIDs, "removed": []uuid.UUID{}}
+		if !recordTeamAudit(c, tx, teamID, models.TeamActionProjectAccess, details) {
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "project").Send(c)
+		return
+	}
+	trace.Log(c, "project_created", "team_id="+teamID.String()+" project_id="+project.ID.String())
+	dto.OK(c, http.StatusCreated, project)
+}
+
+// TeamGetProject godoc
+// @Summary Get a project
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} dto.ProjectEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/projects/{project_id} [get]
+func (r *TeamsHandler) TeamGetProject(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	project, ok := r.loadProject(c, r.uow.Projects(), teamID)
+	if !ok {
+		return
+	}
+	dto.OK(c, http.StatusOK, project)
+}
+
+// TeamPatchProject godoc
+// @Summary Edit a project
+// @Description Team admins, founders and the lead of the project can edit it. Archived projects must be unarchived first.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param project_id path string true "Project ID"
+// @Param request body dto.ProjectUpdateRequest true "Fields to change"
+// @Success 200 {object} dto.ProjectEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/projects/{project_id} [patch]
+func (r *TeamsHandler) TeamPatchProject(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.ProjectUpdateRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if req.Name != nil {
+		*req.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		*req.Description = strings.TrimSpace(*req.Description)
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	project, ok := r.loadProject(c, tx.Projects(), teamID)
+	if !ok || !canManageProject(c, project, userID, role) || !checkNotArchived(c, project) {
+		return
+	}
+	if req.Name != nil {
+		project.Name = *req.Name
+	}
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+	if req.ClearLead {
+		project.LeadID = nil
+	} else if req.LeadID != nil {
+		if !r.checkLead(c, tx.Teams(), teamID, *req.LeadID) {
+			return
+		}
+		project.LeadID = req.LeadID
+	}
+	if req.Status != nil {
+		project.Status = *req.Status
+	}
+	if req.ClearStartDate {
+		project.StartDate = nil
+	} else if req.StartDate != nil {
+		project.StartDate = req.StartDate
+	}
+	if req.ClearTargetDate {
+		project.TargetDate = nil
+	} else if req.TargetDate != nil {
+		project.TargetDate = req.TargetDate
+	}
+	wasRestricted := project.Restricted
+	if req.Restricted != nil {
+		project.Restricted = *req.Restricted
+	}
+	if !checkProjectFields(c, project) {
+		return
+	}
+	if err := tx.Projects().UpdateProject(c.Request.Context(), project); err != nil {
+		dto.RepoError(err, "project").Send(c)
+		return
+	}
+	if project.Restricted != wasRestricted {
+		details := map[string]any{"project_id": project.ID, "name": project.Name, "restricted": project.Restricted}
+		if !recordTeamAudit(c, tx, teamID, models.TeamActionProjectAccess, details) {
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "project").Send(c)
+		return
+	}
+	trace.Log(c, "project_updated", "team_id="+teamID.String()+" project_id="+project.ID.String())
+	dto.OK(c, http.StatusOK, project)
+}
+
+// TeamPutProjectMembers godoc
+// @Summary Replace the members of a project
+// @Description Team admins, founders and the lead of the project can change its members. Members must be members of the team.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param project_id path string true "Project ID"
+// @Param request body dto.ProjectMembersRequest true "Members"
+// @Success 200 {object} dto.ProjectEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/projects/{project_id}/members [put]
+func (r *TeamsHandler) TeamPutProjectMembers(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.ProjectMembersRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	project, ok := r.loadProject(c, tx.Projects(), teamID)
+	if !ok || !canManageProject(c, project, userID, role) || !checkNotArchived(c, project) {
+		return
+	}
+	before := project.MemberIDs
+	if project.MemberIDs, ok = r.checkProjectMembers(c, tx.Teams(), teamID, req.UserIDs); !ok {
+		return
+	}
+	if err := tx.Projects().SetProjectMembers(c.Request.Context(), project.ID, project.MemberIDs); err != nil {
+		dto.RepoError(err, "project").Send(c)
+		return
+	}
+	added, removed := diffIDs(before, project.MemberIDs)
+	if len(added) > 0 || len(removed) > 0 {
+		details := map[string]any{"project_id": project.ID, "name": project.Name, "restricted": project.Restricted, "added": added, "removed": removed}
+		if !recordTeamAudit(c, tx, teamID, models.TeamActionProjectAccess, details) {
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "project").Send(c)
+		return
+	}
+	trace.Log(c, "project_members_updated", "team_id="+teamID.String()+" project_id="+project.ID.String()+" members="+strconv.Itoa(len(project.MemberIDs)))
+	dto.OK(c, http.StatusOK, project)
+}
+
+// TeamArchiveProject godoc
+// @Summary Archive a project
+// @Description Only team admins and founders can archive projects. Archived projects are hidden from the project list,
+// @Description take no new tasks and cannot be edited; their tasks are kept and can still be moved out.
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} dto.ProjectEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/projects/{project_id}/archive [post]
+func (r *TeamsHandler) TeamArchiveProject(c *gin.Context) {
+	r.setProjectArchived(c, true)
+}
+
+// TeamUnarchiveProject godoc
+// @Summary Unarchive a project
+// @Description Only team admins and founders can archive projects.
+// @Tags projects
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} dto.ProjectEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/projects/{project_id}/unarchive [post]
+func (r *TeamsHandler) TeamUnarchiveProject(c *gin.Context) {
+	r.setProjectArchived(c, false)
+}
+
+// setProjectArchived archives or unarchives the project; doing it twice is a no-op.
+func (r *TeamsHandler) setProjectArchived(c *gin.Context, archived bool) {
+	teamID, ok := r.requireTeamAdmin(c, "archive projects")
+	if !ok {
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	project, ok := r.loadProject(c, tx.Projects(), teamID)
+	if !ok {
+		return
+	}
+	if project.Archived() == archived {
+		dto.OK(c, http.StatusOK, project)
+		return
+	}
+	project.ArchivedAt = nil
+	if archived {
+		now := time.Now()
+		project.ArchivedAt = &now
+	}
+	if err := tx.Projects().UpdateProject(c.Request.Context(), project); err != nil {
+		dto.RepoError(err, "project").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "project").Send(c)
+		return
+	}
+	trace.Log(c, "project_archived", "team_id="+teamID.String()+" project_id="+project.ID.String()+" archived="+strconv.FormatBool(archived))
+	dto.OK(c, http.StatusOK, project)
+}
+
+// TeamDeleteProject godoc
+// @Summary Delete a project
+// @Description Only team admins and founders can delete projects. The project goes to the trash with its tasks and
+// @Description their subtasks, and is restored with them; move tasks out of the project first to keep them.
+// @Tags projects
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param project_id path string true "Project ID"
+// @Success 204
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/projects/{project_id} [delete]
+func (r *TeamsHandler) TeamDeleteProject(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "delete projects")
+	if !ok {
+		return
+	}
+	projectID, err := uuid.Parse(c.Param("project_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid project id", nil).Send(c)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	taskIDs, err := tx.Projects().DeleteProject(c.Request.Context(), teamID, projectID)
+	if err != nil {
+		dto.RepoError(err, "project").Send(c)
+		return
+	}
+	if !recordTasksActivity(c, tx, teamID, taskIDs, models.ActivityTaskDeleted, []models.FieldChange{}, map[string]any{"project_id": projectID}) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "project").Send(c)
+		return
+	}
+	trace.Log(c, "project_deleted", "team_id="+teamID.String()+" project_id="+projectID.String())
+	c.Status(http.StatusNoContent)
+}
+
+// TeamMoveProjectTasks godoc
+// @Summary Move tasks to another project
+// @Description Moves the listed tasks of the project, or all of them, into the target project or out of any project.
+// @Description Restricted projects on either side require the caller to be allowed into them. Listed tasks that are
+// @Description not in the project are skipped.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param project_id path string true "Project ID"
+// @Param request body dto.ProjectTasksMoveRequest true "Target project and tasks"
+// @Success 200 {object} dto.ProjectTasksMoveEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/projects/{project_id}/tasks/move [post]
+func (r *TeamsHandler) TeamMoveProjectTasks(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.ProjectTasksMoveRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	project, ok := r.loadProject(c, tx.Projects(), teamID)
+	if !ok {
+		return
+	}
+	if !project.Allows(userID, role) {
+		dto.Forbidden(dto.CodeForbidden, "only members of the project can move its tasks", nil).Send(c)
+		return
+	}
+	if req.TargetProjectID != nil {
+		if *req.TargetProjectID == project.ID {
+			dto.BadRequest(dto.CodeInvalidProject, "the tasks are already in this project", nil).Send(c)
+			return
+		}
+		if _, ok := r.checkTargetProject(c, tx.Projects(), teamID, *req.TargetProjectID, userID, role); !ok {
+			return
+		}
+	}
+	moved, err := tx.Projects().MoveProjectTasks(c.Request.Context(), teamID, project.ID, req.TargetProjectID, req.TaskIDs)
+	if err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	change := models.IDChange("project_id", &project.ID, req.TargetProjectID)
+	if !recordTasksActivity(c, tx, teamID, moved, models.ActivityTaskUpdated, []models.FieldChange{change}, nil) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	target := "none"
+	if req.TargetProjectID != nil {
+		target = req.TargetProjectID.String()
+	}
+	trace.Log(c, "project_tasks_moved", "project_id="+project.ID.String()+" target="+target+" moved="+strconv.Itoa(len(moved)))
+	dto.OK(c, http.StatusOK, dto.ProjectTasksMoveResponse{Moved: len(moved)})
+}
+
+func (r *TeamsHandler) loadProject(c *gin.Context, projects repositories.ProjectRepository, teamID uuid.UUID) (*models.Project, bool) {
+	projectID, err := uuid.Parse(c.Param("project_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid project id", nil).Send(c)
+		return nil, false
+	}
+	project, err := projects.GetProject(c.Request.Context(), teamID, projectID)
+	if err != nil {
+		dto.RepoError(err, "project").Send(c)
+		return nil, false
+	}
+	return project, true
+}
+
+// canManageProject sends the error response and returns false unless the caller is a
+// team admin or founder or the lead of the project.
+func canManageProject(c *gin.Context, p *models.Project, userID uuid.UUID, role models.TeamUserRole) bool {
+	if role == models.AdminUserRole || role == models.FounderUserRole || (p.LeadID != nil && *p.LeadID == userID) {
+		return true
+	}
+	dto.Forbidden(dto.CodeForbidden, "only admin, founder or the project lead can manage the project", nil).Send(c)
+	return false
+}
+
+func checkNotArchived(c *gin.Context, p *models.Project) bool {
+	if p.Archived() {
+		dto.Conflict(dto.CodeProjectArchived, "the project is archived", map[string]any{"project_id": p.ID}).Send(c)
+		return false
+	}
+	return true
+}
+
+// checkProjectFields validates the status and dates of p. It sends the error response
+// and returns false when they are invalid.
+func checkProjectFields(c *gin.Context, p *models.Project) bool {
+	if !p.Status.IsValid() {
+		dto.BadRequest(dto.CodeInvalidProject, "status must be planned, active, paused, completed or cancelled", nil).Send(c)
+		return false
+	}
+	if p.StartDate != nil && p.TargetDate != nil && p.TargetDate.Before(*p.StartDate) {
+		dto.BadRequest(dto.CodeInvalidProject, "target_date is before start_date", nil).Send(c)
+		return false
+	}
+	return true
+}
+
+func (r *TeamsHandler) checkLead(c *gin.Context, teams repositories.TeamRepository, teamID uuid.UUID, leadID uuid.UUID) bool {
+	_, notMembers, err := teamMembersOnly(c.Request.Context(), teams, teamID, []uuid.UUID{leadID})
+	if err != nil {
+		dto.RepoError(err, "team member").Send(c)
+		return false
+	}
+	if len(notMembers) > 0 {
+		dto.BadRequest(dto.CodeNotTeamMember, "the lead must be a member of the team", map[string]any{"user_ids": notMembers}).Send(c)
+		return false
+	}
+	return true
+}
+
+// checkProjectMembers deduplicates ids and makes sure every user is a member of the
+// team. It sends the error response and returns ok=false on failure.
+func (r *TeamsHandler) checkProjectMembers(c *gin.Context, teams repositories.TeamRepository, teamID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, bool) {
+	members, notMembers, err := teamMembersOnly(c.Request.Context(), teams, teamID, ids)
+	if err != nil {
+		dto.RepoError(err, "team member").Send(c)
+		return nil, false
+	}
+	if len(notMembers) > 0 {
+		dto.BadRequest(dto.CodeNotTeamMember, "project members must be members of the team", map[string]any{"user_ids": notMembers}).Send(c)
+		return nil, false
+	}
+	return members, true
+}
+
+// checkTargetProject makes sure tasks can be filed into project projectID by the
+// caller: the project belongs to the team, is not archived and, when restricted, lets
+// the caller in. It sends the error response and returns ok=false on failure.
+func (r *TeamsHandler) checkTargetProject(c *gin.Context, projects repositories.ProjectRepository, teamID uuid.UUID, projectID uuid.UUID, userID uuid.UUID, role models.TeamUserRole) (*models.Project, bool) {
+	project, err := projects.GetProject(c.Request.Context(), teamID, projectID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			dto.BadRequest(dto.CodeInvalidProject, "unknown project", map[string]any{"project_id": projectID}).Send(c)
+			return nil, false
+		}
+		dto.RepoError(err, "project").Send(c)
+		return nil, false
+	}
+	if !checkNotArchived(c, project) {
+		return nil, false
+	}
+	if !project.Allows(userID, role) {
+		dto.Forbidden(dto.CodeForbidden, "only members of the project can file tasks into it", map[string]any{"project_id": projectID}).Send(c)
+		return nil, false
+	}
+	return project, true
+}
+
+// checkTaskProject validates moving task from its current project to projectID, nil
+// meaning no project. Leaving a restricted project requires access to it as well. It
+// sends the error response and returns false when the move is refused.
+func (r *TeamsHandler) checkTaskProject(c *gin.Context, projects repositories.ProjectRepository, task *models.Task, projectID *uuid.UUID, userID uuid.UUID, role models.TeamUserRole) bool {
+	if projectID != nil && task.ProjectID != nil && *projectID == *task.ProjectID {
+		return true
+	}
+	if task.ProjectID != nil {
+		current, err := projects.GetProject(c.Request.Context(), task.TeamID, *task.ProjectID)
+		if err != nil {
+			dto.RepoError(err, "project").Send(c)
+			return false
+		}
+		if !current.Allows(userID, role) {
+			dto.Forbidden(dto.CodeForbidden, "only members of the project can move its tasks", map[string]any{"project_id": current.ID}).Send(c)
+			return false
+		}
+	}
+	if projectID != nil {
+		_, ok := r.checkTargetProject(c, projects, task.TeamID, *projectID, userID, role)
+		return ok
+	}
+	return true
+}
@@ -0,0 +1,168 @@
+package team_test
+
+import (
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjects_SQLite(t *testing.T) {
+	f := newFixture(t)
+	projectsPath := "/api/v1/team/" + f.teamID.String() + "/projects"
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, projectsPath, dto.ProjectRequest{Name: "Launch"}, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := start.AddDate(0, 0, -1)
+	tests := []struct {
+		name string
+		req  dto.ProjectRequest
+	}{
+		{"unknown status", dto.ProjectRequest{Name: "Launch", Status: "someday"}},
+		{"target before start", dto.ProjectRequest{Name: "Launch", StartDate: &start, TargetDate: &before}},
+		{"lead outside the team", dto.ProjectRequest{Name: "Launch", LeadID: &f.outsiderID}},
+		{"member outside the team", dto.ProjectRequest{Name: "Launch", MemberIDs: []uuid.UUID{f.outsiderID}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := testutil.DoJSON(t, f.r, http.MethodPost, projectsPath, tt.req, f.founder)
+			require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+		})
+	}
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, projectsPath, dto.ProjectRequest{Name: "Launch", StartDate: &start}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	launch := testutil.DecodeJSON[dto.ProjectEnvelope](t, rr).Data
+	require.Equal(t, models.ProjectPlanned, launch.Status)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, projectsPath, dto.ProjectRequest{Name: "Launch"}, f.founder)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, projectsPath, dto.ProjectRequest{Name: "Internal", Restricted: true}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	internal := testutil.DecodeJSON[dto.ProjectEnvelope](t, rr).Data
+	launchPath := projectsPath + "/" + launch.ID.String()
+	internalPath := projectsPath + "/" + internal.ID.String()
+
+	create := func(title string, projectID *uuid.UUID, headers map[string]string) (int, uuid.UUID) {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: title, ProjectID: projectID}, headers)
+		if rr.Code != http.StatusCreated {
+			return rr.Code, uuid.Nil
+		}
+		return rr.Code, testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.ID
+	}
+	_, a := create("Write copy", &launch.ID, f.member)
+	_, b := create("Record demo", &launch.ID, f.member)
+	_, loose := create("Tidy backlog", nil, f.member)
+	code, _ := create("Stray", &[]uuid.UUID{uuid.New()}[0], f.member)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	// Restricted projects only take tasks from their members, their lead and admins.
+	code, _ = create("Payroll", &internal.ID, f.member)
+	require.Equal(t, http.StatusForbidden, code)
+	code, secret := create("Payroll", &internal.ID, f.founder)
+	require.Equal(t, http.StatusCreated, code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, f.tasksPath()+"/"+secret.String(), dto.TaskUpdateRequest{ClearProject: true}, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, f.tasksPath()+"/"+loose.String(), dto.TaskUpdateRequest{ProjectID: &internal.ID}, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, internalPath+"/members", dto.ProjectMembersRequest{UserIDs: []uuid.UUID{f.memberID}}, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, internalPath+"/members", dto.ProjectMembersRequest{UserIDs: []uuid.UUID{f.memberID, f.memberID}}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, []uuid.UUID{f.memberID}, testutil.DecodeJSON[dto.ProjectEnvelope](t, rr).Data.MemberIDs)
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, f.tasksPath()+"/"+loose.String(), dto.TaskUpdateRequest{ProjectID: &internal.ID}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, internal.ID, *testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.ProjectID)
+
+	// Progress is rolled up from the workflow categories of the tasks.
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/team/"+f.teamID.String()+"/workflow", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var doneState uuid.UUID
+	for _, s := range testutil.DecodeJSON[dto.WorkflowEnvelope](t, rr).Data.States {
+		if s.Category == models.CategoryDone {
+			doneState = s.ID
+		}
+	}
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath()+"/"+a.String()+"/state", dto.TaskStateRequest{StateID: doneState}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, launchPath, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	progress := testutil.DecodeJSON[dto.ProjectEnvelope](t, rr).Data.Progress
+	require.Equal(t, models.ProjectProgress{Total: 2, NotStarted: 1, Done: 1, Percent: 50}, progress)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?project_id="+launch.ID.String(), nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Len(t, testutil.DecodeJSON[dto.TasksEnvelope](t, rr).Data, 2)
+
+	// Leads manage their project; plain members don't.
+	active := models.ProjectActive
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, launchPath, dto.ProjectUpdateRequest{Status: &active}, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, launchPath, dto.ProjectUpdateRequest{LeadID: &f.memberID}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, launchPath, dto.ProjectUpdateRequest{Status: &active, TargetDate: &before}, f.member)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, launchPath, dto.ProjectUpdateRequest{Status: &active}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, models.ProjectActive, testutil.DecodeJSON[dto.ProjectEnvelope](t, rr).Data.Status)
+
+	// Archived projects are hidden, take no new tasks and can't be edited.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, launchPath+"/archive", nil, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, launchPath+"/archive", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.NotNil(t, testutil.DecodeJSON[dto.ProjectEnvelope](t, rr).Data.ArchivedAt)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, projectsPath, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, testutil.DecodeJSON[dto.ProjectsEnvelope](t, rr).Data, 1)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, projectsPath+"?archived=true", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, testutil.DecodeJSON[dto.ProjectsEnvelope](t, rr).Data, 2)
+	code, _ = create("Late idea", &launch.ID, f.member)
+	require.Equal(t, http.StatusConflict, code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, launchPath, dto.ProjectUpdateRequest{Status: &active}, f.founder)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.Equal(t, dto.CodeProjectArchived, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+
+	// Tasks can still be moved out of an archived project, but not into one.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, internalPath+"/tasks/move", dto.ProjectTasksMoveRequest{TargetProjectID: &launch.ID}, f.member)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, launchPath+"/tasks/move", dto.ProjectTasksMoveRequest{TargetProjectID: &internal.ID, TaskIDs: []uuid.UUID{b, loose}}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, 1, testutil.DecodeJSON[dto.ProjectTasksMoveEnvelope](t, rr).Data.Moved)
+	moved := models.FieldChange{Field: "project_id", Old: launch.ID.String(), New: internal.ID.String()}
+	require.Contains(t, f.fieldChanges(t, b, "project_id"), moved)
+	require.NotContains(t, f.fieldChanges(t, loose, "project_id"), moved)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, launchPath+"/unarchive", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Nil(t, testutil.DecodeJSON[dto.ProjectEnvelope](t, rr).Data.ArchivedAt)
+
+	// Deleting a project moves it to the trash with its tasks; restoring brings them back.
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, internalPath, nil, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, internalPath, nil, f.founder)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, internalPath, nil, f.member)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?project_id="+internal.ID.String(), nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Empty(t, testutil.DecodeJSON[dto.TasksEnvelope](t, rr).Data)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, "/api/v1/team/"+f.teamID.String()+"/trash/"+internal.ID.String()+"/restore", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, 3, testutil.DecodeJSON[dto.TrashItemEnvelope](t, rr).Data.TaskCount)
+	for _, action := range []string{"task.deleted", "task.restored"} {
+		activity := f.activity(t, b, "action="+action)
+		require.Len(t, activity, 1, action)
+		require.Equal(t, internal.ID.String(), activity[0].Details["project_id"])
+	}
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?project_id="+internal.ID.String(), nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Len(t, testutil.DecodeJSON[dto.TasksEnvelope](t, rr).Data, 3)
+}
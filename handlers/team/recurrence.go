@@ -0,0 +1,224 @@
+package team
+
+import (
+	"errors"
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/recurrence"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// upcomingOccurrences is how many future occurrences the recurrence endpoints return.
+const upcomingOccurrences = 5
+
+// TeamGetTaskRecurrence godoc
+// @Summary Get the recurrence of a task
+// @Description Only the current instance of a series has a recurrence. The response lists the next occurrences.
+// @Tags recurrence
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} dto.RecurrenceEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/recurrence [get]
+func (r *TeamsHandler) TeamGetTaskRecurrence(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	rec, err := r.uow.Recurrences().GetTaskRecurrence(c.Request.Context(), task.ID)
+	if err != nil {
+		dto.RepoError(err, "recurrence").Send(c)
+		return
+	}
+	rec.Upcoming = recurrence.Upcoming(rec, upcomingOccurrences)
+	dto.OK(c, http.StatusOK, rec)
+}
+
+// TeamPutTaskRecurrence godoc
+// @Summary Make a task recurring
+// @Description Replaces the schedule of the task, which becomes due at the first occurrence at or after starts_at.
+// @Description on_complete series create the next instance when the task is moved to a done state;
+// @Description on_schedule series create it when the occurrence arrives. Instances copy the assignees, labels,
+// @Description custom field values and checklist of the previous one.
+// @Tags recurrence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.TaskRecurrenceRequest true "Schedule"
+// @Success 200 {object} dto.RecurrenceEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/recurrence [put]
+func (r *TeamsHandler) TeamPutTaskRecurrence(c *gin.Context) {
+	teamID, userID, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.TaskRecurrenceRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+	if !req.Mode.IsValid() {
+		dto.BadRequest(dto.CodeInvalidRecurrence, "mode must be on_complete or on_schedule", nil).Send(c)
+		return
+	}
+	schedule, err := recurrence.ParseSchedule(req.Rule, req.Timezone)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRecurrence, err.Error(), nil).Send(c)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	task, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	if req.StartsAt != nil {
+		start = *req.StartsAt
+	} else if task.DueAt != nil {
+		start = *task.DueAt
+	}
+	occurrence, ok := schedule.First(start)
+	if !ok {
+		dto.BadRequest(dto.CodeInvalidRecurrence, "the rule has no occurrence after starts_at", nil).Send(c)
+		return
+	}
+
+	rec := &models.Recurrence{
+		ID:           uuid.New(),
+		TeamID:       teamID,
+		TaskID:       task.ID,
+		Rule:         schedule.Rule.String(),
+		Timezone:     req.Timezone,
+		Mode:         req.Mode,
+		StartsAt:     start,
+		OccurrenceAt: occurrence,
+		CreatedBy:    &userID,
+	}
+	if rec.Mode == models.RecurOnSchedule {
+		rec.NextRunAt = &occurrence
+	}
+	if err := tx.Recurrences().SetTaskRecurrence(c.Request.Context(), rec); err != nil {
+		dto.RepoError(err, "recurrence").Send(c)
+		return
+	}
+	before := *task
+	task.DueAt = &occurrence
+	if err := tx.Tasks().UpdateTask(c.Request.Context(), task); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	if changes := models.TaskChanges(&before, task); len(changes) > 0 {
+		if !recordActivity(c, tx, task, models.ActivityTaskUpdated, changes, nil) {
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "recurrence").Send(c)
+		return
+	}
+	rec.Upcoming = recurrence.Upcoming(rec, upcomingOccurrences)
+	trace.Log(c, "task_recurrence_set", "task_id="+task.ID.String()+" rule="+rec.Rule+" mode="+string(rec.Mode))
+	dto.OK(c, http.StatusOK, rec)
+}
+
+// TeamDeleteTaskRecurrence godoc
+// @Summary Stop a task from recurring
+// @Description The task and the instances created before are kept.
+// @Tags recurrence
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Success 204
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/recurrence [delete]
+func (r *TeamsHandler) TeamDeleteTaskRecurrence(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+	if err := tx.Recurrences().DeleteTaskRecurrence(c.Request.Context(), task.ID); err != nil {
+		dto.RepoError(err, "recurrence").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "recurrence").Send(c)
+		return
+	}
+	trace.Log(c, "task_recurrence_deleted", "task_id="+task.ID.String())
+	c.Status(http.StatusNoContent)
+}
+
+// advanceOnComplete creates the next instance of the on_complete series task is the
+// current instance of, if any, in tx. It sends the error response and returns ok=false
+// on failure.
+func advanceOnComplete(c *gin.Context, tx repositories.Transaction, task *models.Task) bool {
+	rec, err := tx.Recurrences().GetTaskRecurrence(c.Request.Context(), task.ID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return true
+	}
+	if err != nil {
+		dto.RepoError(err, "recurrence").Send(c)
+		return false
+	}
+	if rec.Mode != models.RecurOnComplete {
+		return true
+	}
+	next, err := recurrence.Advance(c.Request.Context(), tx, rec, time.Now())
+	if errors.Is(err, recurrence.ErrMoved) {
+		return true
+	}
+	if err != nil {
+		dto.RepoError(err, "recurrence").Send(c)
+		return false
+	}
+	if next != nil {
+		trace.Log(c, "task_recurred", "task_id="+task.ID.String()+" next_task_id="+next.ID.String())
+	}
+	return true
+}
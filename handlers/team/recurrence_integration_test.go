@@ -0,0 +1,178 @@
+package team_test
+
+import (
+	"context"
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/jobs"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRecurrence_SQLite(t *testing.T) {
+	f := newFixture(t)
+	teamPath := "/api/v1/team/" + f.teamID.String()
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/labels", dto.LabelRequest{Name: "ops", Color: "#00ff00"}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	label := testutil.DecodeJSON[dto.LabelEnvelope](t, rr).Data.ID
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{
+		Title:       "Rotate keys",
+		AssigneeIDs: []uuid.UUID{f.memberID},
+		LabelIDs:    []uuid.UUID{label},
+	}, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	task := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data
+	taskPath := f.tasksPath() + "/" + task.ID.String()
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, taskPath+"/checklist", dto.ChecklistItemRequest{Content: "revoke old keys"}, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, taskPath+"/recurrence", nil, f.member)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	start := time.Date(2030, 3, 4, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		req      dto.TaskRecurrenceRequest
+		wantCode dto.ErrorCode
+	}{
+		{"missing rule", dto.TaskRecurrenceRequest{Timezone: "UTC", Mode: models.RecurOnComplete}, dto.CodeValidationError},
+		{"invalid rule", dto.TaskRecurrenceRequest{Rule: "FREQ=HOURLY", Timezone: "UTC", Mode: models.RecurOnComplete}, dto.CodeInvalidRecurrence},
+		{"unknown timezone", dto.TaskRecurrenceRequest{Rule: "FREQ=DAILY", Timezone: "Mars/Olympus", Mode: models.RecurOnComplete}, dto.CodeInvalidRecurrence},
+		{"unknown mode", dto.TaskRecurrenceRequest{Rule: "FREQ=DAILY", Timezone: "UTC", Mode: "sometimes"}, dto.CodeInvalidRecurrence},
+		{"ended before start", dto.TaskRecurrenceRequest{Rule: "FREQ=DAILY;UNTIL=20200101", Timezone: "UTC", Mode: models.RecurOnComplete}, dto.CodeInvalidRecurrence},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := testutil.DoJSON(t, f.r, http.MethodPut, taskPath+"/recurrence", tt.req, f.member)
+			require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+			require.Equal(t, tt.wantCode, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+		})
+	}
+
+	// Monday and Thursday mornings in Berlin, three times.
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, taskPath+"/recurrence", dto.TaskRecurrenceRequest{
+		Rule:     "freq=weekly;byday=mo,th;count=3",
+		Timezone: "Europe/Berlin",
+		Mode:     models.RecurOnComplete,
+		StartsAt: &start,
+	}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rec := testutil.DecodeJSON[dto.RecurrenceEnvelope](t, rr).Data
+	require.Equal(t, "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=3", rec.Rule)
+	require.True(t, start.Equal(rec.OccurrenceAt))
+	require.Nil(t, rec.NextRunAt)
+	require.Len(t, rec.Upcoming, 2)
+	require.True(t, time.Date(2030, 3, 7, 9, 0, 0, 0, time.UTC).Equal(rec.Upcoming[0]))
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, taskPath, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.True(t, start.Equal(*testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.DueAt))
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/workflow", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var done uuid.UUID
+	for _, s := range testutil.DecodeJSON[dto.WorkflowEnvelope](t, rr).Data.States {
+		if s.Category == models.CategoryDone {
+			done = s.ID
+		}
+	}
+	complete := func(id uuid.UUID) {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath()+"/"+id.String()+"/state", dto.TaskStateRequest{StateID: done}, f.member)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	}
+	latest := func() models.Task {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?sort=-due_at", nil, f.member)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.TasksEnvelope](t, rr).Data[0]
+	}
+
+	// Completing an instance creates the next one with the same assignees, labels and
+	// an unchecked copy of the checklist.
+	complete(task.ID)
+	next := latest()
+	require.NotEqual(t, task.ID, next.ID)
+	require.Equal(t, "Rotate keys", next.Title)
+	require.True(t, time.Date(2030, 3, 7, 9, 0, 0, 0, time.UTC).Equal(*next.DueAt))
+	require.Equal(t, []uuid.UUID{f.memberID}, next.AssigneeIDs)
+	require.Equal(t, []uuid.UUID{label}, next.LabelIDs)
+	require.NotEqual(t, done, *next.StateID)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+next.ID.String()+"/checklist", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	items := testutil.DecodeJSON[dto.ChecklistEnvelope](t, rr).Data
+	require.Len(t, items, 1)
+	require.Equal(t, "revoke old keys", items[0].Content)
+	require.False(t, items[0].Done)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, taskPath+"/recurrence", nil, f.member)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+next.ID.String()+"/recurrence", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, next.ID, testutil.DecodeJSON[dto.RecurrenceEnvelope](t, rr).Data.TaskID)
+
+	// The third occurrence is the last one: completing it ends the series.
+	complete(next.ID)
+	last := latest()
+	require.True(t, time.Date(2030, 3, 11, 9, 0, 0, 0, time.UTC).Equal(*last.DueAt))
+	complete(last.ID)
+	require.Equal(t, last.ID, latest().ID)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+last.ID.String()+"/recurrence", nil, f.member)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, f.tasksPath()+"/"+last.ID.String()+"/recurrence", nil, f.member)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestRecurrenceScheduler_SQLite(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Stand-up notes"}, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	task := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data
+
+	// The first occurrence is in the past, so the scheduler is due right away.
+	start := time.Now().Add(-72 * time.Hour).Truncate(time.Second)
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, f.tasksPath()+"/"+task.ID.String()+"/recurrence", dto.TaskRecurrenceRequest{
+		Rule:     "FREQ=DAILY",
+		Timezone: "America/New_York",
+		Mode:     models.RecurOnSchedule,
+		StartsAt: &start,
+	}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.NotNil(t, testutil.DecodeJSON[dto.RecurrenceEnvelope](t, rr).Data.NextRunAt)
+
+	count := func() int {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath(), nil, f.member)
+		require.Equal(t, http.StatusOK, rr.Code)
+		return len(testutil.DecodeJSON[dto.TasksEnvelope](t, rr).Data)
+	}
+
+	// Missed occurrences are skipped: one instance is created, due tomorrow, and
+	// running again does nothing until then.
+	scheduler := jobs.NewRecurrenceScheduler(f.uow)
+	require.NoError(t, scheduler.Run(ctx))
+	require.Equal(t, 2, count())
+	require.NoError(t, scheduler.Run(ctx))
+	require.Equal(t, 2, count())
+
+	rec, err := f.uow.Recurrences().GetTaskRecurrence(ctx, task.ID)
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+	require.Nil(t, rec)
+
+	due, err := f.uow.Recurrences().ListDueRecurrences(ctx, time.Now().Add(25*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.True(t, due[0].NextRunAt.After(time.Now()))
+	require.True(t, due[0].OccurrenceAt.Equal(*due[0].NextRunAt))
+}
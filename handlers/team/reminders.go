@@ -0,0 +1,99 @@
+package team
+
+import (
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TeamGetTaskReminders godoc
+// @Summary Get the reminders of a task
+// @Description Empty minutes_before means each recipient gets their default reminders.
+// @Tags reminders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} dto.TaskRemindersEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/reminders [get]
+func (r *TeamsHandler) TeamGetTaskReminders(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	minutes, err := r.uow.Reminders().ListTaskReminders(c.Request.Context(), task.ID)
+	if err != nil {
+		dto.RepoError(err, "reminder").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, models.TaskReminders{TaskID: task.ID, MinutesBefore: minutes})
+}
+
+// TeamPutTaskReminders godoc
+// @Summary Set the reminders of a task
+// @Description Reminders go to the assignees of the task, or to its creator when it has none, and replace their default reminders.
+// @Description When several reminders are missed, e.g. because the due date was set late, only the latest one is sent.
+// @Tags reminders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.TaskRemindersRequest true "Reminders"
+// @Success 200 {object} dto.TaskRemindersEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/reminders [put]
+func (r *TeamsHandler) TeamPutTaskReminders(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.TaskRemindersRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+	minutes := models.NormalizeReminders(req.MinutesBefore)
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	task, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	if err := tx.Reminders().SetTaskReminders(c.Request.Context(), task.ID, minutes); err != nil {
+		dto.RepoError(err, "reminder").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "reminder").Send(c)
+		return
+	}
+	trace.Log(c, "task_reminders_set", "task_id="+task.ID.String())
+	dto.OK(c, http.StatusOK, models.TaskReminders{TaskID: task.ID, MinutesBefore: minutes})
+}
@@ -0,0 +1,181 @@
+package team_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/jobs"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"task_manager/public/testutil/fakes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserPreferences_SQLite(t *testing.T) {
+	f := newFixture(t)
+	const path = "/api/v1/user/me/preferences"
+
+	rr := testutil.DoJSON(t, f.r, http.MethodGet, path, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	p := testutil.DecodeJSON[dto.UserPreferencesEnvelope](t, rr).Data
+	require.Equal(t, "UTC", p.Timezone)
+	require.True(t, p.EmailNotifications)
+	require.Empty(t, p.DefaultReminders)
+
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name string
+		req  dto.UserPreferencesUpdateRequest
+	}{
+		{"unknown timezone", dto.UserPreferencesUpdateRequest{Timezone: str("Mars/Olympus")}},
+		{"server timezone", dto.UserPreferencesUpdateRequest{Timezone: str("Local")}},
+		{"quiet hours without end", dto.UserPreferencesUpdateRequest{QuietHoursStart: str("22:00")}},
+		{"invalid clock", dto.UserPreferencesUpdateRequest{QuietHoursStart: str("22:00"), QuietHoursEnd: str("7am")}},
+		{"reminder too early", dto.UserPreferencesUpdateRequest{DefaultReminders: []int{60 * 24 * 31}}},
+		{"negative reminder", dto.UserPreferencesUpdateRequest{DefaultReminders: []int{-5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := testutil.DoJSON(t, f.r, http.MethodPatch, path, tt.req, f.member)
+			require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+		})
+	}
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, path, dto.UserPreferencesUpdateRequest{
+		Timezone:         str("Europe/Berlin"),
+		QuietHoursStart:  str("22:00"),
+		QuietHoursEnd:    str("07:00"),
+		DefaultReminders: []int{60, 1440, 60},
+	}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	p = testutil.DecodeJSON[dto.UserPreferencesEnvelope](t, rr).Data
+	require.Equal(t, []int{1440, 60}, p.DefaultReminders)
+	require.Equal(t, "22:00", *p.QuietHoursStart)
+
+	// Fields that are not sent keep their value.
+	off := false
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, path, dto.UserPreferencesUpdateRequest{EmailNotifications: &off, ClearQuietHours: true}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	p = testutil.DecodeJSON[dto.UserPreferencesEnvelope](t, rr).Data
+	require.Equal(t, "Europe/Berlin", p.Timezone)
+	require.Equal(t, []int{1440, 60}, p.DefaultReminders)
+	require.Nil(t, p.QuietHoursStart)
+	require.False(t, p.EmailNotifications)
+}
+
+func TestReminders_SQLite(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPatch, "/api/v1/user/me/preferences", dto.UserPreferencesUpdateRequest{DefaultReminders: []int{1440, 60}}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	create := func(title string, due time.Time, assignees []uuid.UUID, headers map[string]string) uuid.UUID {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: title, DueAt: &due, AssigneeIDs: assignees}, headers)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.ID
+	}
+	// Both default reminders have fired; only the one-hour reminder is sent.
+	soon := create("Renew certificate", now.Add(30*time.Minute), []uuid.UUID{f.memberID}, f.founder)
+	late := create("File report", now.Add(-10*time.Minute), []uuid.UUID{f.memberID}, f.founder)
+	// Unassigned tasks notify their creator, who is in quiet hours.
+	quiet := create("Water plants", now.Add(-5*time.Minute), nil, f.founder)
+	// Long overdue tasks are not reported.
+	create("Ancient history", now.Add(-48*time.Hour), []uuid.UUID{f.memberID}, f.founder)
+	// Task reminders replace the defaults: nothing has fired yet.
+	later := create("Book venue", now.Add(3*time.Hour), []uuid.UUID{f.memberID}, f.founder)
+	remindersPath := f.tasksPath() + "/" + later.String() + "/reminders"
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, remindersPath, dto.TaskRemindersRequest{MinutesBefore: []int{0, 120, 0}}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, []int{120, 0}, testutil.DecodeJSON[dto.TaskRemindersEnvelope](t, rr).Data.MinutesBefore)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, remindersPath, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, []int{120, 0}, testutil.DecodeJSON[dto.TaskRemindersEnvelope](t, rr).Data.MinutesBefore)
+
+	clock := func(t time.Time) *string {
+		s := t.In(time.UTC).Format("15:04")
+		return &s
+	}
+	off := false
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, "/api/v1/user/me/preferences", dto.UserPreferencesUpdateRequest{
+		QuietHoursStart:    clock(now.Add(-time.Hour)),
+		QuietHoursEnd:      clock(now.Add(time.Hour)),
+		EmailNotifications: &off,
+	}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	notifications := func(headers map[string]string) map[uuid.UUID][]models.NotificationKind {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/user/me/notifications", nil, headers)
+		require.Equal(t, http.StatusOK, rr.Code)
+		byTask := map[uuid.UUID][]models.NotificationKind{}
+		for _, n := range testutil.DecodeJSON[dto.NotificationsEnvelope](t, rr).Data {
+			byTask[*n.TaskID] = append(byTask[*n.TaskID], n.Kind)
+		}
+		return byTask
+	}
+
+	// A second worker stands in for another replica.
+	require.NoError(t, jobs.NewReminderWorker(f.uow).Run(ctx))
+	require.NoError(t, jobs.NewReminderWorker(f.uow).Run(ctx))
+	require.Equal(t, map[uuid.UUID][]models.NotificationKind{
+		soon: {models.NotificationReminder},
+		late: {models.NotificationOverdue},
+	}, notifications(f.member))
+	require.Empty(t, notifications(f.founder))
+
+	// Once the quiet hours are over, the overdue notice goes out in-app only.
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, "/api/v1/user/me/preferences", dto.UserPreferencesUpdateRequest{ClearQuietHours: true}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.NoError(t, jobs.NewReminderWorker(f.uow).Run(ctx))
+	require.Equal(t, map[uuid.UUID][]models.NotificationKind{quiet: {models.NotificationOverdue}}, notifications(f.founder))
+
+	// Only the member wants emails; sending them twice is a no-op.
+	mail := &fakes.Mailer{}
+	sender := jobs.NewMailSender(f.uow, mail)
+	require.NoError(t, sender.Run(ctx))
+	require.NoError(t, sender.Run(ctx))
+	var subjects []string
+	for _, m := range mail.Sent() {
+		require.Equal(t, "member@example.com", m.To)
+		subjects = append(subjects, m.Subject)
+	}
+	require.ElementsMatch(t, []string{"Reminder: Renew certificate", "Overdue: File report"}, subjects)
+
+	// Archived teams are read-only: their tasks send no reminders.
+	archived := create("Close books", now.Add(30*time.Minute), []uuid.UUID{f.memberID}, f.founder)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, "/api/v1/team/"+f.teamID.String()+"/archive", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.NoError(t, jobs.NewReminderWorker(f.uow).Run(ctx))
+	require.NotContains(t, notifications(f.member), archived)
+}
+
+func TestMailSender_SQLite(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	require.NoError(t, f.uow.Outbox().EnqueueEmail(ctx, &models.OutboxEmail{ID: uuid.New(), Recipient: "member@example.com", Subject: "Hi", Body: "Hello"}))
+
+	// A failed email is locked until its lease expires, then retried.
+	mail := &fakes.Mailer{Err: errors.New("connection refused")}
+	require.NoError(t, jobs.NewMailSender(f.uow, mail).Run(ctx))
+	mail.Err = nil
+	require.NoError(t, jobs.NewMailSender(f.uow, mail).Run(ctx))
+	require.Empty(t, mail.Sent())
+
+	emails, err := f.uow.Outbox().ClaimEmails(ctx, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour), 5, 10)
+	require.NoError(t, err)
+	require.Len(t, emails, 1)
+	require.Equal(t, 2, emails[0].Attempts)
+	// Emails out of attempts are not claimed again.
+	emails, err = f.uow.Outbox().ClaimEmails(ctx, time.Now().Add(3*time.Hour), time.Now().Add(4*time.Hour), 2, 10)
+	require.NoError(t, err)
+	require.Empty(t, emails)
+}
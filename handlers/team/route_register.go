@@ -1,6 +1,8 @@
 package team
 
 import (
+	"task_manager/public/blobstore"
+	"task_manager/public/config"
 	"task_manager/public/repositories"
 
 	"github.com/gin-gonic/gin"
@@ -8,6 +10,27 @@ import (
 
 type TeamsHandler struct {
 	uow repositories.UnitOfWork
+
+	taskMaxDepth int
+
+	blobs              blobstore.Store
+	maxAttachmentBytes int64
+	storageQuotaBytes  int64
+}
+
+func NewTeamsHandler(uow repositories.UnitOfWork) *TeamsHandler {
+	cfg := config.Load()
+	return NewTeamsHandlerWithConfig(uow, cfg, blobstore.NewLocal(cfg.BlobDir))
+}
+
+func NewTeamsHandlerWithConfig(uow repositories.UnitOfWork, cfg config.Config, blobs blobstore.Store) *TeamsHandler {
+	return &TeamsHandler{
+		uow:                uow,
+		taskMaxDepth:       cfg.TaskMaxDepth,
+		blobs:              blobs,
+		maxAttachmentBytes: cfg.AttachmentMaxBytes,
+		storageQuotaBytes:  cfg.TeamStorageQuotaBytes,
+	}
 }
 
 func (r *TeamsHandler) RegisterRoutes(rg *gin.RouterGroup, AuthMiddleware gin.HandlerFunc) {
@@ -17,15 +40,146 @@ func (r *TeamsHandler) RegisterRoutes(rg *gin.RouterGroup, AuthMiddleware gin.Ha
 	rg.DELETE("/:id", r.TeamDelete)
 	rg.GET("/:id", r.TeamGetByID)
 	rg.PUT("/:id", r.TeamEdit)
+	rg.POST("/:id/archive", r.TeamArchive)
+	rg.POST("/:id/unarchive", r.TeamUnarchive)
+	rg.GET("/:id/audit", r.TeamGetAudit)
+
+	// Trash routes
+	rg.GET("/trash", r.TeamGetDeletedTeams)
+	rg.POST("/:id/restore", r.TeamRestore)
+	rg.GET("/:id/trash", r.TeamGetTrash)
+	rg.POST("/:id/trash/:item_id/restore", r.TeamRestoreTrashItem)
 
 	// Teams members routes
-	// rg.GET("/:id/members", r.TeamGetMembers)
+	rg.GET("/:id/members", r.TeamGetMembers)
 	// rg.POST("/:id/members", r.TeamAddMember)
 	// rg.DELETE("/:id/members/:user_id", r.TeamRemoveMember)
 	// rg.PUT("/:id/members/:user_id", r.TeamEditMemberRole)
 
+	// Tasks routes
+	rg.GET("/:id/tasks", r.TeamGetTasks)
+	rg.POST("/:id/tasks", r.TeamPostTask)
+	rg.GET("/:id/tasks/:task_id", r.TeamGetTask)
+	rg.PATCH("/:id/tasks/:task_id", r.TeamPatchTask)
+	rg.DELETE("/:id/tasks/:task_id", r.TeamDeleteTask)
+	rg.POST("/:id/tasks/:task_id/assignees", r.TeamAddTaskAssignees)
+	rg.DELETE("/:id/tasks/:task_id/assignees", r.TeamRemoveTaskAssignees)
+	rg.POST("/:id/tasks/:task_id/state", r.TeamSetTaskState)
+	rg.GET("/:id/tasks/:task_id/subtasks", r.TeamGetSubtasks)
+	rg.POST("/:id/tasks/:task_id/move", r.TeamMoveTask)
+	rg.POST("/:id/tasks/:task_id/labels", r.TeamAddTaskLabels)
+	rg.DELETE("/:id/tasks/:task_id/labels", r.TeamRemoveTaskLabels)
+
+	// Labels routes
+	rg.GET("/:id/labels", r.TeamGetLabels)
+	rg.POST("/:id/labels", r.TeamPostLabel)
+	rg.PATCH("/:id/labels/:label_id", r.TeamPatchLabel)
+	rg.DELETE("/:id/labels/:label_id", r.TeamDeleteLabel)
+	rg.POST("/:id/labels/:label_id/merge", r.TeamMergeLabel)
+
+	// Custom fields routes
+	rg.GET("/:id/fields", r.TeamGetCustomFields)
+	rg.POST("/:id/fields", r.TeamPostCustomField)
+	rg.PATCH("/:id/fields/:field_id", r.TeamPatchCustomField)
+	rg.DELETE("/:id/fields/:field_id", r.TeamDeleteCustomField)
+
+	// Board routes
+	rg.GET("/:id/boards", r.TeamGetBoards)
+	rg.POST("/:id/boards", r.TeamPostBoard)
+	rg.GET("/:id/boards/:board_id", r.TeamGetBoard)
+	rg.PATCH("/:id/boards/:board_id", r.TeamPatchBoard)
+	rg.DELETE("/:id/boards/:board_id", r.TeamDeleteBoard)
+	rg.PUT("/:id/boards/:board_id/columns", r.TeamPutBoardColumns)
+	rg.POST("/:id/boards/:board_id/move", r.TeamMoveBoardCard)
+
+	// Project routes
+	rg.GET("/:id/projects", r.TeamGetProjects)
+	rg.POST("/:id/projects", r.TeamPostProject)
+	rg.GET("/:id/projects/:project_id", r.TeamGetProject)
+	rg.PATCH("/:id/projects/:project_id", r.TeamPatchProject)
+	rg.DELETE("/:id/projects/:project_id", r.TeamDeleteProject)
+	rg.PUT("/:id/projects/:project_id/members", r.TeamPutProjectMembers)
+	rg.POST("/:id/projects/:project_id/archive", r.TeamArchiveProject)
+	rg.POST("/:id/projects/:project_id/unarchive", r.TeamUnarchiveProject)
+	rg.POST("/:id/projects/:project_id/tasks/move", r.TeamMoveProjectTasks)
+
+	// Sprint routes
+	rg.GET("/:id/sprints", r.TeamGetSprints)
+	rg.POST("/:id/sprints", r.TeamPostSprint)
+	rg.GET("/:id/sprints/:sprint_id", r.TeamGetSprint)
+	rg.PATCH("/:id/sprints/:sprint_id", r.TeamPatchSprint)
+	rg.DELETE("/:id/sprints/:sprint_id", r.TeamDeleteSprint)
+	rg.POST("/:id/sprints/:sprint_id/start", r.TeamStartSprint)
+	rg.POST("/:id/sprints/:sprint_id/close", r.TeamCloseSprint)
+
+	// Milestone routes
+	rg.GET("/:id/milestones", r.TeamGetMilestones)
+	rg.POST("/:id/milestones", r.TeamPostMilestone)
+	rg.GET("/:id/milestones/:milestone_id", r.TeamGetMilestone)
+	rg.PATCH("/:id/milestones/:milestone_id", r.TeamPatchMilestone)
+	rg.DELETE("/:id/milestones/:milestone_id", r.TeamDeleteMilestone)
+	rg.POST("/:id/milestones/:milestone_id/complete", r.TeamCompleteMilestone)
+	rg.POST("/:id/milestones/:milestone_id/reopen", r.TeamReopenMilestone)
+
+	// Time tracking routes
+	rg.POST("/:id/tasks/:task_id/timer/start", r.TeamStartTimer)
+	rg.GET("/:id/tasks/:task_id/time-entries", r.TeamGetTaskTimeEntries)
+	rg.POST("/:id/tasks/:task_id/time-entries", r.TeamPostTimeEntry)
+	rg.PATCH("/:id/time-entries/:entry_id", r.TeamPatchTimeEntry)
+	rg.DELETE("/:id/time-entries/:entry_id", r.TeamDeleteTimeEntry)
+	rg.GET("/:id/timesheet", r.TeamGetTimesheet)
+
+	// Estimation routes
+	rg.GET("/:id/estimation", r.TeamGetEstimation)
+	rg.PUT("/:id/estimation", r.TeamPutEstimation)
+	rg.PUT("/:id/members/:user_id/capacity", r.TeamPutMemberCapacity)
+	rg.GET("/:id/workload", r.TeamGetWorkload)
+
+	// Activity routes
+	rg.GET("/:id/tasks/:task_id/activity", r.TeamGetTaskActivity)
+
+	// Recurrence routes
+	rg.GET("/:id/tasks/:task_id/recurrence", r.TeamGetTaskRecurrence)
+	rg.PUT("/:id/tasks/:task_id/recurrence", r.TeamPutTaskRecurrence)
+	rg.DELETE("/:id/tasks/:task_id/recurrence", r.TeamDeleteTaskRecurrence)
+
+	// Reminder routes
+	rg.GET("/:id/tasks/:task_id/reminders", r.TeamGetTaskReminders)
+	rg.PUT("/:id/tasks/:task_id/reminders", r.TeamPutTaskReminders)
+
+	// Task links routes
+	rg.GET("/:id/tasks/:task_id/links", r.TeamGetTaskLinks)
+	rg.POST("/:id/tasks/:task_id/links", r.TeamPostTaskLink)
+	rg.DELETE("/:id/tasks/:task_id/links/:link_id", r.TeamDeleteTaskLink)
+	rg.GET("/:id/dependencies", r.TeamGetDependencies)
+
+	// Comments routes
+	rg.GET("/:id/tasks/:task_id/comments", r.TeamGetComments)
+	rg.POST("/:id/tasks/:task_id/comments", r.TeamPostComment)
+	rg.PATCH("/:id/tasks/:task_id/comments/:comment_id", r.TeamPatchComment)
+	rg.DELETE("/:id/tasks/:task_id/comments/:comment_id", r.TeamDeleteComment)
+	rg.GET("/:id/tasks/:task_id/comments/:comment_id/history", r.TeamGetCommentHistory)
+
+	// Attachments routes
+	rg.GET("/:id/tasks/:task_id/attachments", r.TeamGetAttachments)
+	rg.POST("/:id/tasks/:task_id/attachments", r.TeamPostAttachment)
+	rg.GET("/:id/tasks/:task_id/attachments/:attachment_id/download", r.TeamDownloadAttachment)
+	rg.DELETE("/:id/tasks/:task_id/attachments/:attachment_id", r.TeamDeleteAttachment)
+
+	// Checklist routes
+	rg.GET("/:id/tasks/:task_id/checklist", r.TeamGetChecklist)
+	rg.POST("/:id/tasks/:task_id/checklist", r.TeamPostChecklistItem)
+	rg.PUT("/:id/tasks/:task_id/checklist/order", r.TeamReorderChecklist)
+	rg.PATCH("/:id/tasks/:task_id/checklist/:item_id", r.TeamPatchChecklistItem)
+	rg.DELETE("/:id/tasks/:task_id/checklist/:item_id", r.TeamDeleteChecklistItem)
+	rg.POST("/:id/tasks/:task_id/checklist/:item_id/convert", r.TeamConvertChecklistItem)
+
+	// Workflow routes
+	rg.GET("/:id/workflow", r.TeamGetWorkflow)
+	rg.PUT("/:id/workflow", r.TeamPutWorkflow)
+
 	// Invitations routes
-	// rg.GET("/invitations", r.TeamGetInvitations)
+	rg.GET("/invitations", r.TeamGetInvitations)
 	// rg.POST("/invitations", r.TeamInviteMember)
 	// rg.DELETE("/invitations", r.TeamInviteDelete)
 	// rg.POST("/invitations/accept", r.TeamInviteAccept)
@@ -0,0 +1,532 @@
+package team
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TeamGetSprints godoc
+// @Summary List the sprints of a team
+// @Description Sprints are ordered by start date and come with the current totals of their tasks. Closed sprints
+// @Description also hold the committed and completed snapshots used for velocity reports.
+// @Tags sprints
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param status query string false "Only sprints with this status: planned, active or closed"
+// @Success 200 {object} dto.SprintsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/sprints [get]
+func (r *TeamsHandler) TeamGetSprints(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	status := models.SprintStatus(strings.TrimSpace(c.Query("status")))
+	if status != "" && !status.IsValid() {
+		dto.BadRequest(dto.CodeInvalidRequest, "status must be planned, active or closed", nil).Send(c)
+		return
+	}
+	sprints, err := r.uow.Sprints().ListSprints(c.Request.Context(), teamID, status)
+	if err != nil {
+		dto.RepoError(err, "sprint").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, sprints)
+}
+
+// TeamPostSprint godoc
+// @Summary Create a sprint
+// @Description Only team admins and founders can manage sprints. New sprints are planned; with project_id the
+// @Description sprint only takes tasks of that project.
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body dto.SprintRequest true "Sprint"
+// @Success 201 {object} dto.SprintEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/sprints [post]
+func (r *TeamsHandler) TeamPostSprint(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	if role != models.AdminUserRole && role != models.FounderUserRole {
+		dto.Forbidden(dto.CodeForbidden, "only admin or founder can manage sprints", nil).Send(c)
+		return
+	}
+
+	req := dto.SprintRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.Goal = strings.TrimSpace(req.Goal)
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	sprint := &models.Sprint{
+		ID:        uuid.New(),
+		TeamID:    teamID,
+		ProjectID: req.ProjectID,
+		Name:      req.Name,
+		Goal:      req.Goal,
+		Status:    models.SprintPlanned,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Capacity:  req.Capacity,
+		CreatedBy: &userID,
+	}
+	if !checkSprintDates(c, sprint) {
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	if sprint.ProjectID != nil && !r.checkScopeProject(c, tx.Projects(), teamID, *sprint.ProjectID) {
+		return
+	}
+	if err := tx.Sprints().CreateSprint(c.Request.Context(), sprint); err != nil {
+		dto.RepoError(err, "sprint").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "sprint").Send(c)
+		return
+	}
+	trace.Log(c, "sprint_created", "team_id="+teamID.String()+" sprint_id="+sprint.ID.String())
+	dto.OK(c, http.StatusCreated, sprint)
+}
+
+// TeamGetSprint godoc
+// @Summary Get a sprint
+// @Tags sprints
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param sprint_id path string true "Sprint ID"
+// @Success 200 {object} dto.SprintEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/sprints/{sprint_id} [get]
+func (r *TeamsHandler) TeamGetSprint(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	sprint, ok := r.loadSprint(c, r.uow.Sprints(), teamID)
+	if !ok {
+		return
+	}
+	dto.OK(c, http.StatusOK, sprint)
+}
+
+// TeamPatchSprint godoc
+// @Summary Edit a sprint
+// @Description Only team admins and founders can manage sprints. Closed sprints cannot be edited.
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param sprint_id path string true "Sprint ID"
+// @Param request body dto.SprintUpdateRequest true "Fields to change"
+// @Success 200 {object} dto.SprintEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/sprints/{sprint_id} [patch]
+func (r *TeamsHandler) TeamPatchSprint(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage sprints")
+	if !ok {
+		return
+	}
+
+	req := dto.SprintUpdateRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if req.Name != nil {
+		*req.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Goal != nil {
+		*req.Goal = strings.TrimSpace(*req.Goal)
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	sprint, ok := r.loadSprint(c, tx.Sprints(), teamID)
+	if !ok {
+		return
+	}
+	if sprint.Status == models.SprintClosed {
+		dto.Conflict(dto.CodeSprintClosed, "the sprint is closed", map[string]any{"sprint_id": sprint.ID}).Send(c)
+		return
+	}
+	if req.Name != nil {
+		sprint.Name = *req.Name
+	}
+	if req.Goal != nil {
+		sprint.Goal = *req.Goal
+	}
+	if req.ClearProject {
+		sprint.ProjectID = nil
+	} else if req.ProjectID != nil {
+		if !r.checkScopeProject(c, tx.Projects(), teamID, *req.ProjectID) {
+			return
+		}
+		sprint.ProjectID = req.ProjectID
+	}
+	if req.StartDate != nil {
+		sprint.StartDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		sprint.EndDate = *req.EndDate
+	}
+	if req.ClearCapacity {
+		sprint.Capacity = nil
+	} else if req.Capacity != nil {
+		sprint.Capacity = req.Capacity
+	}
+	if !checkSprintDates(c, sprint) {
+		return
+	}
+	if !saveSprint(c, tx.Sprints(), sprint, sprint.Status) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "sprint").Send(c)
+		return
+	}
+	sprint.Scope.OverCapacity = sprint.Capacity != nil && sprint.Scope.Estimate > *sprint.Capacity
+	trace.Log(c, "sprint_updated", "team_id="+teamID.String()+" sprint_id="+sprint.ID.String())
+	dto.OK(c, http.StatusOK, sprint)
+}
+
+// TeamStartSprint godoc
+// @Summary Start a sprint
+// @Description Only team admins and founders can manage sprints. The sprint must be planned and the team must
+// @Description not have another active sprint. The tasks in the sprint and their estimates are recorded as committed.
+// @Tags sprints
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param sprint_id path string true "Sprint ID"
+// @Success 200 {object} dto.SprintEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/sprints/{sprint_id}/start [post]
+func (r *TeamsHandler) TeamStartSprint(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage sprints")
+	if !ok {
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	sprint, ok := r.loadSprint(c, tx.Sprints(), teamID)
+	if !ok {
+		return
+	}
+	if sprint.Status != models.SprintPlanned {
+		dto.Conflict(dto.CodeConflict, "only planned sprints can be started", map[string]any{"status": sprint.Status}).Send(c)
+		return
+	}
+	active, err := tx.Sprints().ListSprints(c.Request.Context(), teamID, models.SprintActive)
+	if err != nil {
+		dto.RepoError(err, "sprint").Send(c)
+		return
+	}
+	if len(active) > 0 {
+		dto.Conflict(dto.CodeSprintActive, "the team already has an active sprint", map[string]any{"sprint_id": active[0].ID}).Send(c)
+		return
+	}
+
+	now := time.Now()
+	sprint.Status = models.SprintActive
+	sprint.StartedAt = &now
+	sprint.Committed = &models.SprintTotals{Tasks: sprint.Scope.Tasks, Estimate: sprint.Scope.Estimate}
+	if !saveSprint(c, tx.Sprints(), sprint, models.SprintPlanned) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "sprint").Send(c)
+		return
+	}
+	trace.Log(c, "sprint_started", "team_id="+teamID.String()+" sprint_id="+sprint.ID.String())
+	dto.OK(c, http.StatusOK, sprint)
+}
+
+// TeamCloseSprint godoc
+// @Summary Close a sprint
+// @Description Only team admins and founders can manage sprints. The done tasks and their estimates are recorded
+// @Description as completed, then the unfinished tasks move to the target sprint, which must be planned, or back to
+// @Description the backlog. A project sprint can only hand its tasks to a team sprint or a sprint of the same project.
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param sprint_id path string true "Sprint ID"
+// @Param request body dto.SprintCloseRequest true "Where the unfinished tasks go"
+// @Success 200 {object} dto.SprintEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/sprints/{sprint_id}/close [post]
+func (r *TeamsHandler) TeamCloseSprint(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage sprints")
+	if !ok {
+		return
+	}
+
+	req := dto.SprintCloseRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	sprint, ok := r.loadSprint(c, tx.Sprints(), teamID)
+	if !ok {
+		return
+	}
+	if sprint.Status != models.SprintActive {
+		dto.Conflict(dto.CodeConflict, "only active sprints can be closed", map[string]any{"status": sprint.Status}).Send(c)
+		return
+	}
+	if req.TargetSprintID != nil {
+		target, err := tx.Sprints().GetSprint(c.Request.Context(), teamID, *req.TargetSprintID)
+		if errors.Is(err, repositories.ErrNotFound) {
+			dto.BadRequest(dto.CodeInvalidSprint, "unknown target sprint", map[string]any{"sprint_id": *req.TargetSprintID}).Send(c)
+			return
+		}
+		if err != nil {
+			dto.RepoError(err, "sprint").Send(c)
+			return
+		}
+		if target.Status != models.SprintPlanned {
+			dto.BadRequest(dto.CodeInvalidSprint, "unfinished tasks can only move to a planned sprint", map[string]any{"sprint_id": target.ID}).Send(c)
+			return
+		}
+		if target.ProjectID != nil && (sprint.ProjectID == nil || *target.ProjectID != *sprint.ProjectID) {
+			dto.BadRequest(dto.CodeInvalidSprint, "the target sprint belongs to another project", map[string]any{"sprint_id": target.ID}).Send(c)
+			return
+		}
+	}
+
+	carried, err := tx.Sprints().CarryOverTasks(c.Request.Context(), teamID, sprint.ID, req.TargetSprintID)
+	if err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	change := models.IDChange("sprint_id", &sprint.ID, req.TargetSprintID)
+	if !recordTasksActivity(c, tx, teamID, carried, models.ActivityTaskUpdated, []models.FieldChange{change}, nil) {
+		return
+	}
+	carriedOver := len(carried)
+	now := time.Now()
+	sprint.Status = models.SprintClosed
+	sprint.ClosedAt = &now
+	done := sprint.Scope.Done
+	sprint.Completed = &done
+	sprint.CarriedOver = &carriedOver
+	if !saveSprint(c, tx.Sprints(), sprint, models.SprintActive) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "sprint").Send(c)
+		return
+	}
+	sprint.Scope = models.SprintScope{SprintTotals: done, Done: done}
+	sprint.Scope.OverCapacity = sprint.Capacity != nil && done.Estimate > *sprint.Capacity
+	target := "backlog"
+	if req.TargetSprintID != nil {
+		target = req.TargetSprintID.String()
+	}
+	trace.Log(c, "sprint_closed", "team_id="+teamID.String()+" sprint_id="+sprint.ID.String()+" carried_over="+strconv.Itoa(carriedOver)+" target="+target)
+	dto.OK(c, http.StatusOK, sprint)
+}
+
+// TeamDeleteSprint godoc
+// @Summary Delete a sprint
+// @Description Only team admins and founders can manage sprints. Active sprints must be closed first; the tasks of
+// @Description the sprint go back to the backlog.
+// @Tags sprints
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param sprint_id path string true "Sprint ID"
+// @Success 204
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/sprints/{sprint_id} [delete]
+func (r *TeamsHandler) TeamDeleteSprint(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage sprints")
+	if !ok {
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	sprint, ok := r.loadSprint(c, tx.Sprints(), teamID)
+	if !ok {
+		return
+	}
+	if sprint.Status == models.SprintActive {
+		dto.Conflict(dto.CodeSprintActive, "close the sprint before deleting it", map[string]any{"sprint_id": sprint.ID}).Send(c)
+		return
+	}
+	taskIDs, err := tx.Sprints().DeleteSprint(c.Request.Context(), teamID, sprint.ID)
+	if err != nil {
+		dto.RepoError(err, "sprint").Send(c)
+		return
+	}
+	change := models.IDChange("sprint_id", &sprint.ID, nil)
+	if !recordTasksActivity(c, tx, teamID, taskIDs, models.ActivityTaskUpdated, []models.FieldChange{change}, nil) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "sprint").Send(c)
+		return
+	}
+	trace.Log(c, "sprint_deleted", "team_id="+teamID.String()+" sprint_id="+sprint.ID.String())
+	c.Status(http.StatusNoContent)
+}
+
+func (r *TeamsHandler) loadSprint(c *gin.Context, sprints repositories.SprintRepository, teamID uuid.UUID) (*models.Sprint, bool) {
+	sprintID, err := uuid.Parse(c.Param("sprint_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid sprint id", nil).Send(c)
+		return nil, false
+	}
+	sprint, err := sprints.GetSprint(c.Request.Context(), teamID, sprintID)
+	if err != nil {
+		dto.RepoError(err, "sprint").Send(c)
+		return nil, false
+	}
+	return sprint, true
+}
+
+// saveSprint saves the sprint if its stored status is still status, so that concurrent
+// starts and closes cannot both succeed. It sends the error response and returns false
+// on failure.
+func saveSprint(c *gin.Context, sprints repositories.SprintRepository, sp *models.Sprint, status models.SprintStatus) bool {
+	err := sprints.UpdateSprint(c.Request.Context(), sp, status)
+	if errors.Is(err, repositories.ErrNotFound) {
+		dto.Conflict(dto.CodeConflict, "the sprint was changed concurrently, retry", nil).Send(c)
+		return false
+	}
+	if err != nil {
+		dto.RepoError(err, "sprint").Send(c)
+		return false
+	}
+	return true
+}
+
+func checkSprintDates(c *gin.Context, sp *models.Sprint) bool {
+	if sp.EndDate.Before(sp.StartDate) {
+		dto.BadRequest(dto.CodeInvalidSprint, "end_date is before start_date", nil).Send(c)
+		return false
+	}
+	return true
+}
+
+// checkScopeProject makes sure a sprint or a milestone can be scoped to the project: it
+// belongs to the team and is not archived.
+func (r *TeamsHandler) checkScopeProject(c *gin.Context, projects repositories.ProjectRepository, teamID uuid.UUID, projectID uuid.UUID) bool {
+	project, err := projects.GetProject(c.Request.Context(), teamID, projectID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		dto.BadRequest(dto.CodeInvalidProject, "unknown project", map[string]any{"project_id": projectID}).Send(c)
+		return false
+	}
+	if err != nil {
+		dto.RepoError(err, "project").Send(c)
+		return false
+	}
+	return checkNotArchived(c, project)
+}
+
+// checkTaskSprint makes sure a task of project projectID can be added to sprint
+// sprintID: the sprint belongs to the team, is not closed and, for project sprints, is
+// a sprint of the same project. It sends the error response and returns false otherwise.
+func (r *TeamsHandler) checkTaskSprint(c *gin.Context, sprints repositories.SprintRepository, teamID uuid.UUID, sprintID uuid.UUID, projectID *uuid.UUID) bool {
+	sprint, err := sprints.GetSprint(c.Request.Context(), teamID, sprintID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		dto.BadRequest(dto.CodeInvalidSprint, "unknown sprint", map[string]any{"sprint_id": sprintID}).Send(c)
+		return false
+	}
+	if err != nil {
+		dto.RepoError(err, "sprint").Send(c)
+		return false
+	}
+	if sprint.Status == models.SprintClosed {
+		dto.Conflict(dto.CodeSprintClosed, "the sprint is closed", map[string]any{"sprint_id": sprintID}).Send(c)
+		return false
+	}
+	if sprint.ProjectID != nil && (projectID == nil || *projectID != *sprint.ProjectID) {
+		dto.BadRequest(dto.CodeInvalidSprint, "the sprint only takes tasks of its project", map[string]any{"sprint_id": sprintID, "project_id": *sprint.ProjectID}).Send(c)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package team_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSprints_SQLite(t *testing.T) {
+	f := newFixture(t)
+	sprintsPath := "/api/v1/team/" + f.teamID.String() + "/sprints"
+	start := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 14)
+	capacity := 5.0
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, sprintsPath, dto.SprintRequest{Name: "Sprint 1", StartDate: start, EndDate: end}, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, sprintsPath, dto.SprintRequest{Name: "Sprint 1", StartDate: end, EndDate: start}, f.founder)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Equal(t, dto.CodeInvalidSprint, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+
+	createSprint := func(req dto.SprintRequest) models.Sprint {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, sprintsPath, req, f.founder)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.SprintEnvelope](t, rr).Data
+	}
+	first := createSprint(dto.SprintRequest{Name: "Sprint 1", StartDate: start, EndDate: end, Capacity: &capacity})
+	second := createSprint(dto.SprintRequest{Name: "Sprint 2", StartDate: end, EndDate: end.AddDate(0, 0, 14)})
+	require.Equal(t, models.SprintPlanned, first.Status)
+	firstPath := sprintsPath + "/" + first.ID.String()
+	secondPath := sprintsPath + "/" + second.ID.String()
+
+	createTask := func(req dto.TaskCreationRequest) *httptest.ResponseRecorder {
+		t.Helper()
+		return testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), req, f.member)
+	}
+	taskID := func(rr *httptest.ResponseRecorder) uuid.UUID {
+		t.Helper()
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.ID
+	}
+	three, four := 3.0, 4.0
+	a := taskID(createTask(dto.TaskCreationRequest{Title: "Login form", Estimate: &three, SprintID: &first.ID}))
+	b := taskID(createTask(dto.TaskCreationRequest{Title: "Password reset", Estimate: &four, SprintID: &first.ID}))
+	c := taskID(createTask(dto.TaskCreationRequest{Title: "Copy review", SprintID: &first.ID}))
+	require.Equal(t, http.StatusBadRequest, createTask(dto.TaskCreationRequest{Title: "Lost", SprintID: &[]uuid.UUID{uuid.New()}[0]}).Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, firstPath, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	scope := testutil.DecodeJSON[dto.SprintEnvelope](t, rr).Data.Scope
+	require.Equal(t, 3, scope.Tasks)
+	require.Equal(t, 7.0, scope.Estimate)
+	require.True(t, scope.OverCapacity)
+
+	// Starting records the commitment; a team has one active sprint at a time.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, firstPath+"/start", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	started := testutil.DecodeJSON[dto.SprintEnvelope](t, rr).Data
+	require.Equal(t, models.SprintActive, started.Status)
+	require.Equal(t, &models.SprintTotals{Tasks: 3, Estimate: 7}, started.Committed)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, secondPath+"/start", nil, f.founder)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.Equal(t, dto.CodeSprintActive, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, firstPath, nil, f.founder)
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/team/"+f.teamID.String()+"/workflow", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var doneState uuid.UUID
+	for _, s := range testutil.DecodeJSON[dto.WorkflowEnvelope](t, rr).Data.States {
+		if s.Category == models.CategoryDone {
+			doneState = s.ID
+		}
+	}
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath()+"/"+a.String()+"/state", dto.TaskStateRequest{StateID: doneState}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	// Closing records what was completed and carries the rest over.
+	tests := []struct {
+		name   string
+		target uuid.UUID
+	}{
+		{"unknown target", uuid.New()},
+		{"target is the sprint itself", first.ID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := testutil.DoJSON(t, f.r, http.MethodPost, firstPath+"/close", dto.SprintCloseRequest{TargetSprintID: &tt.target}, f.founder)
+			require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+		})
+	}
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, firstPath+"/close", dto.SprintCloseRequest{TargetSprintID: &second.ID}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	closed := testutil.DecodeJSON[dto.SprintEnvelope](t, rr).Data
+	require.Equal(t, models.SprintClosed, closed.Status)
+	require.Equal(t, &models.SprintTotals{Tasks: 1, Estimate: 3}, closed.Completed)
+	require.Equal(t, 2, *closed.CarriedOver)
+	require.Contains(t, f.fieldChanges(t, b, "sprint_id"), models.FieldChange{Field: "sprint_id", Old: first.ID.String(), New: second.ID.String()})
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, firstPath+"/close", dto.SprintCloseRequest{}, f.founder)
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?sprint_id="+second.ID.String(), nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	ids := []uuid.UUID{}
+	for _, task := range testutil.DecodeJSON[dto.TasksEnvelope](t, rr).Data {
+		ids = append(ids, task.ID)
+	}
+	require.ElementsMatch(t, []uuid.UUID{b, c}, ids)
+
+	// Closed sprints are history: no new tasks and no edits.
+	name := "Sprint one"
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, firstPath, dto.SprintUpdateRequest{Name: &name}, f.founder)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, f.tasksPath()+"/"+b.String(), dto.TaskUpdateRequest{SprintID: &first.ID}, f.member)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.Equal(t, dto.CodeSprintClosed, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, sprintsPath+"?status=closed", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, testutil.DecodeJSON[dto.SprintsEnvelope](t, rr).Data, 1)
+
+	// Without a target the unfinished tasks go back to the backlog.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, secondPath+"/start", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, secondPath+"/close", dto.SprintCloseRequest{}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, 2, *testutil.DecodeJSON[dto.SprintEnvelope](t, rr).Data.CarriedOver)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?sprint_id[isnull]=true", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Len(t, testutil.DecodeJSON[dto.TasksEnvelope](t, rr).Data, 2)
+
+	// Project sprints only take tasks of their project.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, "/api/v1/team/"+f.teamID.String()+"/projects", dto.ProjectRequest{Name: "Mobile"}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	project := testutil.DecodeJSON[dto.ProjectEnvelope](t, rr).Data
+	mobile := createSprint(dto.SprintRequest{Name: "Mobile 1", ProjectID: &project.ID, StartDate: start, EndDate: end})
+	require.Equal(t, http.StatusBadRequest, createTask(dto.TaskCreationRequest{Title: "Push", SprintID: &mobile.ID}).Code)
+	push := taskID(createTask(dto.TaskCreationRequest{Title: "Push", ProjectID: &project.ID, SprintID: &mobile.ID}))
+
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, sprintsPath+"/"+mobile.ID.String(), nil, f.founder)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?sprint_id[isnull]=true", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, testutil.DecodeJSON[dto.TasksEnvelope](t, rr).Data, 3)
+	require.Equal(t, []models.FieldChange{{Field: "sprint_id", Old: mobile.ID.String(), New: nil}}, f.fieldChanges(t, push, "sprint_id"))
+}
@@ -0,0 +1,556 @@
+package team
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"task_manager/public/dto"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TeamGetSubtasks godoc
+// @Summary List the subtasks of a task
+// @Description Direct children of the task, each with its own progress when it has subtasks.
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} dto.TasksEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/subtasks [get]
+func (r *TeamsHandler) TeamGetSubtasks(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	subtasks, err := r.uow.Tasks().ListSubtasks(c.Request.Context(), teamID, task.ID)
+	if err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	nodes, err := r.uow.Tasks().GetTaskSubtree(c.Request.Context(), teamID, task.ID)
+	if err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	for _, s := range subtasks {
+		s.Progress = models.SubtaskProgress(nodes, s.ID)
+	}
+	dto.OK(c, http.StatusOK, subtasks)
+}
+
+// TeamMoveTask godoc
+// @Summary Move a task under another parent
+// @Description Moves the task with its subtasks under another task of the same team, or to the top level
+// @Description with a null parent_id. Moves that would create a cycle or exceed the depth limit are refused.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.TaskMoveRequest true "New parent"
+// @Success 200 {object} dto.TeamsTaskEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/move [post]
+func (r *TeamsHandler) TeamMoveTask(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.TaskMoveRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	task, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	if req.ParentID != nil {
+		nodes, err := tx.Tasks().GetTaskSubtree(c.Request.Context(), teamID, task.ID)
+		if err != nil {
+			dto.RepoError(err, "task").Send(c)
+			return
+		}
+		if !r.checkParent(c, tx.Tasks(), teamID, *req.ParentID, task.ID, models.SubtreeHeight(nodes)) {
+			return
+		}
+	}
+	if err := tx.Tasks().SetTaskParent(c.Request.Context(), teamID, task.ID, req.ParentID); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	before := *task
+	task.ParentID = req.ParentID
+	if changes := models.TaskChanges(&before, task); len(changes) > 0 {
+		if !recordActivity(c, tx, task, models.ActivityTaskUpdated, changes, nil) {
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	parent := "none"
+	if req.ParentID != nil {
+		parent = req.ParentID.String()
+	}
+	trace.Log(c, "task_moved", "task_id="+task.ID.String()+" parent_id="+parent)
+
+	dto.OK(c, http.StatusOK, task)
+}
+
+// checkParent validates placing a subtree of the given height under parentID. taskID is
+// the root of the moved subtree, or uuid.Nil for a new task. It sends the error response
+// and returns false when the parent is not acceptable.
+func (r *TeamsHandler) checkParent(c *gin.Context, tasks repositories.TaskRepository, teamID uuid.UUID, parentID uuid.UUID, taskID uuid.UUID, height int) bool {
+	if parentID == taskID {
+		dto.Conflict(dto.CodeSubtaskCycle, "a task cannot be its own parent", nil).Send(c)
+		return false
+	}
+	if _, err := tasks.GetTaskByID(c.Request.Context(), teamID, parentID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			dto.BadRequest(dto.CodeInvalidRequest, "parent task not found in this team", nil).Send(c)
+			return false
+		}
+		dto.RepoError(err, "task").Send(c)
+		return false
+	}
+	ancestors, err := tasks.GetTaskAncestorIDs(c.Request.Context(), teamID, parentID)
+	if err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return false
+	}
+	if taskID != uuid.Nil && slices.Contains(ancestors, taskID) {
+		dto.Conflict(dto.CodeSubtaskCycle, "a task cannot be moved under its own subtask", nil).Send(c)
+		return false
+	}
+	// The parent sits at depth len(ancestors); the moved task goes one level below it.
+	if len(ancestors)+1+height > r.taskMaxDepth {
+		dto.Conflict(dto.CodeSubtaskDepthExceeded, "subtasks can only be nested "+strconv.Itoa(r.taskMaxDepth)+" levels deep", map[string]any{"max_depth": r.taskMaxDepth}).Send(c)
+		return false
+	}
+	return true
+}
+
+// withProgress fills the progress of task from its subtree.
+func (r *TeamsHandler) withProgress(c *gin.Context, tasks repositories.TaskRepository, task *models.Task) bool {
+	nodes, err := tasks.GetTaskSubtree(c.Request.Context(), task.TeamID, task.ID)
+	if err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return false
+	}
+	task.Progress = models.SubtaskProgress(nodes, task.ID)
+	return true
+}
+
+// TeamGetChecklist godoc
+// @Summary List the checklist of a task
+// @Tags checklists
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} dto.ChecklistEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/checklist [get]
+func (r *TeamsHandler) TeamGetChecklist(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	items, err := r.uow.Checklists().ListChecklistItems(c.Request.Context(), task.ID)
+	if err != nil {
+		dto.RepoError(err, "checklist item").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, items)
+}
+
+// TeamPostChecklistItem godoc
+// @Summary Add a checklist item
+// @Description The item is added at the end of the checklist.
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.ChecklistItemRequest true "Checklist item"
+// @Success 201 {object} dto.ChecklistItemEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/checklist [post]
+func (r *TeamsHandler) TeamPostChecklistItem(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	req := dto.ChecklistItemRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	item := &models.ChecklistItem{
+		ID:      uuid.New(),
+		TaskID:  task.ID,
+		Content: req.Content,
+	}
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+	if err := tx.Checklists().CreateChecklistItem(c.Request.Context(), item); err != nil {
+		dto.RepoError(err, "checklist item").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "checklist item").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusCreated, item)
+}
+
+// TeamPatchChecklistItem godoc
+// @Summary Update a checklist item
+// @Description Only the fields present in the body are changed.
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param item_id path string true "Checklist item ID"
+// @Param request body dto.ChecklistItemUpdateRequest true "Checklist item update"
+// @Success 200 {object} dto.ChecklistItemEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/checklist/{item_id} [patch]
+func (r *TeamsHandler) TeamPatchChecklistItem(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	req := dto.ChecklistItemUpdateRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	item, ok := r.loadChecklistItem(c, r.uow.Checklists(), task.ID)
+	if !ok {
+		return
+	}
+	if req.Content != nil {
+		item.Content = *req.Content
+	}
+	if req.Done != nil {
+		item.Done = *req.Done
+	}
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+	if err := tx.Checklists().UpdateChecklistItem(c.Request.Context(), item); err != nil {
+		dto.RepoError(err, "checklist item").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "checklist item").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, item)
+}
+
+// TeamDeleteChecklistItem godoc
+// @Summary Delete a checklist item
+// @Tags checklists
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param item_id path string true "Checklist item ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/checklist/{item_id} [delete]
+func (r *TeamsHandler) TeamDeleteChecklistItem(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid checklist item id", nil).Send(c)
+		return
+	}
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+	if err := tx.Checklists().DeleteChecklistItem(c.Request.Context(), task.ID, itemID); err != nil {
+		dto.RepoError(err, "checklist item").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "checklist item").Send(c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// TeamReorderChecklist godoc
+// @Summary Reorder a checklist
+// @Description item_ids must list every item of the checklist exactly once, in the new order.
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.ChecklistOrderRequest true "New order"
+// @Success 200 {object} dto.ChecklistEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/checklist/order [put]
+func (r *TeamsHandler) TeamReorderChecklist(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	req := dto.ChecklistOrderRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	task, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	items, err := tx.Checklists().ListChecklistItems(c.Request.Context(), task.ID)
+	if err != nil {
+		dto.RepoError(err, "checklist item").Send(c)
+		return
+	}
+	if !samePermutation(items, req.ItemIDs) {
+		dto.BadRequest(dto.CodeInvalidRequest, "item_ids must list every checklist item exactly once", nil).Send(c)
+		return
+	}
+	if err := tx.Checklists().ReorderChecklistItems(c.Request.Context(), task.ID, req.ItemIDs); err != nil {
+		dto.RepoError(err, "checklist item").Send(c)
+		return
+	}
+	items, err = tx.Checklists().ListChecklistItems(c.Request.Context(), task.ID)
+	if err != nil {
+		dto.RepoError(err, "checklist item").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "checklist item").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, items)
+}
+
+func samePermutation(items []*models.ChecklistItem, ids []uuid.UUID) bool {
+	if len(items) != len(ids) {
+		return false
+	}
+	want := make(map[uuid.UUID]bool, len(items))
+	for _, it := range items {
+		want[it.ID] = true
+	}
+	for _, id := range ids {
+		if !want[id] {
+			return false
+		}
+		delete(want, id)
+	}
+	return true
+}
+
+// TeamConvertChecklistItem godoc
+// @Summary Convert a checklist item into a subtask
+// @Description Creates a subtask titled after the item and removes the item from the checklist.
+// @Description The subtask has no custom field values, even for required fields.
+// @Tags checklists
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param item_id path string true "Checklist item ID"
+// @Success 201 {object} dto.TeamsTaskEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/checklist/{item_id}/convert [post]
+func (r *TeamsHandler) TeamConvertChecklistItem(c *gin.Context) {
+	teamID, userID, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	parent, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	item, ok := r.loadChecklistItem(c, tx.Checklists(), parent.ID)
+	if !ok {
+		return
+	}
+	if !r.checkParent(c, tx.Tasks(), teamID, parent.ID, uuid.Nil, 0) {
+		return
+	}
+	w, err := tx.Workflows().GetWorkflow(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "workflow").Send(c)
+		return
+	}
+
+	title := item.Content
+	if len(title) > 200 {
+		title = title[:200]
+	}
+	task := &models.Task{
+		ID:           uuid.New(),
+		TeamID:       teamID,
+		Title:        title,
+		Description:  item.Content,
+		Grouped:      parent.Grouped,
+		ParentID:     &parent.ID,
+		ProjectID:    parent.ProjectID,
+		CreatedBy:    &userID,
+		AssigneeIDs:  []uuid.UUID{},
+		CustomFields: map[string]any{},
+	}
+	if item.Done {
+		for i := range w.States {
+			if w.States[i].Category == models.CategoryDone {
+				task.StateID = &w.States[i].ID
+				break
+			}
+		}
+	} else if initial := w.InitialState(); initial != nil {
+		task.StateID = &initial.ID
+	}
+	if err := tx.Tasks().CreateTask(c.Request.Context(), task); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	if err := tx.Checklists().DeleteChecklistItem(c.Request.Context(), parent.ID, item.ID); err != nil {
+		dto.RepoError(err, "checklist item").Send(c)
+		return
+	}
+	if !recordActivity(c, tx, task, models.ActivityTaskCreated, models.TaskCreationChanges(task), map[string]any{"checklist_item_id": item.ID}) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	trace.Log(c, "checklist_item_converted", "item_id="+item.ID.String()+" task_id="+task.ID.String())
+
+	dto.OK(c, http.StatusCreated, task)
+}
+
+// loadChecklistItem resolves the :item_id parameter within the task.
+// It sends the error response and returns ok=false on failure.
+func (r *TeamsHandler) loadChecklistItem(c *gin.Context, items repositories.ChecklistRepository, taskID uuid.UUID) (*models.ChecklistItem, bool) {
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid checklist item id", nil).Send(c)
+		return nil, false
+	}
+	item, err := items.GetChecklistItem(c.Request.Context(), taskID, itemID)
+	if err != nil {
+		dto.RepoError(err, "checklist item").Send(c)
+		return nil, false
+	}
+	return item, true
+}
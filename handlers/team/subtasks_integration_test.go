@@ -0,0 +1,138 @@
+package team_test
+
+import (
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubtasks_SQLite(t *testing.T) {
+	f := newFixture(t)
+
+	create := func(title string, parentID *uuid.UUID) uuid.UUID {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: title, ParentID: parentID}, f.founder)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.ID
+	}
+
+	// Depth limit defaults to 3: root (0) -> a (1) -> b (2) -> c (3).
+	root := create("root", nil)
+	a := create("a", &root)
+	b := create("b", &a)
+	c := create("c", &b)
+	other := create("other", nil)
+	otherChild := create("other child", &other)
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "too deep", ParentID: &c}, f.founder)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.Equal(t, dto.CodeSubtaskDepthExceeded, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+
+	unknown := uuid.New()
+	tests := []struct {
+		name       string
+		task       uuid.UUID
+		parentID   *uuid.UUID
+		wantStatus int
+		wantCode   dto.ErrorCode
+	}{
+		{"own parent", a, &a, http.StatusConflict, dto.CodeSubtaskCycle},
+		{"under own descendant", a, &c, http.StatusConflict, dto.CodeSubtaskCycle},
+		{"unknown parent", a, &unknown, http.StatusBadRequest, dto.CodeInvalidRequest},
+		{"subtree too deep", a, &otherChild, http.StatusConflict, dto.CodeSubtaskDepthExceeded},
+		{"leaf under other", c, &other, http.StatusOK, ""},
+		{"back to top level", c, nil, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := f.tasksPath() + "/" + tt.task.String() + "/move"
+			rr := testutil.DoJSON(t, f.r, http.MethodPost, path, dto.TaskMoveRequest{ParentID: tt.parentID}, f.member)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantCode != "" {
+				require.Equal(t, tt.wantCode, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+				return
+			}
+			require.Equal(t, tt.parentID, testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.ParentID)
+		})
+	}
+
+	// Progress: root has a single child a, whose only remaining child b is not done.
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+root.String(), nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	progress := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.Progress
+	require.NotNil(t, progress)
+	require.Equal(t, 0, *progress)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/team/"+f.teamID.String()+"/workflow", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var done uuid.UUID
+	for _, s := range testutil.DecodeJSON[dto.WorkflowEnvelope](t, rr).Data.States {
+		if s.Category == models.CategoryDone {
+			done = s.ID
+		}
+	}
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath()+"/"+b.String()+"/state", dto.TaskStateRequest{StateID: done}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+root.String()+"/subtasks", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	subtasks := testutil.DecodeJSON[dto.TasksEnvelope](t, rr).Data
+	require.Len(t, subtasks, 1)
+	require.Equal(t, a, subtasks[0].ID)
+	require.NotNil(t, subtasks[0].Progress)
+	require.Equal(t, 100, *subtasks[0].Progress)
+}
+
+func TestChecklist_SQLite(t *testing.T) {
+	f := newFixture(t)
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Release"}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	task := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data
+	checklist := f.tasksPath() + "/" + task.ID.String() + "/checklist"
+
+	var ids []uuid.UUID
+	for _, content := range []string{"tag", "build", "announce"} {
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, checklist, dto.ChecklistItemRequest{Content: content}, f.member)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		item := testutil.DecodeJSON[dto.ChecklistItemEnvelope](t, rr).Data
+		require.Equal(t, len(ids), item.Position)
+		ids = append(ids, item.ID)
+	}
+
+	done := true
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, checklist+"/"+ids[0].String(), dto.ChecklistItemUpdateRequest{Done: &done}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.True(t, testutil.DecodeJSON[dto.ChecklistItemEnvelope](t, rr).Data.Done)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, checklist+"/order", dto.ChecklistOrderRequest{ItemIDs: ids[:2]}, f.member)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, checklist+"/order", dto.ChecklistOrderRequest{ItemIDs: []uuid.UUID{ids[2], ids[0], ids[1]}}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var order []uuid.UUID
+	for _, it := range testutil.DecodeJSON[dto.ChecklistEnvelope](t, rr).Data {
+		order = append(order, it.ID)
+	}
+	require.Equal(t, []uuid.UUID{ids[2], ids[0], ids[1]}, order)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, checklist+"/"+ids[2].String()+"/convert", nil, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	sub := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data
+	require.Equal(t, "announce", sub.Title)
+	require.Equal(t, &task.ID, sub.ParentID)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, checklist, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, testutil.DecodeJSON[dto.ChecklistEnvelope](t, rr).Data, 2)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, checklist+"/"+ids[1].String(), nil, f.member)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, checklist+"/"+ids[1].String(), nil, f.member)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,110 @@
+package team
+
+import (
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TeamAddTaskAssignees godoc
+// @Summary Assign users to a task
+// @Description Users that are already assigned are skipped. Every user must be a member of the team.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.TaskAssigneesRequest true "Users to assign"
+// @Success 200 {object} dto.TeamsTaskEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/assignees [post]
+func (r *TeamsHandler) TeamAddTaskAssignees(c *gin.Context) {
+	r.changeAssignees(c, true)
+}
+
+// TeamRemoveTaskAssignees godoc
+// @Summary Unassign users from a task
+// @Description Users that are not assigned are ignored.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.TaskAssigneesRequest true "Users to unassign"
+// @Success 200 {object} dto.TeamsTaskEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/assignees [delete]
+func (r *TeamsHandler) TeamRemoveTaskAssignees(c *gin.Context) {
+	r.changeAssignees(c, false)
+}
+
+func (r *TeamsHandler) changeAssignees(c *gin.Context, add bool) {
+	teamID, userID, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.TaskAssigneesRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	task, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	var err error
+	if add {
+		assignees, ok := r.checkAssignees(c, tx.Teams(), teamID, req.UserIDs)
+		if !ok {
+			return
+		}
+		err = tx.Tasks().AddTaskAssignees(c.Request.Context(), task.ID, assignees, &userID)
+	} else {
+		// Former members can still be unassigned, so membership is not checked here.
+		err = tx.Tasks().RemoveTaskAssignees(c.Request.Context(), task.ID, req.UserIDs)
+	}
+	if err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+
+	before := task.AssigneeIDs
+	task, ok = r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	if change, changed := models.IDsChange("assignee_ids", before, task.AssigneeIDs); changed {
+		if !recordActivity(c, tx, task, models.ActivityAssigneesChanged, []models.FieldChange{change}, nil) {
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, task)
+}
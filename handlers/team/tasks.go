@@ -0,0 +1,452 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/listspec"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TeamGetTasks godoc
+// @Summary List team tasks
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from meta.next_cursor of the previous page"
+// @Param sort query string false "Comma separated, prefix with - for descending: title, due_at, created_at, updated_at"
+// @Param include_total query bool false "Include meta.total"
+// @Param title query string false "Filter by exact title; also title[contains]"
+// @Param group_task query bool false "Filter grouped or individual tasks"
+// @Param state_id query string false "Filter by workflow state; also state_id[in]"
+// @Param project_id query string false "Filter by project; also project_id[in], project_id[isnull]"
+// @Param sprint_id query string false "Filter by sprint; sprint_id[isnull]=true lists the backlog"
+// @Param milestone_id query string false "Filter by milestone; also milestone_id[in], milestone_id[isnull]"
+// @Param label_id[any] query string false "Comma separated label ids; tasks with at least one of them"
+// @Param label_id[all] query string false "Comma separated label ids; tasks with every one of them"
+// @Param due_at[lt] query string false "Due before (RFC 3339); also due_at[isnull]"
+// @Param cf.{key} query string false "Filter by a custom field, e.g. cf.points[gte]=3 or cf.platform[any]=ios,web; custom fields other than multi-select can also be sorted by"
+// @Success 200 {object} dto.TasksEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks [get]
+func (r *TeamsHandler) TeamGetTasks(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	fields, err := r.uow.CustomFields().ListCustomFields(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return
+	}
+	q, err := listquery.Parse(c.Request.URL.Query(), listspec.TasksWithCustomFields(fields))
+	if err != nil {
+		dto.ListQueryError(err).Send(c)
+		return
+	}
+	tasks, err := r.uow.Tasks().ListTeamTasks(c.Request.Context(), teamID, q)
+	if err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	dto.OKPage(c, tasks)
+}
+
+// TeamPostTask godoc
+// @Summary Create a task
+// @Description Any team member can create tasks. Assignees must be members of the team and labels must belong to it.
+// @Description custom_fields holds values of the team's custom fields by key; required fields must be set.
+// @Description With parent_id the task is created as a subtask, within the nesting depth limit.
+// @Description With project_id the task is filed into a project, which must not be archived; restricted projects
+// @Description only take tasks from their members, their lead and team admins.
+// @Description With sprint_id the task is planned in a sprint that is not closed; project sprints only take tasks
+// @Description of their project. milestone_id attaches the task to a milestone that is not completed, under the
+// @Description same project rule.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body dto.TaskCreationRequest true "Task creation request"
+// @Success 201 {object} dto.TeamsTaskEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks [post]
+func (r *TeamsHandler) TeamPostTask(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.TaskCreationRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	req.Title = strings.TrimSpace(req.Title)
+
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	task := &models.Task{
+		ID:          uuid.New(),
+		TeamID:      teamID,
+		Title:       req.Title,
+		Description: req.Description,
+		Grouped:     req.GroupTask,
+		ParentID:    req.ParentID,
+		ProjectID:   req.ProjectID,
+		SprintID:    req.SprintID,
+		MilestoneID: req.MilestoneID,
+		CreatedBy:   &userID,
+		DueAt:       req.DueAt,
+		Estimate:    req.Estimate,
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	assignees, ok := r.checkAssignees(c, tx.Teams(), teamID, req.AssigneeIDs)
+	if !ok {
+		return
+	}
+	labels, ok := r.checkLabels(c, tx.Labels(), teamID, req.LabelIDs)
+	if !ok {
+		return
+	}
+	fieldValues, ok := r.checkCustomFields(c, tx, teamID, req.CustomFields, true)
+	if !ok {
+		return
+	}
+	if req.ParentID != nil && !r.checkParent(c, tx.Tasks(), teamID, *req.ParentID, uuid.Nil, 0) {
+		return
+	}
+	if req.ProjectID != nil {
+		if _, ok := r.checkTargetProject(c, tx.Projects(), teamID, *req.ProjectID, userID, role); !ok {
+			return
+		}
+	}
+	if req.SprintID != nil && !r.checkTaskSprint(c, tx.Sprints(), teamID, *req.SprintID, req.ProjectID) {
+		return
+	}
+	if req.MilestoneID != nil && !r.checkTaskMilestone(c, tx.Milestones(), teamID, *req.MilestoneID, req.ProjectID) {
+		return
+	}
+	w, err := tx.Workflows().GetWorkflow(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "workflow").Send(c)
+		return
+	}
+	if initial := w.InitialState(); initial != nil {
+		task.StateID = &initial.ID
+	}
+	if err := tx.Tasks().CreateTask(c.Request.Context(), task); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	if err := tx.Tasks().AddTaskAssignees(c.Request.Context(), task.ID, assignees, &userID); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	if err := tx.Labels().AddTaskLabels(c.Request.Context(), task.ID, labels); err != nil {
+		dto.RepoError(err, "label").Send(c)
+		return
+	}
+	if err := tx.CustomFields().SetTaskFieldValues(c.Request.Context(), task.ID, fieldValues); err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return
+	}
+	task.AssigneeIDs = assignees
+	task.LabelIDs = labels
+	task.CustomFields = map[string]any{}
+	for _, v := range fieldValues {
+		v.Apply(task.CustomFields)
+	}
+	if !recordActivity(c, tx, task, models.ActivityTaskCreated, models.TaskCreationChanges(task), nil) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	trace.Log(c, "task_created", "team_id="+teamID.String()+" task_id="+task.ID.String())
+
+	dto.OK(c, http.StatusCreated, task)
+}
+
+// TeamGetTask godoc
+// @Summary Get a task
+// @Tags tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} dto.TeamsTaskEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id} [get]
+func (r *TeamsHandler) TeamGetTask(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	if !r.withProgress(c, r.uow.Tasks(), task) {
+		return
+	}
+	dto.OK(c, http.StatusOK, task)
+}
+
+// TeamPatchTask godoc
+// @Summary Update a task
+// @Description Only the fields present in the body are changed. Moving the task into or out of a restricted
+// @Description project requires access to that project. Tasks can only be added to sprints that are not closed
+// @Description and to milestones that are not completed.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.TaskUpdateRequest true "Task update request"
+// @Success 200 {object} dto.TeamsTaskEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id} [patch]
+func (r *TeamsHandler) TeamPatchTask(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.TaskUpdateRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		req.Title = &title
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	task, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	before := *task
+	before.CustomFields = maps.Clone(task.CustomFields)
+	if req.Title != nil {
+		task.Title = *req.Title
+	}
+	if req.Description != nil {
+		task.Description = *req.Description
+	}
+	if req.GroupTask != nil {
+		task.Grouped = *req.GroupTask
+	}
+	if req.ClearDueAt {
+		task.DueAt = nil
+	} else if req.DueAt != nil {
+		task.DueAt = req.DueAt
+	}
+	if req.ClearEstimate {
+		task.Estimate = nil
+	} else if req.Estimate != nil {
+		task.Estimate = req.Estimate
+	}
+	if req.ClearProject || req.ProjectID != nil {
+		projectID := req.ProjectID
+		if req.ClearProject {
+			projectID = nil
+		}
+		if !r.checkTaskProject(c, tx.Projects(), task, projectID, userID, role) {
+			return
+		}
+		task.ProjectID = projectID
+	}
+	if req.ClearSprint {
+		task.SprintID = nil
+	} else if req.SprintID != nil {
+		if !r.checkTaskSprint(c, tx.Sprints(), teamID, *req.SprintID, task.ProjectID) {
+			return
+		}
+		task.SprintID = req.SprintID
+	}
+	if req.ClearMilestone {
+		task.MilestoneID = nil
+	} else if req.MilestoneID != nil {
+		if !r.checkTaskMilestone(c, tx.Milestones(), teamID, *req.MilestoneID, task.ProjectID) {
+			return
+		}
+		task.MilestoneID = req.MilestoneID
+	}
+	fieldValues, ok := r.checkCustomFields(c, tx, teamID, req.CustomFields, false)
+	if !ok {
+		return
+	}
+
+	if err := tx.Tasks().UpdateTask(c.Request.Context(), task); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	if err := tx.CustomFields().SetTaskFieldValues(c.Request.Context(), task.ID, fieldValues); err != nil {
+		dto.RepoError(err, "custom field").Send(c)
+		return
+	}
+	for _, v := range fieldValues {
+		v.Apply(task.CustomFields)
+	}
+	if changes := models.TaskChanges(&before, task); len(changes) > 0 {
+		if !recordActivity(c, tx, task, models.ActivityTaskUpdated, changes, nil) {
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, task)
+}
+
+// TeamDeleteTask godoc
+// @Summary Delete a task
+// @Description Only the creator of the task or a team admin/founder can delete it. The task goes to the trash with its
+// @Description subtasks and can be restored with them until the trash is purged.
+// @Tags tasks
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id} [delete]
+func (r *TeamsHandler) TeamDeleteTask(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	isCreator := task.CreatedBy != nil && *task.CreatedBy == userID
+	if !isCreator && role != models.AdminUserRole && role != models.FounderUserRole {
+		dto.Forbidden(dto.CodeForbidden, "only the creator or a team admin can delete the task", nil).Send(c)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	if err := tx.Tasks().DeleteTask(c.Request.Context(), teamID, task.ID); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	if !recordActivity(c, tx, task, models.ActivityTaskDeleted, []models.FieldChange{}, nil) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	trace.Log(c, "task_deleted", "team_id="+teamID.String()+" task_id="+task.ID.String())
+	c.Status(http.StatusNoContent)
+}
+
+// loadTask resolves the :task_id parameter within the team.
+// It sends the error response and returns ok=false on failure.
+func (r *TeamsHandler) loadTask(c *gin.Context, tasks repositories.TaskRepository, teamID uuid.UUID) (*models.Task, bool) {
+	taskID, err := uuid.Parse(strings.TrimSpace(c.Param("task_id")))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid task id", nil).Send(c)
+		return nil, false
+	}
+	task, err := tasks.GetTaskByID(c.Request.Context(), teamID, taskID)
+	if err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return nil, false
+	}
+	return task, true
+}
+
+// checkAssignees deduplicates ids and makes sure every user is a member of the team.
+// It sends the error response and returns ok=false on failure.
+func (r *TeamsHandler) checkAssignees(c *gin.Context, teams repositories.TeamRepository, teamID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, bool) {
+	assignees, notMembers, err := teamMembersOnly(c.Request.Context(), teams, teamID, ids)
+	if err != nil {
+		dto.RepoError(err, "team member").Send(c)
+		return nil, false
+	}
+	if len(notMembers) > 0 {
+		dto.BadRequest(dto.CodeNotTeamMember, "assignees must be members of the team", map[string]any{"user_ids": notMembers}).Send(c)
+		return nil, false
+	}
+	return assignees, true
+}
+
+func teamMembersOnly(ctx context.Context, teams repositories.TeamRepository, teamID uuid.UUID, ids []uuid.UUID) (members []uuid.UUID, notMembers []uuid.UUID, err error) {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	members = []uuid.UUID{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		_, err := teams.GetMemberRole(ctx, teamID, id)
+		if errors.Is(err, repositories.ErrNotFound) {
+			notMembers = append(notMembers, id)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		members = append(members, id)
+	}
+	return members, notMembers, nil
+}
@@ -0,0 +1,167 @@
+package team_test
+
+import (
+	"context"
+	"net/http"
+	"task_manager/public/blobstore"
+	"task_manager/public/config"
+	"task_manager/public/dto"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func signup(t *testing.T, r http.Handler, email string) map[string]string {
+	t.Helper()
+	rr := testutil.DoJSON(t, r, http.MethodPost, "/api/v1/auth/signup", dto.SignupRequest{
+		FirstName: "Test",
+		LastName:  "User",
+		Email:     email,
+		Password:  "password123",
+	}, nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	env := testutil.DecodeJSON[dto.EnvelopeAny](t, rr)
+	data := env.Data.(map[string]any)
+	return map[string]string{"Authorization": "Bearer " + data["access_token"].(string)}
+}
+
+type fixture struct {
+	r          *gin.Engine
+	uow        repositories.UnitOfWork
+	teamID     uuid.UUID
+	founder    map[string]string
+	member     map[string]string
+	memberID   uuid.UUID
+	outsiderID uuid.UUID
+}
+
+// newFixture creates a team with a founder and a standard member, plus a user outside the team.
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	return newFixtureWithConfig(t, config.Load(), blobstore.NewLocal(t.TempDir()))
+}
+
+func newFixtureWithConfig(t *testing.T, cfg config.Config, blobs blobstore.Store) fixture {
+	t.Helper()
+	uow, err := repositories.NewUnitOfWork("sqlite", testutil.NewSQLiteTestDB(t))
+	require.NoError(t, err)
+	r := testutil.NewTestRouterWithConfig(t, uow, "test-secret", cfg, blobs)
+	ctx := context.Background()
+
+	f := fixture{r: r, uow: uow}
+	f.founder = signup(t, r, "founder@example.com")
+	f.member = signup(t, r, "member@example.com")
+	signup(t, r, "outsider@example.com")
+
+	rr := testutil.DoJSON(t, r, http.MethodPost, "/api/v1/team/", dto.TeamCreationRequest{TeamName: "Ops"}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code)
+	f.teamID = testutil.DecodeJSON[dto.Envelope[models.Team]](t, rr).Data.ID
+
+	member, err := uow.Users().GetUserByEmail(ctx, "member@example.com")
+	require.NoError(t, err)
+	f.memberID = member.ID
+	outsider, err := uow.Users().GetUserByEmail(ctx, "outsider@example.com")
+	require.NoError(t, err)
+	f.outsiderID = outsider.ID
+	require.NoError(t, uow.Teams().CreateTeamUser(ctx, &models.UserTeam{
+		ID:     uuid.New(),
+		TeamID: f.teamID,
+		UserID: member.ID,
+		Role:   models.StandardUserRole,
+	}))
+	return f
+}
+
+func (f fixture) tasksPath() string {
+	return "/api/v1/team/" + f.teamID.String() + "/tasks"
+}
+
+// activity returns the entries of the task's activity matching query, as the founder sees them.
+func (f fixture) activity(t *testing.T, taskID uuid.UUID, query string) []models.TaskActivity {
+	t.Helper()
+	rr := testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+taskID.String()+"/activity?"+query, nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	return testutil.DecodeJSON[dto.TaskActivitiesEnvelope](t, rr).Data
+}
+
+// fieldChanges returns the changes of field recorded in the task.updated entries of the task.
+func (f fixture) fieldChanges(t *testing.T, taskID uuid.UUID, field string) []models.FieldChange {
+	t.Helper()
+	changes := []models.FieldChange{}
+	for _, a := range f.activity(t, taskID, "action=task.updated") {
+		for _, c := range a.Changes {
+			if c.Field == field {
+				changes = append(changes, c)
+			}
+		}
+	}
+	return changes
+}
+
+func TestTaskAssignees_SQLite(t *testing.T) {
+	f := newFixture(t)
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{
+		Title:       "Rotate keys",
+		AssigneeIDs: []uuid.UUID{f.outsiderID},
+	}, f.founder)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.Equal(t, dto.CodeNotTeamMember, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Rotate keys"}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	individual := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data
+	require.Empty(t, individual.AssigneeIDs)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Weekly review", GroupTask: true}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	grouped := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data
+
+	assignees := f.tasksPath() + "/" + individual.ID.String() + "/assignees"
+	myTasks := func() []uuid.UUID {
+		rr := testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/user/me/tasks?sort=title", nil, f.member)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var ids []uuid.UUID
+		for _, task := range testutil.DecodeJSON[dto.TasksEnvelope](t, rr).Data {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		userIDs       []uuid.UUID
+		wantStatus    int
+		wantAssignees []uuid.UUID
+		wantMyTasks   []uuid.UUID
+	}{
+		{"outsider is rejected", http.MethodPost, []uuid.UUID{f.outsiderID}, http.StatusBadRequest, nil, []uuid.UUID{grouped.ID}},
+		{"assign member", http.MethodPost, []uuid.UUID{f.memberID, f.memberID}, http.StatusOK, []uuid.UUID{f.memberID}, []uuid.UUID{individual.ID, grouped.ID}},
+		{"assigning twice is a no-op", http.MethodPost, []uuid.UUID{f.memberID}, http.StatusOK, []uuid.UUID{f.memberID}, []uuid.UUID{individual.ID, grouped.ID}},
+		{"unassign member", http.MethodDelete, []uuid.UUID{f.memberID}, http.StatusOK, []uuid.UUID{}, []uuid.UUID{grouped.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := testutil.DoJSON(t, f.r, tt.method, assignees, dto.TaskAssigneesRequest{UserIDs: tt.userIDs}, f.member)
+			require.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantAssignees != nil {
+				require.Equal(t, tt.wantAssignees, testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.AssigneeIDs)
+			}
+			require.Equal(t, tt.wantMyTasks, myTasks())
+		})
+	}
+
+	// Standard members cannot delete tasks created by someone else.
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, f.tasksPath()+"/"+individual.ID.String(), nil, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, f.tasksPath()+"/"+individual.ID.String(), nil, f.founder)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+individual.ID.String(), nil, f.founder)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
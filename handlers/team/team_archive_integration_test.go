@@ -0,0 +1,87 @@
+package team_test
+
+import (
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamArchive_SQLite(t *testing.T) {
+	f := newFixture(t)
+	teamPath := "/api/v1/team/" + f.teamID.String()
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Wrap up"}, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	task := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data
+	taskPath := f.tasksPath() + "/" + task.ID.String()
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, taskPath+"/timer/start", dto.TimerStartRequest{}, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/archive", nil, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/archive", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.NotNil(t, testutil.DecodeJSON[dto.Envelope[models.Team]](t, rr).Data.ArchivedAt)
+	// Archiving twice changes nothing.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/archive", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	// Archived teams are hidden from the team list unless asked for.
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/team/", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Empty(t, testutil.DecodeJSON[dto.TeamsEnvelope](t, rr).Data)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/team/?include_archived=true", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Len(t, testutil.DecodeJSON[dto.TeamsEnvelope](t, rr).Data, 1)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/team/?include_archived=maybe", nil, f.member)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// Members keep reading the team but every change is refused.
+	for _, path := range []string{teamPath, f.tasksPath(), taskPath, teamPath + "/projects"} {
+		rr = testutil.DoJSON(t, f.r, http.MethodGet, path, nil, f.member)
+		require.Equal(t, http.StatusOK, rr.Code, path)
+	}
+	title := "Reopened"
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    any
+		headers map[string]string
+	}{
+		{"create task", http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Late"}, f.member},
+		{"edit task", http.MethodPatch, taskPath, dto.TaskUpdateRequest{Title: &title}, f.member},
+		{"delete task", http.MethodDelete, taskPath, nil, f.founder},
+		{"comment", http.MethodPost, taskPath + "/comments", dto.CommentRequest{Body: "One more thing"}, f.member},
+		{"create project", http.MethodPost, teamPath + "/projects", dto.ProjectRequest{Name: "Phase 2"}, f.founder},
+		{"rename team", http.MethodPut, teamPath, dto.TeamCreationRequest{TeamName: "Ops 2"}, f.founder},
+		{"stop timer", http.MethodPost, "/api/v1/user/me/timer/stop", nil, f.member},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := testutil.DoJSON(t, f.r, tt.method, tt.path, tt.body, tt.headers)
+			require.Equal(t, http.StatusConflict, rr.Code, rr.Body.String())
+			require.Equal(t, dto.CodeTeamArchived, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+		})
+	}
+	// Outsiders learn nothing about the team.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Late"}, signup(t, f.r, "stranger@example.com"))
+	require.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/unarchive", nil, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/unarchive", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Nil(t, testutil.DecodeJSON[dto.Envelope[models.Team]](t, rr).Data.ArchivedAt)
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, taskPath, dto.TaskUpdateRequest{Title: &title}, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, "/api/v1/user/me/timer/stop", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/audit?action[in]=team.archive,team.unarchive", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Len(t, testutil.DecodeJSON[dto.TeamAuditEnvelope](t, rr).Data, 2)
+}
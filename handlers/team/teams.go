@@ -1,11 +1,17 @@
 package team
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 	"task_manager/public/dto"
 	"task_manager/public/jwtauth"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/listspec"
 	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
 	"task_manager/public/validation"
 	"time"
 
@@ -16,11 +22,18 @@ import (
 
 // TeamGetByUserID godoc
 // @Summary Get current teams
-// @Description Get the teams that the user currently in
+// @Description Get the teams that the user currently in. Archived teams are left out unless include_archived is set.
 // @Tags teams
 // @Produce json
 // @Security BearerAuth
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from meta.next_cursor of the previous page"
+// @Param sort query string false "Comma separated, prefix with - for descending: name, created_at, updated_at"
+// @Param include_total query bool false "Include meta.total"
+// @Param name query string false "Filter by exact name; also name[contains]"
+// @Param include_archived query bool false "Include archived teams"
 // @Success 200 {object} dto.TeamsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
 // @Failure 401 {object} dto.ErrorEnvelope
 // @Failure 403 {object} dto.ErrorEnvelope
 // @Router /team [get]
@@ -32,12 +45,26 @@ func (r *TeamsHandler) TeamGetByUserID(c *gin.Context) {
 		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
 		return
 	}
-	teams, err := r.uow.Teams().GetTeamsByUserID(c.Request.Context(), userID)
+	includeArchived := false
+	if raw := strings.TrimSpace(c.Query("include_archived")); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			dto.BadRequest(dto.CodeInvalidRequest, "include_archived must be true or false", nil).Send(c)
+			return
+		}
+		includeArchived = v
+	}
+	q, err := listquery.Parse(c.Request.URL.Query(), listspec.Teams)
 	if err != nil {
-		dto.Internal(dto.CodeInternalError, "Internal server error", err.Error(), nil).Send(c)
+		dto.ListQueryError(err).Send(c)
 		return
 	}
-	dto.OK(c, http.StatusOK, teams)
+	teams, err := r.uow.Teams().GetTeamsByUserID(c.Request.Context(), userID, includeArchived, q)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	dto.OKPage(c, teams)
 }
 
 // TeamPost godoc
@@ -91,7 +118,7 @@ func (r *TeamsHandler) TeamPost(c *gin.Context) {
 	teamsRepo := tx.Teams()
 	err = teamsRepo.CreateTeam(c.Request.Context(), team)
 	if err != nil {
-		dto.Internal(dto.CodeInternalError, "Internal server error", err.Error(), nil).Send(c)
+		dto.RepoError(err, "team").Send(c)
 		return
 	}
 
@@ -102,7 +129,18 @@ func (r *TeamsHandler) TeamPost(c *gin.Context) {
 		Role:   models.FounderUserRole,
 	})
 	if err != nil {
-		dto.Internal(dto.CodeInternalError, "Internal server error", err.Error(), nil).Send(c)
+		dto.RepoError(err, "team member").Send(c)
+		return
+	}
+	if err := tx.Workflows().SaveWorkflow(c.Request.Context(), team.ID, models.DefaultWorkflow(team.ID)); err != nil {
+		dto.RepoError(err, "workflow").Send(c)
+		return
+	}
+	if !recordTeamAudit(c, tx, team.ID, models.TeamActionCreate, map[string]any{"name": team.Name}) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "team").Send(c)
 		return
 	}
 
@@ -111,6 +149,8 @@ func (r *TeamsHandler) TeamPost(c *gin.Context) {
 
 // TeamDelete godoc
 // @Summary Delete a team
+// @Description Moves the team to the trash with everything in it. Only the founder can delete the team, and restore it
+// @Description until the trash is purged.
 // @Tags teams
 // @Produce json
 // @Param request body dto.TeamCreationRequest true "Team creation request"
@@ -130,22 +170,46 @@ func (r *TeamsHandler) TeamDelete(c *gin.Context) {
 		return
 	}
 	founder, err := r.uow.Teams().GetTeamFounderByTeamID(c.Request.Context(), idUUID)
-	if err != nil {
-		dto.Internal(dto.CodeInternalError, "Internal server error", err.Error(), nil).Send(c)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		dto.RepoError(err, "team").Send(c)
 		return
 	}
 
-	if founder == nil || founder.UserID.String() != strings.TrimSpace(userid) {
+	if err != nil || founder.UserID.String() != strings.TrimSpace(userid) {
 		dto.Forbidden(dto.CodeForbidden, "only founder can delete the team", nil).Send(c)
 		return
 	}
 
-	r.uow.Teams().DeleteTeam(c.Request.Context(), idUUID)
+	tx, err := r.uow.Begin(c.Request.Context())
+	if err != nil {
+		dto.Internal(dto.CodeDatabaseError, "database error", err.Error(), nil).Send(c)
+		return
+	}
+	defer tx.Stop()
+
+	team, err := tx.Teams().GetTeamByID(c.Request.Context(), idUUID)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	if err := tx.Teams().DeleteTeam(c.Request.Context(), idUUID); err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	// The entry outlives the team, for the operators of the server.
+	if !recordTeamAudit(c, tx, idUUID, models.TeamActionDelete, map[string]any{"name": team.Name}) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
 	c.Status(http.StatusNoContent)
 }
 
 // TeamEdit godoc
 // @Summary Edit team name
+// @Description Only team admins and founders can rename the team, and not while it is archived.
 // @tags teams
 // @Produce json
 // @Param id path string true "Team ID"
@@ -155,25 +219,32 @@ func (r *TeamsHandler) TeamDelete(c *gin.Context) {
 // @Failure 400 {object} dto.ErrorEnvelope
 // @Failure 401 {object} dto.ErrorEnvelope
 // @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
 // @Router /team/{id} [put]
 func (r *TeamsHandler) TeamEdit(c *gin.Context) {
-	claims := jwt.ExtractClaims(c)
-	userid, _ := claims[jwtauth.IdentityKey].(string)
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return
+	}
 	teamid := c.Param("id")
 	teamidUUID, err := uuid.Parse(strings.TrimSpace(teamid))
 	if err != nil {
 		dto.BadRequest(dto.CodeInvalidRequest, "invalid team id", nil).Send(c)
 		return
 	}
-	getUserRole, err := r.uow.Teams().GetMemberRole(c.Request.Context(), teamidUUID, uuid.MustParse(strings.TrimSpace(userid)))
-	if err != nil {
-		dto.Internal(dto.CodeInternalError, "Internal server error", err.Error(), nil).Send(c)
+	getUserRole, err := r.uow.Teams().GetMemberRole(c.Request.Context(), teamidUUID, userID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		dto.RepoError(err, "team").Send(c)
 		return
 	}
-	if getUserRole == nil || (*getUserRole != models.AdminUserRole && *getUserRole != models.FounderUserRole) {
+	if err != nil || (*getUserRole != models.AdminUserRole && *getUserRole != models.FounderUserRole) {
 		dto.Forbidden(dto.CodeForbidden, "only admin or founder can edit the team", nil).Send(c)
 		return
 	}
+	if !r.checkTeamNotArchived(c, teamidUUID) {
+		return
+	}
 
 	req := dto.TeamCreationRequest{}
 
@@ -181,14 +252,139 @@ func (r *TeamsHandler) TeamEdit(c *gin.Context) {
 		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
 		return
 	}
-	r.uow.Teams().EditTeamName(c.Request.Context(), &models.Team{
+	req.TeamName = strings.TrimSpace(req.TeamName)
+
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+	tx, ok := r.beginTeamWrite(c, teamidUUID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	team, err := tx.Teams().GetTeamByID(c.Request.Context(), teamidUUID)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	err = tx.Teams().EditTeamName(c.Request.Context(), &models.Team{
 		ID:   teamidUUID,
 		Name: req.TeamName,
 	})
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	if team.Name != req.TeamName {
+		if !recordTeamAudit(c, tx, teamidUUID, models.TeamActionRename, map[string]any{"old_name": team.Name, "new_name": req.TeamName}) {
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
 
 	c.Status(http.StatusNoContent)
 }
 
+// TeamArchive godoc
+// @Summary Archive a team
+// @Description Freezes the team: members can still read everything in it, but every change is refused with
+// @Description TEAM_ARCHIVED until it is unarchived. Archived teams are left out of the team list by default.
+// @Description Only the founder can archive the team; archiving it twice is a no-op.
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} dto.TeamsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/archive [post]
+func (r *TeamsHandler) TeamArchive(c *gin.Context) {
+	r.setTeamArchived(c, true)
+}
+
+// TeamUnarchive godoc
+// @Summary Unarchive a team
+// @Description Makes an archived team writable again. Only the founder can unarchive the team.
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} dto.TeamsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/unarchive [post]
+func (r *TeamsHandler) TeamUnarchive(c *gin.Context) {
+	r.setTeamArchived(c, false)
+}
+
+// setTeamArchived archives or unarchives the team; doing it twice is a no-op.
+func (r *TeamsHandler) setTeamArchived(c *gin.Context, archived bool) {
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return
+	}
+	teamID, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid team id", nil).Send(c)
+		return
+	}
+	role, err := r.uow.Teams().GetMemberRole(c.Request.Context(), teamID, userID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	if err != nil || *role != models.FounderUserRole {
+		dto.Forbidden(dto.CodeForbidden, "only founder can archive the team", nil).Send(c)
+		return
+	}
+
+	tx, err := r.uow.Begin(c.Request.Context())
+	if err != nil {
+		dto.Internal(dto.CodeDatabaseError, "database error", err.Error(), nil).Send(c)
+		return
+	}
+	defer tx.Stop()
+
+	team, err := tx.Teams().GetTeamByID(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	if team.Archived() == archived {
+		dto.OK(c, http.StatusOK, team)
+		return
+	}
+	action := models.TeamActionUnarchive
+	team.ArchivedAt = nil
+	if archived {
+		now := time.Now()
+		team.ArchivedAt = &now
+		action = models.TeamActionArchive
+	}
+	if err := tx.Teams().SetTeamArchived(c.Request.Context(), teamID, team.ArchivedAt); err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	if !recordTeamAudit(c, tx, teamID, action, map[string]any{"name": team.Name}) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	trace.Log(c, "team_archived", "team_id="+teamID.String()+" archived="+strconv.FormatBool(archived))
+	dto.OK(c, http.StatusOK, team)
+}
+
 // TeamGetByID godoc
 // @Summary Get team by ID
 // @Param id path string true "Team ID"
@@ -199,8 +395,11 @@ func (r *TeamsHandler) TeamEdit(c *gin.Context) {
 // @Failure 403 {object} dto.ErrorEnvelope
 // @Router /team/{id} [get]
 func (r *TeamsHandler) TeamGetByID(c *gin.Context) {
-	claims := jwt.ExtractClaims(c)
-	id, _ := claims[jwtauth.IdentityKey].(string)
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return
+	}
 	TeamID := c.Param("id")
 	teamIDUUID, err := uuid.Parse(strings.TrimSpace(TeamID))
 	if err != nil {
@@ -208,19 +407,20 @@ func (r *TeamsHandler) TeamGetByID(c *gin.Context) {
 		return
 	}
 
-	getUserRole, err := r.uow.Teams().GetMemberRole(c.Request.Context(), teamIDUUID, uuid.MustParse(strings.TrimSpace(id)))
-	if err != nil {
-		dto.Internal(dto.CodeInternalError, "Internal server error", err.Error(), nil).Send(c)
+	_, err = r.uow.Teams().GetMemberRole(c.Request.Context(), teamIDUUID, userID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		dto.Forbidden(dto.CodeForbidden, "only team members can get the team info", nil).Send(c)
 		return
 	}
-
-	if getUserRole == nil {
-		dto.Forbidden(dto.CodeForbidden, "only team members can get the team info", nil).Send(c)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
 	}
 
 	Team, err := r.uow.Teams().GetTeamByID(c.Request.Context(), teamIDUUID)
 	if err != nil {
-		dto.Internal(dto.CodeInternalError, "Internal server error", err.Error(), nil).Send(c)
+		dto.RepoError(err, "team").Send(c)
+		return
 	}
 
 	dto.OK(c, http.StatusOK, Team)
@@ -0,0 +1,591 @@
+package team
+
+import (
+	"encoding/csv"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxTimesheetDays is the longest range of a timesheet report.
+const maxTimesheetDays = 366
+
+// TeamStartTimer godoc
+// @Summary Start a timer on a task
+// @Description Starts the running timer of the caller on the task. A user has at most one running timer across all
+// @Description teams; it is stopped with POST /user/me/timer/stop.
+// @Tags time tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.TimerStartRequest true "Timer"
+// @Success 201 {object} dto.TimeEntryEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/timer/start [post]
+func (r *TeamsHandler) TeamStartTimer(c *gin.Context) {
+	teamID, userID, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.TimerStartRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	task, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	if err := tx.TimeEntries().LockUserTimeEntries(c.Request.Context(), userID); err != nil {
+		dto.RepoError(err, "user").Send(c)
+		return
+	}
+	running, err := tx.TimeEntries().GetRunningTimer(c.Request.Context(), userID)
+	if err == nil {
+		dto.Conflict(dto.CodeTimerRunning, "a timer is already running", map[string]any{
+			"time_entry_id": running.ID,
+			"task_id":       running.TaskID,
+		}).Send(c)
+		return
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		dto.RepoError(err, "time entry").Send(c)
+		return
+	}
+
+	entry := &models.TimeEntry{
+		ID:        uuid.New(),
+		TeamID:    teamID,
+		TaskID:    task.ID,
+		UserID:    userID,
+		StartedAt: time.Now().UTC().Truncate(time.Second),
+		Note:      req.Note,
+		Billable:  req.Billable == nil || *req.Billable,
+	}
+	if err := tx.TimeEntries().CreateTimeEntry(c.Request.Context(), entry); err != nil {
+		if errors.Is(err, repositories.ErrConflict) {
+			dto.Conflict(dto.CodeTimerRunning, "a timer is already running", nil).Send(c)
+			return
+		}
+		dto.RepoError(err, "time entry").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "time entry").Send(c)
+		return
+	}
+	trace.Log(c, "timer_started", "task_id="+task.ID.String()+" time_entry_id="+entry.ID.String())
+
+	dto.OK(c, http.StatusCreated, entry)
+}
+
+// TeamGetTaskTimeEntries godoc
+// @Summary List the time entries of a task
+// @Description Entries of every member, ordered by start, including running timers.
+// @Tags time tracking
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Success 200 {object} dto.TimeEntriesEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/time-entries [get]
+func (r *TeamsHandler) TeamGetTaskTimeEntries(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	task, ok := r.loadTask(c, r.uow.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	entries, err := r.uow.TimeEntries().ListTaskTimeEntries(c.Request.Context(), task.ID)
+	if err != nil {
+		dto.RepoError(err, "time entry").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, entries)
+}
+
+// TeamPostTimeEntry godoc
+// @Summary Log time on a task
+// @Description Adds a completed entry of the caller. It must end after it starts, last at most 24 hours, not end in
+// @Description the future and not overlap another entry or the running timer of the caller in any team.
+// @Tags time tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.TimeEntryRequest true "Time entry"
+// @Success 201 {object} dto.TimeEntryEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/time-entries [post]
+func (r *TeamsHandler) TeamPostTimeEntry(c *gin.Context) {
+	teamID, userID, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.TimeEntryRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+	entry := &models.TimeEntry{
+		ID:       uuid.New(),
+		TeamID:   teamID,
+		UserID:   userID,
+		Note:     req.Note,
+		Billable: req.Billable == nil || *req.Billable,
+	}
+	if !setEntryTimes(c, entry, req.StartedAt, req.EndedAt) {
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	task, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	entry.TaskID = task.ID
+	if !checkEntryOverlap(c, tx.TimeEntries(), entry, nil) {
+		return
+	}
+	if err := tx.TimeEntries().CreateTimeEntry(c.Request.Context(), entry); err != nil {
+		dto.RepoError(err, "time entry").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "time entry").Send(c)
+		return
+	}
+	trace.Log(c, "time_entry_created", "task_id="+task.ID.String()+" time_entry_id="+entry.ID.String())
+
+	dto.OK(c, http.StatusCreated, entry)
+}
+
+// TeamPatchTimeEntry godoc
+// @Summary Update a time entry
+// @Description Only the owner of the entry or a team admin/founder can change it. Changed times are checked like
+// @Description new entries; the times of a running timer cannot be changed.
+// @Tags time tracking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param entry_id path string true "Time entry ID"
+// @Param request body dto.TimeEntryUpdateRequest true "Changes"
+// @Success 200 {object} dto.TimeEntryEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/time-entries/{entry_id} [patch]
+func (r *TeamsHandler) TeamPatchTimeEntry(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.TimeEntryUpdateRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if req.Note != nil {
+		note := strings.TrimSpace(*req.Note)
+		req.Note = &note
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	entry, ok := r.loadTimeEntry(c, tx.TimeEntries(), teamID, userID, role)
+	if !ok {
+		return
+	}
+	if req.StartedAt != nil || req.EndedAt != nil {
+		if entry.Running() {
+			dto.BadRequest(dto.CodeInvalidTimeEntry, "stop the timer before changing its times", nil).Send(c)
+			return
+		}
+		start, end := entry.StartedAt, *entry.EndedAt
+		if req.StartedAt != nil {
+			start = *req.StartedAt
+		}
+		if req.EndedAt != nil {
+			end = *req.EndedAt
+		}
+		if !setEntryTimes(c, entry, start, end) {
+			return
+		}
+		if !checkEntryOverlap(c, tx.TimeEntries(), entry, &entry.ID) {
+			return
+		}
+	}
+	if req.Note != nil {
+		entry.Note = *req.Note
+	}
+	if req.Billable != nil {
+		entry.Billable = *req.Billable
+	}
+	if err := tx.TimeEntries().UpdateTimeEntry(c.Request.Context(), entry); err != nil {
+		dto.RepoError(err, "time entry").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "time entry").Send(c)
+		return
+	}
+	trace.Log(c, "time_entry_updated", "time_entry_id="+entry.ID.String())
+
+	dto.OK(c, http.StatusOK, entry)
+}
+
+// TeamDeleteTimeEntry godoc
+// @Summary Delete a time entry
+// @Description Only the owner of the entry or a team admin/founder can delete it. Deleting a running timer discards it.
+// @Tags time tracking
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param entry_id path string true "Time entry ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/time-entries/{entry_id} [delete]
+func (r *TeamsHandler) TeamDeleteTimeEntry(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	entry, ok := r.loadTimeEntry(c, r.uow.TimeEntries(), teamID, userID, role)
+	if !ok {
+		return
+	}
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+	if err := tx.TimeEntries().DeleteTimeEntry(c.Request.Context(), teamID, entry.ID); err != nil {
+		dto.RepoError(err, "time entry").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "time entry").Send(c)
+		return
+	}
+	trace.Log(c, "time_entry_deleted", "time_entry_id="+entry.ID.String())
+	c.Status(http.StatusNoContent)
+}
+
+// TeamGetTimesheet godoc
+// @Summary Get a timesheet report
+// @Description Completed time entries that started within the dates, grouped by user, task, project or team. The
+// @Description duration of every entry is rounded before it is summed. Members only see their own entries; team
+// @Description admins and founders see everyone's. With format=csv the entries are returned as a CSV file, one line
+// @Description per entry.
+// @Tags time tracking
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param from query string true "First day, YYYY-MM-DD"
+// @Param to query string true "Last day, YYYY-MM-DD"
+// @Param tz query string false "IANA time zone of the days, defaults to UTC"
+// @Param group_by query string false "user (default), task, project or team"
+// @Param user_id query string false "Only entries of this user"
+// @Param task_id query string false "Only entries of this task"
+// @Param project_id query string false "Only entries of tasks of this project"
+// @Param billable query bool false "Only billable or non-billable entries"
+// @Param rounding query string false "none (default), up, down or nearest"
+// @Param round_to query int false "Rounding increment in minutes, defaults to 15"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} dto.TimesheetEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/timesheet [get]
+func (r *TeamsHandler) TeamGetTimesheet(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	filter, loc, ok := parseTimesheetFilter(c)
+	if !ok {
+		return
+	}
+	if role != models.AdminUserRole && role != models.FounderUserRole {
+		if filter.UserID != nil && *filter.UserID != userID {
+			dto.Forbidden(dto.CodeForbidden, "only admin or founder can see the time of other members", nil).Send(c)
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	group := models.TimesheetByUser
+	if raw := strings.TrimSpace(c.Query("group_by")); raw != "" {
+		group = models.TimesheetGroup(raw)
+		if !group.IsValid() {
+			dto.BadRequest(dto.CodeInvalidRequest, "group_by must be user, task, project or team", nil).Send(c)
+			return
+		}
+	}
+	rounding := models.Rounding{Mode: models.RoundNone}
+	if raw := strings.TrimSpace(c.Query("rounding")); raw != "" {
+		rounding.Mode = models.RoundingMode(raw)
+		if !rounding.Mode.IsValid() {
+			dto.BadRequest(dto.CodeInvalidRequest, "rounding must be none, up, down or nearest", nil).Send(c)
+			return
+		}
+	}
+	if rounding.Mode != models.RoundNone {
+		rounding.Minutes = 15
+		if raw := strings.TrimSpace(c.Query("round_to")); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > 24*60 {
+				dto.BadRequest(dto.CodeInvalidRequest, "round_to must be between 1 and 1440 minutes", nil).Send(c)
+				return
+			}
+			rounding.Minutes = n
+		}
+	}
+	format := strings.TrimSpace(c.DefaultQuery("format", "json"))
+	if format != "json" && format != "csv" {
+		dto.BadRequest(dto.CodeInvalidRequest, "format must be json or csv", nil).Send(c)
+		return
+	}
+
+	entries, err := r.uow.TimeEntries().ListTimesheet(c.Request.Context(), teamID, filter)
+	if err != nil {
+		dto.RepoError(err, "time entry").Send(c)
+		return
+	}
+	ts := models.BuildTimesheet(entries, group, rounding)
+	ts.From, ts.To = filter.From.In(loc), filter.To.In(loc)
+	if format == "csv" {
+		writeTimesheetCSV(c, ts, loc)
+		return
+	}
+	dto.OK(c, http.StatusOK, ts)
+}
+
+// loadTimeEntry resolves the :entry_id parameter within the team and checks that the
+// caller may change it: the owner or a team admin/founder.
+// It sends the error response and returns ok=false on failure.
+func (r *TeamsHandler) loadTimeEntry(c *gin.Context, entries repositories.TimeEntryRepository, teamID uuid.UUID, userID uuid.UUID, role models.TeamUserRole) (*models.TimeEntry, bool) {
+	entryID, err := uuid.Parse(c.Param("entry_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid time entry id", nil).Send(c)
+		return nil, false
+	}
+	entry, err := entries.GetTimeEntry(c.Request.Context(), teamID, entryID)
+	if err != nil {
+		dto.RepoError(err, "time entry").Send(c)
+		return nil, false
+	}
+	if entry.UserID != userID && role != models.AdminUserRole && role != models.FounderUserRole {
+		dto.Forbidden(dto.CodeForbidden, "only the owner or a team admin can change the time entry", nil).Send(c)
+		return nil, false
+	}
+	return entry, true
+}
+
+// setEntryTimes sets the times of a completed entry, truncated to the second, after
+// checking that it ends after it starts, lasts at most MaxTimeEntryDuration and does
+// not end in the future.
+// It sends the error response and returns ok=false on failure.
+func setEntryTimes(c *gin.Context, entry *models.TimeEntry, start time.Time, end time.Time) bool {
+	start = start.UTC().Truncate(time.Second)
+	end = end.UTC().Truncate(time.Second)
+	switch {
+	case !end.After(start):
+		dto.BadRequest(dto.CodeInvalidTimeEntry, "ended_at must be after started_at", nil).Send(c)
+		return false
+	case end.Sub(start) > models.MaxTimeEntryDuration:
+		dto.BadRequest(dto.CodeInvalidTimeEntry, "a time entry can last at most 24 hours", nil).Send(c)
+		return false
+	case end.After(time.Now()):
+		dto.BadRequest(dto.CodeInvalidTimeEntry, "ended_at cannot be in the future", nil).Send(c)
+		return false
+	}
+	entry.StartedAt = start
+	entry.Stop(end)
+	return true
+}
+
+// checkEntryOverlap makes sure the completed entry does not overlap another entry of
+// its user, excluding excludeID. It locks the entries of the user for the rest of the
+// transaction.
+// It sends the error response and returns ok=false on failure.
+func checkEntryOverlap(c *gin.Context, entries repositories.TimeEntryRepository, entry *models.TimeEntry, excludeID *uuid.UUID) bool {
+	if err := entries.LockUserTimeEntries(c.Request.Context(), entry.UserID); err != nil {
+		dto.RepoError(err, "user").Send(c)
+		return false
+	}
+	other, err := entries.FindOverlappingEntry(c.Request.Context(), entry.UserID, entry.StartedAt, *entry.EndedAt, excludeID)
+	if err == nil {
+		dto.Conflict(dto.CodeTimeEntryOverlap, "the time entry overlaps another entry", map[string]any{
+			"time_entry_id": other.ID,
+			"started_at":    other.StartedAt,
+			"ended_at":      other.EndedAt,
+		}).Send(c)
+		return false
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		dto.RepoError(err, "time entry").Send(c)
+		return false
+	}
+	return true
+}
+
+// parseTimesheetFilter reads the dates, time zone and filters of a timesheet request.
+// The days run from midnight to midnight in the time zone; to is inclusive.
+// It sends the error response and returns ok=false on failure.
+func parseTimesheetFilter(c *gin.Context) (models.TimesheetFilter, *time.Location, bool) {
+	filter := models.TimesheetFilter{}
+	loc := time.UTC
+	if tz := strings.TrimSpace(c.Query("tz")); tz != "" {
+		l, err := time.LoadLocation(tz)
+		// "Local" would depend on the server.
+		if err != nil || tz == "Local" {
+			dto.BadRequest(dto.CodeInvalidRequest, "unknown timezone", map[string]any{"tz": tz}).Send(c)
+			return filter, nil, false
+		}
+		loc = l
+	}
+	from, errFrom := time.ParseInLocation(time.DateOnly, strings.TrimSpace(c.Query("from")), loc)
+	to, errTo := time.ParseInLocation(time.DateOnly, strings.TrimSpace(c.Query("to")), loc)
+	if errFrom != nil || errTo != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "from and to must be dates as YYYY-MM-DD", nil).Send(c)
+		return filter, nil, false
+	}
+	to = to.AddDate(0, 0, 1)
+	if !to.After(from) || to.After(from.AddDate(0, 0, maxTimesheetDays)) {
+		dto.BadRequest(dto.CodeInvalidRequest, "to must not be before from, and the range can span at most 366 days", nil).Send(c)
+		return filter, nil, false
+	}
+	filter.From, filter.To = from, to
+
+	ids := []struct {
+		param string
+		dst   **uuid.UUID
+	}{{"user_id", &filter.UserID}, {"task_id", &filter.TaskID}, {"project_id", &filter.ProjectID}}
+	for _, p := range ids {
+		raw := strings.TrimSpace(c.Query(p.param))
+		if raw == "" {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			dto.BadRequest(dto.CodeInvalidRequest, "invalid "+strings.ReplaceAll(p.param, "_", " "), nil).Send(c)
+			return filter, nil, false
+		}
+		*p.dst = &id
+	}
+	if raw := strings.TrimSpace(c.Query("billable")); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			dto.BadRequest(dto.CodeInvalidRequest, "billable must be true or false", nil).Send(c)
+			return filter, nil, false
+		}
+		filter.Billable = &v
+	}
+	return filter, loc, true
+}
+
+// writeTimesheetCSV sends the entries of the timesheet as a CSV file, row by row, with
+// the times in loc.
+func writeTimesheetCSV(c *gin.Context, ts *models.Timesheet, loc *time.Location) {
+	filename := "timesheet-" + ts.From.Format(time.DateOnly) + "-" + ts.To.AddDate(0, 0, -1).Format(time.DateOnly) + ".csv"
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{
+		string(ts.GroupBy), "started_at", "ended_at", "user", "task", "project", "billable", "seconds", "rounded_seconds", "hours", "note",
+	})
+	for _, row := range ts.Rows {
+		for _, e := range row.Entries {
+			_ = w.Write([]string{
+				row.Name,
+				e.StartedAt.In(loc).Format(time.RFC3339),
+				e.EndedAt.In(loc).Format(time.RFC3339),
+				e.UserName,
+				e.TaskTitle,
+				e.ProjectName,
+				strconv.FormatBool(e.Billable),
+				strconv.FormatInt(e.Duration, 10),
+				strconv.FormatInt(e.RoundedSeconds, 10),
+				strconv.FormatFloat(float64(e.RoundedSeconds)/3600, 'f', 2, 64),
+				e.Note,
+			})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		trace.Log(c, "timesheet_export_failed", "err="+err.Error())
+	}
+}
@@ -0,0 +1,120 @@
+package team_test
+
+import (
+	"net/http"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeTracking_SQLite(t *testing.T) {
+	f := newFixture(t)
+	teamPath := "/api/v1/team/" + f.teamID.String()
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Write report"}, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	task := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data
+	entriesPath := f.tasksPath() + "/" + task.ID.String() + "/time-entries"
+
+	// One running timer per user.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath()+"/"+task.ID.String()+"/timer/start", dto.TimerStartRequest{Note: "drafting"}, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	timer := testutil.DecodeJSON[dto.TimeEntryEnvelope](t, rr).Data
+	require.Nil(t, timer.EndedAt)
+	require.True(t, timer.Billable)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath()+"/"+task.ID.String()+"/timer/start", dto.TimerStartRequest{}, f.member)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.Equal(t, dto.CodeTimerRunning, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/user/me/timer", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, timer.ID, testutil.DecodeJSON[dto.TimeEntryEnvelope](t, rr).Data.ID)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, "/api/v1/user/me/timer/stop", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.NotNil(t, testutil.DecodeJSON[dto.TimeEntryEnvelope](t, rr).Data.EndedAt)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/user/me/timer", nil, f.member)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	day := time.Now().UTC().AddDate(0, 0, -2).Truncate(24 * time.Hour)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	notBillable := false
+
+	invalid := []dto.TimeEntryRequest{
+		{StartedAt: at(10, 0), EndedAt: at(9, 0)},
+		{StartedAt: at(0, 0), EndedAt: at(0, 0).Add(25 * time.Hour)},
+		{StartedAt: time.Now().Add(-time.Hour), EndedAt: time.Now().Add(time.Hour)},
+	}
+	for _, req := range invalid {
+		rr = testutil.DoJSON(t, f.r, http.MethodPost, entriesPath, req, f.member)
+		require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+		require.Equal(t, dto.CodeInvalidTimeEntry, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+	}
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, entriesPath, dto.TimeEntryRequest{StartedAt: at(9, 0), EndedAt: at(10, 0)}, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	morning := testutil.DecodeJSON[dto.TimeEntryEnvelope](t, rr).Data
+	require.Equal(t, int64(3600), morning.Duration)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, entriesPath, dto.TimeEntryRequest{StartedAt: at(9, 30), EndedAt: at(10, 30)}, f.member)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.Equal(t, dto.CodeTimeEntryOverlap, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+	// Entries may touch, and other users are not in the way.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, entriesPath, dto.TimeEntryRequest{StartedAt: at(10, 0), EndedAt: at(10, 50), Billable: &notBillable}, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, entriesPath, dto.TimeEntryRequest{StartedAt: at(9, 0), EndedAt: at(9, 20)}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	review := testutil.DecodeJSON[dto.TimeEntryEnvelope](t, rr).Data
+
+	// Only the owner or an admin edits an entry, and edits are checked for overlaps too.
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, teamPath+"/time-entries/"+review.ID.String(), dto.TimeEntryUpdateRequest{Billable: &notBillable}, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	end := at(10, 10)
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, teamPath+"/time-entries/"+morning.ID.String(), dto.TimeEntryUpdateRequest{EndedAt: &end}, f.member)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	note := "kickoff"
+	rr = testutil.DoJSON(t, f.r, http.MethodPatch, teamPath+"/time-entries/"+morning.ID.String(), dto.TimeEntryUpdateRequest{Note: &note}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, note, testutil.DecodeJSON[dto.TimeEntryEnvelope](t, rr).Data.Note)
+
+	date := day.Format(time.DateOnly)
+	timesheetPath := teamPath + "/timesheet?from=" + date + "&to=" + date
+
+	// Members only see their own time; rounding applies to every entry.
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, timesheetPath+"&rounding=up&round_to=30", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	ts := testutil.DecodeJSON[dto.TimesheetEnvelope](t, rr).Data
+	require.Len(t, ts.Rows, 1)
+	require.Len(t, ts.Rows[0].Entries, 2)
+	require.Equal(t, models.TimesheetTotals{Seconds: 7200, BillableSeconds: 3600, Hours: 2, BillableHours: 1}, ts.TimesheetTotals)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, timesheetPath+"&user_id="+review.UserID.String(), nil, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, timesheetPath+"&group_by=team&billable=true", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	ts = testutil.DecodeJSON[dto.TimesheetEnvelope](t, rr).Data
+	require.Len(t, ts.Rows, 1)
+	require.Equal(t, "Ops", ts.Rows[0].Name)
+	require.Equal(t, int64(3600+1200), ts.Seconds)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, timesheetPath+"&group_by=project&format=csv", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Contains(t, rr.Header().Get("Content-Type"), "text/csv")
+	require.Contains(t, rr.Header().Get("Content-Disposition"), "timesheet-"+date+"-"+date+".csv")
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	require.Len(t, lines, 4)
+	require.True(t, strings.HasPrefix(lines[0], "project,started_at"))
+
+	for _, query := range []string{"?from=" + date, "?from=" + date + "&to=" + date + "&group_by=week", "?from=" + date + "&to=" + date + "&tz=Mars/Base"} {
+		rr = testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/timesheet"+query, nil, f.founder)
+		require.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, teamPath+"/time-entries/"+review.ID.String(), nil, f.founder)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, entriesPath, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, testutil.DecodeJSON[dto.TimeEntriesEnvelope](t, rr).Data, 3)
+}
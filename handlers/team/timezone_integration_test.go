@@ -0,0 +1,67 @@
+package team_test
+
+import (
+	"net/http"
+	"net/url"
+	"task_manager/public/dto"
+	"task_manager/public/testutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// SQLite compares timestamps as text, so filters and cursors must not depend on the
+// time zone of the server or of the client.
+func TestListTimeFilters_LocalTimeZone_SQLite(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC-5", -5*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	f := newFixture(t)
+	for _, title := range []string{"Plan", "Build", "Ship"} {
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: title}, f.member)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	at := func(d time.Duration, loc *time.Location) string {
+		return url.QueryEscape(time.Now().Add(d).In(loc).Format(time.RFC3339))
+	}
+	tests := []struct {
+		name  string
+		path  string
+		query string
+		want  int
+	}{
+		{"tasks after a local time", f.tasksPath(), "created_at[gte]=" + at(time.Hour, time.Local), 0},
+		{"tasks before a local time", f.tasksPath(), "created_at[lte]=" + at(time.Hour, time.Local), 3},
+		{"tasks after a utc time", f.tasksPath(), "created_at[gte]=" + at(-time.Hour, time.UTC), 3},
+		{"tasks after a time east of utc", f.tasksPath(), "created_at[gte]=" + at(time.Hour, time.FixedZone("UTC+9", 9*60*60)), 0},
+		{"audit after a local time", "/api/v1/team/" + f.teamID.String() + "/audit", "created_at[gte]=" + at(time.Hour, time.Local), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := testutil.DoJSON(t, f.r, http.MethodGet, tt.path+"?"+tt.query, nil, f.founder)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+			require.Len(t, testutil.DecodeJSON[dto.Envelope[[]any]](t, rr).Data, tt.want)
+		})
+	}
+
+	// Paging one task at a time walks every task exactly once.
+	seen := map[string]bool{}
+	cursor := ""
+	for {
+		rr := testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"?sort=created_at&limit=1&cursor="+url.QueryEscape(cursor), nil, f.founder)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		page := testutil.DecodeJSON[dto.TasksEnvelope](t, rr)
+		for _, task := range page.Data {
+			require.False(t, seen[task.Title], task.Title)
+			seen[task.Title] = true
+		}
+		if !page.Meta.HasMore {
+			break
+		}
+		cursor = page.Meta.NextCursor
+	}
+	require.Len(t, seen, 3)
+}
@@ -0,0 +1,226 @@
+package team
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/jwtauth"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/listspec"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TeamGetDeletedTeams godoc
+// @Summary List deleted teams
+// @Description The teams in the trash that the caller founded, most recently deleted first. They can be restored
+// @Description until the trash is purged.
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.TrashEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Router /team/trash [get]
+func (r *TeamsHandler) TeamGetDeletedTeams(c *gin.Context) {
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return
+	}
+	teams, err := r.uow.Teams().ListDeletedTeams(c.Request.Context(), userID)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, teams)
+}
+
+// TeamRestore godoc
+// @Summary Restore a deleted team
+// @Description Brings the team back from the trash with everything in it. Only the founder can restore a team.
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} dto.TeamsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/restore [post]
+func (r *TeamsHandler) TeamRestore(c *gin.Context) {
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return
+	}
+	teamID, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid team id", nil).Send(c)
+		return
+	}
+	founder, err := r.uow.Teams().GetTeamFounderByTeamID(c.Request.Context(), teamID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	if err != nil || founder.UserID != userID {
+		dto.Forbidden(dto.CodeForbidden, "only founder can restore the team", nil).Send(c)
+		return
+	}
+
+	tx, err := r.uow.Begin(c.Request.Context())
+	if err != nil {
+		dto.Internal(dto.CodeDatabaseError, "database error", err.Error(), nil).Send(c)
+		return
+	}
+	defer tx.Stop()
+
+	if err := tx.Teams().RestoreTeam(c.Request.Context(), teamID); err != nil {
+		dto.RepoError(err, "deleted team").Send(c)
+		return
+	}
+	team, err := tx.Teams().GetTeamByID(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	if !recordTeamAudit(c, tx, teamID, models.TeamActionRestore, map[string]any{"name": team.Name}) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	trace.Log(c, "team_restored", "team_id="+teamID.String())
+	dto.OK(c, http.StatusOK, team)
+}
+
+// TeamGetTrash godoc
+// @Summary List the trash of a team
+// @Description Deleted projects, tasks and comments, most recently deleted first. Tasks deleted with a project or a
+// @Description parent task are restored with it and not listed on their own; task_count tells how many tasks a
+// @Description restore brings back. Items are purged for good after the configured retention period.
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from meta.next_cursor of the previous page"
+// @Param sort query string false "deleted_at or -deleted_at"
+// @Param include_total query bool false "Include meta.total"
+// @Param kind query string false "Only this kind: project, task or comment; kind[in]=a,b for several"
+// @Success 200 {object} dto.TrashEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/trash [get]
+func (r *TeamsHandler) TeamGetTrash(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	q, err := listquery.Parse(c.Request.URL.Query(), listspec.Trash)
+	if err != nil {
+		dto.ListQueryError(err).Send(c)
+		return
+	}
+	items, err := r.uow.Trash().ListTrash(c.Request.Context(), teamID, q)
+	if err != nil {
+		dto.RepoError(err, "trash item").Send(c)
+		return
+	}
+	dto.OKPage(c, items)
+}
+
+// TeamRestoreTrashItem godoc
+// @Summary Restore an item from the trash
+// @Description Restores a deleted project with its tasks, a deleted task with its subtasks, or a deleted comment.
+// @Description Whoever could delete the item can restore it: team admins and founders for projects, the creator of a
+// @Description task and the author of a comment. A task whose parent task or project is still in the trash cannot be
+// @Description restored before them.
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param item_id path string true "ID of the project, task or comment"
+// @Success 200 {object} dto.TrashItemEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/trash/{item_id}/restore [post]
+func (r *TeamsHandler) TeamRestoreTrashItem(c *gin.Context) {
+	teamID, userID, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid item id", nil).Send(c)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	item, err := tx.Trash().GetTrashItem(c.Request.Context(), teamID, itemID)
+	if err != nil {
+		dto.RepoError(err, "trash item").Send(c)
+		return
+	}
+	isAdmin := role == models.AdminUserRole || role == models.FounderUserRole
+	isOwner := item.CreatedBy != nil && *item.CreatedBy == userID
+	if !isAdmin && (item.Kind == models.TrashProject || !isOwner) {
+		dto.Forbidden(dto.CodeForbidden, "only whoever could delete the "+string(item.Kind)+" can restore it", nil).Send(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	var projectTaskIDs []uuid.UUID
+	switch item.Kind {
+	case models.TrashProject:
+		projectTaskIDs, err = tx.Projects().RestoreProject(ctx, teamID, item.ID)
+	case models.TrashTask:
+		err = tx.Tasks().RestoreTask(ctx, teamID, item.ID)
+	case models.TrashComment:
+		err = tx.Comments().RestoreComment(ctx, *item.TaskID, item.ID)
+	}
+	if errors.Is(err, repositories.ErrConflict) {
+		dto.Conflict(dto.CodeParentInTrash, "restore the parent task or the project of the task first", nil).Send(c)
+		return
+	}
+	if err != nil {
+		dto.RepoError(err, string(item.Kind)).Send(c)
+		return
+	}
+	if item.Kind == models.TrashTask {
+		task, err := tx.Tasks().GetTaskByID(ctx, teamID, item.ID)
+		if err != nil {
+			dto.RepoError(err, "task").Send(c)
+			return
+		}
+		if !recordActivity(c, tx, task, models.ActivityTaskRestored, []models.FieldChange{}, map[string]any{"task_count": item.TaskCount}) {
+			return
+		}
+	}
+	if !recordTasksActivity(c, tx, teamID, projectTaskIDs, models.ActivityTaskRestored, []models.FieldChange{}, map[string]any{"project_id": item.ID}) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, string(item.Kind)).Send(c)
+		return
+	}
+	trace.Log(c, "trash_restored", "team_id="+teamID.String()+" kind="+string(item.Kind)+" id="+item.ID.String())
+	dto.OK(c, http.StatusOK, item)
+}
@@ -0,0 +1,173 @@
+package team_test
+
+import (
+	"context"
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrash_SQLite(t *testing.T) {
+	f := newFixture(t)
+	teamPath := "/api/v1/team/" + f.teamID.String()
+	create := func(title string, parentID, projectID *uuid.UUID, headers map[string]string) uuid.UUID {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: title, ParentID: parentID, ProjectID: projectID}, headers)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.ID
+	}
+	trash := func(query string) []models.TrashItem {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/trash"+query, nil, f.member)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.TrashEnvelope](t, rr).Data
+	}
+	restore := func(id uuid.UUID, headers map[string]string) *http.Response {
+		t.Helper()
+		return testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/trash/"+id.String()+"/restore", nil, headers).Result()
+	}
+
+	rr := testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/projects", dto.ProjectRequest{Name: "Launch"}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	project := testutil.DecodeJSON[dto.ProjectEnvelope](t, rr).Data
+	parent := create("Plan", nil, nil, f.member)
+	child := create("Draft", &parent, nil, f.member)
+	create("Review", &child, nil, f.member)
+	inProject := create("Ship", nil, &project.ID, f.member)
+
+	// A deleted task takes its subtasks along; only the task itself is listed.
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, f.tasksPath()+"/"+parent.String(), nil, f.member)
+	require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+	for _, id := range []uuid.UUID{parent, child} {
+		rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+id.String(), nil, f.member)
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	}
+	items := trash("")
+	require.Len(t, items, 1)
+	require.Equal(t, models.TrashTask, items[0].Kind)
+	require.Equal(t, parent, items[0].ID)
+	require.Equal(t, 3, items[0].TaskCount)
+
+	// Projects go to the trash with their tasks, and only admins can restore them.
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, teamPath+"/projects/"+project.ID.String(), nil, f.founder)
+	require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+inProject.String(), nil, f.member)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	items = trash("")
+	require.Len(t, items, 2)
+	require.Equal(t, models.TrashProject, items[0].Kind)
+	require.Len(t, trash("?kind=project"), 1)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/trash?kind=team", nil, f.member)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		headers map[string]string
+		want    int
+	}{
+		{"member restores a project", project.ID, f.member, http.StatusForbidden},
+		{"subtask is restored with its parent", child, f.member, http.StatusNotFound},
+		{"unknown item", uuid.New(), f.member, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, restore(tt.id, tt.headers).StatusCode)
+		})
+	}
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/trash", nil, map[string]string{})
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	res := restore(project.ID, f.founder)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+inProject.String(), nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	// A task deleted on its own while its parent is in the trash waits for the parent.
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, f.tasksPath()+"/"+inProject.String(), nil, f.member)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, teamPath+"/projects/"+project.ID.String(), nil, f.founder)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/trash/"+inProject.String()+"/restore", nil, f.member)
+	require.Equal(t, http.StatusConflict, rr.Code)
+	require.Equal(t, dto.CodeParentInTrash, testutil.DecodeJSON[dto.ErrorEnvelope](t, rr).Data.Code)
+	require.Equal(t, http.StatusOK, restore(project.ID, f.founder).StatusCode)
+	require.Equal(t, http.StatusOK, restore(inProject, f.member).StatusCode)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/trash/"+parent.String()+"/restore", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Equal(t, 3, testutil.DecodeJSON[dto.TrashItemEnvelope](t, rr).Data.TaskCount)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+parent.String()+"/activity?action=task.restored", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Len(t, testutil.DecodeJSON[dto.TaskActivitiesEnvelope](t, rr).Data, 1)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath()+"/"+child.String(), nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Empty(t, trash(""))
+
+	// Deleted comments come back with their body.
+	commentsPath := f.tasksPath() + "/" + parent.String() + "/comments"
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, commentsPath, dto.CommentRequest{Body: "Looks good"}, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	comment := testutil.DecodeJSON[dto.CommentEnvelope](t, rr).Data
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, commentsPath+"/"+comment.ID.String(), nil, f.member)
+	require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+	items = trash("?kind=comment")
+	require.Len(t, items, 1)
+	require.Equal(t, "Looks good", items[0].Title)
+	require.Equal(t, parent, *items[0].TaskID)
+	require.Equal(t, http.StatusOK, restore(comment.ID, f.member).StatusCode)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, commentsPath, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "Looks good", testutil.DecodeJSON[dto.CommentsEnvelope](t, rr).Data[0].Body)
+
+	// Purging removes what was deleted before the cutoff for good.
+	rr = testutil.DoJSON(t, f.r, http.MethodDelete, f.tasksPath()+"/"+child.String(), nil, f.member)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	n, err := f.uow.Trash().PurgeTrash(context.Background(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, n)
+	n, err = f.uow.Trash().PurgeTrash(context.Background(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Empty(t, trash(""))
+	require.Equal(t, http.StatusNotFound, restore(child, f.member).StatusCode)
+}
+
+func TestTrashTeam_SQLite(t *testing.T) {
+	f := newFixture(t)
+	teamPath := "/api/v1/team/" + f.teamID.String()
+
+	rr := testutil.DoJSON(t, f.r, http.MethodDelete, teamPath, nil, f.founder)
+	require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath(), nil, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/team/trash", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Empty(t, testutil.DecodeJSON[dto.TrashEnvelope](t, rr).Data)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/team/trash", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	teams := testutil.DecodeJSON[dto.TrashEnvelope](t, rr).Data
+	require.Len(t, teams, 1)
+	require.Equal(t, models.TrashTeam, teams[0].Kind)
+	require.Equal(t, "Ops", teams[0].Title)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/restore", nil, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/restore", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/restore", nil, f.founder)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, f.tasksPath(), nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/audit?action=team.restore", nil, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Len(t, testutil.DecodeJSON[dto.TeamAuditEnvelope](t, rr).Data, 1)
+}
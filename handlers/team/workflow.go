@@ -0,0 +1,337 @@
+package team
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TeamGetWorkflow godoc
+// @Summary Get the team workflow
+// @Description Ordered workflow states and the allowed transitions between them.
+// @Tags workflow
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} dto.WorkflowEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/workflow [get]
+func (r *TeamsHandler) TeamGetWorkflow(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	w, err := r.uow.Workflows().GetWorkflow(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "workflow").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, w)
+}
+
+// TeamPutWorkflow godoc
+// @Summary Replace the team workflow
+// @Description Only team admins and founders can change the workflow. Existing states are matched by id;
+// @Description states that are left out are deleted, which is refused while tasks are still in them.
+// @Tags workflow
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body dto.WorkflowRequest true "Workflow"
+// @Success 200 {object} dto.WorkflowEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/workflow [put]
+func (r *TeamsHandler) TeamPutWorkflow(c *gin.Context) {
+	teamID, _, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	if role != models.AdminUserRole && role != models.FounderUserRole {
+		dto.Forbidden(dto.CodeForbidden, "only admin or founder can edit the workflow", nil).Send(c)
+		return
+	}
+
+	req := dto.WorkflowRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	for i := range req.States {
+		req.States[i].Name = strings.TrimSpace(req.States[i].Name)
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	current, err := tx.Workflows().GetWorkflow(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "workflow").Send(c)
+		return
+	}
+	w, msg := buildWorkflow(teamID, current, req)
+	if msg != "" {
+		dto.BadRequest(dto.CodeInvalidWorkflow, msg, nil).Send(c)
+		return
+	}
+	if err := tx.Workflows().SaveWorkflow(c.Request.Context(), teamID, w); err != nil {
+		if errors.Is(err, repositories.ErrForeignKey) {
+			dto.Conflict(dto.CodeInvalidWorkflow, "states that still have tasks, including tasks in the trash, cannot be removed", nil).Send(c)
+			return
+		}
+		dto.RepoError(err, "workflow").Send(c)
+		return
+	}
+	details := map[string]any{"old": workflowSummary(current), "new": workflowSummary(w)}
+	if !recordTeamAudit(c, tx, teamID, models.TeamActionWorkflowUpdate, details) {
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "workflow").Send(c)
+		return
+	}
+	trace.Log(c, "workflow_updated", "team_id="+teamID.String())
+
+	dto.OK(c, http.StatusOK, w)
+}
+
+// workflowSummary describes w by state names for the audit log: the states in order
+// and the roles allowed to make every transition, keyed "From -> To".
+func workflowSummary(w *models.Workflow) map[string]any {
+	states := make([]string, len(w.States))
+	for i, st := range w.States {
+		states[i] = st.Name
+	}
+	transitions := make(map[string][]models.TeamUserRole, len(w.Transitions))
+	for _, t := range w.Transitions {
+		from, to := w.State(t.FromStateID), w.State(t.ToStateID)
+		if from != nil && to != nil {
+			transitions[from.Name+" -> "+to.Name] = t.Roles
+		}
+	}
+	return map[string]any{"states": states, "transitions": transitions}
+}
+
+// buildWorkflow checks req against the current workflow of the team. It returns a
+// non-empty message when the request is not a valid workflow.
+func buildWorkflow(teamID uuid.UUID, current *models.Workflow, req dto.WorkflowRequest) (*models.Workflow, string) {
+	w := &models.Workflow{States: []models.WorkflowState{}, Transitions: []models.WorkflowTransition{}}
+	byName := map[string]uuid.UUID{}
+	seenIDs := map[uuid.UUID]bool{}
+	categories := map[models.WorkflowCategory]bool{}
+
+	for i, s := range req.States {
+		if !s.Category.IsValid() {
+			return nil, "invalid category for state " + s.Name
+		}
+		key := strings.ToLower(s.Name)
+		if _, dup := byName[key]; dup {
+			return nil, "duplicate state name " + s.Name
+		}
+		id := uuid.New()
+		if s.ID != nil {
+			if current.State(*s.ID) == nil || seenIDs[*s.ID] {
+				return nil, "unknown state id for state " + s.Name
+			}
+			id = *s.ID
+		}
+		seenIDs[id] = true
+		byName[key] = id
+		categories[s.Category] = true
+		w.States = append(w.States, models.WorkflowState{
+			ID:       id,
+			TeamID:   teamID,
+			Name:     s.Name,
+			Category: s.Category,
+			Position: i,
+		})
+	}
+	if !categories[models.CategoryNotStarted] || !categories[models.CategoryDone] {
+		return nil, "a workflow needs at least one not_started and one done state"
+	}
+
+	edges := map[[2]uuid.UUID]bool{}
+	for _, t := range req.Transitions {
+		from, okFrom := byName[strings.ToLower(strings.TrimSpace(t.From))]
+		to, okTo := byName[strings.ToLower(strings.TrimSpace(t.To))]
+		if !okFrom || !okTo {
+			return nil, "transition " + t.From + " -> " + t.To + " uses an unknown state"
+		}
+		if from == to || edges[[2]uuid.UUID{from, to}] {
+			return nil, "invalid or duplicate transition " + t.From + " -> " + t.To
+		}
+		edges[[2]uuid.UUID{from, to}] = true
+		for _, role := range t.Roles {
+			if !role.IsValid() {
+				return nil, "invalid role " + string(role)
+			}
+		}
+		roles := t.Roles
+		if roles == nil {
+			roles = []models.TeamUserRole{}
+		}
+		w.Transitions = append(w.Transitions, models.WorkflowTransition{
+			ID:          uuid.New(),
+			TeamID:      teamID,
+			FromStateID: from,
+			ToStateID:   to,
+			Roles:       roles,
+		})
+	}
+	return w, ""
+}
+
+// TeamSetTaskState godoc
+// @Summary Move a task to another workflow state
+// @Description The move must be allowed by the transition graph of the team and by the roles of the transition.
+// @Description Moving into a done state is refused while blockers are not done, unless force is set.
+// @Description Completing the current instance of an on_complete recurring task creates the next instance.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param task_id path string true "Task ID"
+// @Param request body dto.TaskStateRequest true "Target state"
+// @Success 200 {object} dto.TeamsTaskEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /team/{id}/tasks/{task_id}/state [post]
+func (r *TeamsHandler) TeamSetTaskState(c *gin.Context) {
+	teamID, _, role, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+
+	req := dto.TaskStateRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+
+	task, ok := r.loadTask(c, tx.Tasks(), teamID)
+	if !ok {
+		return
+	}
+	w, err := tx.Workflows().GetWorkflow(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "workflow").Send(c)
+		return
+	}
+	to, ok := checkTransition(c, w, task, req.StateID, role)
+	if !ok {
+		return
+	}
+	if task.StateID == nil || *task.StateID != to.ID {
+		if !moveTaskState(c, tx, task, to, req.Force) {
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			dto.RepoError(err, "task").Send(c)
+			return
+		}
+		trace.Log(c, "task_state_changed", "task_id="+task.ID.String()+" state="+to.Name+" force="+strconv.FormatBool(req.Force))
+	}
+	dto.OK(c, http.StatusOK, task)
+}
+
+// moveTaskState moves task into the state to within tx, once checkTransition allowed
+// it. Moving into a done state is refused while blockers are not done, unless force is
+// set, and creates the next instance of on_complete recurring tasks. The move is recorded
+// in the task activity. It sends the error response and returns false when the move fails.
+func moveTaskState(c *gin.Context, tx repositories.Transaction, task *models.Task, to *models.WorkflowState, force bool) bool {
+	if to.Category == models.CategoryDone && !force {
+		blockers, err := tx.TaskLinks().ListOpenBlockers(c.Request.Context(), task.ID)
+		if err != nil {
+			dto.RepoError(err, "task").Send(c)
+			return false
+		}
+		if len(blockers) > 0 {
+			dto.Conflict(dto.CodeTaskBlocked, "the task is blocked by tasks that are not done", map[string]any{"blocker_ids": blockers}).Send(c)
+			return false
+		}
+	}
+	if err := tx.Tasks().UpdateTaskState(c.Request.Context(), task.TeamID, task.ID, to.ID); err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return false
+	}
+	var from any
+	if task.StateID != nil {
+		from = *task.StateID
+	}
+	change := models.FieldChange{Field: "state_id", Old: from, New: to.ID}
+	if !recordActivity(c, tx, task, models.ActivityStateChanged, []models.FieldChange{change}, map[string]any{"state": to.Name}) {
+		return false
+	}
+	if to.Category == models.CategoryDone && !advanceOnComplete(c, tx, task) {
+		return false
+	}
+	task.StateID = &to.ID
+	return true
+}
+
+// checkTransition validates moving task to the state toID with the caller's team role.
+// Staying in the same state is always allowed. It sends the error response and returns
+// ok=false when the move is refused.
+func checkTransition(c *gin.Context, w *models.Workflow, task *models.Task, toID uuid.UUID, role models.TeamUserRole) (*models.WorkflowState, bool) {
+	to := w.State(toID)
+	if to == nil {
+		dto.BadRequest(dto.CodeInvalidWorkflow, "unknown workflow state", nil).Send(c)
+		return nil, false
+	}
+	// Tasks without a state may move anywhere.
+	if task.StateID == nil || *task.StateID == to.ID {
+		return to, true
+	}
+	t, ok := w.Transition(*task.StateID, to.ID)
+	if !ok {
+		from := w.State(*task.StateID)
+		details := map[string]any{"to": to.Name}
+		if from != nil {
+			details["from"] = from.Name
+		}
+		dto.Conflict(dto.CodeTransitionNotAllowed, "the workflow does not allow this transition", details).Send(c)
+		return nil, false
+	}
+	if t != nil && !t.Allows(role) {
+		dto.Forbidden(dto.CodeTransitionNotAllowed, "your team role cannot make this transition", map[string]any{"roles": t.Roles}).Send(c)
+		return nil, false
+	}
+	return to, true
+}
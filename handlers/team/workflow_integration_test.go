@@ -0,0 +1,75 @@
+package team_test
+
+import (
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowTransitions_SQLite(t *testing.T) {
+	f := newFixture(t)
+	workflowPath := "/api/v1/team/" + f.teamID.String() + "/workflow"
+
+	rr := testutil.DoJSON(t, f.r, http.MethodGet, workflowPath, nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	w := testutil.DecodeJSON[dto.WorkflowEnvelope](t, rr).Data
+	require.Len(t, w.States, 3)
+	todo, doing, done := w.States[0], w.States[1], w.States[2]
+
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), dto.TaskCreationRequest{Title: "Ship it"}, f.member)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	task := testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data
+	require.Equal(t, todo.ID, *task.StateID)
+
+	req := dto.WorkflowRequest{
+		States: []dto.WorkflowStateRequest{
+			{ID: &todo.ID, Name: todo.Name, Category: todo.Category},
+			{ID: &doing.ID, Name: doing.Name, Category: doing.Category},
+			{ID: &done.ID, Name: done.Name, Category: done.Category},
+		},
+		Transitions: []dto.WorkflowTransitionRequest{
+			{From: "To Do", To: "In Progress"},
+			{From: "In Progress", To: "Done", Roles: []models.TeamUserRole{models.AdminUserRole, models.FounderUserRole}},
+		},
+	}
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, workflowPath, req, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, workflowPath, req, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	// States that still hold tasks cannot be removed.
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, workflowPath, dto.WorkflowRequest{States: req.States[1:]}, f.founder)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, workflowPath, dto.WorkflowRequest{States: []dto.WorkflowStateRequest{
+		{Name: "Backlog", Category: models.CategoryNotStarted},
+		req.States[1], req.States[2],
+	}}, f.founder)
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	statePath := f.tasksPath() + "/" + task.ID.String() + "/state"
+	tests := []struct {
+		name       string
+		auth       map[string]string
+		to         models.WorkflowState
+		wantStatus int
+	}{
+		{"skipping a state is not in the graph", f.member, done, http.StatusConflict},
+		{"member starts the task", f.member, doing, http.StatusOK},
+		{"member cannot finish", f.member, done, http.StatusForbidden},
+		{"founder finishes", f.founder, done, http.StatusOK},
+		{"staying in the same state is allowed", f.member, done, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := testutil.DoJSON(t, f.r, http.MethodPost, statePath, dto.TaskStateRequest{StateID: tt.to.ID}, tt.auth)
+			require.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus == http.StatusOK {
+				require.Equal(t, tt.to.ID, *testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.StateID)
+			}
+		})
+	}
+}
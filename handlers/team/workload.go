@@ -0,0 +1,237 @@
+package team
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/trace"
+	"task_manager/public/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxWorkloadWeeks is the longest horizon of a workload report.
+const maxWorkloadWeeks = 26
+
+// TeamGetEstimation godoc
+// @Summary Get the estimation settings of a team
+// @Description The unit of task estimates (points or hours), the default weekly capacity of the members and the
+// @Description capacity of every member, all in that unit.
+// @Tags workload
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Success 200 {object} dto.EstimationEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/estimation [get]
+func (r *TeamsHandler) TeamGetEstimation(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	est, err := r.uow.Estimation().GetEstimation(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, est)
+}
+
+// TeamPutEstimation godoc
+// @Summary Set the estimation settings of a team
+// @Description Only team admins and founders can change the settings. Changing the unit keeps the numbers of the
+// @Description existing estimates and capacities.
+// @Tags workload
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param request body dto.EstimationRequest true "Settings"
+// @Success 200 {object} dto.EstimationEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/estimation [put]
+func (r *TeamsHandler) TeamPutEstimation(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage estimation")
+	if !ok {
+		return
+	}
+
+	req := dto.EstimationRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	est, err := r.uow.Estimation().GetEstimation(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	est.Unit = req.Unit
+	est.WeeklyCapacity = req.WeeklyCapacity
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+	if err := tx.Estimation().UpdateEstimation(c.Request.Context(), teamID, est); err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	trace.Log(c, "estimation_updated", "team_id="+teamID.String()+" unit="+string(est.Unit))
+
+	dto.OK(c, http.StatusOK, est)
+}
+
+// TeamPutMemberCapacity godoc
+// @Summary Set the weekly capacity of a member
+// @Description Only team admins and founders can set capacities. A null capacity makes the member use the weekly
+// @Description capacity of the team.
+// @Tags workload
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param user_id path string true "User ID"
+// @Param request body dto.MemberCapacityRequest true "Capacity"
+// @Success 200 {object} dto.EstimationEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /team/{id}/members/{user_id}/capacity [put]
+func (r *TeamsHandler) TeamPutMemberCapacity(c *gin.Context) {
+	teamID, ok := r.requireTeamAdmin(c, "manage capacities")
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(strings.TrimSpace(c.Param("user_id")))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id", nil).Send(c)
+		return
+	}
+
+	req := dto.MemberCapacityRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	tx, ok := r.beginTeamWrite(c, teamID)
+	if !ok {
+		return
+	}
+	defer tx.Stop()
+	if err := tx.Estimation().SetMemberCapacity(c.Request.Context(), teamID, userID, req.WeeklyCapacity); err != nil {
+		dto.RepoError(err, "team member").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "team member").Send(c)
+		return
+	}
+	est, err := r.uow.Estimation().GetEstimation(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	trace.Log(c, "member_capacity_updated", "team_id="+teamID.String()+" user_id="+userID.String())
+
+	dto.OK(c, http.StatusOK, est)
+}
+
+// TeamGetWorkload godoc
+// @Summary Get the workload of the team members
+// @Description Estimates of the open assigned tasks per member and week, weeks starting on Monday (UTC). The
+// @Description estimate of a task is shared evenly by its assignees. Tasks in a planned or active sprint are spread
+// @Description over the days left in the sprint, other tasks count in the week they are due; overdue tasks count in
+// @Description the first week. Tasks with neither a sprint nor a due date are reported as unscheduled. A week is
+// @Description over-allocated when its load exceeds the weekly capacity of the member.
+// @Tags workload
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Team ID"
+// @Param from query string false "Day in the first week, YYYY-MM-DD; defaults to today"
+// @Param weeks query int false "Number of weeks, 1 to 26; defaults to 4"
+// @Param user_id query string false "Only this member"
+// @Success 200 {object} dto.WorkloadEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 403 {object} dto.ErrorEnvelope
+// @Router /team/{id}/workload [get]
+func (r *TeamsHandler) TeamGetWorkload(c *gin.Context) {
+	teamID, _, _, ok := r.requireMember(c)
+	if !ok {
+		return
+	}
+	from := time.Now()
+	if raw := strings.TrimSpace(c.Query("from")); raw != "" {
+		day, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			dto.BadRequest(dto.CodeInvalidRequest, "from must be a date as YYYY-MM-DD", nil).Send(c)
+			return
+		}
+		from = day
+	}
+	weeks := 4
+	if raw := strings.TrimSpace(c.Query("weeks")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxWorkloadWeeks {
+			dto.BadRequest(dto.CodeInvalidRequest, "weeks must be between 1 and 26", nil).Send(c)
+			return
+		}
+		weeks = n
+	}
+	var only *uuid.UUID
+	if raw := strings.TrimSpace(c.Query("user_id")); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			dto.BadRequest(dto.CodeInvalidRequest, "invalid user id", nil).Send(c)
+			return
+		}
+		only = &id
+	}
+
+	est, err := r.uow.Estimation().GetEstimation(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	if only != nil {
+		members := []models.MemberCapacity{}
+		for _, m := range est.Members {
+			if m.UserID == *only {
+				members = append(members, m)
+			}
+		}
+		est.Members = members
+	}
+	tasks, err := r.uow.Estimation().ListWorkloadTasks(c.Request.Context(), teamID)
+	if err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, models.BuildWorkload(est, tasks, from, weeks))
+}
@@ -0,0 +1,115 @@
+package team_test
+
+import (
+	"context"
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/repositories/models"
+	"task_manager/public/testutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkload_SQLite(t *testing.T) {
+	f := newFixture(t)
+	teamPath := "/api/v1/team/" + f.teamID.String()
+	founder, err := f.uow.Users().GetUserByEmail(context.Background(), "founder@example.com")
+	require.NoError(t, err)
+
+	rr := testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/estimation", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	est := testutil.DecodeJSON[dto.EstimationEnvelope](t, rr).Data
+	require.Equal(t, models.EstimatePoints, est.Unit)
+	require.Nil(t, est.WeeklyCapacity)
+	require.Len(t, est.Members, 2)
+
+	ten, five := 10.0, 5.0
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, teamPath+"/estimation", dto.EstimationRequest{Unit: models.EstimateHours, WeeklyCapacity: &ten}, f.member)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, teamPath+"/estimation", dto.EstimationRequest{Unit: "days"}, f.founder)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, teamPath+"/estimation", dto.EstimationRequest{Unit: models.EstimateHours, WeeklyCapacity: &ten}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, teamPath+"/members/"+f.memberID.String()+"/capacity", dto.MemberCapacityRequest{WeeklyCapacity: &five}, f.founder)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = testutil.DoJSON(t, f.r, http.MethodPut, teamPath+"/members/"+f.outsiderID.String()+"/capacity", dto.MemberCapacityRequest{WeeklyCapacity: &five}, f.founder)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	week := models.WeekStart(time.Now()).AddDate(0, 0, 7)
+	create := func(req dto.TaskCreationRequest) uuid.UUID {
+		t.Helper()
+		rr := testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath(), req, f.founder)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		return testutil.DecodeJSON[dto.TeamsTaskEnvelope](t, rr).Data.ID
+	}
+	estimate := func(v float64) *float64 { return &v }
+	due := week.Add(50 * time.Hour)
+	member, both := []uuid.UUID{f.memberID}, []uuid.UUID{f.memberID, founder.ID}
+	create(dto.TaskCreationRequest{Title: "Migrate", Estimate: estimate(4), DueAt: &due, AssigneeIDs: member})
+	create(dto.TaskCreationRequest{Title: "Pair review", Estimate: estimate(4), DueAt: &due, AssigneeIDs: both})
+	create(dto.TaskCreationRequest{Title: "Research", Estimate: estimate(3), AssigneeIDs: member})
+	create(dto.TaskCreationRequest{Title: "Unsized", AssigneeIDs: member})
+	create(dto.TaskCreationRequest{Title: "Nobody's", Estimate: estimate(40), DueAt: &due})
+	done := create(dto.TaskCreationRequest{Title: "Shipped", Estimate: estimate(40), DueAt: &due, AssigneeIDs: member})
+
+	// A two-week sprint starting in the second week.
+	rr = testutil.DoJSON(t, f.r, http.MethodPost, teamPath+"/sprints", dto.SprintRequest{
+		Name: "Sprint 1", StartDate: week.AddDate(0, 0, 7), EndDate: week.AddDate(0, 0, 20),
+	}, f.founder)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	sprint := testutil.DecodeJSON[dto.SprintEnvelope](t, rr).Data
+	create(dto.TaskCreationRequest{Title: "Sprint work", Estimate: estimate(14), SprintID: &sprint.ID, AssigneeIDs: []uuid.UUID{founder.ID}})
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, "/api/v1/team/"+f.teamID.String()+"/workflow", nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	for _, s := range testutil.DecodeJSON[dto.WorkflowEnvelope](t, rr).Data.States {
+		if s.Category == models.CategoryDone {
+			rr = testutil.DoJSON(t, f.r, http.MethodPost, f.tasksPath()+"/"+done.String()+"/state", dto.TaskStateRequest{StateID: s.ID}, f.founder)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		}
+	}
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/workload?weeks=3&from="+week.Format(time.DateOnly), nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	workload := testutil.DecodeJSON[dto.WorkloadEnvelope](t, rr).Data
+	require.Equal(t, models.EstimateHours, workload.Unit)
+	require.True(t, workload.From.Equal(week))
+	byUser := map[uuid.UUID]*models.MemberWorkload{}
+	for _, m := range workload.Members {
+		byUser[m.UserID] = m
+	}
+	require.Len(t, byUser, 2)
+
+	m := byUser[f.memberID]
+	require.Equal(t, five, *m.WeeklyCapacity)
+	require.Equal(t, []float64{6, 0, 0}, loads(m))
+	require.Equal(t, 2, m.Weeks[0].Tasks)
+	require.True(t, m.Weeks[0].OverAllocated)
+	require.True(t, m.OverAllocated)
+	require.Equal(t, 3.0, m.Unscheduled)
+	require.Equal(t, 1, m.Unestimated)
+
+	fw := byUser[founder.ID]
+	require.Equal(t, ten, *fw.WeeklyCapacity)
+	require.Equal(t, []float64{2, 7, 7}, loads(fw))
+	require.False(t, fw.OverAllocated)
+
+	rr = testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/workload?user_id="+founder.ID.String(), nil, f.member)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, testutil.DecodeJSON[dto.WorkloadEnvelope](t, rr).Data.Members, 1)
+	for _, query := range []string{"?weeks=0", "?weeks=27", "?from=tomorrow", "?user_id=me"} {
+		rr = testutil.DoJSON(t, f.r, http.MethodGet, teamPath+"/workload"+query, nil, f.member)
+		require.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func loads(m *models.MemberWorkload) []float64 {
+	out := make([]float64, len(m.Weeks))
+	for i, w := range m.Weeks {
+		out[i] = w.Load
+	}
+	return out
+}
@@ -4,14 +4,30 @@ import (
 	"net/http"
 	"task_manager/public/dto"
 	"task_manager/public/jwtauth"
+	"task_manager/public/repositories"
 	"task_manager/public/repositories/models"
 
 	jwt "github.com/appleboy/gin-jwt/v3"
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(rg *gin.RouterGroup, AuthMiddleware gin.HandlerFunc) {
+type Handler struct {
+	uow repositories.UnitOfWork
+}
+
+func NewHandler(uow repositories.UnitOfWork) *Handler {
+	return &Handler{uow: uow}
+}
+
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup, AuthMiddleware gin.HandlerFunc) {
 	rg.GET("/me", AuthMiddleware, Me)
+	rg.GET("/me/tasks", AuthMiddleware, h.MyTasks)
+	rg.GET("/me/notifications", AuthMiddleware, h.MyNotifications)
+	rg.POST("/me/notifications/:notification_id/read", AuthMiddleware, h.MyNotificationRead)
+	rg.GET("/me/preferences", AuthMiddleware, h.MyPreferences)
+	rg.PATCH("/me/preferences", AuthMiddleware, h.MyPreferencesUpdate)
+	rg.GET("/me/timer", AuthMiddleware, h.MyTimer)
+	rg.POST("/me/timer/stop", AuthMiddleware, h.MyTimerStop)
 }
 
 // Me godoc
@@ -38,5 +54,7 @@ func Me(c *gin.Context) {
 		LastName:  last,
 		Email:     email,
 		UserType:  user_type,
+
+		ImpersonatedBy: jwtauth.ActorID(c),
 	})
 }
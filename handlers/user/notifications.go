@@ -0,0 +1,74 @@
+package user
+
+import (
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/jwtauth"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories/listspec"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// MyNotifications godoc
+// @Summary List my notifications
+// @Tags user
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from meta.next_cursor of the previous page"
+// @Param sort query string false "created_at or -created_at (default)"
+// @Param include_total query bool false "Include meta.total"
+// @Param read_at[isnull] query bool false "true for unread notifications only"
+// @Param team_id query string false "Filter by team"
+// @Success 200 {object} dto.NotificationsEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Router /user/me/notifications [get]
+func (h *Handler) MyNotifications(c *gin.Context) {
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return
+	}
+	q, err := listquery.Parse(c.Request.URL.Query(), listspec.Notifications)
+	if err != nil {
+		dto.ListQueryError(err).Send(c)
+		return
+	}
+	notifications, err := h.uow.Notifications().ListUserNotifications(c.Request.Context(), userID, q)
+	if err != nil {
+		dto.RepoError(err, "notification").Send(c)
+		return
+	}
+	dto.OKPage(c, notifications)
+}
+
+// MyNotificationRead godoc
+// @Summary Mark a notification as read
+// @Tags user
+// @Security BearerAuth
+// @Param notification_id path string true "Notification ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /user/me/notifications/{notification_id}/read [post]
+func (h *Handler) MyNotificationRead(c *gin.Context) {
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return
+	}
+	notificationID, err := uuid.Parse(c.Param("notification_id"))
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid notification id", nil).Send(c)
+		return
+	}
+	if err := h.uow.Notifications().MarkNotificationRead(c.Request.Context(), userID, notificationID); err != nil {
+		dto.RepoError(err, "notification").Send(c)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
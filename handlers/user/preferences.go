@@ -0,0 +1,110 @@
+package user
+
+import (
+	"net/http"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/jwtauth"
+	"task_manager/public/repositories/models"
+	"task_manager/public/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MyPreferences godoc
+// @Summary Get my notification preferences
+// @Tags user
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.UserPreferencesEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Router /user/me/preferences [get]
+func (h *Handler) MyPreferences(c *gin.Context) {
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return
+	}
+	p, err := h.uow.Reminders().GetUserPreferences(c.Request.Context(), userID)
+	if err != nil {
+		dto.RepoError(err, "preferences").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, p)
+}
+
+// MyPreferencesUpdate godoc
+// @Summary Update my notification preferences
+// @Description The time zone is used for quiet hours and the dates in emails. Reminders and overdue notices
+// @Description falling into the quiet hours are delivered when they end.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.UserPreferencesUpdateRequest true "Changes"
+// @Success 200 {object} dto.UserPreferencesEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Router /user/me/preferences [patch]
+func (h *Handler) MyPreferencesUpdate(c *gin.Context) {
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return
+	}
+
+	req := dto.UserPreferencesUpdateRequest{}
+	if err := c.BindJSON(&req); err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid request", nil).Send(c)
+		return
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		msg, details, dbg := validation.Format(err)
+		dto.Fail(c, http.StatusBadRequest, dto.CodeValidationError, msg, dbg, details)
+		return
+	}
+
+	p, err := h.uow.Reminders().GetUserPreferences(c.Request.Context(), userID)
+	if err != nil {
+		dto.RepoError(err, "preferences").Send(c)
+		return
+	}
+	if req.Timezone != nil {
+		tz := strings.TrimSpace(*req.Timezone)
+		// "Local" would depend on the server.
+		if _, err := time.LoadLocation(tz); err != nil || tz == "" || tz == "Local" {
+			dto.BadRequest(dto.CodeValidationError, "unknown timezone", map[string]any{"timezone": tz}).Send(c)
+			return
+		}
+		p.Timezone = tz
+	}
+	switch {
+	case req.ClearQuietHours:
+		p.QuietHoursStart, p.QuietHoursEnd = nil, nil
+	case req.QuietHoursStart != nil || req.QuietHoursEnd != nil:
+		if req.QuietHoursStart == nil || req.QuietHoursEnd == nil {
+			dto.BadRequest(dto.CodeValidationError, "quiet_hours_start and quiet_hours_end must be set together", nil).Send(c)
+			return
+		}
+		for _, v := range []string{*req.QuietHoursStart, *req.QuietHoursEnd} {
+			if _, err := models.ParseClock(v); err != nil {
+				dto.BadRequest(dto.CodeValidationError, err.Error(), nil).Send(c)
+				return
+			}
+		}
+		p.QuietHoursStart, p.QuietHoursEnd = req.QuietHoursStart, req.QuietHoursEnd
+	}
+	if req.DefaultReminders != nil {
+		p.DefaultReminders = models.NormalizeReminders(req.DefaultReminders)
+	}
+	if req.EmailNotifications != nil {
+		p.EmailNotifications = *req.EmailNotifications
+	}
+	if err := h.uow.Reminders().SetUserPreferences(c.Request.Context(), p); err != nil {
+		dto.RepoError(err, "preferences").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, p)
+}
@@ -0,0 +1,47 @@
+package user
+
+import (
+	"task_manager/public/dto"
+	"task_manager/public/jwtauth"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories/listspec"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MyTasks godoc
+// @Summary List my tasks
+// @Description Tasks assigned to the current user and grouped tasks of the user's teams, across all teams.
+// @Tags user
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param cursor query string false "Cursor from meta.next_cursor of the previous page"
+// @Param sort query string false "Comma separated, prefix with - for descending: title, due_at, created_at, updated_at"
+// @Param include_total query bool false "Include meta.total"
+// @Param team_id query string false "Filter by team; also team_id[in]"
+// @Param group_task query bool false "Filter grouped or individual tasks"
+// @Param label_id[any] query string false "Comma separated label ids; tasks with at least one of them"
+// @Param label_id[all] query string false "Comma separated label ids; tasks with every one of them"
+// @Success 200 {object} dto.TasksEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Router /user/me/tasks [get]
+func (h *Handler) MyTasks(c *gin.Context) {
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return
+	}
+	q, err := listquery.Parse(c.Request.URL.Query(), listspec.Tasks)
+	if err != nil {
+		dto.ListQueryError(err).Send(c)
+		return
+	}
+	tasks, err := h.uow.Tasks().ListUserTasks(c.Request.Context(), userID, q)
+	if err != nil {
+		dto.RepoError(err, "task").Send(c)
+		return
+	}
+	dto.OKPage(c, tasks)
+}
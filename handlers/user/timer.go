@@ -0,0 +1,96 @@
+package user
+
+import (
+	"net/http"
+	"task_manager/public/dto"
+	"task_manager/public/jwtauth"
+	"task_manager/public/trace"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MyTimer godoc
+// @Summary Get my running timer
+// @Description The running timer of the caller, in whichever team it was started.
+// @Tags user
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.TimeEntryEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Router /user/me/timer [get]
+func (h *Handler) MyTimer(c *gin.Context) {
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return
+	}
+	entry, err := h.uow.TimeEntries().GetRunningTimer(c.Request.Context(), userID)
+	if err != nil {
+		dto.RepoError(err, "running timer").Send(c)
+		return
+	}
+	dto.OK(c, http.StatusOK, entry)
+}
+
+// MyTimerStop godoc
+// @Summary Stop my running timer
+// @Description Ends the running timer of the caller now and returns the completed time entry. Timers in an
+// @Description archived team cannot be stopped until the team is unarchived.
+// @Tags user
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.TimeEntryEnvelope
+// @Failure 400 {object} dto.ErrorEnvelope
+// @Failure 401 {object} dto.ErrorEnvelope
+// @Failure 404 {object} dto.ErrorEnvelope
+// @Failure 409 {object} dto.ErrorEnvelope
+// @Router /user/me/timer/stop [post]
+func (h *Handler) MyTimerStop(c *gin.Context) {
+	userID, err := jwtauth.CurrentUserID(c)
+	if err != nil {
+		dto.BadRequest(dto.CodeInvalidRequest, "invalid user id in state", nil).Send(c)
+		return
+	}
+
+	tx, err := h.uow.Begin(c.Request.Context())
+	if err != nil {
+		dto.Internal(dto.CodeDatabaseError, "database error", err.Error(), nil).Send(c)
+		return
+	}
+	defer tx.Stop()
+
+	if err := tx.TimeEntries().LockUserTimeEntries(c.Request.Context(), userID); err != nil {
+		dto.RepoError(err, "user").Send(c)
+		return
+	}
+	entry, err := tx.TimeEntries().GetRunningTimer(c.Request.Context(), userID)
+	if err != nil {
+		dto.RepoError(err, "running timer").Send(c)
+		return
+	}
+	// Archived teams are read-only, and the time entry belongs to the team.
+	team, err := tx.Teams().LockTeam(c.Request.Context(), entry.TeamID)
+	if err != nil {
+		dto.RepoError(err, "team").Send(c)
+		return
+	}
+	if team.Archived() {
+		dto.Conflict(dto.CodeTeamArchived, "the team is archived and read-only", map[string]any{"team_id": team.ID}).Send(c)
+		return
+	}
+	entry.Stop(time.Now().UTC().Truncate(time.Second))
+	if err := tx.TimeEntries().UpdateTimeEntry(c.Request.Context(), entry); err != nil {
+		dto.RepoError(err, "time entry").Send(c)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		dto.RepoError(err, "time entry").Send(c)
+		return
+	}
+	trace.Log(c, "timer_stopped", "task_id="+entry.TaskID.String()+" time_entry_id="+entry.ID.String())
+
+	dto.OK(c, http.StatusOK, entry)
+}
@@ -1,19 +1,27 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"task_manager/handlers/self"
+	"task_manager/public/blobstore"
 	"task_manager/public/config"
 	"task_manager/public/db"
 	"task_manager/public/dto"
+	"task_manager/public/jobs"
 	"task_manager/public/jwtauth"
+	"task_manager/public/listquery"
 	"task_manager/public/logging"
+	"task_manager/public/mailer"
 	"task_manager/public/repositories"
 	"task_manager/public/trace"
 	"task_manager/public/validation"
+	"time"
 
+	adminhandler "task_manager/handlers/admin"
 	authhandler "task_manager/handlers/auth"
 	oauthhandler "task_manager/handlers/oauth"
+	teamhandler "task_manager/handlers/team"
 	userhandler "task_manager/handlers/user"
 
 	"github.com/gin-contrib/cors"
@@ -53,8 +61,8 @@ func main() {
 
 	ginconfig := cors.DefaultConfig()
 	ginconfig.AllowOrigins = []string{cfg.FrontendURL}
-	ginconfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE"}
-	ginconfig.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", trace.HeaderKey}
+	ginconfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	ginconfig.AllowHeaders = []string{"Origin", "Content-Type", "Authorization", trace.HeaderKey, jwtauth.CSRFHeaderKey}
 
 	r.Use(cors.New(ginconfig))
 	r.Use(trace.Middleware())
@@ -79,6 +87,25 @@ func main() {
 	}
 	usersRepo := uow.Users()
 
+	blobs, err := blobstore.New(cfg)
+	if err != nil {
+		panic(err)
+	}
+	mail, err := mailer.New(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	// Background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go jobs.Every(jobsCtx, "blob_cleanup", time.Minute, jobs.NewBlobCleanup(uow, blobs).Run)
+	go jobs.Every(jobsCtx, "recurrence_scheduler", time.Minute, jobs.NewRecurrenceScheduler(uow).Run)
+	go jobs.Every(jobsCtx, "reminders", time.Minute, jobs.NewReminderWorker(uow).Run)
+	go jobs.Every(jobsCtx, "mail_sender", 30*time.Second, jobs.NewMailSender(uow, mail).Run)
+	go jobs.Every(jobsCtx, "audit_retention", time.Hour, jobs.NewAuditRetention(uow, cfg.TeamAuditRetentionDays).Run)
+	go jobs.Every(jobsCtx, "trash_purge", time.Hour, jobs.NewTrashPurge(uow, cfg.TrashRetentionDays).Run)
+
 	// Auth Middleware config
 	authMiddleware, err := jwtauth.New(usersRepo, cfg.JWTSecret)
 	if err != nil {
@@ -88,6 +115,8 @@ func main() {
 		panic(err)
 	}
 	authhandler.SetMiddleware(authMiddleware)
+	listquery.SetSecret([]byte(cfg.JWTSecret))
+	r.Use(jwtauth.CSRF(authMiddleware))
 
 	v1 := r.Group("/api/v1")
 	authH := authhandler.NewHandler(uow, authMiddleware)
@@ -105,7 +134,15 @@ func main() {
 
 	// User routes
 	userGroup := v1.Group("/user")
-	userhandler.RegisterRoutes(userGroup, authMiddleware.MiddlewareFunc())
+	userhandler.NewHandler(uow).RegisterRoutes(userGroup, authMiddleware.MiddlewareFunc())
+
+	// Team routes
+	teamGroup := v1.Group("/team")
+	teamhandler.NewTeamsHandlerWithConfig(uow, cfg, blobs).RegisterRoutes(teamGroup, authMiddleware.MiddlewareFunc())
+
+	// System administration routes
+	adminGroup := v1.Group("/admin")
+	adminhandler.NewHandler(uow, authMiddleware).RegisterRoutes(adminGroup, authMiddleware.MiddlewareFunc())
 
 	r.NoRoute(func(c *gin.Context) {
 		c.JSON(404, dto.NotFound(dto.CodeNotFound, "page not found", "DEFAULT_PAGE_HANDLER", nil))
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS admin_audit_logs;
+DROP TABLE IF EXISTS password_reset_tokens;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS password_reset_required,
+    DROP COLUMN IF EXISTS disabled_at;
@@ -0,0 +1,31 @@
+-- sqlfluff:dialect:postgres
+-- Account state managed by system administrators
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS disabled_at             TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- One-time tokens issued when an administrator forces a password reset
+CREATE TABLE IF NOT EXISTS password_reset_tokens
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash TEXT        NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Audit trail of every action taken through the admin API.
+-- target_user_id has no foreign key so entries outlive deleted users.
+CREATE TABLE IF NOT EXISTS admin_audit_logs
+(
+    id             UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    actor_id       UUID REFERENCES users (id) ON DELETE SET NULL,
+    action         TEXT        NOT NULL,
+    target_user_id UUID,
+    details        TEXT,
+    trace_id       TEXT,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_created_at ON admin_audit_logs (created_at);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_target ON admin_audit_logs (target_user_id);
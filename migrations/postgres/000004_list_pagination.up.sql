@@ -0,0 +1,15 @@
+-- sqlfluff:dialect:postgres
+-- The repositories have always used the singular name.
+ALTER TABLE IF EXISTS teams_invitations RENAME TO team_invitations;
+
+-- Timestamps used as keyset pagination keys for memberships and invitations.
+ALTER TABLE teams_users
+    ADD COLUMN IF NOT EXISTS joined_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE team_invitations
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_teams_users_team_user ON teams_users (team_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_teams_users_user ON teams_users (user_id);
+CREATE INDEX IF NOT EXISTS idx_teams_users_team_joined ON teams_users (team_id, joined_at, id);
+CREATE INDEX IF NOT EXISTS idx_team_invitations_to_user ON team_invitations (to_user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_teams_created_at ON teams (created_at, id);
@@ -0,0 +1,19 @@
+-- sqlfluff:dialect:postgres
+-- The old schema holds at most one user per task: the first assignee is kept.
+DROP INDEX IF EXISTS idx_tasks_team_created_at;
+DROP INDEX IF EXISTS idx_task_assignees_user;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS user_id_task UUID REFERENCES users (id) ON DELETE CASCADE;
+UPDATE tasks t
+SET user_id_task = (SELECT ta.user_id FROM task_assignees ta WHERE ta.task_id = t.id ORDER BY ta.user_id LIMIT 1);
+
+DROP TABLE IF EXISTS task_assignees;
+
+ALTER TABLE tasks ALTER COLUMN grouped DROP DEFAULT;
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS due_at,
+    DROP COLUMN IF EXISTS created_by,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS title;
@@ -0,0 +1,32 @@
+-- sqlfluff:dialect:postgres
+-- Tasks get a title and timestamps, and the single user_id_task column is replaced by a
+-- join table so a task can have any number of assignees.
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT 'Untitled task',
+    ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS created_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE tasks ALTER COLUMN title DROP DEFAULT;
+ALTER TABLE tasks ALTER COLUMN grouped SET DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS task_assignees
+(
+    task_id     UUID        NOT NULL,
+    user_id     UUID        NOT NULL,
+    assigned_by UUID,
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (task_id, user_id),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (assigned_by) REFERENCES users (id) ON DELETE SET NULL
+);
+INSERT INTO task_assignees (task_id, user_id)
+SELECT id, user_id_task FROM tasks WHERE user_id_task IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS user_id_task;
+
+CREATE INDEX IF NOT EXISTS idx_task_assignees_user ON task_assignees (user_id, task_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_team_created_at ON tasks (team_id, created_at, id);
@@ -0,0 +1,5 @@
+-- sqlfluff:dialect:postgres
+DROP INDEX IF EXISTS idx_tasks_team_state;
+ALTER TABLE tasks DROP COLUMN IF EXISTS state_id;
+DROP TABLE IF EXISTS workflow_transitions;
+DROP TABLE IF EXISTS workflow_states;
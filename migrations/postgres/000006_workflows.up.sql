@@ -0,0 +1,37 @@
+-- sqlfluff:dialect:postgres
+-- Per-team workflow: ordered states grouped into categories, and an optional graph of
+-- allowed transitions. A team without transitions allows every move.
+CREATE TABLE IF NOT EXISTS workflow_states
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id    UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    name       TEXT        NOT NULL,
+    category   TEXT        NOT NULL CHECK (category IN ('not_started', 'active', 'done')),
+    position   INTEGER     NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflow_states_team_name ON workflow_states (team_id, name);
+
+CREATE TABLE IF NOT EXISTS workflow_transitions
+(
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id       UUID NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    from_state_id UUID NOT NULL REFERENCES workflow_states (id) ON DELETE CASCADE,
+    to_state_id   UUID NOT NULL REFERENCES workflow_states (id) ON DELETE CASCADE,
+    roles         TEXT NOT NULL DEFAULT '[]' -- JSON array of team roles; empty means every role
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflow_transitions_edge ON workflow_transitions (from_state_id, to_state_id);
+CREATE INDEX IF NOT EXISTS idx_workflow_transitions_team ON workflow_transitions (team_id);
+
+-- States in use by tasks cannot be deleted.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS state_id UUID REFERENCES workflow_states (id);
+CREATE INDEX IF NOT EXISTS idx_tasks_team_state ON tasks (team_id, state_id);
+
+-- Existing teams get the default workflow; their tasks start in the first state.
+INSERT INTO workflow_states (team_id, name, category, position)
+SELECT t.id, s.name, s.category, s.position
+FROM teams t
+CROSS JOIN (VALUES ('To Do', 'not_started', 0), ('In Progress', 'active', 1), ('Done', 'done', 2)) AS s (name, category, position);
+
+UPDATE tasks
+SET state_id = (SELECT ws.id FROM workflow_states ws WHERE ws.team_id = tasks.team_id AND ws.position = 0);
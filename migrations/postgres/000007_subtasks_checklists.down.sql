@@ -0,0 +1,5 @@
+-- sqlfluff:dialect:postgres
+-- Subtasks become top-level tasks.
+DROP TABLE IF EXISTS task_checklist_items;
+DROP INDEX IF EXISTS idx_tasks_parent;
+ALTER TABLE tasks DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,17 @@
+-- sqlfluff:dialect:postgres
+-- Subtasks: deleting a task deletes its whole subtree.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES tasks (id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_tasks_parent ON tasks (parent_id);
+
+-- Lightweight checklist items that are not full tasks.
+CREATE TABLE IF NOT EXISTS task_checklist_items
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    task_id    UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    content    TEXT        NOT NULL,
+    done       BOOLEAN     NOT NULL DEFAULT FALSE,
+    position   INTEGER     NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_task_checklist_items_task ON task_checklist_items (task_id, position);
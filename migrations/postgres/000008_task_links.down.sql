@@ -0,0 +1,3 @@
+-- sqlfluff:dialect:postgres
+DROP TABLE IF EXISTS task_links;
+ALTER TABLE tasks DROP COLUMN IF EXISTS estimate;
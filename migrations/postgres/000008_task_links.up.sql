@@ -0,0 +1,20 @@
+-- sqlfluff:dialect:postgres
+-- Estimated effort of a task, used by the critical path of the dependency graph.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimate DOUBLE PRECISION;
+
+-- Links between tasks of the same team. A "blocks" link means from_task_id has to be
+-- done before to_task_id; "relates_to" links carry no ordering.
+CREATE TABLE IF NOT EXISTS task_links
+(
+    id           UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id      UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    from_task_id UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    to_task_id   UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    kind         TEXT        NOT NULL CHECK (kind IN ('blocks', 'relates_to')),
+    created_by   UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (from_task_id <> to_task_id),
+    UNIQUE (from_task_id, to_task_id, kind)
+);
+CREATE INDEX IF NOT EXISTS idx_task_links_to ON task_links (to_task_id, kind);
+CREATE INDEX IF NOT EXISTS idx_task_links_team ON task_links (team_id);
@@ -0,0 +1,52 @@
+-- sqlfluff:dialect:postgres
+-- Comments on tasks. Replies point at a top-level comment (one level of threading).
+-- body is the Markdown source, body_html its sanitized rendering.
+CREATE TABLE IF NOT EXISTS task_comments
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id    UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    task_id    UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    parent_id  UUID REFERENCES task_comments (id) ON DELETE CASCADE,
+    author_id  UUID REFERENCES users (id) ON DELETE SET NULL,
+    body       TEXT        NOT NULL,
+    body_html  TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    edited_at  TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_task_comments_task_created_at ON task_comments (task_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_task_comments_parent ON task_comments (parent_id);
+
+-- Previous bodies of edited comments.
+CREATE TABLE IF NOT EXISTS task_comment_revisions
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    comment_id UUID        NOT NULL REFERENCES task_comments (id) ON DELETE CASCADE,
+    body       TEXT        NOT NULL,
+    edited_by  UUID REFERENCES users (id) ON DELETE SET NULL,
+    edited_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_task_comment_revisions_comment ON task_comment_revisions (comment_id, edited_at);
+
+CREATE TABLE IF NOT EXISTS task_comment_mentions
+(
+    comment_id UUID NOT NULL REFERENCES task_comments (id) ON DELETE CASCADE,
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (comment_id, user_id)
+);
+
+-- In-app notifications.
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    team_id    UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    kind       TEXT        NOT NULL,
+    task_id    UUID REFERENCES tasks (id) ON DELETE CASCADE,
+    comment_id UUID REFERENCES task_comments (id) ON DELETE CASCADE,
+    actor_id   UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    read_at    TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created_at ON notifications (user_id, created_at, id);
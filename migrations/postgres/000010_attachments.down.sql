@@ -0,0 +1,5 @@
+-- sqlfluff:dialect:postgres
+DROP TRIGGER IF EXISTS trg_attachments_blob_deletion ON attachments;
+DROP FUNCTION IF EXISTS attachments_blob_deletion();
+DROP TABLE IF EXISTS blob_deletions;
+DROP TABLE IF EXISTS attachments;
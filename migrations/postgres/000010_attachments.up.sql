@@ -0,0 +1,42 @@
+-- sqlfluff:dialect:postgres
+-- Files uploaded to tasks, optionally attached to one of the task's comments.
+-- The content lives in the blob store under storage_key.
+CREATE TABLE IF NOT EXISTS attachments
+(
+    id           UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id      UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    task_id      UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    comment_id   UUID REFERENCES task_comments (id) ON DELETE CASCADE,
+    uploaded_by  UUID REFERENCES users (id) ON DELETE SET NULL,
+    filename     TEXT        NOT NULL,
+    content_type TEXT        NOT NULL,
+    size_bytes   BIGINT      NOT NULL CHECK (size_bytes >= 0),
+    storage_key  TEXT        NOT NULL UNIQUE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_attachments_task_created_at ON attachments (task_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_attachments_team ON attachments (team_id);
+
+-- Blobs whose attachment row is gone. A background job removes them from the blob
+-- store, so deleting a task (or team) never waits on storage.
+CREATE TABLE IF NOT EXISTS blob_deletions
+(
+    id          BIGSERIAL PRIMARY KEY,
+    storage_key TEXT        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE OR REPLACE FUNCTION attachments_blob_deletion() RETURNS trigger AS
+$$
+BEGIN
+    INSERT INTO blob_deletions (storage_key) VALUES (old.storage_key);
+    RETURN old;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_attachments_blob_deletion ON attachments;
+CREATE TRIGGER trg_attachments_blob_deletion
+    AFTER DELETE
+    ON attachments
+    FOR EACH ROW
+EXECUTE FUNCTION attachments_blob_deletion();
@@ -0,0 +1,22 @@
+-- sqlfluff:dialect:postgres
+-- Team-scoped labels. Names are unique within a team regardless of case.
+CREATE TABLE IF NOT EXISTS labels
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id     UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    name        TEXT        NOT NULL,
+    color       TEXT        NOT NULL,
+    description TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_labels_team_name ON labels (team_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS task_labels
+(
+    task_id    UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    label_id   UUID        NOT NULL REFERENCES labels (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (task_id, label_id)
+);
+CREATE INDEX IF NOT EXISTS idx_task_labels_label ON task_labels (label_id, task_id);
@@ -0,0 +1,4 @@
+-- sqlfluff:dialect:postgres
+DROP TABLE IF EXISTS task_field_options;
+DROP TABLE IF EXISTS task_field_values;
+DROP TABLE IF EXISTS custom_fields;
@@ -0,0 +1,47 @@
+-- sqlfluff:dialect:postgres
+-- Typed fields defined per team. options is a JSON array of the choices of select fields;
+-- min_value/max_value bound numbers, the length of text and the number of selected options.
+CREATE TABLE IF NOT EXISTS custom_fields
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id    UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    key        TEXT        NOT NULL,
+    name       TEXT        NOT NULL,
+    type       TEXT        NOT NULL CHECK (type IN ('text', 'number', 'date', 'single_select', 'multi_select', 'user', 'checkbox')),
+    required   BOOLEAN     NOT NULL DEFAULT FALSE,
+    min_value  DOUBLE PRECISION,
+    max_value  DOUBLE PRECISION,
+    pattern    TEXT        NOT NULL DEFAULT '',
+    options    TEXT        NOT NULL DEFAULT '[]',
+    position   INTEGER     NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (team_id, key)
+);
+
+-- One row per task and field, with the value in the column of the field type. Typed
+-- columns keep comparisons and sorting native to the database.
+CREATE TABLE IF NOT EXISTS task_field_values
+(
+    task_id      UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    field_id     UUID NOT NULL REFERENCES custom_fields (id) ON DELETE CASCADE,
+    value_text   TEXT,
+    value_number DOUBLE PRECISION,
+    value_time   TIMESTAMPTZ,
+    value_bool   BOOLEAN,
+    value_user   UUID REFERENCES users (id) ON DELETE SET NULL,
+    PRIMARY KEY (task_id, field_id)
+);
+CREATE INDEX IF NOT EXISTS idx_task_field_values_text ON task_field_values (field_id, value_text);
+CREATE INDEX IF NOT EXISTS idx_task_field_values_number ON task_field_values (field_id, value_number);
+CREATE INDEX IF NOT EXISTS idx_task_field_values_time ON task_field_values (field_id, value_time);
+
+-- The selected options of multi-select fields, one row each.
+CREATE TABLE IF NOT EXISTS task_field_options
+(
+    task_id  UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    field_id UUID NOT NULL REFERENCES custom_fields (id) ON DELETE CASCADE,
+    option   TEXT NOT NULL,
+    PRIMARY KEY (task_id, field_id, option)
+);
+CREATE INDEX IF NOT EXISTS idx_task_field_options_field ON task_field_options (field_id, option);
@@ -0,0 +1,20 @@
+-- sqlfluff:dialect:postgres
+-- A recurring series of tasks. task_id is the current instance; it moves to the next
+-- instance when that is created. next_run_at is when the scheduler creates the next
+-- instance of on_schedule series and is NULL for on_complete series.
+CREATE TABLE IF NOT EXISTS task_recurrences
+(
+    id            UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id       UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    task_id       UUID        NOT NULL UNIQUE REFERENCES tasks (id) ON DELETE CASCADE,
+    rule          TEXT        NOT NULL,
+    timezone      TEXT        NOT NULL,
+    mode          TEXT        NOT NULL CHECK (mode IN ('on_complete', 'on_schedule')),
+    starts_at     TIMESTAMPTZ NOT NULL,
+    occurrence_at TIMESTAMPTZ NOT NULL,
+    next_run_at   TIMESTAMPTZ,
+    created_by    UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_task_recurrences_next_run ON task_recurrences (next_run_at) WHERE next_run_at IS NOT NULL;
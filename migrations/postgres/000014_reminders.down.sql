@@ -0,0 +1,6 @@
+-- sqlfluff:dialect:postgres
+DROP INDEX IF EXISTS idx_tasks_due_at;
+DROP TABLE IF EXISTS email_outbox;
+DROP TABLE IF EXISTS reminder_deliveries;
+DROP TABLE IF EXISTS task_reminders;
+DROP TABLE IF EXISTS user_preferences;
@@ -0,0 +1,54 @@
+-- sqlfluff:dialect:postgres
+-- Notification settings of a user. Quiet hours are "HH:MM" wall-clock times in the
+-- time zone of the user and may wrap past midnight. default_reminders is a JSON array
+-- of minutes before the due date, used for tasks without reminders of their own.
+CREATE TABLE IF NOT EXISTS user_preferences
+(
+    user_id             UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    timezone            TEXT        NOT NULL DEFAULT 'UTC',
+    quiet_hours_start   TEXT,
+    quiet_hours_end     TEXT,
+    default_reminders   TEXT        NOT NULL DEFAULT '[]',
+    email_notifications BOOLEAN     NOT NULL DEFAULT TRUE,
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Reminders of a task in minutes before its due date. They replace the default
+-- reminders of every recipient.
+CREATE TABLE IF NOT EXISTS task_reminders
+(
+    task_id        UUID    NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    minutes_before INTEGER NOT NULL CHECK (minutes_before >= 0),
+    PRIMARY KEY (task_id, minutes_before)
+);
+
+-- Reminders and overdue notices that were sent. The key includes the due date, so
+-- moving the due date arms them again; overdue notices use minutes_before = 0.
+CREATE TABLE IF NOT EXISTS reminder_deliveries
+(
+    task_id        UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id        UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    kind           TEXT        NOT NULL CHECK (kind IN ('reminder', 'overdue')),
+    minutes_before INTEGER     NOT NULL,
+    due_at         TIMESTAMPTZ NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (task_id, user_id, kind, minutes_before, due_at)
+);
+
+-- Emails waiting for the mailer job. A sender claims rows by pushing locked_until into
+-- the future; failed rows are retried once the lock expires.
+CREATE TABLE IF NOT EXISTS email_outbox
+(
+    id           UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    recipient    TEXT        NOT NULL,
+    subject      TEXT        NOT NULL,
+    body         TEXT        NOT NULL,
+    attempts     INTEGER     NOT NULL DEFAULT 0,
+    last_error   TEXT        NOT NULL DEFAULT '',
+    locked_until TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at      TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_email_outbox_pending ON email_outbox (created_at) WHERE sent_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_due_at ON tasks (due_at) WHERE due_at IS NOT NULL;
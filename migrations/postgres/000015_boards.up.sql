@@ -0,0 +1,39 @@
+-- sqlfluff:dialect:postgres
+-- Kanban boards of a team. Cards can be split into swimlanes by assignee, label or the
+-- options of a single-select custom field (e.g. a priority field).
+CREATE TABLE IF NOT EXISTS boards
+(
+    id                UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id           UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    name              TEXT        NOT NULL,
+    swimlane_by       TEXT        NOT NULL DEFAULT 'none' CHECK (swimlane_by IN ('none', 'assignee', 'label', 'custom_field')),
+    swimlane_field_id UUID REFERENCES custom_fields (id) ON DELETE SET NULL,
+    created_by        UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (team_id, name)
+);
+
+-- Columns of a board, each showing the tasks in one workflow state.
+CREATE TABLE IF NOT EXISTS board_columns
+(
+    id        UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    board_id  UUID    NOT NULL REFERENCES boards (id) ON DELETE CASCADE,
+    state_id  UUID    NOT NULL REFERENCES workflow_states (id) ON DELETE CASCADE,
+    name      TEXT    NOT NULL,
+    position  INTEGER NOT NULL DEFAULT 0,
+    wip_limit INTEGER CHECK (wip_limit > 0),
+    wip_mode  TEXT    NOT NULL DEFAULT 'warn' CHECK (wip_mode IN ('warn', 'enforce')),
+    UNIQUE (board_id, state_id)
+);
+
+-- Position of a task on a board as a rank key (see package rank). Tasks without a row
+-- sort after the ranked ones, oldest first. Rank keys compare byte-wise.
+CREATE TABLE IF NOT EXISTS board_cards
+(
+    board_id UUID NOT NULL REFERENCES boards (id) ON DELETE CASCADE,
+    task_id  UUID NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    rank     TEXT COLLATE "C" NOT NULL,
+    PRIMARY KEY (board_id, task_id)
+);
+CREATE INDEX IF NOT EXISTS idx_board_cards_rank ON board_cards (board_id, rank);
@@ -0,0 +1,30 @@
+-- sqlfluff:dialect:postgres
+-- Projects group the tasks of a team. Restricted projects only take tasks from their
+-- members, their lead and the team admins.
+CREATE TABLE IF NOT EXISTS projects
+(
+    id          UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id     UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    name        TEXT        NOT NULL,
+    description TEXT        NOT NULL DEFAULT '',
+    lead_id     UUID REFERENCES users (id) ON DELETE SET NULL,
+    status      TEXT        NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'active', 'paused', 'completed', 'cancelled')),
+    start_date  TIMESTAMPTZ,
+    target_date TIMESTAMPTZ,
+    restricted  BOOLEAN     NOT NULL DEFAULT FALSE,
+    archived_at TIMESTAMPTZ,
+    created_by  UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (team_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS project_members
+(
+    project_id UUID NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (project_id, user_id)
+);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS project_id UUID REFERENCES projects (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_project ON tasks (project_id);
@@ -0,0 +1,5 @@
+-- sqlfluff:dialect:postgres
+DROP INDEX IF EXISTS idx_tasks_sprint;
+ALTER TABLE tasks DROP COLUMN IF EXISTS sprint_id;
+DROP INDEX IF EXISTS idx_sprints_team_active;
+DROP TABLE IF EXISTS sprints;
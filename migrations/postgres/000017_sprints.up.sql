@@ -0,0 +1,32 @@
+-- sqlfluff:dialect:postgres
+-- Sprints are time boxes of a team, optionally scoped to a project. The committed_* and
+-- completed_* columns snapshot the sprint when it starts and when it is closed.
+CREATE TABLE IF NOT EXISTS sprints
+(
+    id                 UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id            UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    project_id         UUID REFERENCES projects (id) ON DELETE SET NULL,
+    name               TEXT        NOT NULL,
+    goal               TEXT        NOT NULL DEFAULT '',
+    status             TEXT        NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'active', 'closed')),
+    start_date         TIMESTAMPTZ NOT NULL,
+    end_date           TIMESTAMPTZ NOT NULL,
+    capacity           DOUBLE PRECISION,
+    committed_tasks    INTEGER,
+    committed_estimate DOUBLE PRECISION,
+    completed_tasks    INTEGER,
+    completed_estimate DOUBLE PRECISION,
+    carried_over       INTEGER,
+    started_at         TIMESTAMPTZ,
+    closed_at          TIMESTAMPTZ,
+    created_by         UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (team_id, name)
+);
+
+-- At most one active sprint per team.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sprints_team_active ON sprints (team_id) WHERE status = 'active';
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sprint_id UUID REFERENCES sprints (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_sprint ON tasks (sprint_id);
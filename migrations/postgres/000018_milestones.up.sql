@@ -0,0 +1,19 @@
+-- sqlfluff:dialect:postgres
+-- Milestones mark a due date of a team, optionally scoped to a project.
+CREATE TABLE IF NOT EXISTS milestones
+(
+    id           UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id      UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    project_id   UUID REFERENCES projects (id) ON DELETE SET NULL,
+    name         TEXT        NOT NULL,
+    description  TEXT        NOT NULL DEFAULT '',
+    due_at       TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ,
+    created_by   UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (team_id, name)
+);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS milestone_id UUID REFERENCES milestones (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_milestone ON tasks (milestone_id);
@@ -0,0 +1,22 @@
+-- sqlfluff:dialect:postgres
+-- Time logged by users on tasks. An entry without ended_at is a running timer.
+CREATE TABLE IF NOT EXISTS time_entries
+(
+    id               UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id          UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    task_id          UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    user_id          UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    started_at       TIMESTAMPTZ NOT NULL,
+    ended_at         TIMESTAMPTZ,
+    duration_seconds BIGINT      NOT NULL DEFAULT 0,
+    note             TEXT        NOT NULL DEFAULT '',
+    billable         BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_time_entries_task ON time_entries (task_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_time_entries_user ON time_entries (user_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_time_entries_team ON time_entries (team_id, started_at);
+-- At most one running timer per user.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries (user_id) WHERE ended_at IS NULL;
@@ -0,0 +1,4 @@
+-- sqlfluff:dialect:postgres
+ALTER TABLE teams_users DROP COLUMN IF EXISTS weekly_capacity;
+ALTER TABLE teams DROP COLUMN IF EXISTS weekly_capacity;
+ALTER TABLE teams DROP COLUMN IF EXISTS estimate_unit;
@@ -0,0 +1,2 @@
+-- sqlfluff:dialect:postgres
+DROP TABLE IF EXISTS task_activities;
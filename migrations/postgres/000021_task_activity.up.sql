@@ -0,0 +1,17 @@
+-- sqlfluff:dialect:postgres
+-- Immutable history of the changes made to tasks. changes holds the old and new values
+-- of the fields, as JSON; actor_id is NULL for changes made by the server.
+CREATE TABLE IF NOT EXISTS task_activities
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id    UUID        NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    task_id    UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    actor_id   UUID REFERENCES users (id) ON DELETE SET NULL,
+    action     TEXT        NOT NULL,
+    changes    TEXT        NOT NULL DEFAULT '[]',
+    details    TEXT,
+    trace_id   TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_activities_task ON task_activities (task_id, created_at);
@@ -0,0 +1,4 @@
+-- sqlfluff:dialect:postgres
+DROP TRIGGER IF EXISTS trg_team_audit_logs_append_only ON team_audit_logs;
+DROP FUNCTION IF EXISTS team_audit_logs_append_only();
+DROP TABLE IF EXISTS team_audit_logs;
@@ -0,0 +1,33 @@
+-- sqlfluff:dialect:postgres
+-- Append-only log of the administrative actions on teams. team_id and actor_id have no
+-- foreign keys so entries outlive deleted teams and users; only the retention job
+-- deletes entries.
+CREATE TABLE IF NOT EXISTS team_audit_logs
+(
+    id         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    team_id    UUID        NOT NULL,
+    actor_id   UUID,
+    action     TEXT        NOT NULL,
+    details    TEXT,
+    ip         TEXT        NOT NULL DEFAULT '',
+    user_agent TEXT        NOT NULL DEFAULT '',
+    trace_id   TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_audit_logs_team ON team_audit_logs (team_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_team_audit_logs_created_at ON team_audit_logs (created_at);
+
+CREATE OR REPLACE FUNCTION team_audit_logs_append_only() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'team audit log entries cannot be changed';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_team_audit_logs_append_only ON team_audit_logs;
+CREATE TRIGGER trg_team_audit_logs_append_only
+    BEFORE UPDATE
+    ON team_audit_logs
+    FOR EACH ROW
+EXECUTE FUNCTION team_audit_logs_append_only();
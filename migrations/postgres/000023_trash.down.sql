@@ -0,0 +1,13 @@
+-- sqlfluff:dialect:postgres
+-- Whatever is in the trash is deleted for good.
+DELETE FROM teams WHERE deleted_at IS NOT NULL;
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+DELETE FROM projects WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_task_comments_deleted_at;
+DROP INDEX IF EXISTS idx_tasks_deleted_at;
+DROP INDEX IF EXISTS idx_tasks_trash_root;
+ALTER TABLE tasks DROP COLUMN IF EXISTS trash_root_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE teams DROP COLUMN IF EXISTS deleted_at;
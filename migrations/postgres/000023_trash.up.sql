@@ -0,0 +1,13 @@
+-- sqlfluff:dialect:postgres
+-- Deleted teams, projects and tasks are kept in the trash until the purge job deletes
+-- them; comments already carry deleted_at.
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+-- The task or project whose deletion took the task to the trash; restoring it restores
+-- every task deleted with it.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS trash_root_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_trash_root ON tasks (trash_root_id) WHERE trash_root_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_task_comments_deleted_at ON task_comments (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+-- sqlfluff:dialect:postgres
+ALTER TABLE teams DROP COLUMN IF EXISTS archived_at;
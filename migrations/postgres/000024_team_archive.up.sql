@@ -0,0 +1,3 @@
+-- sqlfluff:dialect:postgres
+-- Archived teams stay readable but take no more changes.
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS admin_audit_logs;
+DROP TABLE IF EXISTS password_reset_tokens;
+ALTER TABLE users DROP COLUMN password_reset_required;
+ALTER TABLE users DROP COLUMN disabled_at;
@@ -0,0 +1,32 @@
+-- sqlfluff:dialect:sqlite
+-- Account state managed by system administrators
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN password_reset_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- One-time tokens issued when an administrator forces a password reset
+CREATE TABLE IF NOT EXISTS password_reset_tokens
+(
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT      NOT NULL,
+    token_hash TEXT      NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- Audit trail of every action taken through the admin API.
+-- target_user_id has no foreign key so entries outlive deleted users.
+CREATE TABLE IF NOT EXISTS admin_audit_logs
+(
+    id             TEXT PRIMARY KEY,
+    actor_id       TEXT,
+    action         TEXT      NOT NULL,
+    target_user_id TEXT,
+    details        TEXT,
+    trace_id       TEXT,
+    created_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (actor_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_created_at ON admin_audit_logs (created_at);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_target ON admin_audit_logs (target_user_id);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_teams_created_at;
+DROP INDEX IF EXISTS idx_team_invitations_to_user;
+DROP INDEX IF EXISTS idx_teams_users_team_joined;
+DROP INDEX IF EXISTS idx_teams_users_user;
+DROP INDEX IF EXISTS idx_teams_users_team_user;
+ALTER TABLE team_invitations DROP COLUMN created_at;
+ALTER TABLE teams_users DROP COLUMN joined_at;
@@ -0,0 +1,40 @@
+-- sqlfluff:dialect:sqlite
+-- Timestamps used as keyset pagination keys for memberships and invitations.
+-- SQLite cannot add a column with a non-constant default, so both tables are rebuilt.
+CREATE TABLE teams_users_new
+(
+    id        TEXT PRIMARY KEY,
+    team_id   TEXT      NOT NULL,
+    user_id   TEXT      NOT NULL,
+    role      TEXT      NOT NULL CHECK (role IN ('founder', 'admin', 'standard')) DEFAULT 'standard',
+    joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+INSERT INTO teams_users_new (id, team_id, user_id, role)
+SELECT id, team_id, user_id, role FROM teams_users;
+DROP TABLE teams_users;
+ALTER TABLE teams_users_new RENAME TO teams_users;
+
+CREATE TABLE team_invitations_new
+(
+    id           TEXT PRIMARY KEY,
+    team_id      TEXT      NOT NULL,
+    to_user_id   TEXT      NOT NULL,
+    from_user_id TEXT      NOT NULL,
+    accepted     BOOLEAN, -- Can be nullable means it is still not accepted nor denied
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (to_user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (from_user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+INSERT INTO team_invitations_new (id, team_id, to_user_id, from_user_id, accepted)
+SELECT id, team_id, to_user_id, from_user_id, accepted FROM team_invitations;
+DROP TABLE team_invitations;
+ALTER TABLE team_invitations_new RENAME TO team_invitations;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_teams_users_team_user ON teams_users (team_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_teams_users_user ON teams_users (user_id);
+CREATE INDEX IF NOT EXISTS idx_teams_users_team_joined ON teams_users (team_id, joined_at, id);
+CREATE INDEX IF NOT EXISTS idx_team_invitations_to_user ON team_invitations (to_user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_teams_created_at ON teams (created_at, id);
@@ -0,0 +1,25 @@
+-- sqlfluff:dialect:sqlite
+-- The old schema holds exactly one user per task: the first assignee is kept and
+-- tasks without assignees are dropped.
+DROP INDEX IF EXISTS idx_tasks_team_created_at;
+DROP INDEX IF EXISTS idx_task_assignees_user;
+
+ALTER TABLE tasks RENAME TO tasks_new;
+
+CREATE TABLE tasks
+(
+    id           TEXT PRIMARY KEY,
+    team_id      TEXT    NOT NULL,
+    grouped      BOOLEAN NOT NULL, -- Individual or group task
+    user_id_task TEXT    NOT NULL,
+    FOREIGN KEY (user_id_task) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE
+);
+INSERT INTO tasks (id, team_id, grouped, user_id_task)
+SELECT t.id, t.team_id, t.grouped, MIN(ta.user_id)
+FROM tasks_new t
+JOIN task_assignees ta ON ta.task_id = t.id
+GROUP BY t.id, t.team_id, t.grouped;
+
+DROP TABLE task_assignees;
+DROP TABLE tasks_new;
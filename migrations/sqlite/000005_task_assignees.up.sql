@@ -0,0 +1,42 @@
+-- sqlfluff:dialect:sqlite
+-- Tasks get a title and timestamps, and the single user_id_task column is replaced by a
+-- join table so a task can have any number of assignees.
+-- The old table is renamed first: dropping it while task_assignees references it would
+-- cascade into the copied assignments.
+ALTER TABLE tasks RENAME TO tasks_old;
+
+CREATE TABLE tasks
+(
+    id          TEXT PRIMARY KEY,
+    team_id     TEXT      NOT NULL,
+    title       TEXT      NOT NULL,
+    description TEXT      NOT NULL DEFAULT '',
+    grouped     BOOLEAN   NOT NULL DEFAULT FALSE, -- Shared with the whole team
+    created_by  TEXT,
+    due_at      TIMESTAMP,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+INSERT INTO tasks (id, team_id, title, grouped)
+SELECT id, team_id, 'Untitled task', grouped FROM tasks_old;
+
+CREATE TABLE task_assignees
+(
+    task_id     TEXT      NOT NULL,
+    user_id     TEXT      NOT NULL,
+    assigned_by TEXT,
+    assigned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, user_id),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (assigned_by) REFERENCES users (id) ON DELETE SET NULL
+);
+INSERT INTO task_assignees (task_id, user_id)
+SELECT id, user_id_task FROM tasks_old WHERE user_id_task IS NOT NULL;
+
+DROP TABLE tasks_old;
+
+CREATE INDEX IF NOT EXISTS idx_task_assignees_user ON task_assignees (user_id, task_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_team_created_at ON tasks (team_id, created_at, id);
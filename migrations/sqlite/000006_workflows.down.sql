@@ -0,0 +1,32 @@
+-- sqlfluff:dialect:sqlite
+-- SQLite cannot drop a column that is part of a foreign key, so tasks is rebuilt.
+-- Dropping tasks cascades into task_assignees, which is restored from a copy.
+DROP INDEX IF EXISTS idx_tasks_team_state;
+
+CREATE TABLE task_assignees_backup AS SELECT * FROM task_assignees;
+
+CREATE TABLE tasks_new
+(
+    id          TEXT PRIMARY KEY,
+    team_id     TEXT      NOT NULL,
+    title       TEXT      NOT NULL,
+    description TEXT      NOT NULL DEFAULT '',
+    grouped     BOOLEAN   NOT NULL DEFAULT FALSE, -- Shared with the whole team
+    created_by  TEXT,
+    due_at      TIMESTAMP,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+INSERT INTO tasks_new (id, team_id, title, description, grouped, created_by, due_at, created_at, updated_at)
+SELECT id, team_id, title, description, grouped, created_by, due_at, created_at, updated_at FROM tasks;
+DROP TABLE tasks;
+ALTER TABLE tasks_new RENAME TO tasks;
+CREATE INDEX IF NOT EXISTS idx_tasks_team_created_at ON tasks (team_id, created_at, id);
+
+INSERT INTO task_assignees SELECT * FROM task_assignees_backup;
+DROP TABLE task_assignees_backup;
+
+DROP TABLE IF EXISTS workflow_transitions;
+DROP TABLE IF EXISTS workflow_states;
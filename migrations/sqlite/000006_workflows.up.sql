@@ -0,0 +1,46 @@
+-- sqlfluff:dialect:sqlite
+-- Per-team workflow: ordered states grouped into categories, and an optional graph of
+-- allowed transitions. A team without transitions allows every move.
+CREATE TABLE IF NOT EXISTS workflow_states
+(
+    id         TEXT PRIMARY KEY,
+    team_id    TEXT      NOT NULL,
+    name       TEXT      NOT NULL,
+    category   TEXT      NOT NULL CHECK (category IN ('not_started', 'active', 'done')),
+    position   INTEGER   NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflow_states_team_name ON workflow_states (team_id, name);
+
+CREATE TABLE IF NOT EXISTS workflow_transitions
+(
+    id            TEXT PRIMARY KEY,
+    team_id       TEXT NOT NULL,
+    from_state_id TEXT NOT NULL,
+    to_state_id   TEXT NOT NULL,
+    roles         TEXT NOT NULL DEFAULT '[]', -- JSON array of team roles; empty means every role
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (from_state_id) REFERENCES workflow_states (id) ON DELETE CASCADE,
+    FOREIGN KEY (to_state_id) REFERENCES workflow_states (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workflow_transitions_edge ON workflow_transitions (from_state_id, to_state_id);
+CREATE INDEX IF NOT EXISTS idx_workflow_transitions_team ON workflow_transitions (team_id);
+
+-- States in use by tasks cannot be deleted.
+ALTER TABLE tasks ADD COLUMN state_id TEXT REFERENCES workflow_states (id);
+CREATE INDEX IF NOT EXISTS idx_tasks_team_state ON tasks (team_id, state_id);
+
+-- Existing teams get the default workflow; their tasks start in the first state.
+-- The ids are random version 4 UUIDs.
+INSERT INTO workflow_states (id, team_id, name, category, position)
+SELECT lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' ||
+             substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6))),
+       t.id, s.name, s.category, s.position
+FROM teams t
+CROSS JOIN (SELECT 'To Do' AS name, 'not_started' AS category, 0 AS position
+            UNION ALL SELECT 'In Progress', 'active', 1
+            UNION ALL SELECT 'Done', 'done', 2) s;
+
+UPDATE tasks
+SET state_id = (SELECT ws.id FROM workflow_states ws WHERE ws.team_id = tasks.team_id AND ws.position = 0);
@@ -0,0 +1,34 @@
+-- sqlfluff:dialect:sqlite
+-- SQLite cannot drop a column that is part of a foreign key, so tasks is rebuilt.
+-- Dropping tasks cascades into task_assignees, which is restored from a copy.
+-- Subtasks become top-level tasks.
+DROP TABLE IF EXISTS task_checklist_items;
+DROP INDEX IF EXISTS idx_tasks_parent;
+
+CREATE TABLE task_assignees_backup AS SELECT * FROM task_assignees;
+UPDATE tasks SET parent_id = NULL;
+
+CREATE TABLE tasks_new
+(
+    id          TEXT PRIMARY KEY,
+    team_id     TEXT      NOT NULL,
+    title       TEXT      NOT NULL,
+    description TEXT      NOT NULL DEFAULT '',
+    grouped     BOOLEAN   NOT NULL DEFAULT FALSE, -- Shared with the whole team
+    created_by  TEXT,
+    due_at      TIMESTAMP,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    state_id    TEXT REFERENCES workflow_states (id),
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+INSERT INTO tasks_new (id, team_id, title, description, grouped, created_by, due_at, created_at, updated_at, state_id)
+SELECT id, team_id, title, description, grouped, created_by, due_at, created_at, updated_at, state_id FROM tasks;
+DROP TABLE tasks;
+ALTER TABLE tasks_new RENAME TO tasks;
+CREATE INDEX IF NOT EXISTS idx_tasks_team_created_at ON tasks (team_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_tasks_team_state ON tasks (team_id, state_id);
+
+INSERT INTO task_assignees SELECT * FROM task_assignees_backup;
+DROP TABLE task_assignees_backup;
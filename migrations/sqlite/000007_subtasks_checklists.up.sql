@@ -0,0 +1,18 @@
+-- sqlfluff:dialect:sqlite
+-- Subtasks: deleting a task deletes its whole subtree.
+ALTER TABLE tasks ADD COLUMN parent_id TEXT REFERENCES tasks (id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_tasks_parent ON tasks (parent_id);
+
+-- Lightweight checklist items that are not full tasks.
+CREATE TABLE IF NOT EXISTS task_checklist_items
+(
+    id         TEXT PRIMARY KEY,
+    task_id    TEXT      NOT NULL,
+    content    TEXT      NOT NULL,
+    done       BOOLEAN   NOT NULL DEFAULT FALSE,
+    position   INTEGER   NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_task_checklist_items_task ON task_checklist_items (task_id, position);
@@ -0,0 +1,3 @@
+-- sqlfluff:dialect:sqlite
+DROP TABLE IF EXISTS task_links;
+ALTER TABLE tasks DROP COLUMN estimate;
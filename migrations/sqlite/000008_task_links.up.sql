@@ -0,0 +1,24 @@
+-- sqlfluff:dialect:sqlite
+-- Estimated effort of a task, used by the critical path of the dependency graph.
+ALTER TABLE tasks ADD COLUMN estimate REAL;
+
+-- Links between tasks of the same team. A "blocks" link means from_task_id has to be
+-- done before to_task_id; "relates_to" links carry no ordering.
+CREATE TABLE IF NOT EXISTS task_links
+(
+    id           TEXT PRIMARY KEY,
+    team_id      TEXT      NOT NULL,
+    from_task_id TEXT      NOT NULL,
+    to_task_id   TEXT      NOT NULL,
+    kind         TEXT      NOT NULL CHECK (kind IN ('blocks', 'relates_to')),
+    created_by   TEXT,
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (from_task_id <> to_task_id),
+    UNIQUE (from_task_id, to_task_id, kind),
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (from_task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (to_task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_task_links_to ON task_links (to_task_id, kind);
+CREATE INDEX IF NOT EXISTS idx_task_links_team ON task_links (team_id);
@@ -0,0 +1,5 @@
+-- sqlfluff:dialect:sqlite
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS task_comment_mentions;
+DROP TABLE IF EXISTS task_comment_revisions;
+DROP TABLE IF EXISTS task_comments;
@@ -0,0 +1,65 @@
+-- sqlfluff:dialect:sqlite
+-- Comments on tasks. Replies point at a top-level comment (one level of threading).
+-- body is the Markdown source, body_html its sanitized rendering.
+CREATE TABLE IF NOT EXISTS task_comments
+(
+    id         TEXT PRIMARY KEY,
+    team_id    TEXT      NOT NULL,
+    task_id    TEXT      NOT NULL,
+    parent_id  TEXT,
+    author_id  TEXT,
+    body       TEXT      NOT NULL,
+    body_html  TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    edited_at  TIMESTAMP,
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES task_comments (id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_task_comments_task_created_at ON task_comments (task_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_task_comments_parent ON task_comments (parent_id);
+
+-- Previous bodies of edited comments.
+CREATE TABLE IF NOT EXISTS task_comment_revisions
+(
+    id         TEXT PRIMARY KEY,
+    comment_id TEXT      NOT NULL,
+    body       TEXT      NOT NULL,
+    edited_by  TEXT,
+    edited_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (comment_id) REFERENCES task_comments (id) ON DELETE CASCADE,
+    FOREIGN KEY (edited_by) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_task_comment_revisions_comment ON task_comment_revisions (comment_id, edited_at);
+
+CREATE TABLE IF NOT EXISTS task_comment_mentions
+(
+    comment_id TEXT NOT NULL,
+    user_id    TEXT NOT NULL,
+    PRIMARY KEY (comment_id, user_id),
+    FOREIGN KEY (comment_id) REFERENCES task_comments (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- In-app notifications.
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT      NOT NULL,
+    team_id    TEXT      NOT NULL,
+    kind       TEXT      NOT NULL,
+    task_id    TEXT,
+    comment_id TEXT,
+    actor_id   TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    read_at    TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES task_comments (id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created_at ON notifications (user_id, created_at, id);
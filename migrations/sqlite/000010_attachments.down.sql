@@ -0,0 +1,4 @@
+-- sqlfluff:dialect:sqlite
+DROP TRIGGER IF EXISTS trg_attachments_blob_deletion;
+DROP TABLE IF EXISTS blob_deletions;
+DROP TABLE IF EXISTS attachments;
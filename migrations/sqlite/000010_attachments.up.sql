@@ -0,0 +1,38 @@
+-- sqlfluff:dialect:sqlite
+-- Files uploaded to tasks, optionally attached to one of the task's comments.
+-- The content lives in the blob store under storage_key.
+CREATE TABLE IF NOT EXISTS attachments
+(
+    id           TEXT PRIMARY KEY,
+    team_id      TEXT      NOT NULL,
+    task_id      TEXT      NOT NULL,
+    comment_id   TEXT,
+    uploaded_by  TEXT,
+    filename     TEXT      NOT NULL,
+    content_type TEXT      NOT NULL,
+    size_bytes   INTEGER   NOT NULL CHECK (size_bytes >= 0),
+    storage_key  TEXT      NOT NULL UNIQUE,
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES task_comments (id) ON DELETE CASCADE,
+    FOREIGN KEY (uploaded_by) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_attachments_task_created_at ON attachments (task_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_attachments_team ON attachments (team_id);
+
+-- Blobs whose attachment row is gone. A background job removes them from the blob
+-- store, so deleting a task (or team) never waits on storage.
+CREATE TABLE IF NOT EXISTS blob_deletions
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    storage_key TEXT      NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER IF NOT EXISTS trg_attachments_blob_deletion
+    AFTER DELETE
+    ON attachments
+BEGIN
+    INSERT INTO blob_deletions (storage_key) VALUES (old.storage_key);
+END;
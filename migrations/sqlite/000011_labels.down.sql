@@ -0,0 +1,3 @@
+-- sqlfluff:dialect:sqlite
+DROP TABLE IF EXISTS task_labels;
+DROP TABLE IF EXISTS labels;
@@ -0,0 +1,25 @@
+-- sqlfluff:dialect:sqlite
+-- Team-scoped labels. Names are unique within a team regardless of case.
+CREATE TABLE IF NOT EXISTS labels
+(
+    id          TEXT PRIMARY KEY,
+    team_id     TEXT      NOT NULL,
+    name        TEXT      NOT NULL,
+    color       TEXT      NOT NULL,
+    description TEXT      NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_labels_team_name ON labels (team_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS task_labels
+(
+    task_id    TEXT      NOT NULL,
+    label_id   TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, label_id),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (label_id) REFERENCES labels (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_task_labels_label ON task_labels (label_id, task_id);
@@ -0,0 +1,53 @@
+-- sqlfluff:dialect:sqlite
+-- Typed fields defined per team. options is a JSON array of the choices of select fields;
+-- min_value/max_value bound numbers, the length of text and the number of selected options.
+CREATE TABLE IF NOT EXISTS custom_fields
+(
+    id         TEXT PRIMARY KEY,
+    team_id    TEXT      NOT NULL,
+    key        TEXT      NOT NULL,
+    name       TEXT      NOT NULL,
+    type       TEXT      NOT NULL CHECK (type IN ('text', 'number', 'date', 'single_select', 'multi_select', 'user', 'checkbox')),
+    required   BOOLEAN   NOT NULL DEFAULT FALSE,
+    min_value  REAL,
+    max_value  REAL,
+    pattern    TEXT      NOT NULL DEFAULT '',
+    options    TEXT      NOT NULL DEFAULT '[]',
+    position   INTEGER   NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (team_id, key),
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE
+);
+
+-- One row per task and field, with the value in the column of the field type. Typed
+-- columns keep comparisons and sorting native to the database.
+CREATE TABLE IF NOT EXISTS task_field_values
+(
+    task_id      TEXT NOT NULL,
+    field_id     TEXT NOT NULL,
+    value_text   TEXT,
+    value_number REAL,
+    value_time   TIMESTAMP,
+    value_bool   BOOLEAN,
+    value_user   TEXT,
+    PRIMARY KEY (task_id, field_id),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (field_id) REFERENCES custom_fields (id) ON DELETE CASCADE,
+    FOREIGN KEY (value_user) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_task_field_values_text ON task_field_values (field_id, value_text);
+CREATE INDEX IF NOT EXISTS idx_task_field_values_number ON task_field_values (field_id, value_number);
+CREATE INDEX IF NOT EXISTS idx_task_field_values_time ON task_field_values (field_id, value_time);
+
+-- The selected options of multi-select fields, one row each.
+CREATE TABLE IF NOT EXISTS task_field_options
+(
+    task_id  TEXT NOT NULL,
+    field_id TEXT NOT NULL,
+    option   TEXT NOT NULL,
+    PRIMARY KEY (task_id, field_id, option),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (field_id) REFERENCES custom_fields (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_task_field_options_field ON task_field_options (field_id, option);
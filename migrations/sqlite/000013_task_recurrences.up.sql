@@ -0,0 +1,23 @@
+-- sqlfluff:dialect:sqlite
+-- A recurring series of tasks. task_id is the current instance; it moves to the next
+-- instance when that is created. next_run_at is when the scheduler creates the next
+-- instance of on_schedule series and is NULL for on_complete series.
+CREATE TABLE IF NOT EXISTS task_recurrences
+(
+    id            TEXT PRIMARY KEY,
+    team_id       TEXT      NOT NULL,
+    task_id       TEXT      NOT NULL UNIQUE,
+    rule          TEXT      NOT NULL,
+    timezone      TEXT      NOT NULL,
+    mode          TEXT      NOT NULL CHECK (mode IN ('on_complete', 'on_schedule')),
+    starts_at     TIMESTAMP NOT NULL,
+    occurrence_at TIMESTAMP NOT NULL,
+    next_run_at   TIMESTAMP,
+    created_by    TEXT,
+    created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_task_recurrences_next_run ON task_recurrences (next_run_at);
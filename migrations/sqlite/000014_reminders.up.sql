@@ -0,0 +1,58 @@
+-- sqlfluff:dialect:sqlite
+-- Notification settings of a user. Quiet hours are "HH:MM" wall-clock times in the
+-- time zone of the user and may wrap past midnight. default_reminders is a JSON array
+-- of minutes before the due date, used for tasks without reminders of their own.
+CREATE TABLE IF NOT EXISTS user_preferences
+(
+    user_id             TEXT PRIMARY KEY,
+    timezone            TEXT      NOT NULL DEFAULT 'UTC',
+    quiet_hours_start   TEXT,
+    quiet_hours_end     TEXT,
+    default_reminders   TEXT      NOT NULL DEFAULT '[]',
+    email_notifications BOOLEAN   NOT NULL DEFAULT TRUE,
+    updated_at          TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- Reminders of a task in minutes before its due date. They replace the default
+-- reminders of every recipient.
+CREATE TABLE IF NOT EXISTS task_reminders
+(
+    task_id        TEXT    NOT NULL,
+    minutes_before INTEGER NOT NULL CHECK (minutes_before >= 0),
+    PRIMARY KEY (task_id, minutes_before),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+
+-- Reminders and overdue notices that were sent. The key includes the due date, so
+-- moving the due date arms them again; overdue notices use minutes_before = 0.
+CREATE TABLE IF NOT EXISTS reminder_deliveries
+(
+    task_id        TEXT      NOT NULL,
+    user_id        TEXT      NOT NULL,
+    kind           TEXT      NOT NULL CHECK (kind IN ('reminder', 'overdue')),
+    minutes_before INTEGER   NOT NULL,
+    due_at         TIMESTAMP NOT NULL,
+    created_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, user_id, kind, minutes_before, due_at),
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- Emails waiting for the mailer job. A sender claims rows by pushing locked_until into
+-- the future; failed rows are retried once the lock expires.
+CREATE TABLE IF NOT EXISTS email_outbox
+(
+    id           TEXT PRIMARY KEY,
+    recipient    TEXT      NOT NULL,
+    subject      TEXT      NOT NULL,
+    body         TEXT      NOT NULL,
+    attempts     INTEGER   NOT NULL DEFAULT 0,
+    last_error   TEXT      NOT NULL DEFAULT '',
+    locked_until TIMESTAMP,
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at      TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_email_outbox_pending ON email_outbox (sent_at, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_due_at ON tasks (due_at);
@@ -0,0 +1,4 @@
+-- sqlfluff:dialect:sqlite
+DROP TABLE IF EXISTS board_cards;
+DROP TABLE IF EXISTS board_columns;
+DROP TABLE IF EXISTS boards;
@@ -0,0 +1,47 @@
+-- sqlfluff:dialect:sqlite
+-- Kanban boards of a team. Cards can be split into swimlanes by assignee, label or the
+-- options of a single-select custom field (e.g. a priority field).
+CREATE TABLE IF NOT EXISTS boards
+(
+    id                TEXT PRIMARY KEY,
+    team_id           TEXT      NOT NULL,
+    name              TEXT      NOT NULL,
+    swimlane_by       TEXT      NOT NULL DEFAULT 'none' CHECK (swimlane_by IN ('none', 'assignee', 'label', 'custom_field')),
+    swimlane_field_id TEXT,
+    created_by        TEXT,
+    created_at        TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (team_id, name),
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (swimlane_field_id) REFERENCES custom_fields (id) ON DELETE SET NULL,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_boards_team ON boards (team_id, name);
+
+-- Columns of a board, each showing the tasks in one workflow state.
+CREATE TABLE IF NOT EXISTS board_columns
+(
+    id        TEXT PRIMARY KEY,
+    board_id  TEXT    NOT NULL,
+    state_id  TEXT    NOT NULL,
+    name      TEXT    NOT NULL,
+    position  INTEGER NOT NULL DEFAULT 0,
+    wip_limit INTEGER CHECK (wip_limit > 0),
+    wip_mode  TEXT    NOT NULL DEFAULT 'warn' CHECK (wip_mode IN ('warn', 'enforce')),
+    UNIQUE (board_id, state_id),
+    FOREIGN KEY (board_id) REFERENCES boards (id) ON DELETE CASCADE,
+    FOREIGN KEY (state_id) REFERENCES workflow_states (id) ON DELETE CASCADE
+);
+
+-- Position of a task on a board as a rank key (see package rank). Tasks without a row
+-- sort after the ranked ones, oldest first.
+CREATE TABLE IF NOT EXISTS board_cards
+(
+    board_id TEXT NOT NULL,
+    task_id  TEXT NOT NULL,
+    rank     TEXT NOT NULL,
+    PRIMARY KEY (board_id, task_id),
+    FOREIGN KEY (board_id) REFERENCES boards (id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_board_cards_rank ON board_cards (board_id, rank);
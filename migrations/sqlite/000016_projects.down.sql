@@ -0,0 +1,5 @@
+-- sqlfluff:dialect:sqlite
+DROP INDEX IF EXISTS idx_tasks_project;
+ALTER TABLE tasks DROP COLUMN project_id;
+DROP TABLE IF EXISTS project_members;
+DROP TABLE IF EXISTS projects;
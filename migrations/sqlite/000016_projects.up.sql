@@ -0,0 +1,37 @@
+-- sqlfluff:dialect:sqlite
+-- Projects group the tasks of a team. Restricted projects only take tasks from their
+-- members, their lead and the team admins.
+CREATE TABLE IF NOT EXISTS projects
+(
+    id          TEXT PRIMARY KEY,
+    team_id     TEXT      NOT NULL,
+    name        TEXT      NOT NULL,
+    description TEXT      NOT NULL DEFAULT '',
+    lead_id     TEXT,
+    status      TEXT      NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'active', 'paused', 'completed', 'cancelled')),
+    start_date  TIMESTAMP,
+    target_date TIMESTAMP,
+    restricted  BOOLEAN   NOT NULL DEFAULT FALSE,
+    archived_at TIMESTAMP,
+    created_by  TEXT,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (team_id, name),
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (lead_id) REFERENCES users (id) ON DELETE SET NULL,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS project_members
+(
+    project_id TEXT NOT NULL,
+    user_id    TEXT NOT NULL,
+    PRIMARY KEY (project_id, user_id),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- SQLite cannot drop a column that is part of a foreign key, so project_id has none;
+-- deleting a project detaches its tasks in the repository instead.
+ALTER TABLE tasks ADD COLUMN project_id TEXT;
+CREATE INDEX IF NOT EXISTS idx_tasks_project ON tasks (project_id);
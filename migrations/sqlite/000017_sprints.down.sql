@@ -0,0 +1,5 @@
+-- sqlfluff:dialect:sqlite
+DROP INDEX IF EXISTS idx_tasks_sprint;
+ALTER TABLE tasks DROP COLUMN sprint_id;
+DROP INDEX IF EXISTS idx_sprints_team_active;
+DROP TABLE IF EXISTS sprints;
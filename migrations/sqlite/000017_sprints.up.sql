@@ -0,0 +1,37 @@
+-- sqlfluff:dialect:sqlite
+-- Sprints are time boxes of a team, optionally scoped to a project. The committed_* and
+-- completed_* columns snapshot the sprint when it starts and when it is closed.
+CREATE TABLE IF NOT EXISTS sprints
+(
+    id                 TEXT PRIMARY KEY,
+    team_id            TEXT      NOT NULL,
+    project_id         TEXT,
+    name               TEXT      NOT NULL,
+    goal               TEXT      NOT NULL DEFAULT '',
+    status             TEXT      NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'active', 'closed')),
+    start_date         TIMESTAMP NOT NULL,
+    end_date           TIMESTAMP NOT NULL,
+    capacity           REAL,
+    committed_tasks    INTEGER,
+    committed_estimate REAL,
+    completed_tasks    INTEGER,
+    completed_estimate REAL,
+    carried_over       INTEGER,
+    started_at         TIMESTAMP,
+    closed_at          TIMESTAMP,
+    created_by         TEXT,
+    created_at         TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (team_id, name),
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE SET NULL,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+
+-- At most one active sprint per team.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sprints_team_active ON sprints (team_id) WHERE status = 'active';
+
+-- Like tasks.project_id, sprint_id has no foreign key on SQLite; deleting a sprint
+-- detaches its tasks in the repository.
+ALTER TABLE tasks ADD COLUMN sprint_id TEXT;
+CREATE INDEX IF NOT EXISTS idx_tasks_sprint ON tasks (sprint_id);
@@ -0,0 +1,4 @@
+-- sqlfluff:dialect:sqlite
+DROP INDEX IF EXISTS idx_tasks_milestone;
+ALTER TABLE tasks DROP COLUMN milestone_id;
+DROP TABLE IF EXISTS milestones;
@@ -0,0 +1,24 @@
+-- sqlfluff:dialect:sqlite
+-- Milestones mark a due date of a team, optionally scoped to a project.
+CREATE TABLE IF NOT EXISTS milestones
+(
+    id           TEXT PRIMARY KEY,
+    team_id      TEXT      NOT NULL,
+    project_id   TEXT,
+    name         TEXT      NOT NULL,
+    description  TEXT      NOT NULL DEFAULT '',
+    due_at       TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP,
+    created_by   TEXT,
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (team_id, name),
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE SET NULL,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL
+);
+
+-- Like tasks.project_id, milestone_id has no foreign key on SQLite; deleting a milestone
+-- detaches its tasks in the repository.
+ALTER TABLE tasks ADD COLUMN milestone_id TEXT;
+CREATE INDEX IF NOT EXISTS idx_tasks_milestone ON tasks (milestone_id);
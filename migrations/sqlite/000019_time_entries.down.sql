@@ -0,0 +1,2 @@
+-- sqlfluff:dialect:sqlite
+DROP TABLE IF EXISTS time_entries;
@@ -0,0 +1,25 @@
+-- sqlfluff:dialect:sqlite
+-- Time logged by users on tasks. An entry without ended_at is a running timer.
+CREATE TABLE IF NOT EXISTS time_entries
+(
+    id               TEXT PRIMARY KEY,
+    team_id          TEXT      NOT NULL,
+    task_id          TEXT      NOT NULL,
+    user_id          TEXT      NOT NULL,
+    started_at       TIMESTAMP NOT NULL,
+    ended_at         TIMESTAMP,
+    duration_seconds INTEGER   NOT NULL DEFAULT 0,
+    note             TEXT      NOT NULL DEFAULT '',
+    billable         BOOLEAN   NOT NULL DEFAULT TRUE,
+    created_at       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_time_entries_task ON time_entries (task_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_time_entries_user ON time_entries (user_id, started_at);
+CREATE INDEX IF NOT EXISTS idx_time_entries_team ON time_entries (team_id, started_at);
+-- At most one running timer per user.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries (user_id) WHERE ended_at IS NULL;
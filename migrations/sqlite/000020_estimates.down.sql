@@ -0,0 +1,4 @@
+-- sqlfluff:dialect:sqlite
+ALTER TABLE teams_users DROP COLUMN weekly_capacity;
+ALTER TABLE teams DROP COLUMN weekly_capacity;
+ALTER TABLE teams DROP COLUMN estimate_unit;
@@ -0,0 +1,6 @@
+-- sqlfluff:dialect:sqlite
+-- Unit of the task estimates of a team, and the estimate a member can take on per week.
+ALTER TABLE teams ADD COLUMN estimate_unit TEXT NOT NULL DEFAULT 'points' CHECK (estimate_unit IN ('points', 'hours'));
+ALTER TABLE teams ADD COLUMN weekly_capacity REAL;
+-- Overrides teams.weekly_capacity for one member.
+ALTER TABLE teams_users ADD COLUMN weekly_capacity REAL;
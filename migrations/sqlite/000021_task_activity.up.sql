@@ -0,0 +1,20 @@
+-- sqlfluff:dialect:sqlite
+-- Immutable history of the changes made to tasks. changes holds the old and new values
+-- of the fields, as JSON; actor_id is NULL for changes made by the server.
+CREATE TABLE IF NOT EXISTS task_activities
+(
+    id         TEXT PRIMARY KEY,
+    team_id    TEXT      NOT NULL,
+    task_id    TEXT      NOT NULL,
+    actor_id   TEXT,
+    action     TEXT      NOT NULL,
+    changes    TEXT      NOT NULL DEFAULT '[]',
+    details    TEXT,
+    trace_id   TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_activities_task ON task_activities (task_id, created_at);
@@ -0,0 +1,3 @@
+-- sqlfluff:dialect:sqlite
+DROP TRIGGER IF EXISTS trg_team_audit_logs_append_only;
+DROP TABLE IF EXISTS team_audit_logs;
@@ -0,0 +1,26 @@
+-- sqlfluff:dialect:sqlite
+-- Append-only log of the administrative actions on teams. team_id and actor_id have no
+-- foreign keys so entries outlive deleted teams and users; only the retention job
+-- deletes entries.
+CREATE TABLE IF NOT EXISTS team_audit_logs
+(
+    id         TEXT PRIMARY KEY,
+    team_id    TEXT      NOT NULL,
+    actor_id   TEXT,
+    action     TEXT      NOT NULL,
+    details    TEXT,
+    ip         TEXT      NOT NULL DEFAULT '',
+    user_agent TEXT      NOT NULL DEFAULT '',
+    trace_id   TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_audit_logs_team ON team_audit_logs (team_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_team_audit_logs_created_at ON team_audit_logs (created_at);
+
+CREATE TRIGGER IF NOT EXISTS trg_team_audit_logs_append_only
+    BEFORE UPDATE
+    ON team_audit_logs
+BEGIN
+    SELECT RAISE(ABORT, 'team audit log entries cannot be changed');
+END;
@@ -0,0 +1,2 @@
+-- sqlfluff:dialect:sqlite
+ALTER TABLE teams DROP COLUMN archived_at;
@@ -0,0 +1,3 @@
+-- sqlfluff:dialect:sqlite
+-- Archived teams stay readable but take no more changes.
+ALTER TABLE teams ADD COLUMN archived_at TIMESTAMP;
@@ -0,0 +1,41 @@
+// Package blobstore stores the contents of uploaded files. Metadata lives in the
+// database; a Store only knows about opaque keys.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"task_manager/public/config"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+type Store interface {
+	// Put stores size bytes read from r under key, replacing any previous blob.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open reads the blob from offset. A negative length reads to the end. It returns
+	// ErrNotFound when there is no blob under key.
+	Open(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error)
+	// Delete removes the blob; deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// New opens the store selected by cfg.BlobDriver.
+func New(cfg config.Config) (Store, error) {
+	switch cfg.BlobDriver {
+	case "local":
+		return NewLocal(cfg.BlobDir), nil
+	case "s3":
+		return NewS3(S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported blob driver: %s", cfg.BlobDriver)
+	}
+}
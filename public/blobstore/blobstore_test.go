@@ -0,0 +1,122 @@
+package blobstore_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"task_manager/public/blobstore"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a minimal in-memory stand-in for an S3-compatible server: path-style
+// PUT, GET (with a single byte range) and DELETE of objects.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test-key/") || r.Header.Get("X-Amz-Date") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.objects[r.URL.Path] = body
+	case http.MethodGet:
+		body, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		spec, ranged := strings.CutPrefix(r.Header.Get("Range"), "bytes=")
+		if !ranged {
+			_, _ = w.Write(body)
+			return
+		}
+		first, last, _ := strings.Cut(spec, "-")
+		start, _ := strconv.Atoi(first)
+		end := len(body) - 1
+		if last != "" {
+			end, _ = strconv.Atoi(last)
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(body[start : min(end, len(body)-1)+1])
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestStores(t *testing.T) {
+	server := httptest.NewServer(&fakeS3{objects: map[string][]byte{}})
+	t.Cleanup(server.Close)
+	s3, err := blobstore.NewS3(blobstore.S3Config{
+		Endpoint:        server.URL,
+		Bucket:          "attachments",
+		AccessKeyID:     "test-key",
+		SecretAccessKey: "test-secret",
+	})
+	require.NoError(t, err)
+
+	stores := map[string]blobstore.Store{
+		"local": blobstore.NewLocal(t.TempDir()),
+		"s3":    s3,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			const key = "team/task/report final.txt"
+			content := "0123456789abcdef"
+			require.NoError(t, store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain"))
+
+			tests := []struct {
+				name   string
+				offset int64
+				length int64
+				want   string
+			}{
+				{"whole blob", 0, -1, content},
+				{"range", 2, 4, "2345"},
+				{"from offset to the end", 10, -1, "abcdef"},
+				{"empty range", 3, 0, ""},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					rc, err := store.Open(ctx, key, tt.offset, tt.length)
+					require.NoError(t, err)
+					defer rc.Close()
+					got, err := io.ReadAll(rc)
+					require.NoError(t, err)
+					require.Equal(t, tt.want, string(got))
+				})
+			}
+
+			require.NoError(t, store.Delete(ctx, key))
+			require.NoError(t, store.Delete(ctx, key), "deleting a missing blob is not an error")
+			_, err := store.Open(ctx, key, 0, -1)
+			require.ErrorIs(t, err, blobstore.ErrNotFound)
+		})
+	}
+}
+
+func TestLocalRejectsEscapingKeys(t *testing.T) {
+	store := blobstore.NewLocal(t.TempDir())
+	err := store.Put(context.Background(), "../outside", strings.NewReader("x"), 1, "text/plain")
+	require.Error(t, err)
+}
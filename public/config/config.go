@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -13,6 +14,22 @@ func getEnv(key string, def string) string {
 	return v
 }
 
+func getEnvInt64(key string, def int64) int64 {
+	v, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func getEnvInt(key string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
 var (
 	AppVersion = "dev"
 	AppCommit  = "none"
@@ -39,6 +56,34 @@ type Config struct {
 	OAuthWebRedirectTemplate    string
 
 	FrontendURL string
+
+	// How many levels of subtasks a task can have below it.
+	TaskMaxDepth int
+
+	BlobDriver        string // local | s3
+	BlobDir           string
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+
+	// Largest single upload, and the total size of all uploads of a team.
+	AttachmentMaxBytes    int64
+	TeamStorageQuotaBytes int64
+
+	// How long team audit log entries are kept; 0 keeps them forever.
+	TeamAuditRetentionDays int
+	// How long deleted teams, projects, tasks and comments stay in the trash; 0 keeps
+	// them forever.
+	TrashRetentionDays int
+
+	MailDriver   string // log | smtp
+	MailFrom     string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
 }
 
 func Load() Config {
@@ -60,5 +105,23 @@ func Load() Config {
 		OAuthMobileDeeplinkTemplate: getEnv("OAUTH_MOBILE_DEEPLINK_TEMPLATE", ""),
 		OAuthWebRedirectTemplate:    getEnv("OAUTH_WEB_REDIRECT_TEMPLATE", ""),
 		FrontendURL:                 getEnv("FRONTEND_URL", "http://localhost:3000"),
+		TaskMaxDepth:                getEnvInt("TASK_MAX_DEPTH", 3),
+		BlobDriver:                  getEnv("BLOB_DRIVER", "local"),
+		BlobDir:                     getEnv("BLOB_DIR", "data/blobs"),
+		S3Endpoint:                  getEnv("S3_ENDPOINT", ""),
+		S3Region:                    getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:                    getEnv("S3_BUCKET", ""),
+		S3AccessKeyID:               getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey:           getEnv("S3_SECRET_ACCESS_KEY", ""),
+		AttachmentMaxBytes:          getEnvInt64("ATTACHMENT_MAX_BYTES", 25<<20),
+		TeamStorageQuotaBytes:       getEnvInt64("TEAM_STORAGE_QUOTA_BYTES", 1<<30),
+		TeamAuditRetentionDays:      getEnvInt("TEAM_AUDIT_RETENTION_DAYS", 365),
+		TrashRetentionDays:          getEnvInt("TRASH_RETENTION_DAYS", 30),
+		MailDriver:                  getEnv("MAIL_DRIVER", "log"),
+		MailFrom:                    getEnv("MAIL_FROM", "Task Manager <no-reply@localhost>"),
+		SMTPHost:                    getEnv("SMTP_HOST", ""),
+		SMTPPort:                    getEnv("SMTP_PORT", "587"),
+		SMTPUsername:                getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:                getEnv("SMTP_PASSWORD", ""),
 	}
 }
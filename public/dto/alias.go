@@ -17,9 +17,66 @@ type LogoutEnvelope = Envelope[LogoutResponse]
 // It avoids inline/anonymous schemas in the generated OpenAPI spec.
 type MeEnvelope = Envelope[MeResponse]
 
+// CSRFTokenEnvelope is the unified envelope for the CSRF token endpoint.
+type CSRFTokenEnvelope = Envelope[CSRFTokenResponse]
+
+// MessageEnvelope is the unified envelope for endpoints that only return a message.
+type MessageEnvelope = Envelope[MessageResponse]
+
+type (
+	AdminUsersEnvelope         = Envelope[AdminUserListResponse]
+	AdminUserEnvelope          = Envelope[*models.User]
+	AdminUserDetailEnvelope    = Envelope[AdminUserDetailResponse]
+	AdminAuditEnvelope         = Envelope[AdminAuditListResponse]
+	AdminPasswordResetEnvelope = Envelope[AdminPasswordResetResponse]
+	ImpersonationEnvelope      = Envelope[ImpersonationResponse]
+)
+
 type (
-	TeamsEnvelope            = Envelope[[]models.Team]
-	TeamsInvitationsEnvelope = Envelope[models.Invitation]
-	TeamsTaskEnvelope        = Envelope[models.Task]
-	UserTeamsEnvelope        = Envelope[models.UserTeam]
+	TeamsEnvelope               = Envelope[[]models.Team]
+	TeamsInvitationsEnvelope    = Envelope[[]models.Invitation]
+	TeamsTaskEnvelope           = Envelope[models.Task]
+	TasksEnvelope               = Envelope[[]models.Task]
+	WorkflowEnvelope            = Envelope[models.Workflow]
+	ChecklistEnvelope           = Envelope[[]models.ChecklistItem]
+	ChecklistItemEnvelope       = Envelope[models.ChecklistItem]
+	TaskLinkEnvelope            = Envelope[models.TaskLink]
+	TaskLinksEnvelope           = Envelope[[]models.TaskLink]
+	DependencyGraphEnvelope     = Envelope[models.DependencyGraph]
+	CommentEnvelope             = Envelope[models.Comment]
+	CommentsEnvelope            = Envelope[[]models.Comment]
+	CommentRevisionsEnvelope    = Envelope[[]models.CommentRevision]
+	LabelEnvelope               = Envelope[models.Label]
+	LabelsEnvelope              = Envelope[[]models.Label]
+	AttachmentEnvelope          = Envelope[models.Attachment]
+	CustomFieldEnvelope         = Envelope[models.CustomField]
+	CustomFieldsEnvelope        = Envelope[[]models.CustomField]
+	RecurrenceEnvelope          = Envelope[models.Recurrence]
+	TaskRemindersEnvelope       = Envelope[models.TaskReminders]
+	ProjectEnvelope             = Envelope[models.Project]
+	ProjectsEnvelope            = Envelope[[]models.Project]
+	ProjectTasksMoveEnvelope    = Envelope[ProjectTasksMoveResponse]
+	SprintEnvelope              = Envelope[models.Sprint]
+	SprintsEnvelope             = Envelope[[]models.Sprint]
+	MilestoneEnvelope           = Envelope[models.Milestone]
+	MilestonesEnvelope          = Envelope[[]models.Milestone]
+	MilestoneCompletionEnvelope = Envelope[models.MilestoneCompletion]
+	TimeEntryEnvelope           = Envelope[models.TimeEntry]
+	TimeEntriesEnvelope         = Envelope[[]models.TimeEntry]
+	TimesheetEnvelope           = Envelope[models.Timesheet]
+	EstimationEnvelope          = Envelope[models.Estimation]
+	WorkloadEnvelope            = Envelope[models.Workload]
+	TaskActivitiesEnvelope      = Envelope[[]models.TaskActivity]
+	TeamAuditEnvelope           = Envelope[[]models.TeamAuditLog]
+	TrashEnvelope               = Envelope[[]models.TrashItem]
+	TrashItemEnvelope           = Envelope[models.TrashItem]
+	BoardEnvelope               = Envelope[models.Board]
+	BoardsEnvelope              = Envelope[[]models.Board]
+	BoardViewEnvelope           = Envelope[models.BoardView]
+	BoardMoveEnvelope           = Envelope[models.BoardMove]
+	UserPreferencesEnvelope     = Envelope[models.UserPreferences]
+	AttachmentsEnvelope         = Envelope[[]models.Attachment]
+	NotificationsEnvelope       = Envelope[[]models.Notification]
+	UserTeamsEnvelope           = Envelope[models.UserTeam]
+	TeamMembersEnvelope         = Envelope[[]models.UserTeam]
 )
@@ -1,6 +1,11 @@
 package dto
 
-import "task_manager/public/repositories/models"
+import (
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Request DTOs
 type SignupRequest struct {
@@ -15,11 +20,397 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+type PasswordResetRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
 type TeamCreationRequest struct {
 	TeamName string `json:"team_name" validate:"required,min=2,max=100"`
 }
 
+type TaskCreationRequest struct {
+	Title       string `json:"title" validate:"required,min=1,max=200"`
+	Description string `json:"description" validate:"max=10000"`
+	// Grouped tasks are shared with the whole team.
+	GroupTask   bool        `json:"group_task"`
+	DueAt       *time.Time  `json:"due_at"`
+	AssigneeIDs []uuid.UUID `json:"assignee_ids" validate:"max=50"`
+	LabelIDs    []uuid.UUID `json:"label_ids" validate:"max=50"`
+	// In the estimate unit of the team: points or hours.
+	Estimate *float64 `json:"estimate" validate:"omitempty,gte=0"`
+	// Creates a subtask of this task.
+	ParentID *uuid.UUID `json:"parent_id"`
+	// Files the task into this project.
+	ProjectID *uuid.UUID `json:"project_id"`
+	// Plans the task in this sprint.
+	SprintID *uuid.UUID `json:"sprint_id"`
+	// Attaches the task to this milestone.
+	MilestoneID *uuid.UUID `json:"milestone_id"`
+	// Custom field values by field key; required fields must be set.
+	CustomFields map[string]any `json:"custom_fields" validate:"max=100"`
+}
+
+// TaskUpdateRequest only changes the fields that are present.
+type TaskUpdateRequest struct {
+	Title       *string    `json:"title" validate:"omitempty,min=1,max=200"`
+	Description *string    `json:"description" validate:"omitempty,max=10000"`
+	GroupTask   *bool      `json:"group_task"`
+	DueAt       *time.Time `json:"due_at"`
+	// Removes the due date; due_at is ignored when set.
+	ClearDueAt bool     `json:"clear_due_at"`
+	Estimate   *float64 `json:"estimate" validate:"omitempty,gte=0"`
+	// Removes the estimate; estimate is ignored when set.
+	ClearEstimate bool       `json:"clear_estimate"`
+	ProjectID     *uuid.UUID `json:"project_id"`
+	// Takes the task out of its project; project_id is ignored when set.
+	ClearProject bool       `json:"clear_project"`
+	SprintID     *uuid.UUID `json:"sprint_id"`
+	// Moves the task back to the backlog; sprint_id is ignored when set.
+	ClearSprint bool       `json:"clear_sprint"`
+	MilestoneID *uuid.UUID `json:"milestone_id"`
+	// Detaches the task from its milestone; milestone_id is ignored when set.
+	ClearMilestone bool `json:"clear_milestone"`
+	// Custom field values by field key; only the listed fields change and null clears
+	// a field.
+	CustomFields map[string]any `json:"custom_fields" validate:"max=100"`
+}
+
+type TaskAssigneesRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" validate:"required,min=1,max=50"`
+}
+
+type TaskLabelsRequest struct {
+	LabelIDs []uuid.UUID `json:"label_ids" validate:"required,min=1,max=50"`
+}
+
+type LabelRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=50"`
+	// Hex color, e.g. "#d73a4a".
+	Color       string `json:"color" validate:"required,hexcolor"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+// LabelUpdateRequest only changes the fields that are present.
+type LabelUpdateRequest struct {
+	Name        *string `json:"name" validate:"omitempty,min=1,max=50"`
+	Color       *string `json:"color" validate:"omitempty,hexcolor"`
+	Description *string `json:"description" validate:"omitempty,max=500"`
+}
+
+// LabelMergeRequest merges the label of the path into target_id.
+type LabelMergeRequest struct {
+	TargetID uuid.UUID `json:"target_id" validate:"required"`
+}
+
+type CustomFieldRequest struct {
+	// Lowercase letters, digits and underscores, starting with a letter. The key is
+	// used in task payloads and filters (cf.<key>) and cannot be changed.
+	Key  string                 `json:"key" validate:"required,max=50"`
+	Name string                 `json:"name" validate:"required,min=1,max=100"`
+	Type models.CustomFieldType `json:"type" validate:"required"`
+	// Tasks cannot be created without a value, nor can the value be cleared.
+	Required bool `json:"required"`
+	// Bounds of numbers, of the length of text and of the number of selected options.
+	Min     *float64 `json:"min"`
+	Max     *float64 `json:"max"`
+	Pattern string   `json:"pattern" validate:"max=200"`
+	// Choices of select fields.
+	Options  []string `json:"options" validate:"max=100,dive,max=100"`
+	Position *int     `json:"position" validate:"omitempty,gte=0"`
+}
+
+// CustomFieldUpdateRequest only changes the fields that are present. Removing an option
+// clears it from the tasks that had it selected.
+type CustomFieldUpdateRequest struct {
+	Name     *string  `json:"name" validate:"omitempty,min=1,max=100"`
+	Required *bool    `json:"required"`
+	Min      *float64 `json:"min"`
+	Max      *float64 `json:"max"`
+	// Removes the bounds; min and max are ignored when set.
+	ClearBounds bool     `json:"clear_bounds"`
+	Pattern     *string  `json:"pattern" validate:"omitempty,max=200"`
+	Options     []string `json:"options" validate:"omitempty,max=100,dive,max=100"`
+	Position    *int     `json:"position" validate:"omitempty,gte=0"`
+}
+
+// TaskRecurrenceRequest replaces the schedule of a task. The task becomes the current
+// instance of the series and is due at the first occurrence at or after starts_at.
+type TaskRecurrenceRequest struct {
+	// RRULE with FREQ=DAILY, WEEKLY, MONTHLY or YEARLY and optionally INTERVAL, BYDAY,
+	// BYMONTHDAY, BYMONTH, COUNT or UNTIL, e.g. "FREQ=WEEKLY;BYDAY=MO,TH".
+	Rule string `json:"rule" validate:"required,max=500"`
+	// IANA time zone name, e.g. "Europe/Berlin".
+	Timezone string                `json:"timezone" validate:"required,max=64"`
+	Mode     models.RecurrenceMode `json:"mode" validate:"required"`
+	// Defaults to the due date of the task, or now.
+	StartsAt *time.Time `json:"starts_at"`
+}
+
+type TaskRemindersRequest struct {
+	// Minutes before the due date, at most 30 days. Empty restores the default reminders
+	// of each recipient.
+	MinutesBefore []int `json:"minutes_before" validate:"max=10,dive,gte=0,lte=43200"`
+}
+
+// UserPreferencesUpdateRequest only changes the fields that are present.
+type UserPreferencesUpdateRequest struct {
+	// IANA time zone name, e.g. "Europe/Berlin".
+	Timezone *string `json:"timezone" validate:"omitempty,max=64"`
+	// "HH:MM" in the time zone of the user; set both to turn quiet hours on.
+	QuietHoursStart *string `json:"quiet_hours_start"`
+	QuietHoursEnd   *string `json:"quiet_hours_end"`
+	// Turns quiet hours off; quiet_hours_start and quiet_hours_end are ignored when set.
+	ClearQuietHours bool `json:"clear_quiet_hours"`
+	// Minutes before the due date, at most 30 days, for tasks without reminders of
+	// their own.
+	DefaultReminders   []int `json:"default_reminders" validate:"omitempty,max=10,dive,gte=0,lte=43200"`
+	EmailNotifications *bool `json:"email_notifications"`
+}
+
+type ProjectRequest struct {
+	Name        string     `json:"name" validate:"required,min=1,max=100"`
+	Description string     `json:"description" validate:"max=10000"`
+	LeadID      *uuid.UUID `json:"lead_id"`
+	// Defaults to planned.
+	Status     models.ProjectStatus `json:"status"`
+	StartDate  *time.Time           `json:"start_date"`
+	TargetDate *time.Time           `json:"target_date"`
+	// Only members, the lead and team admins can file tasks into restricted projects.
+	Restricted bool        `json:"restricted"`
+	MemberIDs  []uuid.UUID `json:"member_ids" validate:"max=200"`
+}
+
+// ProjectUpdateRequest only changes the fields that are present.
+type ProjectUpdateRequest struct {
+	Name        *string    `json:"name" validate:"omitempty,min=1,max=100"`
+	Description *string    `json:"description" validate:"omitempty,max=10000"`
+	LeadID      *uuid.UUID `json:"lead_id"`
+	// Removes the lead; lead_id is ignored when set.
+	ClearLead  bool                  `json:"clear_lead"`
+	Status     *models.ProjectStatus `json:"status"`
+	StartDate  *time.Time            `json:"start_date"`
+	TargetDate *time.Time            `json:"target_date"`
+	// Remove the dates; start_date and target_date are ignored when set.
+	ClearStartDate  bool  `json:"clear_start_date"`
+	ClearTargetDate bool  `json:"clear_target_date"`
+	Restricted      *bool `json:"restricted"`
+}
+
+type ProjectMembersRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" validate:"max=200"`
+}
+
+// ProjectTasksMoveRequest moves tasks of a project into another project.
+type ProjectTasksMoveRequest struct {
+	// Null takes the tasks out of any project.
+	TargetProjectID *uuid.UUID `json:"target_project_id"`
+	// Tasks to move; empty moves every task of the project.
+	TaskIDs []uuid.UUID `json:"task_ids" validate:"max=500"`
+}
+
+type SprintRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+	Goal string `json:"goal" validate:"max=2000"`
+	// Scopes the sprint to a project of the team.
+	ProjectID *uuid.UUID `json:"project_id"`
+	StartDate time.Time  `json:"start_date" validate:"required"`
+	EndDate   time.Time  `json:"end_date" validate:"required"`
+	Capacity  *float64   `json:"capacity" validate:"omitempty,gte=0"`
+}
+
+// SprintUpdateRequest only changes the fields that are present.
+type SprintUpdateRequest struct {
+	Name      *string    `json:"name" validate:"omitempty,min=1,max=100"`
+	Goal      *string    `json:"goal" validate:"omitempty,max=2000"`
+	ProjectID *uuid.UUID `json:"project_id"`
+	// Makes the sprint a team sprint; project_id is ignored when set.
+	ClearProject bool       `json:"clear_project"`
+	StartDate    *time.Time `json:"start_date"`
+	EndDate      *time.Time `json:"end_date"`
+	Capacity     *float64   `json:"capacity" validate:"omitempty,gte=0"`
+	// Removes the capacity; capacity is ignored when set.
+	ClearCapacity bool `json:"clear_capacity"`
+}
+
+type MilestoneRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Description string `json:"description" validate:"max=10000"`
+	// Scopes the milestone to a project of the team.
+	ProjectID *uuid.UUID `json:"project_id"`
+	DueAt     time.Time  `json:"due_at" validate:"required"`
+}
+
+// MilestoneUpdateRequest only changes the fields that are present.
+type MilestoneUpdateRequest struct {
+	Name        *string    `json:"name" validate:"omitempty,min=1,max=100"`
+	Description *string    `json:"description" validate:"omitempty,max=10000"`
+	ProjectID   *uuid.UUID `json:"project_id"`
+	// Makes the milestone a team milestone; project_id is ignored when set.
+	ClearProject bool       `json:"clear_project"`
+	DueAt        *time.Time `json:"due_at"`
+}
+
+type MilestoneCompleteRequest struct {
+	// Completes the milestone even though some of its tasks are not done.
+	Force bool `json:"force"`
+}
+
+type SprintCloseRequest struct {
+	// Planned sprint that takes the unfinished tasks; null moves them to the backlog.
+	TargetSprintID *uuid.UUID `json:"target_sprint_id"`
+}
+
+type EstimationRequest struct {
+	Unit models.EstimateUnit `json:"unit" validate:"required,oneof=points hours"`
+	// Default weekly capacity of the members; null removes it.
+	WeeklyCapacity *float64 `json:"weekly_capacity" validate:"omitempty,gte=0"`
+}
+
+type MemberCapacityRequest struct {
+	// Null makes the member use the weekly capacity of the team.
+	WeeklyCapacity *float64 `json:"weekly_capacity" validate:"omitempty,gte=0"`
+}
+
+type TimerStartRequest struct {
+	Note string `json:"note" validate:"max=2000"`
+	// Defaults to true.
+	Billable *bool `json:"billable"`
+}
+
+// TimeEntryRequest logs time on a task by hand.
+type TimeEntryRequest struct {
+	StartedAt time.Time `json:"started_at" validate:"required"`
+	EndedAt   time.Time `json:"ended_at" validate:"required"`
+	Note      string    `json:"note" validate:"max=2000"`
+	// Defaults to true.
+	Billable *bool `json:"billable"`
+}
+
+// TimeEntryUpdateRequest only changes the fields that are present. The times of a
+// running timer cannot be changed.
+type TimeEntryUpdateRequest struct {
+	StartedAt *time.Time `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Note      *string    `json:"note" validate:"omitempty,max=2000"`
+	Billable  *bool      `json:"billable"`
+}
+
+type BoardColumnRequest struct {
+	StateID uuid.UUID `json:"state_id" validate:"required"`
+	// Defaults to the name of the state.
+	Name     string `json:"name" validate:"max=100"`
+	WIPLimit *int   `json:"wip_limit" validate:"omitempty,gte=1"`
+	// Defaults to warn.
+	WIPMode models.WIPMode `json:"wip_mode"`
+}
+
+type BoardRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+	// Defaults to none.
+	SwimlaneBy models.SwimlaneBy `json:"swimlane_by"`
+	// Single-select custom field of custom_field swimlanes.
+	SwimlaneFieldID *uuid.UUID `json:"swimlane_field_id"`
+	// Defaults to one column per workflow state, in workflow order.
+	Columns []BoardColumnRequest `json:"columns" validate:"omitempty,max=50,dive"`
+}
+
+// BoardUpdateRequest only changes the fields that are present.
+type BoardUpdateRequest struct {
+	Name            *string            `json:"name" validate:"omitempty,min=1,max=100"`
+	SwimlaneBy      *models.SwimlaneBy `json:"swimlane_by"`
+	SwimlaneFieldID *uuid.UUID         `json:"swimlane_field_id"`
+}
+
+// BoardColumnsRequest replaces the columns of a board, in display order.
+type BoardColumnsRequest struct {
+	Columns []BoardColumnRequest `json:"columns" validate:"required,min=1,max=50,dive"`
+}
+
+// BoardMoveRequest moves a card into a column, between after_id and before_id. With
+// neither, the card goes to the bottom of the column.
+type BoardMoveRequest struct {
+	TaskID   uuid.UUID `json:"task_id" validate:"required"`
+	ColumnID uuid.UUID `json:"column_id" validate:"required"`
+	// Card right above the new position.
+	AfterID *uuid.UUID `json:"after_id"`
+	// Card right below the new position. With after_id, the two must be adjacent, or
+	// the move is refused as a conflict with a concurrent change.
+	BeforeID *uuid.UUID `json:"before_id"`
+	// Moves into a done column even while blockers are not done.
+	Force bool `json:"force"`
+}
+
+type TaskMoveRequest struct {
+	// New parent task; null makes the task a top-level task.
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+type ChecklistItemRequest struct {
+	Content string `json:"content" validate:"required,min=1,max=500"`
+}
+
+type ChecklistItemUpdateRequest struct {
+	Content *string `json:"content" validate:"omitempty,min=1,max=500"`
+	Done    *bool   `json:"done"`
+}
+
+// ChecklistOrderRequest lists every item of the checklist in the new order.
+type ChecklistOrderRequest struct {
+	ItemIDs []uuid.UUID `json:"item_ids" validate:"required,min=1,max=500"`
+}
+
+type CommentRequest struct {
+	// Markdown; "@firstname.lastname" and "@<user id>" mention team members.
+	Body string `json:"body" validate:"required,min=1,max=10000"`
+	// Replies to this top-level comment.
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+type CommentUpdateRequest struct {
+	Body string `json:"body" validate:"required,min=1,max=10000"`
+}
+
+type TaskStateRequest struct {
+	StateID uuid.UUID `json:"state_id" validate:"required"`
+	// Moves the task to a done state even though some of its blockers are not done.
+	Force bool `json:"force"`
+}
+
+// TaskLinkRequest links the task of the path to task_id. "blocked_by" is stored as a
+// "blocks" link from task_id.
+type TaskLinkRequest struct {
+	TaskID uuid.UUID `json:"task_id" validate:"required"`
+	Type   string    `json:"type" validate:"required,oneof=blocks blocked_by relates_to"`
+}
+
+type WorkflowStateRequest struct {
+	// Id of an existing state; omit it for new states.
+	ID       *uuid.UUID              `json:"id"`
+	Name     string                  `json:"name" validate:"required,min=1,max=50"`
+	Category models.WorkflowCategory `json:"category" validate:"required"`
+}
+
+type WorkflowTransitionRequest struct {
+	// State names
+	From string `json:"from" validate:"required"`
+	To   string `json:"to" validate:"required"`
+	// Team roles allowed to make the transition; empty means every member.
+	Roles []models.TeamUserRole `json:"roles"`
+}
+
+// WorkflowRequest replaces the workflow of a team. States are listed in order.
+// Without transitions every move between states is allowed.
+type WorkflowRequest struct {
+	States      []WorkflowStateRequest      `json:"states" validate:"required,min=2,max=50,dive"`
+	Transitions []WorkflowTransitionRequest `json:"transitions" validate:"max=500,dive"`
+}
+
 // Response DTOs
+type ProjectTasksMoveResponse struct {
+	Moved int `json:"moved"`
+}
+
 type AuthTokenResponse struct {
 	AccessToken  string `json:"access_token"`
 	TokenType    string `json:"token_type"`
@@ -33,6 +424,12 @@ type MeResponse struct {
 	LastName  string          `json:"lastname"`
 	Email     string          `json:"email"`
 	UserType  models.UserType `json:"user_type"`
+	// Set when the request uses an impersonation token: the administrator's user id.
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
 }
 
 type LogoutResponse struct {
@@ -44,3 +441,42 @@ type AppInfoResponse struct {
 	Commit    string `json:"commit"`
 	BuildTime string `json:"build_time"`
 }
+
+type AdminUserListResponse struct {
+	Users  []*models.User `json:"users"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+type AdminUserDetailResponse struct {
+	User       *models.User          `json:"user"`
+	Teams      []*models.Team        `json:"teams"`
+	Identities []models.AuthProvider `json:"identities"`
+}
+
+type AdminAuditListResponse struct {
+	Entries []*models.AdminAuditLog `json:"entries"`
+	Total   int                     `json:"total"`
+	Limit   int                     `json:"limit"`
+	Offset  int                     `json:"offset"`
+}
+
+type AdminPasswordResetResponse struct {
+	// Hand this token to the user out-of-band; it is only shown once.
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ImpersonationResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresAt   int64  `json:"expires_at"`
+	UserID      string `json:"user_id"`
+	ActorID     string `json:"actor_id"`
+}
+
+type CSRFTokenResponse struct {
+	Token      string `json:"csrf_token"`
+	HeaderName string `json:"header_name"`
+}
@@ -1,9 +1,11 @@
 package dto
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strings"
+	"task_manager/public/listquery"
 	"task_manager/public/trace"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +23,14 @@ type Envelope[T any] struct {
 	TraceID    string `json:"trace_id,omitempty"`
 	Debug      string `json:"debug,omitempty"`
 	Data       T      `json:"data"`
+	Meta       *Meta  `json:"meta,omitempty"`
+}
+
+// Meta carries the pagination state of list responses.
+type Meta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int   `json:"total,omitempty"`
 }
 
 type (
@@ -41,6 +51,30 @@ func OK[T any](c *gin.Context, status int, data T) {
 	})
 }
 
+// OKPage sends one page of a list; data is the page's items.
+func OKPage[T any](c *gin.Context, res listquery.Result[T]) {
+	c.JSON(http.StatusOK, Envelope[[]T]{
+		StatusCode: http.StatusOK,
+		Success:    true,
+		TraceID:    trace.Get(c),
+		Data:       res.Items,
+		Meta: &Meta{
+			NextCursor: res.NextCursor,
+			HasMore:    res.HasMore,
+			Total:      res.Total,
+		},
+	})
+}
+
+// ListQueryError turns a listquery.Parse error into a 400 response.
+func ListQueryError(err error) *ErrorEnvelope {
+	var qerr *listquery.Error
+	if errors.As(err, &qerr) {
+		return BadRequest(CodeInvalidRequest, qerr.Message, map[string]any{"param": qerr.Param})
+	}
+	return BadRequest(CodeInvalidRequest, "invalid list parameters", nil)
+}
+
 func Fail(c *gin.Context, status int, code ErrorCode, message string, debug string, details map[string]any) {
 	if status == 0 {
 		status = http.StatusInternalServerError
@@ -25,6 +25,53 @@ const (
 	CodeMissingToken ErrorCode = "MISSING_TOKEN"
 
 	CodeInvalidEmail ErrorCode = "INVALID_EMAIL"
+
+	CodeImpersonationDenied ErrorCode = "IMPERSONATION_NOT_ALLOWED"
+	CodeCSRFInvalid         ErrorCode = "CSRF_TOKEN_INVALID"
+
+	CodeInvalidReference    ErrorCode = "INVALID_REFERENCE"
+	CodeTransactionConflict ErrorCode = "TRANSACTION_CONFLICT"
+
+	CodeNotTeamMember ErrorCode = "NOT_TEAM_MEMBER"
+
+	CodeInvalidWorkflow      ErrorCode = "INVALID_WORKFLOW"
+	CodeTransitionNotAllowed ErrorCode = "WORKFLOW_TRANSITION_NOT_ALLOWED"
+
+	CodeSubtaskDepthExceeded ErrorCode = "SUBTASK_DEPTH_EXCEEDED"
+	CodeSubtaskCycle         ErrorCode = "SUBTASK_CYCLE"
+
+	CodeDependencyCycle ErrorCode = "DEPENDENCY_CYCLE"
+	CodeTaskBlocked     ErrorCode = "TASK_BLOCKED"
+
+	CodeFileTooLarge         ErrorCode = "FILE_TOO_LARGE"
+	CodeStorageQuotaExceeded ErrorCode = "STORAGE_QUOTA_EXCEEDED"
+	CodeRangeNotSatisfiable  ErrorCode = "RANGE_NOT_SATISFIABLE"
+
+	CodeInvalidCustomField ErrorCode = "INVALID_CUSTOM_FIELD"
+
+	CodeInvalidRecurrence ErrorCode = "INVALID_RECURRENCE"
+
+	CodeInvalidProject  ErrorCode = "INVALID_PROJECT"
+	CodeProjectArchived ErrorCode = "PROJECT_ARCHIVED"
+
+	CodeInvalidSprint ErrorCode = "INVALID_SPRINT"
+	CodeSprintClosed  ErrorCode = "SPRINT_CLOSED"
+	CodeSprintActive  ErrorCode = "SPRINT_ALREADY_ACTIVE"
+
+	CodeInvalidMilestone   ErrorCode = "INVALID_MILESTONE"
+	CodeMilestoneCompleted ErrorCode = "MILESTONE_COMPLETED"
+	CodeMilestoneOpenTasks ErrorCode = "MILESTONE_HAS_OPEN_TASKS"
+
+	CodeInvalidBoard     ErrorCode = "INVALID_BOARD"
+	CodeWIPLimitExceeded ErrorCode = "WIP_LIMIT_EXCEEDED"
+
+	CodeInvalidTimeEntry ErrorCode = "INVALID_TIME_ENTRY"
+	CodeTimeEntryOverlap ErrorCode = "TIME_ENTRY_OVERLAP"
+	CodeTimerRunning     ErrorCode = "TIMER_ALREADY_RUNNING"
+
+	CodeParentInTrash ErrorCode = "PARENT_IN_TRASH"
+
+	CodeTeamArchived ErrorCode = "TEAM_ARCHIVED"
 )
 
 type ErrorData struct {
@@ -46,7 +93,38 @@ func (c ErrorCode) IsValid() bool {
 		CodeInternalError,
 		CodeInvalidToken,
 		CodeMissingToken,
-		CodeInvalidEmail:
+		CodeInvalidEmail,
+		CodeImpersonationDenied,
+		CodeCSRFInvalid,
+		CodeInvalidReference,
+		CodeTransactionConflict,
+		CodeNotTeamMember,
+		CodeInvalidWorkflow,
+		CodeTransitionNotAllowed,
+		CodeSubtaskDepthExceeded,
+		CodeSubtaskCycle,
+		CodeDependencyCycle,
+		CodeTaskBlocked,
+		CodeFileTooLarge,
+		CodeStorageQuotaExceeded,
+		CodeRangeNotSatisfiable,
+		CodeInvalidCustomField,
+		CodeInvalidRecurrence,
+		CodeInvalidProject,
+		CodeProjectArchived,
+		CodeInvalidSprint,
+		CodeSprintClosed,
+		CodeSprintActive,
+		CodeInvalidMilestone,
+		CodeMilestoneCompleted,
+		CodeMilestoneOpenTasks,
+		CodeInvalidBoard,
+		CodeWIPLimitExceeded,
+		CodeInvalidTimeEntry,
+		CodeTimeEntryOverlap,
+		CodeTimerRunning,
+		CodeParentInTrash,
+		CodeTeamArchived:
 		return true
 	default:
 		return false
@@ -1,7 +1,9 @@
 package dto
 
 import (
+	"errors"
 	"net/http"
+	"task_manager/public/repositories/repoerr"
 
 	"github.com/gin-gonic/gin"
 )
@@ -35,6 +37,10 @@ func Conflict(code ErrorCode, msg string, details map[string]any) *ErrorEnvelope
 	return &ErrorEnvelope{StatusCode: http.StatusConflict, Data: ErrorData{Code: code, Message: msg, Details: details}}
 }
 
+func TooLarge(code ErrorCode, msg string, details map[string]any) *ErrorEnvelope {
+	return &ErrorEnvelope{StatusCode: http.StatusRequestEntityTooLarge, Data: ErrorData{Code: code, Message: msg, Details: details}}
+}
+
 func Internal(code ErrorCode, msg string, debug string, details map[string]any) *ErrorEnvelope {
 	return &ErrorEnvelope{
 		StatusCode: http.StatusInternalServerError,
@@ -50,3 +56,36 @@ func NotFound(code ErrorCode, msg string, debug string, details map[string]any)
 		Debug:      debug,
 	}
 }
+
+// RepoError maps a repository error to the matching error response. resource names the
+// entity in client-facing messages (e.g. "user", "team"). Errors that are not part of the
+// repository error set become a 500 with the original error as debug text.
+func RepoError(err error, resource string) *ErrorEnvelope {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(err, repoerr.ErrNotFound):
+		return NotFound(CodeNotFound, resource+" not found", err.Error(), nil)
+	case errors.Is(err, repoerr.ErrConflict):
+		return &ErrorEnvelope{
+			StatusCode: http.StatusConflict,
+			Data:       ErrorData{Code: CodeConflict, Message: resource + " already exists"},
+			Debug:      err.Error(),
+		}
+	case errors.Is(err, repoerr.ErrForeignKey):
+		return &ErrorEnvelope{
+			StatusCode: http.StatusConflict,
+			Data:       ErrorData{Code: CodeInvalidReference, Message: resource + " references a record that does not exist or is still in use"},
+			Debug:      err.Error(),
+		}
+	case errors.Is(err, repoerr.ErrSerialization):
+		return &ErrorEnvelope{
+			StatusCode: http.StatusConflict,
+			Data:       ErrorData{Code: CodeTransactionConflict, Message: "concurrent update, please retry"},
+			Debug:      err.Error(),
+		}
+	default:
+		return Internal(CodeDatabaseError, "database error", err.Error(), nil)
+	}
+}
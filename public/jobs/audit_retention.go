@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"task_manager/public/repositories"
+	"time"
+)
+
+// AuditRetention deletes the team audit log entries older than the retention period.
+// Entries are otherwise never changed or deleted.
+type AuditRetention struct {
+	uow       repositories.UnitOfWork
+	now       func() time.Time
+	retention time.Duration
+}
+
+// NewAuditRetention keeps entries for the given number of days; 0 keeps them forever.
+func NewAuditRetention(uow repositories.UnitOfWork, days int) *AuditRetention {
+	return &AuditRetention{uow: uow, now: time.Now, retention: time.Duration(days) * 24 * time.Hour}
+}
+
+func (j *AuditRetention) Run(ctx context.Context) error {
+	if j.retention <= 0 {
+		return nil
+	}
+	n, err := j.uow.Audit().PurgeTeamAuditLogs(ctx, j.now().Add(-j.retention))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("job audit_retention: deleted %d team audit entries", n)
+	}
+	return nil
+}
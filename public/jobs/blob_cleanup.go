@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"task_manager/public/blobstore"
+	"task_manager/public/repositories"
+)
+
+// BlobCleanup removes the blobs of deleted attachments. Attachment rows queue their
+// storage key in blob_deletions when they are deleted, including through cascades
+// from tasks and teams.
+type BlobCleanup struct {
+	uow   repositories.UnitOfWork
+	blobs blobstore.Store
+	batch int
+}
+
+func NewBlobCleanup(uow repositories.UnitOfWork, blobs blobstore.Store) *BlobCleanup {
+	return &BlobCleanup{uow: uow, blobs: blobs, batch: 100}
+}
+
+// Run deletes queued blobs until the queue is empty. A blob is dequeued only after it
+// is gone from the store, so a failed deletion is retried on the next run.
+func (j *BlobCleanup) Run(ctx context.Context) error {
+	repo := j.uow.Attachments()
+	for {
+		deletions, err := repo.ListBlobDeletions(ctx, j.batch)
+		if err != nil {
+			return err
+		}
+		for _, d := range deletions {
+			if err := j.blobs.Delete(ctx, d.StorageKey); err != nil {
+				return err
+			}
+			if err := repo.DeleteBlobDeletion(ctx, d.ID); err != nil {
+				return err
+			}
+		}
+		if len(deletions) < j.batch {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// Package jobs holds the background work of the server. Jobs only touch the
+// database through the unit of work, so several replicas can run them at once.
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every calls fn every interval until ctx is done. Errors are logged; the next tick
+// tries again.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := fn(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("job %s: %v", name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"task_manager/public/mailer"
+	"task_manager/public/repositories"
+	"time"
+)
+
+// MailSender delivers the email outbox. Emails are claimed for lease before they are
+// sent, so replicas running the job at once send each email once; an email whose
+// sender died mid-send is sent again when the lease expires.
+type MailSender struct {
+	uow         repositories.UnitOfWork
+	mailer      mailer.Mailer
+	now         func() time.Time
+	batch       int
+	lease       time.Duration
+	maxAttempts int
+}
+
+func NewMailSender(uow repositories.UnitOfWork, m mailer.Mailer) *MailSender {
+	return &MailSender{uow: uow, mailer: m, now: time.Now, batch: 50, lease: 5 * time.Minute, maxAttempts: 5}
+}
+
+// Run sends every email that is not claimed by another sender. Failed emails are
+// retried after the lease until maxAttempts is reached.
+func (j *MailSender) Run(ctx context.Context) error {
+	repo := j.uow.Outbox()
+	for {
+		now := j.now()
+		emails, err := repo.ClaimEmails(ctx, now, now.Add(j.lease), j.maxAttempts, j.batch)
+		if err != nil {
+			return err
+		}
+		for _, e := range emails {
+			err := j.mailer.Send(ctx, mailer.Message{To: e.Recipient, Subject: e.Subject, Body: e.Body})
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				log.Printf("email %s attempt %d: %v", e.ID, e.Attempts, err)
+				if err := repo.MarkEmailFailed(ctx, e.ID, err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := repo.MarkEmailSent(ctx, e.ID, j.now()); err != nil {
+				return err
+			}
+		}
+		if len(emails) < j.batch {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"task_manager/public/recurrence"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"time"
+)
+
+// RecurrenceScheduler creates the next instance of on_schedule recurring tasks once the
+// occurrence of their current instance has arrived. The schedule lives in
+// task_recurrences.next_run_at, so nothing is lost across restarts, and each series is
+// advanced in a transaction that first locks it, so replicas running the job at the
+// same time create every instance once.
+type RecurrenceScheduler struct {
+	uow   repositories.UnitOfWork
+	now   func() time.Time
+	batch int
+}
+
+func NewRecurrenceScheduler(uow repositories.UnitOfWork) *RecurrenceScheduler {
+	return &RecurrenceScheduler{uow: uow, now: time.Now, batch: 100}
+}
+
+// Run advances every due series. A series that fails is logged and retried on the
+// next run; it does not hold up the others. Failed series keep their next_run_at, so
+// they stay at the front of the due list and are skipped by offset.
+func (j *RecurrenceScheduler) Run(ctx context.Context) error {
+	now := j.now()
+	failed := 0
+	for {
+		limit := j.batch + failed
+		due, err := j.uow.Recurrences().ListDueRecurrences(ctx, now, limit)
+		if err != nil {
+			return err
+		}
+		advanced := 0
+		for _, rec := range due[min(failed, len(due)):] {
+			err := j.advance(ctx, rec, now)
+			switch {
+			case err == nil || errors.Is(err, recurrence.ErrMoved):
+				advanced++
+			case ctx.Err() != nil:
+				return ctx.Err()
+			default:
+				log.Printf("recurrence %s: %v", rec.ID, err)
+				failed++
+			}
+		}
+		if advanced == 0 || len(due) < limit {
+			return nil
+		}
+	}
+}
+
+func (j *RecurrenceScheduler) advance(ctx context.Context, rec *models.Recurrence, now time.Time) error {
+	tx, err := j.uow.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Stop()
+	if _, err := recurrence.Advance(ctx, tx, rec, now); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
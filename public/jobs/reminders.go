@@ -0,0 +1,156 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReminderWorker notifies the recipients of a task, its assignees or else its creator,
+// when one of its reminders fires and when it becomes overdue. Each notice is recorded
+// in reminder_deliveries in the transaction that creates it, keyed by task, user and
+// due date, so restarts and replicas running the worker at once never send it twice.
+//
+// A notice is only sent within lookback of when it fired: older ones, missed while
+// nothing ran, are dropped rather than delivered late. Notices falling into the quiet
+// hours of a user wait until they end, which is why lookback spans a whole day.
+type ReminderWorker struct {
+	uow      repositories.UnitOfWork
+	now      func() time.Time
+	lookback time.Duration
+}
+
+func NewReminderWorker(uow repositories.UnitOfWork) *ReminderWorker {
+	return &ReminderWorker{uow: uow, now: time.Now, lookback: 24 * time.Hour}
+}
+
+// notice is one reminder or overdue notice of a task for a user.
+type notice struct {
+	kind          models.DeliveryKind
+	minutesBefore int
+}
+
+// Run sends the notices that are due. A notice that fails is logged and retried on the
+// next run.
+func (j *ReminderWorker) Run(ctx context.Context) error {
+	now := j.now()
+	tasks, err := j.uow.Reminders().ListDueTasks(ctx, now.Add(-j.lookback), now.Add(models.MaxReminderMinutes*time.Minute))
+	if err != nil {
+		return err
+	}
+	users := map[uuid.UUID]*models.User{}
+	prefs := map[uuid.UUID]*models.UserPreferences{}
+	for _, task := range tasks {
+		for _, userID := range task.Recipients() {
+			if _, ok := prefs[userID]; !ok {
+				if users[userID], err = j.uow.Users().GetUserByID(ctx, userID); err != nil {
+					return err
+				}
+				if prefs[userID], err = j.uow.Reminders().GetUserPreferences(ctx, userID); err != nil {
+					return err
+				}
+			}
+			user, p := users[userID], prefs[userID]
+			if user.IsDisabled() || p.InQuietHours(now) {
+				continue
+			}
+			for _, n := range j.notices(task, p, now) {
+				if err := j.deliver(ctx, task, user, p, n); err != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					log.Printf("reminder task=%s user=%s kind=%s: %v", task.ID, userID, n.kind, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// notices returns what is due for the task and a recipient with preferences p. Of the
+// reminders that fired, only the latest is sent: a task created an hour before it is
+// due should not get its one-day reminder. Once the task is due, only the overdue
+// notice is sent; it doubles as the reminder at the due time.
+func (j *ReminderWorker) notices(task *models.DueTask, p *models.UserPreferences, now time.Time) []notice {
+	if !task.DueAt.After(now) {
+		return []notice{{kind: models.DeliveryOverdue}}
+	}
+	reminders := task.Reminders
+	if len(reminders) == 0 {
+		reminders = p.DefaultReminders
+	}
+	latest := -1
+	for _, m := range reminders {
+		fired := task.DueAt.Add(-time.Duration(m) * time.Minute)
+		if !fired.After(now) && now.Sub(fired) < j.lookback && (latest < 0 || m < latest) {
+			latest = m
+		}
+	}
+	if latest < 0 {
+		return nil
+	}
+	return []notice{{kind: models.DeliveryReminder, minutesBefore: latest}}
+}
+
+func (j *ReminderWorker) deliver(ctx context.Context, task *models.DueTask, user *models.User, p *models.UserPreferences, n notice) error {
+	tx, err := j.uow.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Stop()
+
+	first, err := tx.Reminders().RecordDelivery(ctx, models.ReminderDelivery{
+		TaskID:        task.ID,
+		UserID:        user.ID,
+		Kind:          n.kind,
+		MinutesBefore: n.minutesBefore,
+		DueAt:         task.DueAt,
+	})
+	if err != nil || !first {
+		return err
+	}
+	kind := models.NotificationReminder
+	if n.kind == models.DeliveryOverdue {
+		kind = models.NotificationOverdue
+	}
+	err = tx.Notifications().CreateNotifications(ctx, []*models.Notification{{
+		ID:     uuid.New(),
+		UserID: user.ID,
+		TeamID: task.TeamID,
+		Kind:   kind,
+		TaskID: &task.ID,
+	}})
+	if err != nil {
+		return err
+	}
+	if p.EmailNotifications {
+		subject, body := reminderEmail(task, p, n)
+		err := tx.Outbox().EnqueueEmail(ctx, &models.OutboxEmail{
+			ID:        uuid.New(),
+			Recipient: user.Email,
+			Subject:   subject,
+			Body:      body,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// reminderEmail writes the email for a notice, with the due date in the time zone of
+// the recipient.
+func reminderEmail(task *models.DueTask, p *models.UserPreferences, n notice) (string, string) {
+	due := task.DueAt.In(p.Location()).Format("Mon, 02 Jan 2006 15:04 MST")
+	if n.kind == models.DeliveryOverdue {
+		return fmt.Sprintf("Overdue: %s", task.Title),
+			fmt.Sprintf("%q was due %s and is not done yet.\n", task.Title, due)
+	}
+	return fmt.Sprintf("Reminder: %s", task.Title),
+		fmt.Sprintf("%q is due %s.\n", task.Title, due)
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"task_manager/public/repositories"
+	"time"
+)
+
+// TrashPurge deletes for good what has been in the trash longer than the retention
+// period. Until then, deleted teams, projects, tasks and comments can be restored.
+type TrashPurge struct {
+	uow       repositories.UnitOfWork
+	now       func() time.Time
+	retention time.Duration
+}
+
+// NewTrashPurge keeps deleted items for the given number of days; 0 keeps them forever.
+func NewTrashPurge(uow repositories.UnitOfWork, days int) *TrashPurge {
+	return &TrashPurge{uow: uow, now: time.Now, retention: time.Duration(days) * 24 * time.Hour}
+}
+
+func (j *TrashPurge) Run(ctx context.Context) error {
+	if j.retention <= 0 {
+		return nil
+	}
+	tx, err := j.uow.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Stop()
+	n, err := tx.Trash().PurgeTrash(ctx, j.now().Add(-j.retention))
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("job trash_purge: purged %d items from the trash", n)
+	}
+	return nil
+}
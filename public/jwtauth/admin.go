@@ -0,0 +1,56 @@
+package jwtauth
+
+import (
+	"errors"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+
+	jwt "github.com/appleboy/gin-jwt/v3"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAccountDisabled       = errors.New("account is disabled")
+	ErrPasswordResetRequired = errors.New("password reset required")
+)
+
+// CurrentUserID returns the id of the authenticated user from the JWT claims.
+func CurrentUserID(c *gin.Context) (uuid.UUID, error) {
+	claims := jwt.ExtractClaims(c)
+	raw, _ := claims[IdentityKey].(string)
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return uuid.Nil, errors.New("missing user id claim")
+	}
+	return uuid.Parse(raw)
+}
+
+// RequireAdmin only lets system administrators through.
+// It must run after the JWT middleware. The user type is re-read from the database
+// instead of trusting the token claims, so demoted or disabled admins lose access immediately.
+func RequireAdmin(users repositories.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := CurrentUserID(c)
+		if err != nil {
+			dto.Unauthorized(dto.CodeUnauthorized, "unauthorized", nil).Send(c)
+			return
+		}
+		u, err := users.GetUserByID(c.Request.Context(), userID)
+		if errors.Is(err, repositories.ErrNotFound) {
+			dto.Forbidden(dto.CodeForbidden, "admin access required", nil).Send(c)
+			return
+		}
+		if err != nil {
+			dto.RepoError(err, "user").Send(c)
+			return
+		}
+		if u.IsDisabled() || u.UserType != models.AdminUser {
+			dto.Forbidden(dto.CodeForbidden, "admin access required", nil).Send(c)
+			return
+		}
+		c.Next()
+	}
+}
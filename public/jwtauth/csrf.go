@@ -0,0 +1,116 @@
+package jwtauth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"task_manager/public/dto"
+	"task_manager/public/trace"
+
+	jwt "github.com/appleboy/gin-jwt/v3"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	CSRFCookieName = "csrf_token"
+	CSRFHeaderKey  = "X-CSRF-Token"
+)
+
+// CSRF enforces a signed double-submit token on state-changing requests that are
+// authenticated by the JWT cookie. Requests authenticated by a valid Bearer token in
+// the Authorization header are not affected: browsers never attach that header
+// cross-site on their own. Any other Authorization header does not exempt the request,
+// since the JWT middleware then falls back to the cookie.
+//
+// The token is bound to the user id and signed with the JWT key, so a cookie planted
+// from a sibling subdomain (which SameSite=Lax does not stop) cannot be forged.
+// Register it on the engine before any routes.
+func CSRF(mw *jwt.GinJWTMiddleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			c.Next()
+			return
+		}
+		if bearerAuthenticated(c, mw) {
+			c.Next()
+			return
+		}
+		userID, ok := cookieUserID(c, mw)
+		if !ok {
+			// Not cookie-authenticated; the JWT middleware decides what happens next.
+			c.Next()
+			return
+		}
+
+		header := strings.TrimSpace(c.GetHeader(CSRFHeaderKey))
+		cookie, _ := c.Cookie(CSRFCookieName)
+		if header == "" || cookie == "" ||
+			!hmac.Equal([]byte(header), []byte(cookie)) ||
+			!validCSRFToken(mw.Key, userID, header) {
+			trace.Log(c, "csrf_rejected", "user_id="+userID+" method="+c.Request.Method+" path="+c.Request.URL.Path)
+			dto.Forbidden(dto.CodeCSRFInvalid, "missing or invalid CSRF token", nil).Send(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// IssueCSRFToken creates a token for userID and stores it in the (JS-readable) CSRF cookie.
+// Clients echo it back in the X-CSRF-Token header.
+func IssueCSRFToken(c *gin.Context, mw *jwt.GinJWTMiddleware, userID string) (string, error) {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	n := base64.RawURLEncoding.EncodeToString(nonce)
+	token := n + "." + signCSRF(mw.Key, userID, n)
+
+	maxAge := int(mw.CookieMaxAge.Seconds())
+	c.SetSameSite(mw.CookieSameSite)
+	c.SetCookie(CSRFCookieName, token, maxAge, "/", mw.CookieDomain, mw.SecureCookie, false)
+	return token, nil
+}
+
+// bearerAuthenticated reports whether the request carries a valid token in the
+// Authorization header, read the way the JWT middleware reads it, so that the header
+// and not the cookie is what authenticates the request.
+func bearerAuthenticated(c *gin.Context, mw *jwt.GinJWTMiddleware) bool {
+	scheme, raw, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || scheme != mw.TokenHeadName || raw == "" {
+		return false
+	}
+	_, err := mw.ParseTokenString(raw)
+	return err == nil
+}
+
+func cookieUserID(c *gin.Context, mw *jwt.GinJWTMiddleware) (string, bool) {
+	raw, err := c.Cookie(mw.CookieName)
+	if err != nil || raw == "" {
+		return "", false
+	}
+	token, err := mw.ParseTokenString(raw)
+	if err != nil {
+		return "", false
+	}
+	userID, _ := jwt.ExtractClaimsFromToken(token)[IdentityKey].(string)
+	userID = strings.TrimSpace(userID)
+	return userID, userID != ""
+}
+
+func signCSRF(key []byte, userID string, nonce string) string {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte("csrf:" + userID + ":" + nonce))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
+
+func validCSRFToken(key []byte, userID string, token string) bool {
+	nonce, sig, ok := strings.Cut(token, ".")
+	if !ok || nonce == "" || sig == "" {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(signCSRF(key, userID, nonce)))
+}
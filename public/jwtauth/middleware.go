@@ -28,6 +28,8 @@ type UserIdentity struct {
 	LastName  string          `json:"lastname,omitempty"`
 	Avatar    string          `json:"avatar_url,omitempty"`
 	UserType  models.UserType `json:"user_type,omitempty"`
+	// ActorID is set on impersonation tokens: the administrator acting as this user.
+	ActorID string `json:"act,omitempty"`
 }
 
 func New(users repositories.UserRepository, secret string) (*jwt.GinJWTMiddleware, error) {
@@ -45,9 +47,17 @@ func New(users repositories.UserRepository, secret string) (*jwt.GinJWTMiddlewar
 		MaxRefresh:  24 * time.Hour,
 		IdentityKey: IdentityKey,
 
+		// Impersonation tokens are short-lived; everything else uses Timeout.
+		TimeoutFunc: func(data any) time.Duration {
+			if v, ok := data.(*UserIdentity); ok && v.ActorID != "" {
+				return ImpersonationTTL
+			}
+			return time.Hour
+		},
+
 		PayloadFunc: func(data any) gojwt.MapClaims {
 			if v, ok := data.(*UserIdentity); ok {
-				return gojwt.MapClaims{
+				claims := gojwt.MapClaims{
 					IdentityKey: v.ID,
 					"email":     v.Email,
 					"provider":  v.Provider,
@@ -56,6 +66,11 @@ func New(users repositories.UserRepository, secret string) (*jwt.GinJWTMiddlewar
 					"avatar":    v.Avatar,
 					"user_type": v.UserType,
 				}
+				if v.ActorID != "" {
+					// RFC 8693 actor claim
+					claims[ActorClaim] = map[string]any{"sub": v.ActorID}
+				}
+				return claims
 			}
 			return gojwt.MapClaims{}
 		},
@@ -68,7 +83,7 @@ func New(users repositories.UserRepository, secret string) (*jwt.GinJWTMiddlewar
 			first, _ := claims["firstname"].(string)
 			last, _ := claims["lastname"].(string)
 			avatar, _ := claims["avatar"].(string)
-			userType, _ := claims["user_type"].(models.UserType)
+			userType, _ := claims["user_type"].(string)
 			return &UserIdentity{
 				ID:        id,
 				Email:     email,
@@ -76,7 +91,8 @@ func New(users repositories.UserRepository, secret string) (*jwt.GinJWTMiddlewar
 				FirstName: first,
 				LastName:  last,
 				Avatar:    avatar,
-				UserType:  userType,
+				UserType:  models.UserType(userType),
+				ActorID:   actorFromClaims(claims),
 			}
 		},
 
@@ -92,17 +108,26 @@ func New(users repositories.UserRepository, secret string) (*jwt.GinJWTMiddlewar
 			}
 
 			u, err := users.GetUserByEmail(c.Request.Context(), req.Email)
-			if err != nil || u == nil {
+			if err != nil {
 				return nil, jwt.ErrFailedAuthentication
 			}
 			hash, err := users.GetPasswordHashByUserID(c.Request.Context(), u.ID)
-			if err != nil || hash == "" {
+			if err != nil {
 				// likely an OAuth-created user without local password
 				return nil, jwt.ErrFailedAuthentication
 			}
 			if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(req.Password)); err != nil {
 				return nil, jwt.ErrFailedAuthentication
 			}
+			// Only reveal that the account is disabled or has a pending reset once the
+			// caller has proven they know the password.
+			if u.IsDisabled() {
+				trace.Log(c, "login_local_disabled", "user_id="+u.ID.String())
+				return nil, ErrAccountDisabled
+			}
+			if u.PasswordResetRequired {
+				return nil, ErrPasswordResetRequired
+			}
 
 			trace.Log(c, "login_local", "user_id="+u.ID.String()+" email="+u.Email+" user_type="+string(u.UserType))
 			return &UserIdentity{
@@ -116,7 +141,12 @@ func New(users repositories.UserRepository, secret string) (*jwt.GinJWTMiddlewar
 		},
 
 		Authorizer: func(c *gin.Context, data any) bool {
-			_, ok := data.(*UserIdentity)
+			v, ok := data.(*UserIdentity)
+			if ok && v.ActorID != "" {
+				trace.Log(c, "impersonated_request",
+					"actor_id="+v.ActorID+" user_id="+v.ID+" method="+c.Request.Method+" path="+c.Request.URL.Path,
+				)
+			}
 			return ok
 		},
 
@@ -0,0 +1,28 @@
+package jwtauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// PasswordResetTTL is how long an administrator-issued reset token stays valid.
+const PasswordResetTTL = 24 * time.Hour
+
+// NewPasswordResetToken returns a random reset token and the hash that should be stored.
+func NewPasswordResetToken() (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashPasswordResetToken(token), nil
+}
+
+// HashPasswordResetToken hashes a reset token for storage/lookup; raw tokens are never persisted.
+func HashPasswordResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
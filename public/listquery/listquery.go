@@ -0,0 +1,357 @@
+// Package listquery parses and validates list parameters (limit, cursor, sort and field
+// filters) from a query string and turns them into SQL for the supported dialects.
+//
+// Query string format:
+//
+//	?limit=50&sort=-created_at,name&name[contains]=ops&role[in]=admin,founder&cursor=...
+//
+// A plain "field=value" is shorthand for "field[eq]=value". Multi-valued fields (see
+// Field.Many) are filtered with "field[any]=a,b" or "field[all]=a,b". Every field and operator has to
+// be whitelisted in the resource's Spec; column names never come from the request.
+// Pagination is keyset based: cursors are opaque, signed, and only valid for the sort and
+// filters they were issued for.
+package listquery
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ParamLimit        = "limit"
+	ParamCursor       = "cursor"
+	ParamSort         = "sort"
+	ParamIncludeTotal = "include_total"
+
+	defaultLimit = 50
+	maxLimit     = 200
+	maxInValues  = 100
+)
+
+// Type is the value type of a field. It decides how filter and cursor values are parsed.
+type Type int
+
+const (
+	String Type = iota
+	Int
+	Float
+	Bool
+	Time
+	UUID
+)
+
+// Op is a filter operator.
+type Op string
+
+const (
+	OpEq       Op = "eq"
+	OpNe       Op = "ne"
+	OpLt       Op = "lt"
+	OpLte      Op = "lte"
+	OpGt       Op = "gt"
+	OpGte      Op = "gte"
+	OpIn       Op = "in"
+	OpContains Op = "contains"
+	OpIsNull   Op = "isnull"
+	// OpAny and OpAll match rows where a multi-valued field holds at least one, or
+	// every one, of the listed values.
+	OpAny Op = "any"
+	OpAll Op = "all"
+)
+
+// Field describes one whitelisted field of a resource.
+type Field struct {
+	// Column is the SQL expression for the field, e.g. "t.created_at".
+	Column string
+	Type   Type
+	// Nullable fields sort with NULLs last in both directions.
+	Nullable bool
+	Sortable bool
+	// Ops are the allowed filter operators; a field without ops cannot be filtered.
+	Ops []Op
+	// Enum optionally restricts the accepted values of a String field.
+	Enum []string
+	// Many makes the field multi-valued. It is the correlated "<table> <alias> WHERE ..."
+	// clause selecting the rows that hold the values of the current row, and Column is
+	// read from those rows, e.g. Many: "task_labels tl WHERE tl.task_id = tk.id" with
+	// Column: "tl.label_id". Multi-valued fields cannot be sorted.
+	Many string
+}
+
+// Spec is the per-resource whitelist.
+type Spec struct {
+	Fields map[string]Field
+	// IDColumn is a unique column used as the final sort key so the order is total.
+	IDColumn string
+	// DefaultSort uses the same syntax as the sort parameter, e.g. "-created_at".
+	DefaultSort  string
+	DefaultLimit int
+	MaxLimit     int
+}
+
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+type Filter struct {
+	Field string
+	Op    Op
+	// Values holds the typed values: one value for most operators, several for OpIn,
+	// and a bool for OpIsNull.
+	Values []any
+}
+
+// Query is a validated list request. Build it with Parse.
+type Query struct {
+	Limit     int
+	Sort      []SortKey
+	Filters   []Filter
+	WithTotal bool
+
+	spec  *Spec
+	after *cursor
+}
+
+// Error is returned by Parse for invalid input; it is safe to show to clients.
+type Error struct {
+	Param   string
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func invalid(param string, format string, args ...any) *Error {
+	return &Error{Param: param, Message: fmt.Sprintf(format, args...)}
+}
+
+// Parse validates the list parameters in values against spec.
+// Query keys that are neither list parameters nor fields of spec are ignored, so handlers
+// can accept extra parameters of their own.
+func Parse(values url.Values, spec *Spec) (Query, error) {
+	q := Query{spec: spec}
+
+	q.Limit = spec.DefaultLimit
+	if q.Limit <= 0 {
+		q.Limit = defaultLimit
+	}
+	limitMax := spec.MaxLimit
+	if limitMax <= 0 {
+		limitMax = maxLimit
+	}
+	if raw := strings.TrimSpace(values.Get(ParamLimit)); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > limitMax {
+			return Query{}, invalid(ParamLimit, "limit must be between 1 and %d", limitMax)
+		}
+		q.Limit = n
+	}
+
+	if raw := strings.TrimSpace(values.Get(ParamIncludeTotal)); raw != "" {
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return Query{}, invalid(ParamIncludeTotal, "include_total must be a boolean")
+		}
+		q.WithTotal = b
+	}
+
+	sortRaw := strings.TrimSpace(values.Get(ParamSort))
+	if sortRaw == "" {
+		sortRaw = spec.DefaultSort
+	}
+	sort, err := parseSort(sortRaw, spec)
+	if err != nil {
+		return Query{}, err
+	}
+	q.Sort = sort
+
+	// Iterate in a stable order so the cursor signature does not depend on map order.
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		switch key {
+		case ParamLimit, ParamCursor, ParamSort, ParamIncludeTotal:
+			continue
+		}
+		name, op, explicit, err := splitFilterKey(key)
+		if err != nil {
+			return Query{}, err
+		}
+		field, ok := spec.Fields[name]
+		if !ok {
+			if explicit {
+				return Query{}, invalid(key, "unknown filter field %q", name)
+			}
+			continue
+		}
+		if !slices.Contains(field.Ops, op) {
+			if !explicit && len(field.Ops) == 0 {
+				continue
+			}
+			return Query{}, invalid(key, "operator %q is not supported for %q", op, name)
+		}
+		for _, raw := range values[key] {
+			f, err := parseFilter(key, name, op, field, raw)
+			if err != nil {
+				return Query{}, err
+			}
+			q.Filters = append(q.Filters, f)
+		}
+	}
+
+	if raw := strings.TrimSpace(values.Get(ParamCursor)); raw != "" {
+		c, err := decodeCursor(raw, q.signature())
+		if err != nil {
+			return Query{}, invalid(ParamCursor, "invalid cursor")
+		}
+		if err := c.typed(q.Sort, spec); err != nil {
+			return Query{}, invalid(ParamCursor, "invalid cursor")
+		}
+		q.after = c
+	}
+	return q, nil
+}
+
+func parseSort(raw string, spec *Spec) ([]SortKey, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var out []SortKey
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		k := SortKey{Field: part}
+		if strings.HasPrefix(part, "-") {
+			k = SortKey{Field: part[1:], Desc: true}
+		} else if strings.HasPrefix(part, "+") {
+			k.Field = part[1:]
+		}
+		field, ok := spec.Fields[k.Field]
+		if !ok || !field.Sortable {
+			return nil, invalid(ParamSort, "cannot sort by %q", k.Field)
+		}
+		if seen[k.Field] {
+			return nil, invalid(ParamSort, "duplicate sort field %q", k.Field)
+		}
+		seen[k.Field] = true
+		out = append(out, k)
+	}
+	return out, nil
+}
+
+// splitFilterKey splits "name[op]" into its parts; a bare "name" means OpEq.
+func splitFilterKey(key string) (name string, op Op, explicit bool, err error) {
+	open := strings.IndexByte(key, '[')
+	if open < 0 {
+		return key, OpEq, false, nil
+	}
+	if !strings.HasSuffix(key, "]") || open == 0 {
+		return "", "", true, invalid(key, "malformed filter %q", key)
+	}
+	return key[:open], Op(key[open+1 : len(key)-1]), true, nil
+}
+
+func parseFilter(param string, name string, op Op, field Field, raw string) (Filter, error) {
+	f := Filter{Field: name, Op: op}
+	switch op {
+	case OpIsNull:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return Filter{}, invalid(param, "%s must be a boolean", param)
+		}
+		f.Values = []any{b}
+	case OpContains:
+		if strings.TrimSpace(raw) == "" {
+			return Filter{}, invalid(param, "%s must not be empty", param)
+		}
+		f.Values = []any{raw}
+	case OpIn, OpAny, OpAll:
+		parts := strings.Split(raw, ",")
+		if len(parts) > maxInValues {
+			return Filter{}, invalid(param, "%s accepts at most %d values", param, maxInValues)
+		}
+		for _, p := range parts {
+			v, err := parseValue(field, strings.TrimSpace(p))
+			if err != nil {
+				return Filter{}, invalid(param, "invalid value %q for %s", p, param)
+			}
+			// OpAll counts the matching values, so they have to be distinct.
+			if !slices.Contains(f.Values, v) {
+				f.Values = append(f.Values, v)
+			}
+		}
+	default:
+		v, err := parseValue(field, strings.TrimSpace(raw))
+		if err != nil {
+			return Filter{}, invalid(param, "invalid value %q for %s", raw, param)
+		}
+		f.Values = []any{v}
+	}
+	return f, nil
+}
+
+// parseValue converts a request or cursor string into the Go value bound for field.
+func parseValue(field Field, raw string) (any, error) {
+	switch field.Type {
+	case Int:
+		return strconv.ParseInt(raw, 10, 64)
+	case Float:
+		return strconv.ParseFloat(raw, 64)
+	case Bool:
+		return strconv.ParseBool(raw)
+	case Time:
+		// SQLite compares timestamps as text, so every bound time must be in UTC like the
+		// stored ones.
+		if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+			return t.UTC(), nil
+		}
+		return time.Parse(time.DateOnly, raw)
+	case UUID:
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		return id.String(), nil
+	default:
+		if len(field.Enum) > 0 && !slices.Contains(field.Enum, raw) {
+			return nil, fmt.Errorf("value %q not allowed", raw)
+		}
+		return raw, nil
+	}
+}
+
+// signature identifies the ordering and filtering a cursor was issued for.
+func (q Query) signature() string {
+	var b strings.Builder
+	for _, k := range q.Sort {
+		if k.Desc {
+			b.WriteByte('-')
+		}
+		b.WriteString(k.Field)
+		b.WriteByte(',')
+	}
+	b.WriteByte('|')
+	for _, f := range q.Filters {
+		fmt.Fprintf(&b, "%s[%s]=%v;", f.Field, f.Op, f.Values)
+	}
+	return b.String()
+}
+
+// Default returns the first page of spec with its default sort and limit, for callers
+// that list without client input.
+func Default(spec *Spec) Query {
+	q, err := Parse(nil, spec)
+	if err != nil {
+		panic("listquery: invalid default sort: " + err.Error())
+	}
+	return q
+}
@@ -0,0 +1,181 @@
+package listquery_test
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"task_manager/public/listquery"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+var spec = &listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"name":     {Column: "name", Type: listquery.String, Sortable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpContains}},
+		"role":     {Column: "role", Type: listquery.String, Sortable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn}, Enum: []string{"admin", "standard"}},
+		"rank":     {Column: "rank", Type: listquery.Int, Sortable: true, Ops: []listquery.Op{listquery.OpGte, listquery.OpLt}},
+		"due_at":   {Column: "due_at", Type: listquery.Time, Nullable: true, Sortable: true, Ops: []listquery.Op{listquery.OpIsNull}},
+		"internal": {Column: "internal", Type: listquery.String},
+		"tag":      {Column: "rt.tag", Type: listquery.String, Many: "row_tags rt WHERE rt.row_id = id", Ops: []listquery.Op{listquery.OpAny, listquery.OpAll}},
+	},
+	IDColumn:    "id",
+	DefaultSort: "rank",
+	MaxLimit:    100,
+}
+
+func TestParse_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		param string
+	}{
+		{"limit too large", "limit=101", "limit"},
+		{"limit not a number", "limit=abc", "limit"},
+		{"unknown sort field", "sort=password", "sort"},
+		{"not sortable", "sort=internal", "sort"},
+		{"duplicate sort", "sort=name,-name", "sort"},
+		{"unknown filter field", "password[eq]=x", "password[eq]"},
+		{"operator not allowed", "name[gt]=x", "name[gt]"},
+		{"enum value", "role[in]=admin,root", "role[in]"},
+		{"int value", "rank[gte]=high", "rank[gte]"},
+		{"malformed key", "name[eq=x", "name[eq"},
+		{"tampered cursor", "cursor=abc.def", "cursor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			_, err = listquery.Parse(v, spec)
+			var qerr *listquery.Error
+			require.ErrorAs(t, err, &qerr)
+			require.Equal(t, tt.param, qerr.Param)
+		})
+	}
+}
+
+func TestParse_IgnoresUnrelatedParams(t *testing.T) {
+	v, _ := url.ParseQuery("include_archived=true&internal=x")
+	q, err := listquery.Parse(v, spec)
+	require.NoError(t, err)
+	require.Empty(t, q.Filters)
+	require.Equal(t, 50, q.Limit)
+}
+
+func TestBuild_Dialects(t *testing.T) {
+	v := url.Values{"role[in]": {"admin,standard"}, "name[contains]": {"50%_off"}, "sort": {"-rank"}}
+	q, err := listquery.Parse(v, spec)
+	require.NoError(t, err)
+
+	pg := q.Build(listquery.Postgres, "team-id")
+	require.Equal(t, `LOWER(name) LIKE $2 ESCAPE '\' AND role IN ($3, $4)`, pg.Where)
+	require.Equal(t, []any{"team-id", `%50\%\_off%`, "admin", "standard"}, pg.Args)
+	require.Equal(t, "rank DESC, id DESC", pg.OrderBy)
+	require.Equal(t, 51, pg.Limit)
+
+	lite := q.Build(listquery.SQLite)
+	require.Equal(t, `LOWER(name) LIKE ? ESCAPE '\' AND role IN (?, ?)`, lite.Where)
+	require.Equal(t, lite.Where, lite.CountWhere)
+}
+
+func TestBuild_ManyValued(t *testing.T) {
+	v := url.Values{"tag[any]": {"red,blue"}, "tag[all]": {"red,green,red"}}
+	q, err := listquery.Parse(v, spec)
+	require.NoError(t, err)
+
+	pg := q.Build(listquery.Postgres)
+	require.Equal(t,
+		`(SELECT COUNT(DISTINCT rt.tag) FROM row_tags rt WHERE rt.row_id = id AND rt.tag IN ($1, $2)) = 2`+
+			` AND EXISTS (SELECT 1 FROM row_tags rt WHERE rt.row_id = id AND rt.tag IN ($3, $4))`,
+		pg.Where)
+	require.Equal(t, []any{"red", "green", "red", "blue"}, pg.Args)
+}
+
+type row struct {
+	ID    string
+	Name  string
+	Rank  int64
+	DueAt *time.Time
+}
+
+func rowValues(r row) (string, map[string]any) {
+	return r.ID, map[string]any{"name": r.Name, "rank": r.Rank, "due_at": r.DueAt}
+}
+
+// TestPaginate_SQLite walks every page of a real table and checks that keyset pagination
+// returns each row exactly once, in the same order as a single unpaginated query.
+func TestPaginate_SQLite(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE items (id TEXT PRIMARY KEY, name TEXT, role TEXT, rank INTEGER, due_at TIMESTAMP, internal TEXT)`)
+	require.NoError(t, err)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 23; i++ {
+		var due *time.Time
+		if i%3 != 0 {
+			d := base.Add(time.Duration(i%5) * time.Hour)
+			due = &d
+		}
+		_, err := db.Exec(`INSERT INTO items (id, name, role, rank, due_at) VALUES (?, ?, ?, ?, ?)`,
+			fmt.Sprintf("id-%02d", i), fmt.Sprintf("item %d", i), "standard", i%4, due)
+		require.NoError(t, err)
+	}
+
+	fetch := func(q listquery.Query) []row {
+		s := q.Build(listquery.SQLite)
+		rows, err := db.Query(`SELECT id, name, rank, due_at FROM items WHERE `+s.Where+s.Suffix(), s.Args...)
+		require.NoError(t, err)
+		defer rows.Close()
+		var out []row
+		for rows.Next() {
+			var r row
+			require.NoError(t, rows.Scan(&r.ID, &r.Name, &r.Rank, &r.DueAt))
+			out = append(out, r)
+		}
+		require.NoError(t, rows.Err())
+		return out
+	}
+
+	for _, sort := range []string{"rank", "-rank,name", "due_at", "-due_at,-rank"} {
+		t.Run(sort, func(t *testing.T) {
+			all, err := listquery.Parse(url.Values{"sort": {sort}, "limit": {"100"}}, spec)
+			require.NoError(t, err)
+			want := listquery.Paginate(all, fetch(all), rowValues).Items
+			require.Len(t, want, 23)
+
+			var got []row
+			params := url.Values{"sort": {sort}, "limit": {"5"}}
+			for pages := 0; ; pages++ {
+				require.Less(t, pages, 10)
+				q, err := listquery.Parse(params, spec)
+				require.NoError(t, err)
+				res := listquery.Paginate(q, fetch(q), rowValues)
+				got = append(got, res.Items...)
+				if !res.HasMore {
+					break
+				}
+				params.Set("cursor", res.NextCursor)
+			}
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func TestCursor_BoundToQuery(t *testing.T) {
+	q, err := listquery.Parse(url.Values{"sort": {"name"}, "limit": {"1"}}, spec)
+	require.NoError(t, err)
+	res := listquery.Paginate(q, []row{{ID: "a", Name: "a"}, {ID: "b", Name: "b"}}, rowValues)
+	require.True(t, res.HasMore)
+
+	_, err = listquery.Parse(url.Values{"sort": {"name"}, "cursor": {res.NextCursor}}, spec)
+	require.NoError(t, err)
+	_, err = listquery.Parse(url.Values{"sort": {"-name"}, "cursor": {res.NextCursor}}, spec)
+	require.Error(t, err)
+	_, err = listquery.Parse(url.Values{"sort": {"name"}, "name": {"x"}, "cursor": {res.NextCursor}}, spec)
+	require.Error(t, err)
+}
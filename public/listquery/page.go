@@ -0,0 +1,56 @@
+package listquery
+
+import "reflect"
+
+// Page is the pagination state returned next to the items.
+type Page struct {
+	NextCursor string
+	HasMore    bool
+	// Total is only set when the client asked for it (include_total=true).
+	Total *int
+}
+
+type Result[T any] struct {
+	Items []T
+	Page
+}
+
+// RowFunc returns the id and the values of the sortable fields of an item, keyed by
+// field name. Values may be pointers; nil pointers are treated as NULL.
+type RowFunc[T any] func(item T) (id string, values map[string]any)
+
+// Paginate trims the extra row fetched with SQL.Limit and builds the next cursor from the
+// last item of the page.
+func Paginate[T any](q Query, items []T, row RowFunc[T]) Result[T] {
+	res := Result[T]{Items: items}
+	if res.Items == nil {
+		res.Items = []T{}
+	}
+	if len(items) <= q.Limit {
+		return res
+	}
+	res.Items = items[:q.Limit]
+	res.HasMore = true
+
+	id, fields := row(res.Items[len(res.Items)-1])
+	values := make([]any, len(q.Sort))
+	for i, k := range q.Sort {
+		values[i] = deref(fields[k.Field])
+	}
+	res.NextCursor = encodeCursor(q.signature(), values, id)
+	return res
+}
+
+func deref(v any) any {
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() && rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
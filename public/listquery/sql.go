@@ -0,0 +1,220 @@
+package listquery
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect selects the placeholder style.
+type Dialect string
+
+const (
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// SQL is the output of Query.Build. Where and CountWhere are never empty, so they can
+// always be appended with " AND ".
+type SQL struct {
+	// Where holds the filters and the keyset condition of the cursor.
+	Where string
+	Args  []any
+	// CountWhere holds only the filters, for counting the total.
+	CountWhere string
+	CountArgs  []any
+	OrderBy    string
+	// Limit is one more than the page size so Paginate can tell whether there are more rows.
+	Limit int
+}
+
+// Suffix returns the ORDER BY and LIMIT clauses.
+func (s SQL) Suffix() string {
+	return " ORDER BY " + s.OrderBy + " LIMIT " + strconv.Itoa(s.Limit)
+}
+
+type builder struct {
+	d    Dialect
+	args []any
+}
+
+func (b *builder) bind(v any) string {
+	b.args = append(b.args, v)
+	if b.d == Postgres {
+		return "$" + strconv.Itoa(len(b.args))
+	}
+	return "?"
+}
+
+// sortAtom is one ORDER BY term together with the cursor value for it.
+type sortAtom struct {
+	expr  string
+	desc  bool
+	value any
+}
+
+// Build renders the query for dialect d. base are the arguments already bound by the
+// caller's own WHERE clause; they are kept at the front of Args/CountArgs and the
+// placeholders of the generated SQL are numbered after them.
+func (q Query) Build(d Dialect, base ...any) SQL {
+
+	fb := &builder{d: d, args: append([]any(nil), base...)}
+	conds := q.filterConds(fb)
+	out := SQL{
+		CountWhere: joinConds(conds),
+		CountArgs:  fb.args,
+		Limit:      q.Limit + 1,
+	}
+
+	atoms := q.sortAtoms()
+	order := make([]string, 0, len(atoms))
+	for _, a := range atoms {
+		dir := " ASC"
+		if a.desc {
+			dir = " DESC"
+		}
+		order = append(order, a.expr+dir)
+	}
+	out.OrderBy = strings.Join(order, ", ")
+
+	wb := &builder{d: d, args: append([]any(nil), base...)}
+	conds = q.filterConds(wb)
+	if q.after != nil {
+		conds = append(conds, keyset(wb, atoms))
+	}
+	out.Where = joinConds(conds)
+	out.Args = wb.args
+	return out
+}
+
+func joinConds(conds []string) string {
+	if len(conds) == 0 {
+		return "1 = 1"
+	}
+	return strings.Join(conds, " AND ")
+}
+
+func (q Query) filterConds(b *builder) []string {
+	var conds []string
+	for _, f := range q.Filters {
+		field := q.spec.Fields[f.Field]
+		col := field.Column
+		if field.Many != "" {
+			conds = append(conds, manyCond(b, field, f))
+			continue
+		}
+		switch f.Op {
+		case OpEq:
+			conds = append(conds, col+" = "+b.bind(f.Values[0]))
+		case OpNe:
+			conds = append(conds, col+" <> "+b.bind(f.Values[0]))
+		case OpLt:
+			conds = append(conds, col+" < "+b.bind(f.Values[0]))
+		case OpLte:
+			conds = append(conds, col+" <= "+b.bind(f.Values[0]))
+		case OpGt:
+			conds = append(conds, col+" > "+b.bind(f.Values[0]))
+		case OpGte:
+			conds = append(conds, col+" >= "+b.bind(f.Values[0]))
+		case OpIn:
+			ph := make([]string, len(f.Values))
+			for i, v := range f.Values {
+				ph[i] = b.bind(v)
+			}
+			conds = append(conds, col+" IN ("+strings.Join(ph, ", ")+")")
+		case OpContains:
+			pattern := "%" + escapeLike(strings.ToLower(f.Values[0].(string))) + "%"
+			conds = append(conds, "LOWER("+col+") LIKE "+b.bind(pattern)+` ESCAPE '\'`)
+		case OpIsNull:
+			if f.Values[0].(bool) {
+				conds = append(conds, col+" IS NULL")
+			} else {
+				conds = append(conds, col+" IS NOT NULL")
+			}
+		}
+	}
+	return conds
+}
+
+// manyCond renders a filter on a multi-valued field as a correlated subquery.
+func manyCond(b *builder, field Field, f Filter) string {
+	ph := make([]string, len(f.Values))
+	for i, v := range f.Values {
+		ph[i] = b.bind(v)
+	}
+	match := field.Many + " AND " + field.Column + " IN (" + strings.Join(ph, ", ") + ")"
+	if f.Op == OpAll {
+		return "(SELECT COUNT(DISTINCT " + field.Column + ") FROM " + match + ") = " + strconv.Itoa(len(f.Values))
+	}
+	return "EXISTS (SELECT 1 FROM " + match + ")"
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// sortAtoms expands the sort keys into ORDER BY terms. Nullable fields get an extra
+// "is null" term first so NULLs sort last, and the id column ends the list.
+func (q Query) sortAtoms() []sortAtom {
+	var atoms []sortAtom
+	var vals []any
+	if q.after != nil {
+		vals = q.after.Values
+	}
+	lastDesc := false
+	for i, k := range q.Sort {
+		field := q.spec.Fields[k.Field]
+		var v any
+		if vals != nil {
+			v = vals[i]
+		}
+		if field.Nullable {
+			isNull := 0
+			if vals != nil && v == nil {
+				isNull = 1
+			}
+			atoms = append(atoms, sortAtom{
+				expr:  "CASE WHEN " + field.Column + " IS NULL THEN 1 ELSE 0 END",
+				value: isNull,
+			})
+		}
+		atoms = append(atoms, sortAtom{expr: field.Column, desc: k.Desc, value: v})
+		lastDesc = k.Desc
+	}
+	var id any
+	if q.after != nil {
+		id = q.after.ID
+	}
+	return append(atoms, sortAtom{expr: q.spec.IDColumn, desc: lastDesc, value: id})
+}
+
+// keyset renders "row after cursor" for a mixed-direction ordering:
+//
+//	(a > va) OR (a = va AND b < vb) OR (a = va AND b = vb AND id > vid)
+//
+// A NULL cursor value only matches with IS NULL; nothing sorts after it within the term.
+func keyset(b *builder, atoms []sortAtom) string {
+	var ors []string
+	for i, a := range atoms {
+		if a.value == nil {
+			continue
+		}
+		var ands []string
+		for _, prev := range atoms[:i] {
+			if prev.value == nil {
+				ands = append(ands, prev.expr+" IS NULL")
+			} else {
+				ands = append(ands, prev.expr+" = "+b.bind(prev.value))
+			}
+		}
+		cmp := " > "
+		if a.desc {
+			cmp = " < "
+		}
+		ands = append(ands, a.expr+cmp+b.bind(a.value))
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	if len(ors) == 0 {
+		return "1 = 0"
+	}
+	return "(" + strings.Join(ors, " OR ") + ")"
+}
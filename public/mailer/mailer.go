@@ -0,0 +1,38 @@
+// Package mailer sends plain-text emails. Callers do not send directly: emails are
+// queued in the email outbox in the transaction that caused them and delivered by the
+// mail job, so a rolled back transaction sends nothing.
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"task_manager/public/config"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// New opens the mailer selected by cfg.MailDriver.
+func New(cfg config.Config) (Mailer, error) {
+	switch cfg.MailDriver {
+	case "log":
+		return NewLog(), nil
+	case "smtp":
+		return NewSMTP(SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %s", cfg.MailDriver)
+	}
+}
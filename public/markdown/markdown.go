@@ -0,0 +1,134 @@
+// Package markdown renders the small Markdown subset used in comments to HTML.
+//
+// The input is HTML-escaped before any formatting is applied and only a fixed set of
+// tags is ever emitted, so the output is safe to embed without further sanitizing.
+// Supported: paragraphs and line breaks, ATX headings, block quotes nested up to eight
+// levels, bullet and numbered lists, fenced code blocks, inline code, bold, italic and
+// links with http, https or mailto targets.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingRe  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bulletRe   = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	numberedRe = regexp.MustCompile(`^\s*\d{1,9}[.)]\s+(.*)$`)
+	quoteRe    = regexp.MustCompile(`^\s*>\s?(.*)$`)
+
+	codeSpanRe = regexp.MustCompile("`([^`]+)`")
+	linkRe     = regexp.MustCompile(`\[([^\]]+)\]\(([^()\s*]+)\)`)
+	boldRe     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	italicRe   = regexp.MustCompile(`\*([^*\s](?:[^*]*[^*\s])?)\*`)
+)
+
+// maxQuoteDepth caps the nesting of block quotes. Each level renders the quoted lines
+// again, so deeper markers are kept as text to bound the work per comment.
+const maxQuoteDepth = 8
+
+// Render converts src to HTML.
+func Render(src string) string {
+	return render(src, 0)
+}
+
+// render converts src, found at the given block quote depth, to HTML.
+func render(src string, depth int) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var out strings.Builder
+	var para []string
+
+	flush := func() {
+		if len(para) > 0 {
+			out.WriteString("<p>" + strings.Join(para, "<br>") + "</p>\n")
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, html.EscapeString(lines[i]))
+			}
+			out.WriteString("<pre><code>" + strings.Join(code, "\n") + "</code></pre>\n")
+
+		case headingRe.MatchString(trimmed):
+			flush()
+			m := headingRe.FindStringSubmatch(trimmed)
+			level := strconv.Itoa(len(m[1]))
+			out.WriteString("<h" + level + ">" + inline(m[2]) + "</h" + level + ">\n")
+
+		case depth < maxQuoteDepth && quoteRe.MatchString(line):
+			flush()
+			var quoted []string
+			for ; i < len(lines) && quoteRe.MatchString(lines[i]); i++ {
+				quoted = append(quoted, quoteRe.FindStringSubmatch(lines[i])[1])
+			}
+			i--
+			out.WriteString("<blockquote>\n" + render(strings.Join(quoted, "\n"), depth+1) + "</blockquote>\n")
+
+		case bulletRe.MatchString(line), numberedRe.MatchString(line):
+			flush()
+			re, tag := bulletRe, "ul"
+			if !bulletRe.MatchString(line) {
+				re, tag = numberedRe, "ol"
+			}
+			out.WriteString("<" + tag + ">\n")
+			for ; i < len(lines) && re.MatchString(lines[i]); i++ {
+				out.WriteString("<li>" + inline(re.FindStringSubmatch(lines[i])[1]) + "</li>\n")
+			}
+			i--
+			out.WriteString("</" + tag + ">\n")
+
+		default:
+			para = append(para, inline(trimmed))
+		}
+	}
+	flush()
+	return out.String()
+}
+
+// inline escapes s and applies the inline formatting. Code spans are left untouched.
+func inline(s string) string {
+	var out strings.Builder
+	last := 0
+	for _, m := range codeSpanRe.FindAllStringSubmatchIndex(s, -1) {
+		out.WriteString(format(html.EscapeString(s[last:m[0]])))
+		out.WriteString("<code>" + html.EscapeString(s[m[2]:m[3]]) + "</code>")
+		last = m[1]
+	}
+	out.WriteString(format(html.EscapeString(s[last:])))
+	return out.String()
+}
+
+// format applies links and emphasis to already escaped text.
+func format(s string) string {
+	s = linkRe.ReplaceAllStringFunc(s, func(m string) string {
+		parts := linkRe.FindStringSubmatch(m)
+		text, href := parts[1], parts[2]
+		if !safeURL(href) {
+			return text
+		}
+		return `<a href="` + href + `" rel="nofollow noopener noreferrer">` + text + `</a>`
+	})
+	s = boldRe.ReplaceAllString(s, "<strong>$1</strong>")
+	s = italicRe.ReplaceAllString(s, "<em>$1</em>")
+	return s
+}
+
+func safeURL(href string) bool {
+	lower := strings.ToLower(href)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "mailto:")
+}
@@ -0,0 +1,50 @@
+package markdown_test
+
+import (
+	"strings"
+	"task_manager/public/markdown"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"paragraphs", "one\ntwo\n\nthree", "<p>one<br>two</p>\n<p>three</p>\n"},
+		{"heading", "## Plan ##", "<h2>Plan</h2>\n"},
+		{"emphasis", "**bold** and *it*", "<p><strong>bold</strong> and <em>it</em></p>\n"},
+		{"bullet list", "- a\n- b", "<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n"},
+		{"numbered list", "1. a\n2) b", "<ol>\n<li>a</li>\n<li>b</li>\n</ol>\n"},
+		{"quote", "> said\n> twice", "<blockquote>\n<p>said<br>twice</p>\n</blockquote>\n"},
+		{"nested quote", "> > said", "<blockquote>\n<blockquote>\n<p>said</p>\n</blockquote>\n</blockquote>\n"},
+		{"code block", "```\n<b>x</b>\n```", "<pre><code>&lt;b&gt;x&lt;/b&gt;</code></pre>\n"},
+		{"code span", "run `**x**`", "<p>run <code>**x**</code></p>\n"},
+		{"link", "[docs](https://example.com/a?b=1&c=2)", `<p><a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener noreferrer">docs</a></p>` + "\n"},
+		{"unsafe link", "[x](javascript:alert(1))", "<p>[x](javascript:alert(1))</p>\n"},
+		{"unsafe scheme", "[x](data:text/html)", "<p>x</p>\n"},
+		{"raw html is escaped", `<script>alert("x")</script>`, "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</p>\n"},
+		{"attribute injection", `[x](https://a.b/"onmouseover=f)`, `<p><a href="https://a.b/&#34;onmouseover=f" rel="nofollow noopener noreferrer">x</a></p>` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, markdown.Render(tt.in))
+		})
+	}
+}
+
+func TestRender_DeepQuotes(t *testing.T) {
+	// The longest comment allowed, all quote markers, renders at most eight levels deep
+	// and keeps the rest as text.
+	in := strings.Repeat(">", 10000)
+	start := time.Now()
+	out := markdown.Render(in)
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, 8, strings.Count(out, "<blockquote>"))
+	require.Equal(t, 8, strings.Count(out, "</blockquote>"))
+	require.Contains(t, out, "<p>"+strings.Repeat("&gt;", 10000-8)+"</p>")
+}
@@ -0,0 +1,88 @@
+// Package rank generates lexicographic rank keys for manually ordered lists. A key
+// can always be generated between two others, so moving an item only rewrites the
+// key of that item.
+//
+// Keys are strings of base-62 digits ("0-9A-Za-z") that compare byte-wise; they never
+// end in the zero digit, which keeps room below every key. Databases must compare
+// them byte-wise too (COLLATE "C" on postgres).
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+var ErrInvalidKey = errors.New("invalid rank key")
+
+// Between returns a key that sorts after a and before b. An empty a means the start
+// of the list and an empty b its end, so Between("", "") is the first key of an empty
+// list. It fails when a or b is not a valid key or a does not sort before b.
+func Between(a string, b string) (string, error) {
+	if !valid(a) || !valid(b) || (a != "" && b != "" && a >= b) {
+		return "", ErrInvalidKey
+	}
+	return midpoint(a, b), nil
+}
+
+// Valid reports whether s is a key produced by this package.
+func Valid(s string) bool {
+	return s != "" && valid(s)
+}
+
+func valid(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(digits, s[i]) < 0 {
+			return false
+		}
+	}
+	return !strings.HasSuffix(s, digits[:1])
+}
+
+// midpoint returns a key strictly between a and b, where a < b, "" for a is the zero
+// key and "" for b is infinity.
+func midpoint(a string, b string) string {
+	if b != "" {
+		// Skip the common prefix, reading a as padded with zero digits.
+		n := 0
+		for n < len(b) && digitAt(a, n) == strings.IndexByte(digits, b[n]) {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+	lo := digitAt(a, 0)
+	hi := base
+	if b != "" {
+		hi = strings.IndexByte(digits, b[0])
+	}
+	if hi-lo > 1 {
+		return string(digits[(lo+hi)/2])
+	}
+	// The first digits are adjacent. A longer b has its first digit alone in between;
+	// otherwise keep the first digit of a and go one level deeper.
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[lo]) + midpoint(rest, "")
+}
+
+// digitAt returns the value of the digit of s at i, or 0 past its end.
+func digitAt(s string, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+	return strings.IndexByte(digits, s[i])
+}
@@ -0,0 +1,75 @@
+package rank_test
+
+import (
+	"math/rand"
+	"slices"
+	"task_manager/public/rank"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"empty list", "", "", "V"},
+		{"after", "V", "", "k"},
+		{"before", "", "V", "F"},
+		{"adjacent digits", "V", "W", "VV"},
+		{"longer upper bound", "V", "W1", "W"},
+		{"common prefix", "V1", "V3", "V2"},
+		{"lower bound shorter", "V", "V1", "V0V"},
+		{"top of the range", "z", "", "zV"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rank.Between(tt.a, tt.b)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.True(t, rank.Valid(got))
+		})
+	}
+}
+
+func TestBetween_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"equal", "V", "V"},
+		{"reversed", "W", "V"},
+		{"trailing zero", "V0", ""},
+		{"bad digit", "V-", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := rank.Between(tt.a, tt.b)
+			require.ErrorIs(t, err, rank.ErrInvalidKey)
+		})
+	}
+}
+
+// TestBetween_RandomInserts keeps inserting at random positions and checks the keys
+// stay strictly ordered.
+func TestBetween_RandomInserts(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	keys := []string{}
+	for range 2000 {
+		i := rng.Intn(len(keys) + 1)
+		a, b := "", ""
+		if i > 0 {
+			a = keys[i-1]
+		}
+		if i < len(keys) {
+			b = keys[i]
+		}
+		k, err := rank.Between(a, b)
+		require.NoError(t, err)
+		keys = slices.Insert(keys, i, k)
+	}
+	require.True(t, slices.IsSorted(keys))
+	require.Len(t, slices.Compact(slices.Clone(keys)), len(keys))
+}
@@ -0,0 +1,168 @@
+// Package recurrence creates the instances of recurring tasks. It is shared by the task
+// handlers, which advance on_complete series when a task is completed, and the
+// scheduler job, which advances on_schedule series.
+package recurrence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/rrule"
+	"time"
+
+	// Embedded so time zones resolve on hosts without a zoneinfo database.
+	_ "time/tzdata"
+
+	"github.com/google/uuid"
+)
+
+// ErrMoved is returned by Advance when the series no longer is at the task it was
+// loaded with, because another request or replica advanced it first.
+var ErrMoved = errors.New("recurrence already advanced")
+
+// Schedule is a parsed rule with its time zone.
+type Schedule struct {
+	Rule     *rrule.Rule
+	Location *time.Location
+}
+
+// ParseSchedule validates an RRULE and an IANA time zone name.
+func ParseSchedule(rule string, timezone string) (Schedule, error) {
+	r, err := rrule.Parse(rule)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid rule: %w", err)
+	}
+	if timezone == "" {
+		return Schedule{}, errors.New("timezone is required")
+	}
+	loc, err := time.LoadLocation(timezone)
+	// "Local" would depend on the server.
+	if err != nil || timezone == "Local" {
+		return Schedule{}, fmt.Errorf("unknown timezone %q", timezone)
+	}
+	return Schedule{Rule: r, Location: loc}, nil
+}
+
+// First returns the first occurrence at or after start.
+func (s Schedule) First(start time.Time) (time.Time, bool) {
+	start = start.In(s.Location)
+	return s.Rule.Next(start, start.Add(-time.Nanosecond))
+}
+
+// Next returns the first occurrence of the series starting at start that is after after.
+func (s Schedule) Next(start time.Time, after time.Time) (time.Time, bool) {
+	return s.Rule.Next(start.In(s.Location), after)
+}
+
+// Upcoming returns up to n occurrences of rec after its current one.
+func Upcoming(rec *models.Recurrence, n int) []time.Time {
+	s, err := ParseSchedule(rec.Rule, rec.Timezone)
+	if err != nil {
+		return nil
+	}
+	return s.Rule.Occurrences(rec.StartsAt.In(s.Location), rec.OccurrenceAt, n)
+}
+
+// Advance creates the next instance of the series in tx and moves the series to it.
+// The instance copies the title, description, assignees, labels, custom field values
+// and checklist (unchecked) of the current instance, starts in the initial workflow
+// state and is due at the next occurrence after both the current one and now, so
+// occurrences missed while nothing ran are skipped.
+//
+// It returns nil, nil when the series has ended; the recurrence is then deleted. When
+// the series has moved on since rec was loaded, it returns ErrMoved.
+func Advance(ctx context.Context, tx repositories.Transaction, rec *models.Recurrence, now time.Time) (*models.Task, error) {
+	if err := tx.Recurrences().LockRecurrence(ctx, rec.ID, rec.TaskID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrMoved
+		}
+		return nil, err
+	}
+	s, err := ParseSchedule(rec.Rule, rec.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	after := rec.OccurrenceAt
+	if now.After(after) {
+		after = now
+	}
+	next, ok := s.Next(rec.StartsAt, after)
+	if !ok {
+		return nil, tx.Recurrences().DeleteTaskRecurrence(ctx, rec.TaskID)
+	}
+
+	current, err := tx.Tasks().GetTaskByID(ctx, rec.TeamID, rec.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	w, err := tx.Workflows().GetWorkflow(ctx, rec.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	task := &models.Task{
+		ID:          uuid.New(),
+		TeamID:      current.TeamID,
+		Title:       current.Title,
+		Description: current.Description,
+		Grouped:     current.Grouped,
+		ParentID:    current.ParentID,
+		ProjectID:   current.ProjectID,
+		CreatedBy:   current.CreatedBy,
+		DueAt:       &next,
+		Estimate:    current.Estimate,
+		AssigneeIDs: current.AssigneeIDs,
+		LabelIDs:    current.LabelIDs,
+	}
+	if initial := w.InitialState(); initial != nil {
+		task.StateID = &initial.ID
+	}
+	if err := tx.Tasks().CreateTask(ctx, task); err != nil {
+		return nil, err
+	}
+	if err := tx.Tasks().AddTaskAssignees(ctx, task.ID, task.AssigneeIDs, nil); err != nil {
+		return nil, err
+	}
+	if err := tx.Labels().AddTaskLabels(ctx, task.ID, task.LabelIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.CustomFields().CopyTaskFieldValues(ctx, current.ID, task.ID); err != nil {
+		return nil, err
+	}
+	task.CustomFields = current.CustomFields
+	// The server creates the instance, so the activity has no actor.
+	err = tx.Activity().CreateTaskActivity(ctx, &models.TaskActivity{
+		ID:        uuid.New(),
+		TeamID:    task.TeamID,
+		TaskID:    task.ID,
+		Action:    models.ActivityTaskCreated,
+		Changes:   models.TaskCreationChanges(task),
+		Details:   map[string]any{"recurrence_of": current.ID},
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	items, err := tx.Checklists().ListChecklistItems(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		copied := &models.ChecklistItem{ID: uuid.New(), TaskID: task.ID, Content: item.Content}
+		if err := tx.Checklists().CreateChecklistItem(ctx, copied); err != nil {
+			return nil, err
+		}
+	}
+
+	rec.TaskID = task.ID
+	rec.OccurrenceAt = next
+	rec.NextRunAt = nil
+	if rec.Mode == models.RecurOnSchedule {
+		rec.NextRunAt = &next
+	}
+	if err := tx.Recurrences().MoveRecurrence(ctx, rec); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
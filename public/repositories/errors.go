@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"task_manager/public/repositories/repoerr"
+	"task_manager/public/repositories/sqlite"
+
+	postgres "task_manager/public/repositories/postgres"
+)
+
+// Repository errors. Drivers wrap the underlying database error, so check with errors.Is.
+var (
+	ErrNotFound      = repoerr.ErrNotFound
+	ErrConflict      = repoerr.ErrConflict
+	ErrForeignKey    = repoerr.ErrForeignKey
+	ErrSerialization = repoerr.ErrSerialization
+)
+
+// translateError maps a raw driver error (e.g. from COMMIT) onto the repository errors.
+func translateError(driver string, err error) error {
+	switch driver {
+	case "sqlite":
+		return sqlite.TranslateError(err)
+	case "postgres":
+		return postgres.TranslateError(err)
+	default:
+		return err
+	}
+}
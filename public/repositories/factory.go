@@ -29,3 +29,234 @@ func NewTeamRepositoryWithDBTX(driver string, db dbx.DBTX) (TeamRepository, erro
 		return nil, fmt.Errorf("unsupported db driver: %s", driver)
 	}
 }
+
+func NewAuditRepositoryWithDBTX(driver string, db dbx.DBTX) (AuditRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewAuditRepository(db), nil
+	case "postgres":
+		return postgres.NewAuditRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewTaskRepositoryWithDBTX(driver string, db dbx.DBTX) (TaskRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewTaskRepository(db), nil
+	case "postgres":
+		return postgres.NewTaskRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewWorkflowRepositoryWithDBTX(driver string, db dbx.DBTX) (WorkflowRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewWorkflowRepository(db), nil
+	case "postgres":
+		return postgres.NewWorkflowRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewChecklistRepositoryWithDBTX(driver string, db dbx.DBTX) (ChecklistRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewChecklistRepository(db), nil
+	case "postgres":
+		return postgres.NewChecklistRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewTaskLinkRepositoryWithDBTX(driver string, db dbx.DBTX) (TaskLinkRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewTaskLinkRepository(db), nil
+	case "postgres":
+		return postgres.NewTaskLinkRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewCommentRepositoryWithDBTX(driver string, db dbx.DBTX) (CommentRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewCommentRepository(db), nil
+	case "postgres":
+		return postgres.NewCommentRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewNotificationRepositoryWithDBTX(driver string, db dbx.DBTX) (NotificationRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewNotificationRepository(db), nil
+	case "postgres":
+		return postgres.NewNotificationRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewAttachmentRepositoryWithDBTX(driver string, db dbx.DBTX) (AttachmentRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewAttachmentRepository(db), nil
+	case "postgres":
+		return postgres.NewAttachmentRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewLabelRepositoryWithDBTX(driver string, db dbx.DBTX) (LabelRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewLabelRepository(db), nil
+	case "postgres":
+		return postgres.NewLabelRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewCustomFieldRepositoryWithDBTX(driver string, db dbx.DBTX) (CustomFieldRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewCustomFieldRepository(db), nil
+	case "postgres":
+		return postgres.NewCustomFieldRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewRecurrenceRepositoryWithDBTX(driver string, db dbx.DBTX) (RecurrenceRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewRecurrenceRepository(db), nil
+	case "postgres":
+		return postgres.NewRecurrenceRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewReminderRepositoryWithDBTX(driver string, db dbx.DBTX) (ReminderRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewReminderRepository(db), nil
+	case "postgres":
+		return postgres.NewReminderRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewOutboxRepositoryWithDBTX(driver string, db dbx.DBTX) (OutboxRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewOutboxRepository(db), nil
+	case "postgres":
+		return postgres.NewOutboxRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewBoardRepositoryWithDBTX(driver string, db dbx.DBTX) (BoardRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewBoardRepository(db), nil
+	case "postgres":
+		return postgres.NewBoardRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewProjectRepositoryWithDBTX(driver string, db dbx.DBTX) (ProjectRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewProjectRepository(db), nil
+	case "postgres":
+		return postgres.NewProjectRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewSprintRepositoryWithDBTX(driver string, db dbx.DBTX) (SprintRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewSprintRepository(db), nil
+	case "postgres":
+		return postgres.NewSprintRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewMilestoneRepositoryWithDBTX(driver string, db dbx.DBTX) (MilestoneRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewMilestoneRepository(db), nil
+	case "postgres":
+		return postgres.NewMilestoneRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewTimeEntryRepositoryWithDBTX(driver string, db dbx.DBTX) (TimeEntryRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewTimeEntryRepository(db), nil
+	case "postgres":
+		return postgres.NewTimeEntryRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewEstimationRepositoryWithDBTX(driver string, db dbx.DBTX) (EstimationRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewEstimationRepository(db), nil
+	case "postgres":
+		return postgres.NewEstimationRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewActivityRepositoryWithDBTX(driver string, db dbx.DBTX) (ActivityRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewActivityRepository(db), nil
+	case "postgres":
+		return postgres.NewActivityRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
+
+func NewTrashRepositoryWithDBTX(driver string, db dbx.DBTX) (TrashRepository, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.NewTrashRepository(db), nil
+	case "postgres":
+		return postgres.NewTrashRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unsupported db driver: %s", driver)
+	}
+}
@@ -2,11 +2,21 @@ package repositories
 
 import (
 	"context"
+	"task_manager/public/listquery"
 	"task_manager/public/repositories/models"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// Repository methods report failures with the errors in errors.go: lookups of a single
+// row return ErrNotFound instead of a nil result, and updates/deletes by id return
+// ErrNotFound when no row matched. List methods return an empty result, not ErrNotFound.
+//
+// Teams, projects and tasks in the trash, and everything in a team in the trash, are
+// left out of lookups, lists and aggregates unless a method says otherwise.
+//
+// Paginated list methods take a listquery.Query parsed with the matching listspec.
 type UserRepository interface {
 	CreateUser(ctx context.Context, u *models.User) error
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
@@ -21,22 +31,375 @@ type UserRepository interface {
 	GetAuthProviderByUserAndProvider(ctx context.Context, userID uuid.UUID, provider models.Provider) (*models.AuthProvider, error)
 	ListAuthProvidersByUserID(ctx context.Context, userID uuid.UUID) ([]models.AuthProvider, error)
 	CreateAuthProvider(ctx context.Context, ap *models.AuthProvider) error
+
+	// Administration
+	ListUsers(ctx context.Context, filter models.UserListFilter) ([]*models.User, int, error)
+	SetUserDisabled(ctx context.Context, userID uuid.UUID, disabled bool) error
+	UpdateUserType(ctx context.Context, userID uuid.UUID, userType models.UserType) error
+	SetPasswordResetRequired(ctx context.Context, userID uuid.UUID, required bool) error
+	DeleteUser(ctx context.Context, userID uuid.UUID) error
+
+	// Password reset tokens
+	CreatePasswordResetToken(ctx context.Context, t *models.PasswordResetToken) error
+	GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
+	DeletePasswordResetTokensByUserID(ctx context.Context, userID uuid.UUID) error
 }
 
 type TeamRepository interface {
 	CreateTeam(ctx context.Context, team *models.Team) error
 	GetTeamByID(ctx context.Context, teamID uuid.UUID) (*models.Team, error)
-	GetTeamsMembers(ctx context.Context, teamID uuid.UUID) ([]*models.UserTeam, error)
+	// LockTeam returns the team and keeps it from being archived or deleted until the
+	// transaction ends, so a change can rely on the team state it checked.
+	LockTeam(ctx context.Context, teamID uuid.UUID) (*models.Team, error)
+	GetTeamsMembers(ctx context.Context, teamID uuid.UUID, q listquery.Query) (listquery.Result[*models.UserTeam], error)
+	// GetTeamFounderByTeamID also finds the founder of a team in the trash.
 	GetTeamFounderByTeamID(ctx context.Context, teamID uuid.UUID) (*models.UserTeam, error)
 	GetMemberRole(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (*models.TeamUserRole, error)
 	CreateTeamUser(ctx context.Context, userTeam *models.UserTeam) error
 	DeleteTeamUser(ctx context.Context, userTeamID *uuid.UUID) error
 	EditTeamName(ctx context.Context, team *models.Team) error
+	// SetTeamArchived archives the team at archivedAt, or unarchives it when nil.
+	SetTeamArchived(ctx context.Context, teamID uuid.UUID, archivedAt *time.Time) error
+	// DeleteTeam moves the team to the trash; everything in it is hidden with it.
 	DeleteTeam(ctx context.Context, teamID uuid.UUID) error
+	// RestoreTeam brings the team back from the trash.
+	RestoreTeam(ctx context.Context, teamID uuid.UUID) error
+	// ListDeletedTeams returns the teams in the trash that the user founded, most
+	// recently deleted first.
+	ListDeletedTeams(ctx context.Context, userID uuid.UUID) ([]*models.TrashItem, error)
 	RemoveTeamUser(ctx context.Context, userID uuid.UUID) error
-	GetTeamsByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Team, error)
+	// GetTeamsByUserID lists the teams of the user, leaving archived teams out unless
+	// includeArchived is set.
+	GetTeamsByUserID(ctx context.Context, userID uuid.UUID, includeArchived bool, q listquery.Query) (listquery.Result[*models.Team], error)
 	CreateTeamInvitation(ctx context.Context, invitation *models.Invitation) error
 	UpdateTeamInvitationStatus(ctx context.Context, invitationID uuid.UUID, accept bool) error
-	GetUserInvitations(ctx context.Context, userID uuid.UUID) ([]*models.Invitation, error)
+	GetUserInvitations(ctx context.Context, userID uuid.UUID, q listquery.Query) (listquery.Result[*models.Invitation], error)
 	DeleteUserInvitation(ctx context.Context, invitationID uuid.UUID) error
 }
+
+type TaskRepository interface {
+	CreateTask(ctx context.Context, t *models.Task) error
+	// GetTaskByID only finds tasks of the given team.
+	GetTaskByID(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID) (*models.Task, error)
+	// UpdateTask saves the editable fields; the state is changed with UpdateTaskState.
+	UpdateTask(ctx context.Context, t *models.Task) error
+	UpdateTaskState(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID, stateID uuid.UUID) error
+	// DeleteTask moves the task and its subtasks to the trash.
+	DeleteTask(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID) error
+	// RestoreTask brings back the task and the subtasks deleted with it. Only tasks that
+	// were deleted themselves can be restored; it returns ErrConflict while the parent or
+	// the project of the task is in the trash.
+	RestoreTask(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID) error
+	ListTeamTasks(ctx context.Context, teamID uuid.UUID, q listquery.Query) (listquery.Result[*models.Task], error)
+	// ListUserTasks lists the tasks assigned to the user plus the grouped tasks of the user's teams.
+	ListUserTasks(ctx context.Context, userID uuid.UUID, q listquery.Query) (listquery.Result[*models.Task], error)
+
+	// Subtasks
+	SetTaskParent(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID, parentID *uuid.UUID) error
+	ListSubtasks(ctx context.Context, teamID uuid.UUID, parentID uuid.UUID) ([]*models.Task, error)
+	GetTaskAncestorIDs(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID) ([]uuid.UUID, error)
+	GetTaskSubtree(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID) ([]models.TaskNode, error)
+
+	// Assignees. assignedBy is nil for assignments made by the server.
+	AddTaskAssignees(ctx context.Context, taskID uuid.UUID, userIDs []uuid.UUID, assignedBy *uuid.UUID) error
+	RemoveTaskAssignees(ctx context.Context, taskID uuid.UUID, userIDs []uuid.UUID) error
+}
+
+type WorkflowRepository interface {
+	// GetWorkflow returns the states of the team in order and its transitions.
+	GetWorkflow(ctx context.Context, teamID uuid.UUID) (*models.Workflow, error)
+	// SaveWorkflow replaces the workflow of the team. States are matched by id; states that
+	// are left out are deleted, which fails with ErrForeignKey while tasks still use them.
+	// Run it inside a transaction.
+	SaveWorkflow(ctx context.Context, teamID uuid.UUID, w *models.Workflow) error
+}
+
+type ChecklistRepository interface {
+	ListChecklistItems(ctx context.Context, taskID uuid.UUID) ([]*models.ChecklistItem, error)
+	GetChecklistItem(ctx context.Context, taskID uuid.UUID, itemID uuid.UUID) (*models.ChecklistItem, error)
+	// CreateChecklistItem appends the item to the end of the checklist.
+	CreateChecklistItem(ctx context.Context, item *models.ChecklistItem) error
+	UpdateChecklistItem(ctx context.Context, item *models.ChecklistItem) error
+	DeleteChecklistItem(ctx context.Context, taskID uuid.UUID, itemID uuid.UUID) error
+	// ReorderChecklistItems sets the positions to the order of itemIDs.
+	ReorderChecklistItems(ctx context.Context, taskID uuid.UUID, itemIDs []uuid.UUID) error
+}
+
+type TaskLinkRepository interface {
+	// CreateTaskLink fails with ErrConflict when the same link already exists.
+	CreateTaskLink(ctx context.Context, l *models.TaskLink) error
+	GetTaskLink(ctx context.Context, teamID uuid.UUID, linkID uuid.UUID) (*models.TaskLink, error)
+	DeleteTaskLink(ctx context.Context, teamID uuid.UUID, linkID uuid.UUID) error
+	// ListTaskLinks lists the links from and to the task.
+	ListTaskLinks(ctx context.Context, taskID uuid.UUID) ([]*models.TaskLink, error)
+	// HasBlockingPath reports whether fromTaskID transitively blocks toTaskID. Links of
+	// tasks in the trash count too, so restoring a task never closes a cycle.
+	HasBlockingPath(ctx context.Context, fromTaskID uuid.UUID, toTaskID uuid.UUID) (bool, error)
+	// ListOpenBlockers returns the tasks that directly block the task and are not in a done state.
+	ListOpenBlockers(ctx context.Context, taskID uuid.UUID) ([]uuid.UUID, error)
+	// GetDependencyGraph returns the links of the team and the tasks they connect; the
+	// critical path is left for the caller to compute.
+	GetDependencyGraph(ctx context.Context, teamID uuid.UUID) (*models.DependencyGraph, error)
+}
+
+type CommentRepository interface {
+	// CreateComment also records the mentions of the comment.
+	CreateComment(ctx context.Context, c *models.Comment) error
+	// GetComment finds deleted comments too.
+	GetComment(ctx context.Context, taskID uuid.UUID, commentID uuid.UUID) (*models.Comment, error)
+	// UpdateComment saves the body and mentions of c and keeps the previous body as a
+	// revision. Deleted comments are not found.
+	UpdateComment(ctx context.Context, c *models.Comment, editedBy uuid.UUID) error
+	SoftDeleteComment(ctx context.Context, taskID uuid.UUID, commentID uuid.UUID) error
+	// RestoreComment undoes SoftDeleteComment until the comment is purged.
+	RestoreComment(ctx context.Context, taskID uuid.UUID, commentID uuid.UUID) error
+	// ListTaskComments lists the comments of the task, deleted ones included.
+	ListTaskComments(ctx context.Context, taskID uuid.UUID, q listquery.Query) (listquery.Result[*models.Comment], error)
+	ListCommentRevisions(ctx context.Context, commentID uuid.UUID) ([]*models.CommentRevision, error)
+}
+
+type NotificationRepository interface {
+	CreateNotifications(ctx context.Context, notifications []*models.Notification) error
+	ListUserNotifications(ctx context.Context, userID uuid.UUID, q listquery.Query) (listquery.Result[*models.Notification], error)
+	MarkNotificationRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) error
+}
+
+type LabelRepository interface {
+	// ListLabels returns the labels of the team ordered by name.
+	ListLabels(ctx context.Context, teamID uuid.UUID) ([]*models.Label, error)
+	GetLabel(ctx context.Context, teamID uuid.UUID, labelID uuid.UUID) (*models.Label, error)
+	// CreateLabel fails with ErrConflict when the team already has a label of that name.
+	CreateLabel(ctx context.Context, l *models.Label) error
+	// UpdateLabel saves the label and bumps updated_at of every task carrying it.
+	UpdateLabel(ctx context.Context, l *models.Label) error
+	DeleteLabel(ctx context.Context, teamID uuid.UUID, labelID uuid.UUID) error
+	// MergeLabels moves the tasks of sourceID to targetID and deletes sourceID. Run it
+	// inside a transaction.
+	MergeLabels(ctx context.Context, teamID uuid.UUID, sourceID uuid.UUID, targetID uuid.UUID) error
+	AddTaskLabels(ctx context.Context, taskID uuid.UUID, labelIDs []uuid.UUID) error
+	RemoveTaskLabels(ctx context.Context, taskID uuid.UUID, labelIDs []uuid.UUID) error
+}
+
+type CustomFieldRepository interface {
+	// ListCustomFields returns the fields of the team ordered by position.
+	ListCustomFields(ctx context.Context, teamID uuid.UUID) ([]*models.CustomField, error)
+	GetCustomField(ctx context.Context, teamID uuid.UUID, fieldID uuid.UUID) (*models.CustomField, error)
+	// CreateCustomField fails with ErrConflict when the team already has a field with that key.
+	CreateCustomField(ctx context.Context, f *models.CustomField) error
+	// UpdateCustomField saves the field, drops the task values that are no longer among
+	// its options and bumps updated_at of every task holding a value. Run it inside a
+	// transaction.
+	UpdateCustomField(ctx context.Context, f *models.CustomField) error
+	DeleteCustomField(ctx context.Context, teamID uuid.UUID, fieldID uuid.UUID) error
+	// SetTaskFieldValues replaces the values of the given fields on the task; nil values
+	// clear the field. Values must have been normalized with CustomField.Normalize.
+	SetTaskFieldValues(ctx context.Context, taskID uuid.UUID, values []models.CustomFieldValue) error
+	// CopyTaskFieldValues copies every custom field value of one task to another.
+	CopyTaskFieldValues(ctx context.Context, fromTaskID uuid.UUID, toTaskID uuid.UUID) error
+}
+
+type RecurrenceRepository interface {
+	GetTaskRecurrence(ctx context.Context, taskID uuid.UUID) (*models.Recurrence, error)
+	// SetTaskRecurrence creates the recurrence of rec.TaskID or replaces its schedule.
+	SetTaskRecurrence(ctx context.Context, rec *models.Recurrence) error
+	DeleteTaskRecurrence(ctx context.Context, taskID uuid.UUID) error
+	// ListDueRecurrences returns up to limit series whose next_run_at is not after now,
+	// earliest first. Series of archived teams wait until the team is unarchived.
+	ListDueRecurrences(ctx context.Context, now time.Time, limit int) ([]*models.Recurrence, error)
+	// LockRecurrence fails with ErrNotFound unless the series is still at taskID. On
+	// postgres the row stays locked until the transaction ends, so concurrent advances
+	// of one series are serialized and the later ones see the series has moved on.
+	LockRecurrence(ctx context.Context, id uuid.UUID, taskID uuid.UUID) error
+	// MoveRecurrence saves the task, occurrence and next run of the series.
+	MoveRecurrence(ctx context.Context, rec *models.Recurrence) error
+}
+
+type ReminderRepository interface {
+	// GetUserPreferences returns DefaultUserPreferences for users who never saved theirs.
+	GetUserPreferences(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error)
+	SetUserPreferences(ctx context.Context, p *models.UserPreferences) error
+	// ListTaskReminders returns the minutes before the due date in the order they fire.
+	ListTaskReminders(ctx context.Context, taskID uuid.UUID) ([]int, error)
+	// SetTaskReminders replaces the reminders of the task.
+	SetTaskReminders(ctx context.Context, taskID uuid.UUID, minutesBefore []int) error
+	// ListDueTasks returns the tasks that are not done and due after from and not after
+	// to, earliest first. Tasks of deleted or archived teams are left out.
+	ListDueTasks(ctx context.Context, from time.Time, to time.Time) ([]*models.DueTask, error)
+	// RecordDelivery returns false when the delivery was already recorded, by this or by
+	// another process.
+	RecordDelivery(ctx context.Context, d models.ReminderDelivery) (bool, error)
+}
+
+type OutboxRepository interface {
+	EnqueueEmail(ctx context.Context, e *models.OutboxEmail) error
+	// ClaimEmails locks up to limit unsent emails with fewer than maxAttempts attempts
+	// until lockedUntil and counts an attempt for each. Emails locked by another sender
+	// are skipped.
+	ClaimEmails(ctx context.Context, now time.Time, lockedUntil time.Time, maxAttempts int, limit int) ([]*models.OutboxEmail, error)
+	MarkEmailSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error
+	// MarkEmailFailed records the error; the email is retried once its lock expires.
+	MarkEmailFailed(ctx context.Context, id uuid.UUID, lastError string) error
+}
+
+type ProjectRepository interface {
+	// ListProjects returns the projects of the team with their members and progress,
+	// ordered by name. Archived projects are left out unless includeArchived is set.
+	ListProjects(ctx context.Context, teamID uuid.UUID, includeArchived bool) ([]*models.Project, error)
+	GetProject(ctx context.Context, teamID uuid.UUID, projectID uuid.UUID) (*models.Project, error)
+	// CreateProject inserts the project and its members.
+	CreateProject(ctx context.Context, p *models.Project) error
+	// UpdateProject saves every field of the project but its members.
+	UpdateProject(ctx context.Context, p *models.Project) error
+	// SetProjectMembers replaces the members of the project.
+	SetProjectMembers(ctx context.Context, projectID uuid.UUID, userIDs []uuid.UUID) error
+	// DeleteProject moves the project to the trash with its tasks and their subtasks,
+	// and returns the ids of those tasks. Projects in the trash are not found or listed.
+	DeleteProject(ctx context.Context, teamID uuid.UUID, projectID uuid.UUID) ([]uuid.UUID, error)
+	// RestoreProject brings back the project and the tasks deleted with it, and returns
+	// the ids of those tasks.
+	RestoreProject(ctx context.Context, teamID uuid.UUID, projectID uuid.UUID) ([]uuid.UUID, error)
+	// MoveProjectTasks moves the listed tasks of project fromID, or all of them when
+	// taskIDs is empty, into project toID, or out of any project when toID is nil. It
+	// returns the ids of the tasks moved; listed tasks outside fromID are skipped.
+	MoveProjectTasks(ctx context.Context, teamID uuid.UUID, fromID uuid.UUID, toID *uuid.UUID, taskIDs []uuid.UUID) ([]uuid.UUID, error)
+}
+
+type SprintRepository interface {
+	// ListSprints returns the sprints of the team with their scope, ordered by start date.
+	// An empty status lists sprints of every status.
+	ListSprints(ctx context.Context, teamID uuid.UUID, status models.SprintStatus) ([]*models.Sprint, error)
+	GetSprint(ctx context.Context, teamID uuid.UUID, sprintID uuid.UUID) (*models.Sprint, error)
+	CreateSprint(ctx context.Context, sp *models.Sprint) error
+	// UpdateSprint saves every field of the sprint, provided its stored status is still
+	// status; otherwise it returns ErrNotFound. Starting the second active sprint of a
+	// team returns ErrConflict.
+	UpdateSprint(ctx context.Context, sp *models.Sprint, status models.SprintStatus) error
+	// DeleteSprint deletes the sprint; its tasks go back to the backlog. It returns the
+	// ids of those tasks.
+	DeleteSprint(ctx context.Context, teamID uuid.UUID, sprintID uuid.UUID) ([]uuid.UUID, error)
+	// CarryOverTasks moves the tasks of sprint fromID that are not in a done-category
+	// state into sprint toID, or to the backlog when toID is nil, and returns the ids of
+	// the tasks moved.
+	CarryOverTasks(ctx context.Context, teamID uuid.UUID, fromID uuid.UUID, toID *uuid.UUID) ([]uuid.UUID, error)
+}
+
+type MilestoneRepository interface {
+	// ListMilestones returns the milestones of the team with their progress, ordered by
+	// due date. projectID limits the list to the milestones of a project. Completed
+	// milestones are left out unless includeCompleted is set.
+	ListMilestones(ctx context.Context, teamID uuid.UUID, projectID *uuid.UUID, includeCompleted bool) ([]*models.Milestone, error)
+	GetMilestone(ctx context.Context, teamID uuid.UUID, milestoneID uuid.UUID) (*models.Milestone, error)
+	CreateMilestone(ctx context.Context, m *models.Milestone) error
+	UpdateMilestone(ctx context.Context, m *models.Milestone) error
+	// DeleteMilestone deletes the milestone; its tasks are kept without a milestone. It
+	// returns the ids of those tasks.
+	DeleteMilestone(ctx context.Context, teamID uuid.UUID, milestoneID uuid.UUID) ([]uuid.UUID, error)
+}
+
+type TimeEntryRepository interface {
+	// LockUserTimeEntries serializes changes to the time entries of the user until the
+	// transaction ends, so overlap checks and timer starts do not race.
+	LockUserTimeEntries(ctx context.Context, userID uuid.UUID) error
+	// GetRunningTimer returns the entry of the user without an end, across teams.
+	GetRunningTimer(ctx context.Context, userID uuid.UUID) (*models.TimeEntry, error)
+	GetTimeEntry(ctx context.Context, teamID uuid.UUID, entryID uuid.UUID) (*models.TimeEntry, error)
+	// FindOverlappingEntry returns the earliest entry of the user, in any team, that
+	// overlaps [start, end); running timers reach to the end of time. excludeID leaves
+	// out the entry being edited.
+	FindOverlappingEntry(ctx context.Context, userID uuid.UUID, start time.Time, end time.Time, excludeID *uuid.UUID) (*models.TimeEntry, error)
+	// ListTaskTimeEntries returns the entries of the task, including running timers,
+	// ordered by start.
+	ListTaskTimeEntries(ctx context.Context, taskID uuid.UUID) ([]*models.TimeEntry, error)
+	// CreateTimeEntry returns ErrConflict when it starts the second running timer of a user.
+	CreateTimeEntry(ctx context.Context, e *models.TimeEntry) error
+	UpdateTimeEntry(ctx context.Context, e *models.TimeEntry) error
+	DeleteTimeEntry(ctx context.Context, teamID uuid.UUID, entryID uuid.UUID) error
+	// ListTimesheet returns the completed entries of the team selected by filter,
+	// ordered by start.
+	ListTimesheet(ctx context.Context, teamID uuid.UUID, filter models.TimesheetFilter) ([]*models.TimesheetEntry, error)
+}
+
+type EstimationRepository interface {
+	// GetEstimation returns the estimation settings of the team with the capacity of
+	// every member, ordered by name.
+	GetEstimation(ctx context.Context, teamID uuid.UUID) (*models.Estimation, error)
+	// UpdateEstimation saves the unit and the weekly capacity of the team; est.Members
+	// is ignored.
+	UpdateEstimation(ctx context.Context, teamID uuid.UUID, est *models.Estimation) error
+	// SetMemberCapacity sets or, with nil, clears the weekly capacity of a member.
+	SetMemberCapacity(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, capacity *float64) error
+	// ListWorkloadTasks returns the assigned tasks of the team that are not in a
+	// done-category state.
+	ListWorkloadTasks(ctx context.Context, teamID uuid.UUID) ([]*models.WorkloadTask, error)
+}
+
+type ActivityRepository interface {
+	CreateTaskActivity(ctx context.Context, a *models.TaskActivity) error
+	// ListTaskActivity lists the activity of the task, newest first by default.
+	ListTaskActivity(ctx context.Context, taskID uuid.UUID, q listquery.Query) (listquery.Result[*models.TaskActivity], error)
+}
+
+type BoardRepository interface {
+	// ListBoards returns the boards of the team with their columns, ordered by name.
+	ListBoards(ctx context.Context, teamID uuid.UUID) ([]*models.Board, error)
+	GetBoard(ctx context.Context, teamID uuid.UUID, boardID uuid.UUID) (*models.Board, error)
+	// CreateBoard inserts the board and its columns.
+	CreateBoard(ctx context.Context, b *models.Board) error
+	// UpdateBoard saves the name and swimlanes of the board.
+	UpdateBoard(ctx context.Context, b *models.Board) error
+	// SetBoardColumns replaces the columns of the board, positioned in slice order.
+	SetBoardColumns(ctx context.Context, boardID uuid.UUID, columns []models.BoardColumn) error
+	DeleteBoard(ctx context.Context, teamID uuid.UUID, boardID uuid.UUID) error
+	// LockBoard serializes moves on the board until the transaction ends, so concurrent
+	// moves never hand out the same rank.
+	LockBoard(ctx context.Context, teamID uuid.UUID, boardID uuid.UUID) error
+	// ListColumnCards returns the ranks of all tasks in the state, in card order: ranked
+	// cards first, then the unranked ones oldest first.
+	ListColumnCards(ctx context.Context, b *models.Board, stateID uuid.UUID) ([]models.BoardCard, error)
+	// ListColumnTasks returns up to limit tasks of the state in card order, and the
+	// number of tasks in the state.
+	ListColumnTasks(ctx context.Context, b *models.Board, stateID uuid.UUID, limit int) ([]*models.Task, int, error)
+	CountStateTasks(ctx context.Context, teamID uuid.UUID, stateID uuid.UUID) (int, error)
+	// SetCardRank sets the rank of the task on the board.
+	SetCardRank(ctx context.Context, boardID uuid.UUID, taskID uuid.UUID, rank string) error
+}
+
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, a *models.Attachment) error
+	GetAttachment(ctx context.Context, taskID uuid.UUID, attachmentID uuid.UUID) (*models.Attachment, error)
+	ListTaskAttachments(ctx context.Context, taskID uuid.UUID) ([]*models.Attachment, error)
+	// DeleteAttachment removes the row; the blob is queued in blob_deletions.
+	DeleteAttachment(ctx context.Context, taskID uuid.UUID, attachmentID uuid.UUID) error
+	// LockTeamStorage serializes uploads to the team until the transaction ends, so
+	// concurrent uploads cannot both pass the storage quota check.
+	LockTeamStorage(ctx context.Context, teamID uuid.UUID) error
+	// TeamStorageUsed is the total size of the attachments of the team in bytes.
+	TeamStorageUsed(ctx context.Context, teamID uuid.UUID) (int64, error)
+	// ListBlobDeletions returns up to limit queued blob deletions, oldest first.
+	ListBlobDeletions(ctx context.Context, limit int) ([]*models.BlobDeletion, error)
+	DeleteBlobDeletion(ctx context.Context, id int64) error
+}
+
+type TrashRepository interface {
+	// ListTrash lists the deleted projects, tasks and comments of the team, most
+	// recently deleted first by default.
+	ListTrash(ctx context.Context, teamID uuid.UUID, q listquery.Query) (listquery.Result[*models.TrashItem], error)
+	// GetTrashItem finds a project, task or comment of the team that ListTrash lists.
+	GetTrashItem(ctx context.Context, teamID uuid.UUID, id uuid.UUID) (*models.TrashItem, error)
+	// PurgeTrash deletes for good the teams, projects, tasks and comments deleted before
+	// the cutoff and returns how many were deleted, not counting the rows that go with
+	// them, like the subtasks of a task or the content of a team. Comments that have replies keep
+	// their place in the thread with their content erased.
+	PurgeTrash(ctx context.Context, before time.Time) (int, error)
+}
+
+type AuditRepository interface {
+	CreateAdminAuditLog(ctx context.Context, entry *models.AdminAuditLog) error
+	ListAdminAuditLogs(ctx context.Context, filter models.AdminAuditFilter) ([]*models.AdminAuditLog, int, error)
+	CreateTeamAuditLog(ctx context.Context, entry *models.TeamAuditLog) error
+	// ListTeamAuditLogs lists the audit log of the team, newest first by default.
+	ListTeamAuditLogs(ctx context.Context, teamID uuid.UUID, q listquery.Query) (listquery.Result[*models.TeamAuditLog], error)
+	// PurgeTeamAuditLogs deletes the team audit entries created before the cutoff and
+	// returns how many were deleted.
+	PurgeTeamAuditLogs(ctx context.Context, before time.Time) (int, error)
+}
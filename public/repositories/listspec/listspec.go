@@ -0,0 +1,247 @@
+// Package listspec holds the list-query whitelists of the paginated repository methods,
+// together with the functions that extract the cursor values of a row. The column
+// expressions use the table aliases of the repository queries and are the same for
+// every driver.
+package listspec
+
+import (
+	"maps"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories/models"
+)
+
+var (
+	timeOps = []listquery.Op{listquery.OpLt, listquery.OpLte, listquery.OpGt, listquery.OpGte}
+	roles   = []string{string(models.FounderUserRole), string(models.AdminUserRole), string(models.StandardUserRole)}
+)
+
+// Teams lists teams (alias t).
+var Teams = &listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"name":       {Column: "t.name", Type: listquery.String, Sortable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpContains}},
+		"created_at": {Column: "t.created_at", Type: listquery.Time, Sortable: true, Ops: timeOps},
+		"updated_at": {Column: "t.updated_at", Type: listquery.Time, Sortable: true, Ops: timeOps},
+	},
+	IDColumn:    "t.id",
+	DefaultSort: "created_at",
+}
+
+func TeamRow(t *models.Team) (string, map[string]any) {
+	return t.ID.String(), map[string]any{"name": t.Name, "created_at": t.CreatedAt, "updated_at": t.UpdatedAt}
+}
+
+// TeamMembers lists memberships of a team (alias tu).
+var TeamMembers = &listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"role":      {Column: "tu.role", Type: listquery.String, Sortable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpNe, listquery.OpIn}, Enum: roles},
+		"user_id":   {Column: "tu.user_id", Type: listquery.UUID, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn}},
+		"joined_at": {Column: "tu.joined_at", Type: listquery.Time, Sortable: true, Ops: timeOps},
+	},
+	IDColumn:    "tu.id",
+	DefaultSort: "joined_at",
+}
+
+func MemberRow(m *models.UserTeam) (string, map[string]any) {
+	return m.ID.String(), map[string]any{"role": string(m.Role), "joined_at": m.JoinedAt}
+}
+
+// Invitations lists invitations (alias ti).
+var Invitations = &listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"team_id":    {Column: "ti.team_id", Type: listquery.UUID, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn}},
+		"accepted":   {Column: "ti.accepted", Type: listquery.Bool, Nullable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpIsNull}},
+		"created_at": {Column: "ti.created_at", Type: listquery.Time, Sortable: true, Ops: timeOps},
+	},
+	IDColumn:    "ti.id",
+	DefaultSort: "-created_at",
+}
+
+func InvitationRow(i *models.Invitation) (string, map[string]any) {
+	return i.ID.String(), map[string]any{"created_at": i.CreatedAt}
+}
+
+// Tasks lists tasks (alias tk).
+var Tasks = &listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"team_id":      {Column: "tk.team_id", Type: listquery.UUID, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn}},
+		"title":        {Column: "tk.title", Type: listquery.String, Sortable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpContains}},
+		"group_task":   {Column: "tk.grouped", Type: listquery.Bool, Ops: []listquery.Op{listquery.OpEq}},
+		"state_id":     {Column: "tk.state_id", Type: listquery.UUID, Nullable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpNe, listquery.OpIn, listquery.OpIsNull}},
+		"project_id":   {Column: "tk.project_id", Type: listquery.UUID, Nullable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn, listquery.OpIsNull}},
+		"sprint_id":    {Column: "tk.sprint_id", Type: listquery.UUID, Nullable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn, listquery.OpIsNull}},
+		"milestone_id": {Column: "tk.milestone_id", Type: listquery.UUID, Nullable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn, listquery.OpIsNull}},
+		"label_id":     {Column: "tl.label_id", Type: listquery.UUID, Many: "task_labels tl WHERE tl.task_id = tk.id", Ops: []listquery.Op{listquery.OpEq, listquery.OpAny, listquery.OpAll}},
+		"created_by":   {Column: "tk.created_by", Type: listquery.UUID, Nullable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpIsNull}},
+		"due_at":       {Column: "tk.due_at", Type: listquery.Time, Nullable: true, Sortable: true, Ops: []listquery.Op{listquery.OpLt, listquery.OpLte, listquery.OpGt, listquery.OpGte, listquery.OpIsNull}},
+		"created_at":   {Column: "tk.created_at", Type: listquery.Time, Sortable: true, Ops: timeOps},
+		"updated_at":   {Column: "tk.updated_at", Type: listquery.Time, Sortable: true, Ops: timeOps},
+	},
+	IDColumn:    "tk.id",
+	DefaultSort: "-created_at",
+}
+
+// TaskRow includes the values of the custom fields, so tasks have to be loaded with
+// their relations before they are paginated.
+func TaskRow(t *models.Task) (string, map[string]any) {
+	values := map[string]any{"title": t.Title, "due_at": t.DueAt, "created_at": t.CreatedAt, "updated_at": t.UpdatedAt}
+	for key, v := range t.CustomFields {
+		if _, many := v.([]string); !many {
+			values[CustomFieldPrefix+key] = v
+		}
+	}
+	return t.ID.String(), values
+}
+
+// CustomFieldPrefix prefixes the keys of custom fields in task list queries, e.g.
+// "cf.priority[eq]=high" or "sort=-cf.points".
+const CustomFieldPrefix = "cf."
+
+// TasksWithCustomFields is Tasks extended with the custom fields of a team. Multi-select
+// fields are filtered like label_id; every other field type can be filtered and sorted,
+// with tasks lacking a value sorting last.
+func TasksWithCustomFields(fields []*models.CustomField) *listquery.Spec {
+	spec := *Tasks
+	spec.Fields = maps.Clone(Tasks.Fields)
+	for _, f := range fields {
+		spec.Fields[CustomFieldPrefix+f.Key] = customField(f)
+	}
+	return &spec
+}
+
+func customField(f *models.CustomField) listquery.Field {
+	// Field ids are UUIDs generated by the server, so inlining them is safe.
+	id := f.ID.String()
+	if f.Type == models.FieldMultiSelect {
+		return listquery.Field{
+			Column: "fo.option",
+			Type:   listquery.String,
+			Enum:   f.Options,
+			Many:   "task_field_options fo WHERE fo.task_id = tk.id AND fo.field_id = '" + id + "'",
+			Ops:    []listquery.Op{listquery.OpEq, listquery.OpAny, listquery.OpAll},
+		}
+	}
+	value := func(column string) string {
+		return "(SELECT fv." + column + " FROM task_field_values fv WHERE fv.task_id = tk.id AND fv.field_id = '" + id + "')"
+	}
+	field := listquery.Field{Nullable: true, Sortable: true}
+	switch f.Type {
+	case models.FieldNumber:
+		field.Column, field.Type = value("value_number"), listquery.Float
+		field.Ops = []listquery.Op{listquery.OpEq, listquery.OpNe, listquery.OpLt, listquery.OpLte, listquery.OpGt, listquery.OpGte, listquery.OpIsNull}
+	case models.FieldDate:
+		field.Column, field.Type = value("value_time"), listquery.Time
+		field.Ops = []listquery.Op{listquery.OpEq, listquery.OpLt, listquery.OpLte, listquery.OpGt, listquery.OpGte, listquery.OpIsNull}
+	case models.FieldCheckbox:
+		field.Column, field.Type = value("value_bool"), listquery.Bool
+		field.Ops = []listquery.Op{listquery.OpEq, listquery.OpIsNull}
+	case models.FieldUser:
+		field.Column, field.Type = value("value_user"), listquery.UUID
+		field.Ops = []listquery.Op{listquery.OpEq, listquery.OpIn, listquery.OpIsNull}
+	case models.FieldSingleSelect:
+		field.Column, field.Type, field.Enum = value("value_text"), listquery.String, f.Options
+		field.Ops = []listquery.Op{listquery.OpEq, listquery.OpNe, listquery.OpIn, listquery.OpIsNull}
+	default:
+		field.Column, field.Type = value("value_text"), listquery.String
+		field.Ops = []listquery.Op{listquery.OpEq, listquery.OpContains, listquery.OpIsNull}
+	}
+	return field
+}
+
+// Comments lists the comments of a task (alias cm).
+var Comments = &listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"parent_id":  {Column: "cm.parent_id", Type: listquery.UUID, Nullable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpIsNull}},
+		"author_id":  {Column: "cm.author_id", Type: listquery.UUID, Nullable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn}},
+		"created_at": {Column: "cm.created_at", Type: listquery.Time, Sortable: true, Ops: timeOps},
+	},
+	IDColumn:    "cm.id",
+	DefaultSort: "created_at",
+}
+
+func CommentRow(c *models.Comment) (string, map[string]any) {
+	return c.ID.String(), map[string]any{"created_at": c.CreatedAt}
+}
+
+// Notifications lists the notifications of a user (alias n).
+var Notifications = &listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"kind":       {Column: "n.kind", Type: listquery.String, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn}, Enum: []string{string(models.NotificationMention)}},
+		"team_id":    {Column: "n.team_id", Type: listquery.UUID, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn}},
+		"read_at":    {Column: "n.read_at", Type: listquery.Time, Nullable: true, Ops: []listquery.Op{listquery.OpIsNull}},
+		"created_at": {Column: "n.created_at", Type: listquery.Time, Sortable: true, Ops: timeOps},
+	},
+	IDColumn:    "n.id",
+	DefaultSort: "-created_at",
+}
+
+func NotificationRow(n *models.Notification) (string, map[string]any) {
+	return n.ID.String(), map[string]any{"created_at": n.CreatedAt}
+}
+
+// TaskActivity lists the activity of a task (alias ta).
+var TaskActivity = &listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"action":     {Column: "ta.action", Type: listquery.String, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn}, Enum: activityActions()},
+		"actor_id":   {Column: "ta.actor_id", Type: listquery.UUID, Nullable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn, listquery.OpIsNull}},
+		"created_at": {Column: "ta.created_at", Type: listquery.Time, Sortable: true, Ops: timeOps},
+	},
+	IDColumn:    "ta.id",
+	DefaultSort: "-created_at",
+}
+
+func TaskActivityRow(a *models.TaskActivity) (string, map[string]any) {
+	return a.ID.String(), map[string]any{"created_at": a.CreatedAt}
+}
+
+func activityActions() []string {
+	out := make([]string, len(models.TaskActivityActions))
+	for i, a := range models.TaskActivityActions {
+		out[i] = string(a)
+	}
+	return out
+}
+
+// TeamAudit lists the audit log of a team (alias al).
+var TeamAudit = &listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"action":     {Column: "al.action", Type: listquery.String, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn}, Enum: teamAuditActions()},
+		"actor_id":   {Column: "al.actor_id", Type: listquery.UUID, Nullable: true, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn, listquery.OpIsNull}},
+		"created_at": {Column: "al.created_at", Type: listquery.Time, Sortable: true, Ops: timeOps},
+	},
+	IDColumn:    "al.id",
+	DefaultSort: "-created_at",
+}
+
+func TeamAuditRow(e *models.TeamAuditLog) (string, map[string]any) {
+	return e.ID.String(), map[string]any{"created_at": e.CreatedAt}
+}
+
+func teamAuditActions() []string {
+	out := make([]string, len(models.TeamAuditActions))
+	for i, a := range models.TeamAuditActions {
+		out[i] = string(a)
+	}
+	return out
+}
+
+// Trash lists the trash of a team (alias tr).
+var Trash = &listquery.Spec{
+	Fields: map[string]listquery.Field{
+		"kind":       {Column: "tr.kind", Type: listquery.String, Ops: []listquery.Op{listquery.OpEq, listquery.OpIn}, Enum: trashKinds()},
+		"deleted_at": {Column: "tr.deleted_at", Type: listquery.Time, Sortable: true, Ops: timeOps},
+	},
+	IDColumn:    "tr.id",
+	DefaultSort: "-deleted_at",
+}
+
+func TrashRow(item *models.TrashItem) (string, map[string]any) {
+	return item.ID.String(), map[string]any{"deleted_at": item.DeletedAt}
+}
+
+func trashKinds() []string {
+	out := make([]string, len(models.TrashKinds))
+	for i, k := range models.TrashKinds {
+		out[i] = string(k)
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//swagger:enum TaskActivityAction
+type TaskActivityAction string
+
+const (
+	ActivityTaskCreated      TaskActivityAction = "task.created"
+	ActivityTaskUpdated      TaskActivityAction = "task.updated"
+	ActivityTaskDeleted      TaskActivityAction = "task.deleted"
+	ActivityTaskRestored     TaskActivityAction = "task.restored"
+	ActivityStateChanged     TaskActivityAction = "task.state_changed"
+	ActivityAssigneesChanged TaskActivityAction = "task.assignees_changed"
+	ActivityLabelsChanged    TaskActivityAction = "task.labels_changed"
+	ActivityCommentAdded     TaskActivityAction = "comment.added"
+	ActivityAttachmentAdded  TaskActivityAction = "attachment.added"
+)
+
+// TaskActivityActions lists the actions of the activity feed, for filters.
+var TaskActivityActions = []TaskActivityAction{
+	ActivityTaskCreated,
+	ActivityTaskUpdated,
+	ActivityTaskDeleted,
+	ActivityTaskRestored,
+	ActivityStateChanged,
+	ActivityAssigneesChanged,
+	ActivityLabelsChanged,
+	ActivityCommentAdded,
+	ActivityAttachmentAdded,
+}
+
+// FieldChange is the value of a task field before and after a change; nil stands for
+// an unset field.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// TaskActivity is an entry of the activity feed of a task. Entries are written in the
+// transaction of the change they record and are never updated.
+type TaskActivity struct {
+	ID     uuid.UUID `json:"id"`
+	TeamID uuid.UUID `json:"team_id"`
+	TaskID uuid.UUID `json:"task_id"`
+	// Nil for changes made by the server, and once the actor has been deleted.
+	ActorID *uuid.UUID         `json:"actor_id"`
+	Action  TaskActivityAction `json:"action"`
+	Changes []FieldChange      `json:"changes"`
+	// Context of the change, such as the id of the comment or attachment that was added.
+	Details   map[string]any `json:"details,omitempty"`
+	TraceID   string         `json:"trace_id,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// TaskChanges lists the editable fields whose values differ between before and after,
+// in a fixed order. Custom fields are reported as "custom_fields.<key>". Relations
+// (assignees and labels) and the state are recorded by their own actions.
+func TaskChanges(before, after *Task) []FieldChange {
+	changes := []FieldChange{}
+	add := func(field string, old, new any) {
+		if !reflect.DeepEqual(old, new) {
+			changes = append(changes, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+	add("title", before.Title, after.Title)
+	add("description", before.Description, after.Description)
+	add("group_task", before.Grouped, after.Grouped)
+	add("parent_id", uuidValue(before.ParentID), uuidValue(after.ParentID))
+	add("project_id", uuidValue(before.ProjectID), uuidValue(after.ProjectID))
+	add("sprint_id", uuidValue(before.SprintID), uuidValue(after.SprintID))
+	add("milestone_id", uuidValue(before.MilestoneID), uuidValue(after.MilestoneID))
+	add("due_at", timeValue(before.DueAt), timeValue(after.DueAt))
+	add("estimate", floatValue(before.Estimate), floatValue(after.Estimate))
+
+	keys := slices.Sorted(maps.Keys(before.CustomFields))
+	for _, key := range slices.Sorted(maps.Keys(after.CustomFields)) {
+		if _, ok := before.CustomFields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		add("custom_fields."+key, before.CustomFields[key], after.CustomFields[key])
+	}
+	return changes
+}
+
+// TaskCreationChanges lists the fields task was created with, including its initial
+// state, assignees and labels.
+func TaskCreationChanges(task *Task) []FieldChange {
+	changes := TaskChanges(&Task{}, task)
+	if task.StateID != nil {
+		changes = append(changes, FieldChange{Field: "state_id", New: *task.StateID})
+	}
+	if change, ok := IDsChange("assignee_ids", nil, task.AssigneeIDs); ok {
+		changes = append(changes, change)
+	}
+	if change, ok := IDsChange("label_ids", nil, task.LabelIDs); ok {
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// IDChange is the change of a reference field from before to after, nil standing for
+// unset, for changes made to many tasks at once.
+func IDChange(field string, before, after *uuid.UUID) FieldChange {
+	return FieldChange{Field: field, Old: uuidValue(before), New: uuidValue(after)}
+}
+
+// IDsChange reports the change from the ids before to the ids after as a FieldChange,
+// ignoring the order; ok is false when the sets are equal.
+func IDsChange(field string, before, after []uuid.UUID) (FieldChange, bool) {
+	old, new := slices.Clone(before), slices.Clone(after)
+	sortIDs(old)
+	sortIDs(new)
+	if slices.Equal(old, new) {
+		return FieldChange{}, false
+	}
+	if old == nil {
+		old = []uuid.UUID{}
+	}
+	if new == nil {
+		new = []uuid.UUID{}
+	}
+	return FieldChange{Field: field, Old: old, New: new}, true
+}
+
+func sortIDs(ids []uuid.UUID) {
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return slices.Compare(a[:], b[:]) })
+}
+
+// The helpers below turn nil pointers into untyped nils, so unset fields compare equal
+// and are serialized as null.
+
+func uuidValue(id *uuid.UUID) any {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
+
+func timeValue(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+func floatValue(f *float64) any {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
@@ -0,0 +1,55 @@
+package models_test
+
+import (
+	"task_manager/public/repositories/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskChanges(t *testing.T) {
+	due := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	estimate := 3.0
+	project := uuid.New()
+	base := func() *models.Task {
+		return &models.Task{Title: "Launch", CustomFields: map[string]any{"priority": "high", "tags": []string{"a"}}}
+	}
+
+	tests := []struct {
+		name   string
+		change func(*models.Task)
+		want   []models.FieldChange
+	}{
+		{"nothing", func(*models.Task) {}, []models.FieldChange{}},
+		{"title and due date", func(t *models.Task) {
+			t.Title = "Ship"
+			t.DueAt = &due
+		}, []models.FieldChange{{Field: "title", Old: "Launch", New: "Ship"}, {Field: "due_at", Old: nil, New: due}}},
+		{"project and estimate", func(t *models.Task) {
+			t.ProjectID = &project
+			t.Estimate = &estimate
+		}, []models.FieldChange{{Field: "project_id", Old: nil, New: project}, {Field: "estimate", Old: nil, New: estimate}}},
+		{"custom fields", func(t *models.Task) {
+			t.CustomFields = map[string]any{"points": 5.0, "tags": []string{"a"}}
+		}, []models.FieldChange{{Field: "custom_fields.points", Old: nil, New: 5.0}, {Field: "custom_fields.priority", Old: "high", New: nil}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := base()
+			tt.change(after)
+			require.Equal(t, tt.want, models.TaskChanges(base(), after))
+		})
+	}
+}
+
+func TestIDsChange(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	_, changed := models.IDsChange("label_ids", []uuid.UUID{a, b}, []uuid.UUID{b, a})
+	require.False(t, changed)
+
+	change, changed := models.IDsChange("label_ids", nil, []uuid.UUID{a})
+	require.True(t, changed)
+	require.Equal(t, models.FieldChange{Field: "label_ids", Old: []uuid.UUID{}, New: []uuid.UUID{a}}, change)
+}
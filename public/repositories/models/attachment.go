@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is a file uploaded to a task, optionally to one of its comments. The
+// content is kept in the blob store under StorageKey.
+type Attachment struct {
+	ID        uuid.UUID  `json:"id"`
+	TeamID    uuid.UUID  `json:"team_id"`
+	TaskID    uuid.UUID  `json:"task_id"`
+	CommentID *uuid.UUID `json:"comment_id"`
+	// Nil once the uploader has been deleted.
+	UploadedBy  *uuid.UUID `json:"uploaded_by"`
+	Filename    string     `json:"filename"`
+	ContentType string     `json:"content_type"`
+	SizeBytes   int64      `json:"size_bytes"`
+	StorageKey  string     `json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// BlobDeletion is a blob left behind by a deleted attachment, waiting to be removed
+// from the blob store.
+type BlobDeletion struct {
+	ID         int64
+	StorageKey string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//swagger:enum AdminAction
+type AdminAction string
+
+const (
+	AdminActionDisableUser   AdminAction = "user.disable"
+	AdminActionEnableUser    AdminAction = "user.enable"
+	AdminActionPromoteUser   AdminAction = "user.promote"
+	AdminActionDemoteUser    AdminAction = "user.demote"
+	AdminActionPasswordReset AdminAction = "user.password_reset"
+	AdminActionDeleteUser    AdminAction = "user.delete"
+	AdminActionImpersonate   AdminAction = "user.impersonate"
+)
+
+// AdminAuditLog records a single action taken through the admin API.
+type AdminAuditLog struct {
+	ID uuid.UUID `json:"id"`
+	// Nil once the acting administrator has been deleted.
+	ActorID      *uuid.UUID     `json:"actor_id"`
+	Action       AdminAction    `json:"action"`
+	TargetUserID *uuid.UUID     `json:"target_user_id"`
+	Details      map[string]any `json:"details,omitempty"`
+	TraceID      string         `json:"trace_id,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+type AdminAuditFilter struct {
+	ActorID      *uuid.UUID
+	TargetUserID *uuid.UUID
+	Action       AdminAction
+	Limit        int
+	Offset       int
+}
+
+//swagger:enum TeamAuditAction
+type TeamAuditAction string
+
+const (
+	TeamActionCreate         TeamAuditAction = "team.create"
+	TeamActionRename         TeamAuditAction = "team.rename"
+	TeamActionDelete         TeamAuditAction = "team.delete"
+	TeamActionRestore        TeamAuditAction = "team.restore"
+	TeamActionArchive        TeamAuditAction = "team.archive"
+	TeamActionUnarchive      TeamAuditAction = "team.unarchive"
+	TeamActionWorkflowUpdate TeamAuditAction = "workflow.update"
+	TeamActionProjectAccess  TeamAuditAction = "project.access"
+)
+
+// TeamAuditActions lists the actions of the team audit log, for filters.
+var TeamAuditActions = []TeamAuditAction{
+	TeamActionCreate,
+	TeamActionRename,
+	TeamActionDelete,
+	TeamActionRestore,
+	TeamActionArchive,
+	TeamActionUnarchive,
+	TeamActionWorkflowUpdate,
+	TeamActionProjectAccess,
+}
+
+// TeamAuditLog records an administrative action on a team. Entries cannot be changed
+// and outlive the team and the actor; they are removed by the retention job only.
+type TeamAuditLog struct {
+	ID      uuid.UUID       `json:"id"`
+	TeamID  uuid.UUID       `json:"team_id"`
+	ActorID *uuid.UUID      `json:"actor_id"`
+	Action  TeamAuditAction `json:"action"`
+	// Old and new values, or the ids the action was about.
+	Details   map[string]any `json:"details,omitempty"`
+	IP        string         `json:"ip"`
+	UserAgent string         `json:"user_agent"`
+	TraceID   string         `json:"trace_id,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//swagger:enum SwimlaneBy
+type SwimlaneBy string
+
+const (
+	SwimlaneNone     SwimlaneBy = "none"
+	SwimlaneAssignee SwimlaneBy = "assignee"
+	SwimlaneLabel    SwimlaneBy = "label"
+	// SwimlaneCustomField groups by the options of a single-select custom field, e.g. a
+	// priority field.
+	SwimlaneCustomField SwimlaneBy = "custom_field"
+)
+
+func (s SwimlaneBy) IsValid() bool {
+	switch s {
+	case SwimlaneNone, SwimlaneAssignee, SwimlaneLabel, SwimlaneCustomField:
+		return true
+	default:
+		return false
+	}
+}
+
+//swagger:enum WIPMode
+type WIPMode string
+
+const (
+	// WIPWarn lets moves over the WIP limit through with a warning.
+	WIPWarn WIPMode = "warn"
+	// WIPEnforce refuses moves over the WIP limit.
+	WIPEnforce WIPMode = "enforce"
+)
+
+func (m WIPMode) IsValid() bool {
+	return m == WIPWarn || m == WIPEnforce
+}
+
+// Board is a kanban view of the tasks of a team. Each column shows the tasks in one
+// workflow state; the order of the cards is kept per board.
+type Board struct {
+	ID         uuid.UUID  `json:"id"`
+	TeamID     uuid.UUID  `json:"team_id"`
+	Name       string     `json:"name"`
+	SwimlaneBy SwimlaneBy `json:"swimlane_by"`
+	// Single-select custom field of custom_field swimlanes; nil once the field is deleted.
+	SwimlaneFieldID *uuid.UUID    `json:"swimlane_field_id"`
+	Columns         []BoardColumn `json:"columns"`
+	// Nil once the creator has been deleted.
+	CreatedBy *uuid.UUID `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (b *Board) Column(id uuid.UUID) *BoardColumn {
+	for i := range b.Columns {
+		if b.Columns[i].ID == id {
+			return &b.Columns[i]
+		}
+	}
+	return nil
+}
+
+type BoardColumn struct {
+	ID       uuid.UUID `json:"id"`
+	BoardID  uuid.UUID `json:"board_id"`
+	StateID  uuid.UUID `json:"state_id"`
+	Name     string    `json:"name"`
+	Position int       `json:"position"`
+	// Most tasks the column should hold; nil for no limit.
+	WIPLimit *int    `json:"wip_limit"`
+	WIPMode  WIPMode `json:"wip_mode"`
+}
+
+// OverLimit reports whether count tasks exceed the WIP limit of the column.
+func (c *BoardColumn) OverLimit(count int) bool {
+	return c.WIPLimit != nil && count > *c.WIPLimit
+}
+
+// BoardCard is the position of a task in a column; Rank is empty for tasks that were
+// never moved on the board.
+type BoardCard struct {
+	TaskID uuid.UUID
+	Rank   string
+}
+
+// BoardView is a board with the cards of its columns.
+type BoardView struct {
+	Board *Board `json:"board"`
+	// Keys of the swimlanes in display order: user ids, label ids or options. The empty
+	// key holds the cards without an assignee, label or value; boards without
+	// swimlanes have only that one.
+	Swimlanes []string          `json:"swimlanes"`
+	Columns   []BoardColumnView `json:"columns"`
+}
+
+type BoardColumnView struct {
+	BoardColumn
+	// All tasks in the column, including the ones past the card limit.
+	TaskCount int  `json:"task_count"`
+	OverLimit bool `json:"over_limit"`
+	// One lane per swimlane, in the order of BoardView.Swimlanes.
+	Lanes []BoardLane `json:"lanes"`
+}
+
+type BoardLane struct {
+	Key   string  `json:"key"`
+	Tasks []*Task `json:"tasks"`
+}
+
+// BoardMove is the outcome of moving a card.
+type BoardMove struct {
+	Task     *Task     `json:"task"`
+	ColumnID uuid.UUID `json:"column_id"`
+	Rank     string    `json:"rank"`
+	// Set when the move exceeded the WIP limit of a warn column.
+	Warnings []string `json:"warnings"`
+}
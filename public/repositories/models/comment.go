@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment is a comment on a task. Replies have a ParentID pointing at a top-level
+// comment of the same task; replies cannot be replied to.
+type Comment struct {
+	ID       uuid.UUID  `json:"id"`
+	TeamID   uuid.UUID  `json:"team_id"`
+	TaskID   uuid.UUID  `json:"task_id"`
+	ParentID *uuid.UUID `json:"parent_id"`
+	// Nil once the author has been deleted.
+	AuthorID *uuid.UUID `json:"author_id"`
+	// Markdown source and its sanitized HTML rendering.
+	Body       string      `json:"body"`
+	BodyHTML   string      `json:"body_html"`
+	MentionIDs []uuid.UUID `json:"mention_ids"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+	// Set once the body has been changed; see CommentRevision.
+	EditedAt  *time.Time `json:"edited_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+func (c *Comment) IsDeleted() bool {
+	return c != nil && c.DeletedAt != nil
+}
+
+// Redact clears the content of a deleted comment. Deleted comments are still listed so
+// that their replies keep their place in the thread.
+func (c *Comment) Redact() {
+	if !c.IsDeleted() {
+		return
+	}
+	c.Body = ""
+	c.BodyHTML = ""
+	c.MentionIDs = []uuid.UUID{}
+}
+
+// CommentRevision is a previous body of an edited comment.
+type CommentRevision struct {
+	ID        uuid.UUID  `json:"id"`
+	CommentID uuid.UUID  `json:"comment_id"`
+	Body      string     `json:"body"`
+	EditedBy  *uuid.UUID `json:"edited_by"`
+	EditedAt  time.Time  `json:"edited_at"`
+}
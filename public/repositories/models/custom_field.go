@@ -0,0 +1,314 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+//swagger:enum CustomFieldType
+type CustomFieldType string
+
+const (
+	FieldText         CustomFieldType = "text"
+	FieldNumber       CustomFieldType = "number"
+	FieldDate         CustomFieldType = "date"
+	FieldSingleSelect CustomFieldType = "single_select"
+	FieldMultiSelect  CustomFieldType = "multi_select"
+	FieldUser         CustomFieldType = "user"
+	FieldCheckbox     CustomFieldType = "checkbox"
+)
+
+func (t CustomFieldType) IsValid() bool {
+	switch t {
+	case FieldText, FieldNumber, FieldDate, FieldSingleSelect, FieldMultiSelect, FieldUser, FieldCheckbox:
+		return true
+	default:
+		return false
+	}
+}
+
+// CustomField is a typed task field defined by a team. Tasks hold its value under Key
+// in Task.CustomFields.
+//
+// Min and Max bound the value of number fields, the length of text fields and the
+// number of selected options of multi-select fields. Pattern is a regular expression
+// text values must match.
+type CustomField struct {
+	ID        uuid.UUID       `json:"id"`
+	TeamID    uuid.UUID       `json:"team_id"`
+	Key       string          `json:"key"`
+	Name      string          `json:"name"`
+	Type      CustomFieldType `json:"type"`
+	Required  bool            `json:"required"`
+	Min       *float64        `json:"min"`
+	Max       *float64        `json:"max"`
+	Pattern   string          `json:"pattern"`
+	Options   []string        `json:"options"`
+	Position  int             `json:"position"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// CustomFieldValue is the value of a field on a task. A nil Value clears the field.
+type CustomFieldValue struct {
+	Field *CustomField
+	Value any
+}
+
+var fieldKeyRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// Check validates the definition of the field.
+func (f *CustomField) Check() error {
+	if !fieldKeyRe.MatchString(f.Key) {
+		return errors.New("key must start with a letter and contain only lowercase letters, digits and underscores")
+	}
+	if !f.Type.IsValid() {
+		return fmt.Errorf("unknown field type %q", f.Type)
+	}
+	if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+		return errors.New("min must not be greater than max")
+	}
+	switch f.Type {
+	case FieldNumber, FieldText, FieldMultiSelect:
+	default:
+		if f.Min != nil || f.Max != nil {
+			return fmt.Errorf("min and max do not apply to %s fields", f.Type)
+		}
+	}
+	if f.Pattern != "" {
+		if f.Type != FieldText {
+			return errors.New("pattern only applies to text fields")
+		}
+		if _, err := regexp.Compile(f.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %v", err)
+		}
+	}
+	isSelect := f.Type == FieldSingleSelect || f.Type == FieldMultiSelect
+	if isSelect && len(f.Options) == 0 {
+		return errors.New("select fields need at least one option")
+	}
+	if !isSelect && len(f.Options) > 0 {
+		return fmt.Errorf("options do not apply to %s fields", f.Type)
+	}
+	for i, o := range f.Options {
+		if strings.TrimSpace(o) == "" {
+			return errors.New("options must not be empty")
+		}
+		if slices.Contains(f.Options[:i], o) {
+			return fmt.Errorf("duplicate option %q", o)
+		}
+	}
+	return nil
+}
+
+// Normalize validates a value decoded from JSON against the field and converts it to the
+// Go type stored for the field: string (text, single-select), float64, time.Time (a date
+// at midnight UTC), []string (multi-select, in option order), uuid.UUID or bool.
+// nil clears the field and is refused for required fields.
+func (f *CustomField) Normalize(v any) (any, error) {
+	if v == nil {
+		if f.Required {
+			return nil, errors.New("is required")
+		}
+		return nil, nil
+	}
+	switch f.Type {
+	case FieldText:
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("must be a string")
+		}
+		if err := f.inRange(float64(utf8.RuneCountInString(s)), "length"); err != nil {
+			return nil, err
+		}
+		if f.Pattern != "" && !regexp.MustCompile(f.Pattern).MatchString(s) {
+			return nil, errors.New("does not match the pattern")
+		}
+		return s, nil
+	case FieldNumber:
+		n, ok := v.(float64)
+		if !ok || math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, errors.New("must be a number")
+		}
+		if err := f.inRange(n, "value"); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case FieldDate:
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("must be a date (YYYY-MM-DD)")
+		}
+		d, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			return nil, errors.New("must be a date (YYYY-MM-DD)")
+		}
+		return d, nil
+	case FieldSingleSelect:
+		s, ok := v.(string)
+		if !ok || !slices.Contains(f.Options, s) {
+			return nil, errors.New("must be one of the options")
+		}
+		return s, nil
+	case FieldMultiSelect:
+		items, ok := v.([]any)
+		if !ok {
+			return nil, errors.New("must be a list of options")
+		}
+		selected := map[string]bool{}
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok || !slices.Contains(f.Options, s) {
+				return nil, errors.New("must only contain options of the field")
+			}
+			selected[s] = true
+		}
+		if err := f.inRange(float64(len(selected)), "number of options"); err != nil {
+			return nil, err
+		}
+		out := []string{}
+		for _, o := range f.Options {
+			if selected[o] {
+				out = append(out, o)
+			}
+		}
+		if len(out) == 0 && f.Required {
+			return nil, errors.New("is required")
+		}
+		return out, nil
+	case FieldUser:
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("must be a user id")
+		}
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, errors.New("must be a user id")
+		}
+		return id, nil
+	case FieldCheckbox:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, errors.New("must be a boolean")
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unknown field type %q", f.Type)
+	}
+}
+
+func (f *CustomField) inRange(n float64, what string) error {
+	if f.Min != nil && n < *f.Min {
+		return fmt.Errorf("%s must be at least %v", what, *f.Min)
+	}
+	if f.Max != nil && n > *f.Max {
+		return fmt.Errorf("%s must be at most %v", what, *f.Max)
+	}
+	return nil
+}
+
+// FieldValueColumns is a task_field_values row as scanned by the repositories.
+type FieldValueColumns struct {
+	Type   CustomFieldType
+	Text   sql.NullString
+	Number sql.NullFloat64
+	Time   sql.NullTime
+	Bool   sql.NullBool
+	User   uuid.NullUUID
+}
+
+// Value returns the value of the row as served in Task.CustomFields: dates are
+// formatted as YYYY-MM-DD. It is nil when the column of the type is NULL, e.g. after
+// the user of a user field was deleted.
+func (c FieldValueColumns) Value() any {
+	switch c.Type {
+	case FieldNumber:
+		if c.Number.Valid {
+			return c.Number.Float64
+		}
+	case FieldDate:
+		if c.Time.Valid {
+			return c.Time.Time.UTC().Format(time.DateOnly)
+		}
+	case FieldCheckbox:
+		if c.Bool.Valid {
+			return c.Bool.Bool
+		}
+	case FieldUser:
+		if c.User.Valid {
+			return c.User.UUID
+		}
+	default:
+		if c.Text.Valid {
+			return c.Text.String
+		}
+	}
+	return nil
+}
+
+// SelectedOptions collects task_field_options rows by task and field key.
+type SelectedOptions map[uuid.UUID]map[string]*selectedOptions
+
+type selectedOptions struct {
+	options  string
+	selected []string
+}
+
+// Add records option as selected on the task. options is the JSON options column of
+// the field.
+func (s SelectedOptions) Add(taskID uuid.UUID, key string, options string, option string) {
+	if s[taskID] == nil {
+		s[taskID] = map[string]*selectedOptions{}
+	}
+	if s[taskID][key] == nil {
+		s[taskID][key] = &selectedOptions{options: options}
+	}
+	s[taskID][key].selected = append(s[taskID][key].selected, option)
+}
+
+// Apply stores the selections in the CustomFields of the tasks, in option order.
+func (s SelectedOptions) Apply(byID map[uuid.UUID]*Task) {
+	for taskID, fields := range s {
+		t, ok := byID[taskID]
+		if !ok {
+			continue
+		}
+		for key, sel := range fields {
+			var options []string
+			_ = json.Unmarshal([]byte(sel.options), &options)
+			slices.SortFunc(sel.selected, func(a, b string) int {
+				return slices.Index(options, a) - slices.Index(options, b)
+			})
+			t.CustomFields[key] = sel.selected
+		}
+	}
+}
+
+// Apply stores the value in fields, a Task.CustomFields map, in the form tasks are
+// served with. Cleared values and empty selections are removed.
+func (v CustomFieldValue) Apply(fields map[string]any) {
+	switch val := v.Value.(type) {
+	case nil:
+		delete(fields, v.Field.Key)
+	case []string:
+		if len(val) == 0 {
+			delete(fields, v.Field.Key)
+		} else {
+			fields[v.Field.Key] = val
+		}
+	case time.Time:
+		fields[v.Field.Key] = val.UTC().Format(time.DateOnly)
+	default:
+		fields[v.Field.Key] = val
+	}
+}
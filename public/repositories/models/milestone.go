@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//swagger:enum MilestoneHealth
+type MilestoneHealth string
+
+const (
+	MilestoneOnTrack   MilestoneHealth = "on_track"
+	MilestoneAtRisk    MilestoneHealth = "at_risk"
+	MilestoneOverdue   MilestoneHealth = "overdue"
+	MilestoneCompleted MilestoneHealth = "completed"
+)
+
+// MilestoneRiskMargin is how many percentage points the elapsed share of a milestone's
+// time may run ahead of its share of done tasks before the milestone is at risk.
+const MilestoneRiskMargin = 25
+
+// Milestone is a due date of a team, optionally scoped to one of its projects, that
+// tasks are attached to.
+type Milestone struct {
+	ID     uuid.UUID `json:"id"`
+	TeamID uuid.UUID `json:"team_id"`
+	// Only tasks of this project can be attached; nil for team milestones.
+	ProjectID   *uuid.UUID `json:"project_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	DueAt       time.Time  `json:"due_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	// Nil once the creator has been deleted.
+	CreatedBy *uuid.UUID        `json:"created_by"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Progress  MilestoneProgress `json:"progress"`
+	// Share of the time between the creation of the milestone and its due date that
+	// has passed, capped at 100; set by Assess.
+	TimeElapsed int             `json:"time_elapsed_percent"`
+	Health      MilestoneHealth `json:"health"`
+}
+
+func (m *Milestone) Completed() bool {
+	return m.CompletedAt != nil
+}
+
+// OpenTasks is the number of attached tasks that are not done.
+func (m *Milestone) OpenTasks() int {
+	return m.Progress.Total - m.Progress.Done
+}
+
+// Assess sets TimeElapsed and Health as of now. A milestone with open tasks is overdue
+// once its due date has passed, and at risk while the elapsed share of its time runs
+// more than MilestoneRiskMargin points ahead of its share of done tasks.
+func (m *Milestone) Assess(now time.Time) {
+	m.TimeElapsed = 100
+	if window := m.DueAt.Sub(m.CreatedAt); window > 0 && now.Before(m.DueAt) {
+		m.TimeElapsed = max(0, int(now.Sub(m.CreatedAt)*100/window))
+	}
+	switch {
+	case m.Completed():
+		m.Health = MilestoneCompleted
+	case m.OpenTasks() == 0:
+		m.Health = MilestoneOnTrack
+	case !now.Before(m.DueAt):
+		m.Health = MilestoneOverdue
+	case m.TimeElapsed-m.Progress.Percent > MilestoneRiskMargin:
+		m.Health = MilestoneAtRisk
+	default:
+		m.Health = MilestoneOnTrack
+	}
+}
+
+// MilestoneProgress is ProjectProgress with the share of every category; Percent is the
+// share of done tasks.
+type MilestoneProgress struct {
+	ProjectProgress
+	NotStartedPercent int `json:"not_started_percent"`
+	ActivePercent     int `json:"active_percent"`
+}
+
+// Add counts n tasks in category.
+func (p *MilestoneProgress) Add(category WorkflowCategory, n int) {
+	p.ProjectProgress.Add(category, n)
+	p.NotStartedPercent = p.NotStarted * 100 / p.Total
+	p.ActivePercent = p.Active * 100 / p.Total
+}
+
+// MilestoneCompletion is the outcome of completing a milestone.
+type MilestoneCompletion struct {
+	Milestone *Milestone `json:"milestone"`
+	// Set when the milestone was completed with open tasks.
+	Warnings []string `json:"warnings"`
+}
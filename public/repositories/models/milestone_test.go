@@ -0,0 +1,48 @@
+package models_test
+
+import (
+	"task_manager/public/repositories/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMilestoneAssess(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	due := created.AddDate(0, 0, 10)
+	completed := due
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		done, open  int
+		completedAt *time.Time
+		wantElapsed int
+		wantHealth  models.MilestoneHealth
+	}{
+		{"just created", created, 0, 4, nil, 0, models.MilestoneOnTrack},
+		{"behind by less than the margin", created.AddDate(0, 0, 5), 1, 3, nil, 50, models.MilestoneOnTrack},
+		{"behind by more than the margin", created.AddDate(0, 0, 6), 1, 3, nil, 60, models.MilestoneAtRisk},
+		{"ahead of time", created.AddDate(0, 0, 9), 3, 1, nil, 90, models.MilestoneOnTrack},
+		{"no open tasks", created.AddDate(0, 0, 9), 2, 0, nil, 90, models.MilestoneOnTrack},
+		{"no tasks", created.AddDate(0, 0, 9), 0, 0, nil, 90, models.MilestoneOnTrack},
+		{"past due with open tasks", due, 3, 1, nil, 100, models.MilestoneOverdue},
+		{"past due without open tasks", due.AddDate(0, 0, 1), 4, 0, nil, 100, models.MilestoneOnTrack},
+		{"completed", due.AddDate(0, 0, 1), 3, 1, &completed, 100, models.MilestoneCompleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &models.Milestone{CreatedAt: created, DueAt: due, CompletedAt: tt.completedAt}
+			if tt.done > 0 {
+				m.Progress.Add(models.CategoryDone, tt.done)
+			}
+			if tt.open > 0 {
+				m.Progress.Add(models.CategoryNotStarted, tt.open)
+			}
+			m.Assess(tt.now)
+			require.Equal(t, tt.wantElapsed, m.TimeElapsed)
+			require.Equal(t, tt.wantHealth, m.Health)
+		})
+	}
+}
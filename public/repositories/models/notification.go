@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//swagger:enum NotificationKind
+type NotificationKind string
+
+const (
+	// NotificationMention is sent to users mentioned in a comment.
+	NotificationMention NotificationKind = "mention"
+	// NotificationReminder is sent to the recipients of a task when one of its reminders fires.
+	NotificationReminder NotificationKind = "reminder"
+	// NotificationOverdue is sent to the recipients of a task once it is past due and not done.
+	NotificationOverdue NotificationKind = "overdue"
+)
+
+type Notification struct {
+	ID        uuid.UUID        `json:"id"`
+	UserID    uuid.UUID        `json:"user_id"`
+	TeamID    uuid.UUID        `json:"team_id"`
+	Kind      NotificationKind `json:"kind"`
+	TaskID    *uuid.UUID       `json:"task_id"`
+	CommentID *uuid.UUID       `json:"comment_id"`
+	// User whose action caused the notification.
+	ActorID   *uuid.UUID `json:"actor_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at"`
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//swagger:enum ProjectStatus
+type ProjectStatus string
+
+const (
+	ProjectPlanned   ProjectStatus = "planned"
+	ProjectActive    ProjectStatus = "active"
+	ProjectPaused    ProjectStatus = "paused"
+	ProjectCompleted ProjectStatus = "completed"
+	ProjectCancelled ProjectStatus = "cancelled"
+)
+
+func (s ProjectStatus) IsValid() bool {
+	switch s {
+	case ProjectPlanned, ProjectActive, ProjectPaused, ProjectCompleted, ProjectCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// Project groups tasks of a team; a task belongs to at most one project.
+//
+// Tasks of a restricted project stay visible to the whole team, but only the members
+// and the lead of the project and the team admins can file tasks into it or move them
+// out. Archived projects take no new tasks and cannot be edited until they are
+// unarchived.
+type Project struct {
+	ID          uuid.UUID `json:"id"`
+	TeamID      uuid.UUID `json:"team_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	// Nil when the project has no lead.
+	LeadID     *uuid.UUID    `json:"lead_id"`
+	Status     ProjectStatus `json:"status"`
+	StartDate  *time.Time    `json:"start_date"`
+	TargetDate *time.Time    `json:"target_date"`
+	Restricted bool          `json:"restricted"`
+	MemberIDs  []uuid.UUID   `json:"member_ids"`
+	ArchivedAt *time.Time    `json:"archived_at"`
+	// Nil once the creator has been deleted.
+	CreatedBy *uuid.UUID      `json:"created_by"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Progress  ProjectProgress `json:"progress"`
+}
+
+func (p *Project) Archived() bool {
+	return p.ArchivedAt != nil
+}
+
+// Allows reports whether a team member with role can file tasks into the project and
+// move them out.
+func (p *Project) Allows(userID uuid.UUID, role TeamUserRole) bool {
+	if !p.Restricted || role == AdminUserRole || role == FounderUserRole {
+		return true
+	}
+	return (p.LeadID != nil && *p.LeadID == userID) || slices.Contains(p.MemberIDs, userID)
+}
+
+// ProjectProgress counts the tasks of a project by the category of their workflow
+// state; tasks without a state count as not started.
+type ProjectProgress struct {
+	Total      int `json:"total"`
+	NotStarted int `json:"not_started"`
+	Active     int `json:"active"`
+	Done       int `json:"done"`
+	// Share of done tasks, rounded down; 0 for projects without tasks.
+	Percent int `json:"percent"`
+}
+
+// Add counts n tasks in category.
+func (p *ProjectProgress) Add(category WorkflowCategory, n int) {
+	switch category {
+	case CategoryActive:
+		p.Active += n
+	case CategoryDone:
+		p.Done += n
+	default:
+		p.NotStarted += n
+	}
+	p.Total += n
+	p.Percent = p.Done * 100 / p.Total
+}
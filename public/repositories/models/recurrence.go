@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//swagger:enum RecurrenceMode
+type RecurrenceMode string
+
+const (
+	// RecurOnComplete creates the next instance when the current one is moved to a
+	// done-category state.
+	RecurOnComplete RecurrenceMode = "on_complete"
+	// RecurOnSchedule creates the next instance when the occurrence of the current one
+	// arrives, whether or not it was completed.
+	RecurOnSchedule RecurrenceMode = "on_schedule"
+)
+
+func (m RecurrenceMode) IsValid() bool {
+	return m == RecurOnComplete || m == RecurOnSchedule
+}
+
+// Recurrence repeats a task on an iCalendar RRULE. The series moves from instance to
+// instance: TaskID is always the latest one, due at OccurrenceAt.
+type Recurrence struct {
+	ID     uuid.UUID `json:"id"`
+	TeamID uuid.UUID `json:"team_id"`
+	TaskID uuid.UUID `json:"task_id"`
+	// RRULE, e.g. "FREQ=WEEKLY;BYDAY=MO".
+	Rule string `json:"rule"`
+	// IANA time zone the rule is expanded in, e.g. "Europe/Berlin".
+	Timezone string         `json:"timezone"`
+	Mode     RecurrenceMode `json:"mode"`
+	// DTSTART of the rule; COUNT counts occurrences from here.
+	StartsAt     time.Time `json:"starts_at"`
+	OccurrenceAt time.Time `json:"occurrence_at"`
+	// When the scheduler creates the next instance; nil for on_complete series.
+	NextRunAt *time.Time `json:"next_run_at"`
+	// Nil once the creator has been deleted.
+	CreatedBy *uuid.UUID `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// The next occurrences after the current instance; only set by the endpoints that
+	// return a single recurrence.
+	Upcoming []time.Time `json:"upcoming,omitempty"`
+}
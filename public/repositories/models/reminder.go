@@ -0,0 +1,140 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxReminderMinutes is the earliest a reminder can fire: 30 days before the due date.
+const MaxReminderMinutes = 30 * 24 * 60
+
+// UserPreferences are the notification settings of a user. Users who never saved
+// theirs get DefaultUserPreferences.
+type UserPreferences struct {
+	UserID uuid.UUID `json:"user_id"`
+	// IANA time zone name, e.g. "Europe/Berlin"; quiet hours and the dates in emails use it.
+	Timezone string `json:"timezone"`
+	// "HH:MM" in the time zone of the user. Nothing is delivered from start until end;
+	// the window may wrap past midnight, e.g. 22:00 to 07:00.
+	QuietHoursStart *string `json:"quiet_hours_start"`
+	QuietHoursEnd   *string `json:"quiet_hours_end"`
+	// Minutes before the due date, for tasks without reminders of their own.
+	DefaultReminders []int `json:"default_reminders"`
+	// Reminders and overdue notices are also sent by email.
+	EmailNotifications bool      `json:"email_notifications"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+func DefaultUserPreferences(userID uuid.UUID) *UserPreferences {
+	return &UserPreferences{
+		UserID:             userID,
+		Timezone:           "UTC",
+		DefaultReminders:   []int{},
+		EmailNotifications: true,
+	}
+}
+
+// Location returns the time zone of the user, or UTC when it cannot be loaded.
+func (p *UserPreferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// InQuietHours reports whether t falls into the quiet hours of the user.
+func (p *UserPreferences) InQuietHours(t time.Time) bool {
+	if p.QuietHoursStart == nil || p.QuietHoursEnd == nil {
+		return false
+	}
+	start, err1 := ParseClock(*p.QuietHoursStart)
+	end, err2 := ParseClock(*p.QuietHoursEnd)
+	if err1 != nil || err2 != nil || start == end {
+		return false
+	}
+	local := t.In(p.Location())
+	now := local.Hour()*60 + local.Minute()
+	if start < end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// ParseClock parses an "HH:MM" wall-clock time into minutes after midnight.
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// NormalizeReminders removes duplicate reminders and orders them as they fire.
+func NormalizeReminders(minutes []int) []int {
+	out := append([]int{}, minutes...)
+	slices.SortFunc(out, func(a, b int) int { return b - a })
+	return slices.Compact(out)
+}
+
+// TaskReminders are the reminders of a task in minutes before its due date, in the
+// order they fire. Empty means the default reminders of each recipient apply.
+type TaskReminders struct {
+	TaskID        uuid.UUID `json:"task_id"`
+	MinutesBefore []int     `json:"minutes_before"`
+}
+
+//swagger:enum DeliveryKind
+type DeliveryKind string
+
+const (
+	DeliveryReminder DeliveryKind = "reminder"
+	DeliveryOverdue  DeliveryKind = "overdue"
+)
+
+// ReminderDelivery records that a reminder or an overdue notice for a due date of a
+// task was sent to a user.
+type ReminderDelivery struct {
+	TaskID        uuid.UUID
+	UserID        uuid.UUID
+	Kind          DeliveryKind
+	MinutesBefore int
+	DueAt         time.Time
+}
+
+// DueTask is an open task with a due date, with what the reminder worker needs to
+// notify its recipients.
+type DueTask struct {
+	ID          uuid.UUID
+	TeamID      uuid.UUID
+	Title       string
+	DueAt       time.Time
+	CreatedBy   *uuid.UUID
+	AssigneeIDs []uuid.UUID
+	// Reminders of the task; empty when the defaults of the recipients apply.
+	Reminders []int
+}
+
+// Recipients are the assignees, or the creator of unassigned tasks.
+func (t *DueTask) Recipients() []uuid.UUID {
+	if len(t.AssigneeIDs) > 0 {
+		return t.AssigneeIDs
+	}
+	if t.CreatedBy != nil {
+		return []uuid.UUID{*t.CreatedBy}
+	}
+	return nil
+}
+
+// OutboxEmail is an email waiting to be sent by the mailer job.
+type OutboxEmail struct {
+	ID        uuid.UUID
+	Recipient string
+	Subject   string
+	Body      string
+	Attempts  int
+	CreatedAt time.Time
+}
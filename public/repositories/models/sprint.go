@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//swagger:enum SprintStatus
+type SprintStatus string
+
+const (
+	SprintPlanned SprintStatus = "planned"
+	SprintActive  SprintStatus = "active"
+	SprintClosed  SprintStatus = "closed"
+)
+
+func (s SprintStatus) IsValid() bool {
+	switch s {
+	case SprintPlanned, SprintActive, SprintClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sprint is a time box of a team, optionally scoped to one of its projects.
+//
+// A sprint is planned, then started and finally closed; a team has at most one active
+// sprint. Starting a sprint records what was committed to it and closing it records
+// what was completed, after which its unfinished tasks are carried over to another
+// sprint or back to the backlog.
+type Sprint struct {
+	ID     uuid.UUID `json:"id"`
+	TeamID uuid.UUID `json:"team_id"`
+	// Only tasks of this project can be added to the sprint; nil for team sprints.
+	ProjectID *uuid.UUID   `json:"project_id"`
+	Name      string       `json:"name"`
+	Goal      string       `json:"goal"`
+	Status    SprintStatus `json:"status"`
+	StartDate time.Time    `json:"start_date"`
+	EndDate   time.Time    `json:"end_date"`
+	// Estimate the team expects to get through; nil when not planned.
+	Capacity  *float64   `json:"capacity"`
+	StartedAt *time.Time `json:"started_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+	// Snapshot of the tasks in the sprint when it started.
+	Committed *SprintTotals `json:"committed"`
+	// Snapshot of the done tasks in the sprint when it was closed.
+	Completed *SprintTotals `json:"completed"`
+	// Number of unfinished tasks moved out when the sprint was closed.
+	CarriedOver *int `json:"carried_over"`
+	// Nil once the creator has been deleted.
+	CreatedBy *uuid.UUID `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// Current totals of the tasks in the sprint.
+	Scope SprintScope `json:"scope"`
+}
+
+// SprintTotals counts tasks and sums their estimates; unestimated tasks add nothing to
+// Estimate.
+type SprintTotals struct {
+	Tasks    int     `json:"tasks"`
+	Estimate float64 `json:"estimate"`
+}
+
+// SprintScope holds the current totals of the tasks in a sprint.
+type SprintScope struct {
+	SprintTotals
+	// Tasks in a done-category workflow state.
+	Done SprintTotals `json:"done"`
+	// Set when the estimate of the tasks exceeds the capacity of the sprint.
+	OverCapacity bool `json:"over_capacity"`
+}
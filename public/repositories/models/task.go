@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Task is a unit of work owned by a team.
+//
+// A grouped task is shared with the whole team: it shows up in every member's task list
+// and its assignees are only the people currently driving it. An individual task is
+// visible in the task lists of its assignees only.
+type Task struct {
+	ID          uuid.UUID `json:"id"`
+	TeamID      uuid.UUID `json:"team_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Grouped     bool      `json:"group_task"`
+	// Workflow state of the task; see Workflow.
+	StateID *uuid.UUID `json:"state_id"`
+	// Set on subtasks.
+	ParentID *uuid.UUID `json:"parent_id"`
+	// Project the task belongs to, if any.
+	ProjectID *uuid.UUID `json:"project_id"`
+	// Sprint the task is planned in; nil for tasks in the backlog.
+	SprintID *uuid.UUID `json:"sprint_id"`
+	// Milestone the task is attached to, if any.
+	MilestoneID *uuid.UUID `json:"milestone_id"`
+	// Nil once the creator has been deleted.
+	CreatedBy *uuid.UUID `json:"created_by"`
+	DueAt     *time.Time `json:"due_at"`
+	// Estimated effort in the estimate unit of the team; nil when the task has not been estimated.
+	Estimate    *float64    `json:"estimate"`
+	AssigneeIDs []uuid.UUID `json:"assignee_ids"`
+	LabelIDs    []uuid.UUID `json:"label_ids"`
+	// Values of the team's custom fields by field key; fields without a value are
+	// left out. See CustomField.Normalize for the value types.
+	CustomFields map[string]any `json:"custom_fields"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+
+	// Completion percentage derived from the subtasks; only set on tasks that have
+	// subtasks, by the endpoints that return a single task or its subtasks.
+	Progress *int `json:"progress,omitempty"`
+}
+
+// TaskNode is a task seen as part of a subtask tree.
+type TaskNode struct {
+	ID       uuid.UUID
+	ParentID uuid.UUID
+	// Depth below the root of the tree; children of the root are at depth 1.
+	Depth int
+	// Done is true when the task is in a done-category workflow state.
+	Done bool
+}
+
+// SubtaskProgress computes the completion percentage of the task id from the nodes of
+// its subtree. A leaf counts as 0 or 100 depending on its state, and a task with
+// subtasks is the average of its children. It returns nil when id has no subtasks.
+func SubtaskProgress(nodes []TaskNode, id uuid.UUID) *int {
+	children := map[uuid.UUID][]TaskNode{}
+	for _, n := range nodes {
+		children[n.ParentID] = append(children[n.ParentID], n)
+	}
+	var progress func(id uuid.UUID, depth int) (float64, bool)
+	progress = func(id uuid.UUID, depth int) (float64, bool) {
+		kids := children[id]
+		// The depth guard keeps a corrupted tree from recursing forever.
+		if len(kids) == 0 || depth > len(nodes) {
+			return 0, false
+		}
+		var sum float64
+		for _, k := range kids {
+			if p, ok := progress(k.ID, depth+1); ok {
+				sum += p
+			} else if k.Done {
+				sum += 100
+			}
+		}
+		return sum / float64(len(kids)), true
+	}
+	p, ok := progress(id, 0)
+	if !ok {
+		return nil
+	}
+	pct := int(p)
+	return &pct
+}
+
+// SubtreeHeight is the number of levels of subtasks below the root of nodes.
+func SubtreeHeight(nodes []TaskNode) int {
+	h := 0
+	for _, n := range nodes {
+		h = max(h, n.Depth)
+	}
+	return h
+}
+
+type ChecklistItem struct {
+	ID        uuid.UUID `json:"id"`
+	TaskID    uuid.UUID `json:"task_id"`
+	Content   string    `json:"content"`
+	Done      bool      `json:"done"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
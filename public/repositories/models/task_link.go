@@ -0,0 +1,147 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//swagger:enum TaskLinkKind
+type TaskLinkKind string
+
+const (
+	// LinkBlocks means the from task has to be done before the to task.
+	LinkBlocks    TaskLinkKind = "blocks"
+	LinkRelatesTo TaskLinkKind = "relates_to"
+)
+
+func (k TaskLinkKind) IsValid() bool {
+	switch k {
+	case LinkBlocks, LinkRelatesTo:
+		return true
+	default:
+		return false
+	}
+}
+
+// TaskLink links two tasks of the same team.
+type TaskLink struct {
+	ID         uuid.UUID    `json:"id"`
+	TeamID     uuid.UUID    `json:"team_id"`
+	FromTaskID uuid.UUID    `json:"from_task_id"`
+	ToTaskID   uuid.UUID    `json:"to_task_id"`
+	Kind       TaskLinkKind `json:"kind"`
+	// Nil once the creator has been deleted.
+	CreatedBy *uuid.UUID `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// DependencyNode is a task of the dependency graph.
+type DependencyNode struct {
+	ID       uuid.UUID  `json:"id"`
+	Title    string     `json:"title"`
+	StateID  *uuid.UUID `json:"state_id"`
+	Done     bool       `json:"done"`
+	Estimate *float64   `json:"estimate"`
+	DueAt    *time.Time `json:"due_at"`
+}
+
+// CriticalPath is the chain of blocking tasks with the most remaining work.
+type CriticalPath struct {
+	TaskIDs []uuid.UUID `json:"task_ids"`
+	// Sum of the estimates of the tasks of the path that are not done yet.
+	Estimate float64 `json:"estimate"`
+	// Due date of the last task of the path, if any.
+	DueAt *time.Time `json:"due_at"`
+}
+
+// DependencyGraph is every task of a team that takes part in a link, and the links.
+type DependencyGraph struct {
+	Nodes        []DependencyNode `json:"nodes"`
+	Links        []TaskLink       `json:"links"`
+	CriticalPath CriticalPath     `json:"critical_path"`
+}
+
+// ComputeCriticalPath finds the longest chain of "blocks" links, weighing every task by
+// its estimate. Done and unestimated tasks weigh nothing. Between chains of equal weight
+// the one that has to finish first, by the due date of its last task, wins. Tasks on a
+// cycle are ignored.
+func ComputeCriticalPath(nodes []DependencyNode, links []TaskLink) CriticalPath {
+	byID := make(map[uuid.UUID]*DependencyNode, len(nodes))
+	for i := range nodes {
+		byID[nodes[i].ID] = &nodes[i]
+	}
+	next := map[uuid.UUID][]uuid.UUID{}
+	indegree := map[uuid.UUID]int{}
+	for _, l := range links {
+		if l.Kind != LinkBlocks || byID[l.FromTaskID] == nil || byID[l.ToTaskID] == nil {
+			continue
+		}
+		next[l.FromTaskID] = append(next[l.FromTaskID], l.ToTaskID)
+		indegree[l.ToTaskID]++
+	}
+
+	weight := func(n *DependencyNode) float64 {
+		if n.Done || n.Estimate == nil {
+			return 0
+		}
+		return *n.Estimate
+	}
+	// best[id] is the weight of the heaviest chain ending at id, prev[id] its predecessor.
+	best := make(map[uuid.UUID]float64, len(nodes))
+	prev := map[uuid.UUID]uuid.UUID{}
+	queue := []uuid.UUID{}
+	for _, n := range nodes {
+		if indegree[n.ID] == 0 {
+			queue = append(queue, n.ID)
+			best[n.ID] = weight(byID[n.ID])
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, to := range next[id] {
+			w := best[id] + weight(byID[to])
+			p, seen := prev[to]
+			if !seen || w > best[to] || (w == best[to] && dueBefore(byID[id], byID[p])) {
+				best[to] = w
+				prev[to] = id
+			}
+			indegree[to]--
+			if indegree[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	var end *DependencyNode
+	for i := range nodes {
+		n := &nodes[i]
+		if _, ok := best[n.ID]; !ok || indegree[n.ID] > 0 {
+			continue
+		}
+		if end == nil || best[n.ID] > best[end.ID] || (best[n.ID] == best[end.ID] && dueBefore(n, end)) {
+			end = n
+		}
+	}
+	path := CriticalPath{TaskIDs: []uuid.UUID{}}
+	if end == nil {
+		return path
+	}
+	path.Estimate = best[end.ID]
+	path.DueAt = end.DueAt
+	for id, ok := end.ID, true; ok; id, ok = prev[id] {
+		path.TaskIDs = append(path.TaskIDs, id)
+	}
+	slices.Reverse(path.TaskIDs)
+	return path
+}
+
+// dueBefore reports whether a is due before b; tasks without a due date come last.
+func dueBefore(a, b *DependencyNode) bool {
+	if a.DueAt == nil {
+		return false
+	}
+	return b.DueAt == nil || a.DueAt.Before(*b.DueAt)
+}
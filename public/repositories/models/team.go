@@ -6,34 +6,37 @@ import (
 	"github.com/google/uuid"
 )
 
-type Task struct {
-	ID         uuid.UUID  `json:"id"`
-	TeamID     uuid.UUID  `json:"team_id"`
-	Grouped    bool       `json:"group_task"`
-	UserTaskID *uuid.UUID `json:"user_task_id"`
-}
-
 // TODO: Split into two models
 type Team struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// Set while the team is archived: members can still read it but change nothing.
+	ArchivedAt *time.Time `json:"archived_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (t *Team) Archived() bool {
+	return t.ArchivedAt != nil
 }
 
 type UserTeam struct {
-	ID     uuid.UUID    `json:"id"`
-	TeamID uuid.UUID    `json:"team_id"`
-	UserID uuid.UUID    `json:"user_id"`
-	Role   TeamUserRole `json:"role"`
+	ID       uuid.UUID    `json:"id"`
+	TeamID   uuid.UUID    `json:"team_id"`
+	UserID   uuid.UUID    `json:"user_id"`
+	Role     TeamUserRole `json:"role"`
+	JoinedAt time.Time    `json:"joined_at"`
 }
 
 type Invitation struct {
 	ID     uuid.UUID `json:"id"`
 	TeamID uuid.UUID `json:"team_id"`
-	UserID uuid.UUID `json:"user_id"`
+	// UserID is the invited user
+	UserID     uuid.UUID `json:"user_id"`
+	FromUserID uuid.UUID `json:"from_user_id"`
 	// Can be None means it is not accepted yet
-	Accepted *bool `json:"accepted"`
+	Accepted  *bool     `json:"accepted"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 //swagger:enum TeamUserRole
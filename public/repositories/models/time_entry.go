@@ -0,0 +1,204 @@
+package models
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxTimeEntryDuration is the longest time entry that can be logged by hand.
+const MaxTimeEntryDuration = 24 * time.Hour
+
+// TimeEntry is time a user spent on a task. An entry without an end is the running
+// timer of the user; a user has at most one.
+type TimeEntry struct {
+	ID        uuid.UUID  `json:"id"`
+	TeamID    uuid.UUID  `json:"team_id"`
+	TaskID    uuid.UUID  `json:"task_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	// Whole seconds between the start and the end; 0 while the timer runs.
+	Duration  int64     `json:"duration_seconds"`
+	Note      string    `json:"note"`
+	Billable  bool      `json:"billable"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (e *TimeEntry) Running() bool {
+	return e.EndedAt == nil
+}
+
+// Stop ends the entry at end and sets its duration.
+func (e *TimeEntry) Stop(end time.Time) {
+	e.EndedAt = &end
+	e.Duration = int64(end.Sub(e.StartedAt) / time.Second)
+}
+
+//swagger:enum TimesheetGroup
+type TimesheetGroup string
+
+const (
+	TimesheetByUser    TimesheetGroup = "user"
+	TimesheetByTask    TimesheetGroup = "task"
+	TimesheetByProject TimesheetGroup = "project"
+	TimesheetByTeam    TimesheetGroup = "team"
+)
+
+func (g TimesheetGroup) IsValid() bool {
+	switch g {
+	case TimesheetByUser, TimesheetByTask, TimesheetByProject, TimesheetByTeam:
+		return true
+	default:
+		return false
+	}
+}
+
+//swagger:enum RoundingMode
+type RoundingMode string
+
+const (
+	RoundNone    RoundingMode = "none"
+	RoundUp      RoundingMode = "up"
+	RoundDown    RoundingMode = "down"
+	RoundNearest RoundingMode = "nearest"
+)
+
+func (m RoundingMode) IsValid() bool {
+	switch m {
+	case RoundNone, RoundUp, RoundDown, RoundNearest:
+		return true
+	default:
+		return false
+	}
+}
+
+// Rounding rounds the duration of every entry of a timesheet to a multiple of Minutes.
+type Rounding struct {
+	Mode    RoundingMode `json:"mode"`
+	Minutes int          `json:"minutes"`
+}
+
+// Apply rounds a duration in seconds; halves are rounded up by RoundNearest.
+func (r Rounding) Apply(seconds int64) int64 {
+	step := int64(r.Minutes) * 60
+	if step <= 0 {
+		return seconds
+	}
+	switch r.Mode {
+	case RoundUp:
+		return (seconds + step - 1) / step * step
+	case RoundDown:
+		return seconds / step * step
+	case RoundNearest:
+		return (seconds + step/2) / step * step
+	default:
+		return seconds
+	}
+}
+
+// TimesheetFilter selects the completed time entries of a team that started in
+// [From, To). Nil fields do not filter.
+type TimesheetFilter struct {
+	From      time.Time
+	To        time.Time
+	UserID    *uuid.UUID
+	TaskID    *uuid.UUID
+	ProjectID *uuid.UUID
+	Billable  *bool
+}
+
+// TimesheetEntry is a completed time entry with the names shown in a timesheet.
+type TimesheetEntry struct {
+	TimeEntry
+	UserName  string `json:"user_name"`
+	TaskTitle string `json:"task_title"`
+	// Nil for tasks outside of any project.
+	ProjectID   *uuid.UUID `json:"project_id"`
+	ProjectName string     `json:"project_name"`
+	TeamName    string     `json:"-"`
+	// Duration after the rounding of the timesheet.
+	RoundedSeconds int64 `json:"rounded_seconds"`
+}
+
+// TimesheetTotals are rounded seconds, and the same in hours with two decimals.
+type TimesheetTotals struct {
+	Seconds         int64   `json:"seconds"`
+	BillableSeconds int64   `json:"billable_seconds"`
+	Hours           float64 `json:"hours"`
+	BillableHours   float64 `json:"billable_hours"`
+}
+
+func (t *TimesheetTotals) add(e *TimesheetEntry) {
+	t.Seconds += e.RoundedSeconds
+	if e.Billable {
+		t.BillableSeconds += e.RoundedSeconds
+	}
+	t.Hours = hours(t.Seconds)
+	t.BillableHours = hours(t.BillableSeconds)
+}
+
+func hours(seconds int64) float64 {
+	return math.Round(float64(seconds)/36) / 100
+}
+
+// TimesheetRow is the time of one user, task, project or team.
+type TimesheetRow struct {
+	// Nil for the row of tasks outside of any project.
+	ID   *uuid.UUID `json:"id"`
+	Name string     `json:"name"`
+	TimesheetTotals
+	Entries []*TimesheetEntry `json:"entries"`
+}
+
+type Timesheet struct {
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+	GroupBy  TimesheetGroup `json:"group_by"`
+	Rounding Rounding       `json:"rounding"`
+	TimesheetTotals
+	Rows []*TimesheetRow `json:"rows"`
+}
+
+// BuildTimesheet rounds the duration of every entry and groups the entries into rows
+// ordered by name. Entries keep their order within a row.
+func BuildTimesheet(entries []*TimesheetEntry, group TimesheetGroup, rounding Rounding) *Timesheet {
+	ts := &Timesheet{GroupBy: group, Rounding: rounding, Rows: []*TimesheetRow{}}
+	rows := map[uuid.UUID]*TimesheetRow{}
+	for _, e := range entries {
+		e.RoundedSeconds = rounding.Apply(e.Duration)
+		id, name := e.TeamID, e.TeamName
+		switch group {
+		case TimesheetByUser:
+			id, name = e.UserID, e.UserName
+		case TimesheetByTask:
+			id, name = e.TaskID, e.TaskTitle
+		case TimesheetByProject:
+			// uuid.Nil keys the entries without a project.
+			id, name = uuid.Nil, ""
+			if e.ProjectID != nil {
+				id, name = *e.ProjectID, e.ProjectName
+			}
+		}
+		row := rows[id]
+		if row == nil {
+			row = &TimesheetRow{Name: name}
+			if id != uuid.Nil {
+				row.ID = &id
+			}
+			rows[id] = row
+			ts.Rows = append(ts.Rows, row)
+		}
+		row.Entries = append(row.Entries, e)
+		row.add(e)
+		ts.add(e)
+	}
+	slices.SortStableFunc(ts.Rows, func(a, b *TimesheetRow) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return ts
+}
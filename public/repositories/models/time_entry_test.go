@@ -0,0 +1,66 @@
+package models_test
+
+import (
+	"task_manager/public/repositories/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundingApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		rounding models.Rounding
+		seconds  int64
+		want     int64
+	}{
+		{"none", models.Rounding{Mode: models.RoundNone, Minutes: 15}, 500, 500},
+		{"no increment", models.Rounding{Mode: models.RoundUp}, 500, 500},
+		{"up", models.Rounding{Mode: models.RoundUp, Minutes: 15}, 901, 1800},
+		{"up on a multiple", models.Rounding{Mode: models.RoundUp, Minutes: 15}, 900, 900},
+		{"down", models.Rounding{Mode: models.RoundDown, Minutes: 15}, 1799, 900},
+		{"down below the increment", models.Rounding{Mode: models.RoundDown, Minutes: 15}, 899, 0},
+		{"nearest below half", models.Rounding{Mode: models.RoundNearest, Minutes: 10}, 299, 0},
+		{"nearest at half", models.Rounding{Mode: models.RoundNearest, Minutes: 10}, 300, 600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.rounding.Apply(tt.seconds))
+		})
+	}
+}
+
+func TestBuildTimesheet(t *testing.T) {
+	ann, bob := uuid.New(), uuid.New()
+	project := uuid.New()
+	entry := func(user uuid.UUID, name string, seconds int64, billable bool, projectID *uuid.UUID) *models.TimesheetEntry {
+		e := &models.TimesheetEntry{UserName: name, ProjectID: projectID}
+		if projectID != nil {
+			e.ProjectName = "Web"
+		}
+		e.UserID, e.Duration, e.Billable = user, seconds, billable
+		return e
+	}
+	entries := []*models.TimesheetEntry{
+		entry(bob, "Bob", 1000, true, &project),
+		entry(ann, "Ann", 2000, false, nil),
+		entry(bob, "Bob", 700, false, nil),
+	}
+
+	ts := models.BuildTimesheet(entries, models.TimesheetByUser, models.Rounding{Mode: models.RoundUp, Minutes: 15})
+	require.Len(t, ts.Rows, 2)
+	require.Equal(t, "Ann", ts.Rows[0].Name)
+	require.Equal(t, int64(2700), ts.Rows[0].Seconds)
+	require.Equal(t, "Bob", ts.Rows[1].Name)
+	require.Equal(t, int64(2700), ts.Rows[1].Seconds)
+	require.Equal(t, int64(1800), ts.Rows[1].BillableSeconds)
+	require.Equal(t, 0.75, ts.Rows[1].Hours)
+	require.Equal(t, int64(5400), ts.Seconds)
+
+	ts = models.BuildTimesheet(entries, models.TimesheetByProject, models.Rounding{Mode: models.RoundNone})
+	require.Len(t, ts.Rows, 2)
+	require.Nil(t, ts.Rows[0].ID)
+	require.Equal(t, int64(2700), ts.Rows[0].Seconds)
+	require.Equal(t, project, *ts.Rows[1].ID)
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//swagger:enum TrashKind
+type TrashKind string
+
+const (
+	TrashTeam    TrashKind = "team"
+	TrashProject TrashKind = "project"
+	TrashTask    TrashKind = "task"
+	TrashComment TrashKind = "comment"
+)
+
+// TrashKinds lists the kinds of the team trash, for filters. Deleted teams are listed
+// apart, to their founders.
+var TrashKinds = []TrashKind{TrashProject, TrashTask, TrashComment}
+
+// TrashItem is something deleted that can still be restored. Tasks deleted along with a
+// project or a parent task are not listed on their own; they are restored with it.
+type TrashItem struct {
+	Kind   TrashKind `json:"kind"`
+	ID     uuid.UUID `json:"id"`
+	TeamID uuid.UUID `json:"team_id"`
+	// The task of a comment.
+	TaskID *uuid.UUID `json:"task_id,omitempty"`
+	// The name of a team or project, the title of a task or the start of a comment.
+	Title string `json:"title"`
+	// The creator of a task or the author of a comment.
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	// The number of tasks a restore brings back: the task with its subtasks, or the
+	// tasks of the project with theirs.
+	TaskCount int       `json:"task_count"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
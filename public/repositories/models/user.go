@@ -31,6 +31,33 @@ type User struct {
 	UserType  UserType  `json:"user_type"` // "admin" | "standard"
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Set by a system administrator; disabled users cannot log in.
+	DisabledAt            *time.Time `json:"disabled_at,omitempty"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+}
+
+func (u *User) IsDisabled() bool {
+	return u != nil && u.DisabledAt != nil
+}
+
+// UserListFilter narrows down the users returned by ListUsers.
+type UserListFilter struct {
+	// Query matches (case-insensitive) against email, first name and last name.
+	Query    string
+	UserType UserType
+	Disabled *bool
+	Limit    int
+	Offset   int
+}
+
+// PasswordResetToken is a one-time token issued when an administrator forces a password reset.
+// Only the SHA-256 hash of the token is stored.
+type PasswordResetToken struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	TokenHash string    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type AuthProvider struct {
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+//swagger:enum WorkflowCategory
+type WorkflowCategory string
+
+const (
+	CategoryNotStarted WorkflowCategory = "not_started"
+	CategoryActive     WorkflowCategory = "active"
+	CategoryDone       WorkflowCategory = "done"
+)
+
+func (c WorkflowCategory) IsValid() bool {
+	switch c {
+	case CategoryNotStarted, CategoryActive, CategoryDone:
+		return true
+	default:
+		return false
+	}
+}
+
+type WorkflowState struct {
+	ID       uuid.UUID        `json:"id"`
+	TeamID   uuid.UUID        `json:"team_id"`
+	Name     string           `json:"name"`
+	Category WorkflowCategory `json:"category"`
+	Position int              `json:"position"`
+}
+
+// WorkflowTransition allows moving tasks from one state to another.
+type WorkflowTransition struct {
+	ID          uuid.UUID `json:"id"`
+	TeamID      uuid.UUID `json:"team_id"`
+	FromStateID uuid.UUID `json:"from_state_id"`
+	ToStateID   uuid.UUID `json:"to_state_id"`
+	// Team roles allowed to make the transition; empty means every member.
+	Roles []TeamUserRole `json:"roles"`
+}
+
+func (t *WorkflowTransition) Allows(role TeamUserRole) bool {
+	return len(t.Roles) == 0 || slices.Contains(t.Roles, role)
+}
+
+// Workflow is the ordered list of states of a team and the transitions between them.
+// A workflow without transitions allows every move.
+type Workflow struct {
+	States      []WorkflowState      `json:"states"`
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+func (w *Workflow) State(id uuid.UUID) *WorkflowState {
+	for i := range w.States {
+		if w.States[i].ID == id {
+			return &w.States[i]
+		}
+	}
+	return nil
+}
+
+// InitialState is the state new tasks start in: the first not-started state.
+func (w *Workflow) InitialState() *WorkflowState {
+	for i := range w.States {
+		if w.States[i].Category == CategoryNotStarted {
+			return &w.States[i]
+		}
+	}
+	return nil
+}
+
+// Transition looks up the move from one state to another. ok is false when the
+// transition graph does not allow it; t is nil when the workflow has no graph.
+func (w *Workflow) Transition(from uuid.UUID, to uuid.UUID) (t *WorkflowTransition, ok bool) {
+	if len(w.Transitions) == 0 {
+		return nil, true
+	}
+	for i := range w.Transitions {
+		if w.Transitions[i].FromStateID == from && w.Transitions[i].ToStateID == to {
+			return &w.Transitions[i], true
+		}
+	}
+	return nil, false
+}
+
+// DefaultWorkflow is created together with every new team.
+func DefaultWorkflow(teamID uuid.UUID) *Workflow {
+	states := []WorkflowState{
+		{Name: "To Do", Category: CategoryNotStarted},
+		{Name: "In Progress", Category: CategoryActive},
+		{Name: "Done", Category: CategoryDone},
+	}
+	for i := range states {
+		states[i].ID = uuid.New()
+		states[i].TeamID = teamID
+		states[i].Position = i
+	}
+	return &Workflow{States: states, Transitions: []WorkflowTransition{}}
+}
@@ -0,0 +1,199 @@
+package models
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//swagger:enum EstimateUnit
+type EstimateUnit string
+
+const (
+	EstimatePoints EstimateUnit = "points"
+	EstimateHours  EstimateUnit = "hours"
+)
+
+func (u EstimateUnit) IsValid() bool {
+	switch u {
+	case EstimatePoints, EstimateHours:
+		return true
+	default:
+		return false
+	}
+}
+
+// Estimation is how a team estimates its tasks. Task estimates, sprint capacities and
+// weekly capacities are all in Unit.
+type Estimation struct {
+	Unit EstimateUnit `json:"unit"`
+	// Estimate a member can take on per week; nil when members have no capacity.
+	WeeklyCapacity *float64         `json:"weekly_capacity"`
+	Members        []MemberCapacity `json:"members"`
+}
+
+// MemberCapacity is the weekly capacity of a team member.
+type MemberCapacity struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	// Overrides the weekly capacity of the team; nil uses the team's.
+	WeeklyCapacity *float64 `json:"weekly_capacity"`
+}
+
+// Capacity is the weekly capacity of the member under est, nil when unlimited.
+func (m MemberCapacity) Capacity(est *Estimation) *float64 {
+	if m.WeeklyCapacity != nil {
+		return m.WeeklyCapacity
+	}
+	return est.WeeklyCapacity
+}
+
+// WorkloadTask is an open task with assignees, as far as the workload is concerned.
+type WorkloadTask struct {
+	ID       uuid.UUID
+	Estimate *float64
+	DueAt    *time.Time
+	// Dates of the planned or active sprint of the task; nil outside of such a sprint.
+	SprintStart *time.Time
+	SprintEnd   *time.Time
+	AssigneeIDs []uuid.UUID
+}
+
+type WorkloadWeek struct {
+	// Monday 00:00 UTC.
+	Start time.Time `json:"week_start"`
+	Load  float64   `json:"load"`
+	// Number of tasks adding to the load.
+	Tasks         int  `json:"tasks"`
+	OverAllocated bool `json:"over_allocated"`
+}
+
+type MemberWorkload struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	// Nil when the member has no capacity.
+	WeeklyCapacity *float64        `json:"weekly_capacity"`
+	Weeks          []*WorkloadWeek `json:"weeks"`
+	// Estimate of the assigned tasks with neither a due date nor a sprint.
+	Unscheduled float64 `json:"unscheduled"`
+	// Number of assigned open tasks without an estimate.
+	Unestimated   int  `json:"unestimated_tasks"`
+	OverAllocated bool `json:"over_allocated"`
+}
+
+type Workload struct {
+	Unit    EstimateUnit      `json:"unit"`
+	From    time.Time         `json:"from"`
+	Weeks   int               `json:"weeks"`
+	Members []*MemberWorkload `json:"members"`
+}
+
+// WeekStart is the Monday 00:00 UTC of the week of t.
+func WeekStart(t time.Time) time.Time {
+	day := t.UTC().Truncate(24 * time.Hour)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// BuildWorkload spreads the estimates of the open tasks over the members of the team,
+// for the weeks starting with the week of from. The estimate of a task is shared evenly
+// by its assignees. A task in a sprint loads the days of the sprint that are left, from
+// its start date to its end date; other tasks load the week they are due. Overdue tasks
+// and tasks of sprints that are over load the first week, and load beyond the last week
+// is left out.
+func BuildWorkload(est *Estimation, tasks []*WorkloadTask, from time.Time, weeks int) *Workload {
+	from = WeekStart(from)
+	end := from.AddDate(0, 0, 7*weeks)
+	w := &Workload{Unit: est.Unit, From: from, Weeks: weeks, Members: []*MemberWorkload{}}
+	byUser := make(map[uuid.UUID]*MemberWorkload, len(est.Members))
+	for _, m := range est.Members {
+		mw := &MemberWorkload{UserID: m.UserID, Name: m.Name, WeeklyCapacity: m.Capacity(est), Weeks: make([]*WorkloadWeek, weeks)}
+		for i := range mw.Weeks {
+			mw.Weeks[i] = &WorkloadWeek{Start: from.AddDate(0, 0, 7*i)}
+		}
+		byUser[m.UserID] = mw
+		w.Members = append(w.Members, mw)
+	}
+
+	for _, t := range tasks {
+		var members []*MemberWorkload
+		for _, id := range t.AssigneeIDs {
+			if mw := byUser[id]; mw != nil {
+				members = append(members, mw)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		if t.Estimate == nil {
+			for _, mw := range members {
+				mw.Unestimated++
+			}
+			continue
+		}
+		share := *t.Estimate / float64(len(members))
+		// Share of the estimate falling into every week.
+		load := make([]float64, weeks)
+		switch {
+		case t.SprintStart != nil && t.SprintEnd != nil:
+			start := t.SprintStart.UTC().Truncate(24 * time.Hour)
+			if start.Before(from) {
+				start = from
+			}
+			stop := t.SprintEnd.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+			if !stop.After(start) {
+				load[0] = share
+				break
+			}
+			span := stop.Sub(start)
+			for i := range load {
+				ws, we := from.AddDate(0, 0, 7*i), from.AddDate(0, 0, 7*(i+1))
+				if ws.Before(start) {
+					ws = start
+				}
+				if stop.Before(we) {
+					we = stop
+				}
+				if overlap := we.Sub(ws); overlap > 0 {
+					load[i] = share * float64(overlap) / float64(span)
+				}
+			}
+		case t.DueAt != nil:
+			if t.DueAt.Before(end) {
+				load[max(0, int(t.DueAt.Sub(from)/(7*24*time.Hour)))] = share
+			}
+		default:
+			for _, mw := range members {
+				mw.Unscheduled += share
+			}
+			continue
+		}
+		for _, mw := range members {
+			for i, l := range load {
+				if l > 0 {
+					mw.Weeks[i].Load += l
+					mw.Weeks[i].Tasks++
+				}
+			}
+		}
+	}
+
+	for _, mw := range w.Members {
+		mw.Unscheduled = round2(mw.Unscheduled)
+		for _, week := range mw.Weeks {
+			week.Load = round2(week.Load)
+			week.OverAllocated = mw.WeeklyCapacity != nil && week.Load > *mw.WeeklyCapacity
+			mw.OverAllocated = mw.OverAllocated || week.OverAllocated
+		}
+	}
+	slices.SortStableFunc(w.Members, func(a, b *MemberWorkload) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return w
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
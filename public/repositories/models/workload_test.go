@@ -0,0 +1,55 @@
+package models_test
+
+import (
+	"task_manager/public/repositories/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeekStart(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	for _, day := range []time.Time{monday, monday.Add(13 * time.Hour), monday.AddDate(0, 0, 6).Add(23 * time.Hour)} {
+		require.Equal(t, monday, models.WeekStart(day), day.String())
+	}
+	require.Equal(t, monday.AddDate(0, 0, 7), models.WeekStart(monday.AddDate(0, 0, 7)))
+}
+
+func TestBuildWorkload(t *testing.T) {
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	user := uuid.New()
+	at := func(days int) *time.Time {
+		d := from.AddDate(0, 0, days)
+		return &d
+	}
+	estimate := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name string
+		task models.WorkloadTask
+		want []float64
+	}{
+		{"due in the second week", models.WorkloadTask{Estimate: estimate(3), DueAt: at(9)}, []float64{0, 3}},
+		{"overdue", models.WorkloadTask{Estimate: estimate(3), DueAt: at(-20)}, []float64{3, 0}},
+		{"due after the last week", models.WorkloadTask{Estimate: estimate(3), DueAt: at(14)}, []float64{0, 0}},
+		{"sprint over both weeks", models.WorkloadTask{Estimate: estimate(10), SprintStart: at(5), SprintEnd: at(9)}, []float64{4, 6}},
+		{"sprint started last week", models.WorkloadTask{Estimate: estimate(6), SprintStart: at(-7), SprintEnd: at(2)}, []float64{6, 0}},
+		{"sprint that is over", models.WorkloadTask{Estimate: estimate(6), SprintStart: at(-14), SprintEnd: at(-8)}, []float64{6, 0}},
+		{"sprint beyond the last week", models.WorkloadTask{Estimate: estimate(6), SprintStart: at(7), SprintEnd: at(20)}, []float64{0, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.task.AssigneeIDs = []uuid.UUID{user}
+			est := &models.Estimation{Members: []models.MemberCapacity{{UserID: user}}}
+			w := models.BuildWorkload(est, []*models.WorkloadTask{&tt.task}, from, 2)
+			require.Len(t, w.Members, 1)
+			got := make([]float64, len(w.Members[0].Weeks))
+			for i, week := range w.Members[0].Weeks {
+				got[i] = week.Load
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package postgress
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	dbx "task_manager/public/db"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories/listspec"
+	"task_manager/public/repositories/models"
+
+	"github.com/google/uuid"
+)
+
+type ActivityRepository struct {
+	db dbx.DBTX
+}
+
+func NewActivityRepository(db dbx.DBTX) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+const activityColumns = `ta.id, ta.team_id, ta.task_id, ta.actor_id, ta.action, ta.changes, ta.details, COALESCE(ta.trace_id, ''), ta.created_at`
+
+func scanActivity(s rowScanner) (*models.TaskActivity, error) {
+	var a models.TaskActivity
+	var changes string
+	var details sql.NullString
+	if err := s.Scan(&a.ID, &a.TeamID, &a.TaskID, &a.ActorID, &a.Action, &changes, &details, &a.TraceID, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(changes), &a.Changes); err != nil {
+		return nil, err
+	}
+	if details.Valid && details.String != "" {
+		if err := json.Unmarshal([]byte(details.String), &a.Details); err != nil {
+			return nil, err
+		}
+	}
+	return &a, nil
+}
+
+func (r *ActivityRepository) CreateTaskActivity(ctx context.Context, a *models.TaskActivity) error {
+	if a.Changes == nil {
+		a.Changes = []models.FieldChange{}
+	}
+	changes, err := json.Marshal(a.Changes)
+	if err != nil {
+		return err
+	}
+	var details *string
+	if len(a.Details) > 0 {
+		b, err := json.Marshal(a.Details)
+		if err != nil {
+			return err
+		}
+		s := string(b)
+		details = &s
+	}
+	_, err = r.db.ExecContext(
+		ctx,
+		`INSERT INTO task_activities (id, team_id, task_id, actor_id, action, changes, details, trace_id, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9)`,
+		a.ID,
+		a.TeamID,
+		a.TaskID,
+		a.ActorID,
+		string(a.Action),
+		string(changes),
+		details,
+		a.TraceID,
+		a.CreatedAt,
+	)
+	return TranslateError(err)
+}
+
+func (r *ActivityRepository) ListTaskActivity(ctx context.Context, taskID uuid.UUID, q listquery.Query) (listquery.Result[*models.TaskActivity], error) {
+	const from = `FROM task_activities ta WHERE ta.task_id = $1`
+	s := q.Build(dialect, taskID)
+	rows, err := r.db.QueryContext(ctx, `SELECT `+activityColumns+` `+from+` AND `+s.Where+s.Suffix(), s.Args...)
+	if err != nil {
+		return listquery.Result[*models.TaskActivity]{}, TranslateError(err)
+	}
+	defer rows.Close()
+
+	var items []*models.TaskActivity
+	for rows.Next() {
+		a, err := scanActivity(rows)
+		if err != nil {
+			return listquery.Result[*models.TaskActivity]{}, err
+		}
+		items = append(items, a)
+	}
+	if err := rows.Err(); err != nil {
+		return listquery.Result[*models.TaskActivity]{}, err
+	}
+	res := listquery.Paginate(q, items, listspec.TaskActivityRow)
+	res.Total, err = listTotal(ctx, r.db, q, from, s)
+	return res, err
+}
@@ -0,0 +1,136 @@
+package postgress
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AttachmentRepository struct {
+	db dbx.DBTX
+}
+
+func NewAttachmentRepository(db dbx.DBTX) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+const attachmentColumns = `id, team_id, task_id, comment_id, uploaded_by, filename, content_type, size_bytes, storage_key, created_at`
+
+func scanAttachment(s rowScanner) (*models.Attachment, error) {
+	var a models.Attachment
+	if err := s.Scan(&a.ID, &a.TeamID, &a.TaskID, &a.CommentID, &a.UploadedBy, &a.Filename, &a.ContentType, &a.SizeBytes, &a.StorageKey, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AttachmentRepository) CreateAttachment(ctx context.Context, a *models.Attachment) error {
+	a.CreatedAt = time.Now()
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO attachments (id, team_id, task_id, comment_id, uploaded_by, filename, content_type, size_bytes, storage_key, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		a.ID,
+		a.TeamID,
+		a.TaskID,
+		a.CommentID,
+		a.UploadedBy,
+		a.Filename,
+		a.ContentType,
+		a.SizeBytes,
+		a.StorageKey,
+		a.CreatedAt,
+	)
+	return TranslateError(err)
+}
+
+func (r *AttachmentRepository) GetAttachment(ctx context.Context, taskID uuid.UUID, attachmentID uuid.UUID) (*models.Attachment, error) {
+	a, err := scanAttachment(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+attachmentColumns+` FROM attachments WHERE id = $1 AND task_id = $2`,
+		attachmentID,
+		taskID,
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return a, nil
+}
+
+func (r *AttachmentRepository) ListTaskAttachments(ctx context.Context, taskID uuid.UUID) ([]*models.Attachment, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+attachmentColumns+` FROM attachments WHERE task_id = $1 ORDER BY created_at, id`,
+		taskID,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	attachments := []*models.Attachment{}
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+func (r *AttachmentRepository) DeleteAttachment(ctx context.Context, taskID uuid.UUID, attachmentID uuid.UUID) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`DELETE FROM attachments WHERE id = $1 AND task_id = $2`,
+		attachmentID,
+		taskID,
+	)
+	return expectAffected(res, err)
+}
+
+func (r *AttachmentRepository) LockTeamStorage(ctx context.Context, teamID uuid.UUID) error {
+	var locked uuid.UUID
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM teams WHERE id = $1 FOR UPDATE`, teamID).Scan(&locked)
+	return TranslateError(err)
+}
+
+func (r *AttachmentRepository) TeamStorageUsed(ctx context.Context, teamID uuid.UUID) (int64, error) {
+	var used int64
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT COALESCE(SUM(size_bytes), 0)::BIGINT FROM attachments WHERE team_id = $1`,
+		teamID,
+	).Scan(&used)
+	return used, TranslateError(err)
+}
+
+func (r *AttachmentRepository) ListBlobDeletions(ctx context.Context, limit int) ([]*models.BlobDeletion, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, storage_key, created_at FROM blob_deletions ORDER BY id LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	var deletions []*models.BlobDeletion
+	for rows.Next() {
+		var d models.BlobDeletion
+		if err := rows.Scan(&d.ID, &d.StorageKey, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		deletions = append(deletions, &d)
+	}
+	return deletions, rows.Err()
+}
+
+func (r *AttachmentRepository) DeleteBlobDeletion(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM blob_deletions WHERE id = $1`, id)
+	return TranslateError(err)
+}
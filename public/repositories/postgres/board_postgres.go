@@ -0,0 +1,270 @@
+package postgress
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BoardRepository struct {
+	db dbx.DBTX
+}
+
+func NewBoardRepository(db dbx.DBTX) *BoardRepository {
+	return &BoardRepository{db: db}
+}
+
+const boardColumns = `id, team_id, name, swimlane_by, swimlane_field_id, created_by, created_at, updated_at`
+
+const boardColumnColumns = `id, board_id, state_id, name, position, wip_limit, wip_mode`
+
+// cardOrder sorts the tasks of a column: ranked cards first, then the others oldest first.
+const cardOrder = `ORDER BY bc.rank IS NULL, bc.rank, tk.created_at, tk.id`
+
+func scanBoard(s rowScanner) (*models.Board, error) {
+	var b models.Board
+	if err := s.Scan(&b.ID, &b.TeamID, &b.Name, &b.SwimlaneBy, &b.SwimlaneFieldID, &b.CreatedBy, &b.CreatedAt, &b.UpdatedAt); err != nil {
+		return nil, err
+	}
+	b.Columns = []models.BoardColumn{}
+	return &b, nil
+}
+
+func (r *BoardRepository) ListBoards(ctx context.Context, teamID uuid.UUID) ([]*models.Board, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+boardColumns+` FROM boards WHERE team_id = $1 ORDER BY name, id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	boards := []*models.Board{}
+	for rows.Next() {
+		b, err := scanBoard(rows)
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return boards, r.loadColumns(ctx, boards)
+}
+
+func (r *BoardRepository) GetBoard(ctx context.Context, teamID uuid.UUID, boardID uuid.UUID) (*models.Board, error) {
+	b, err := scanBoard(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+boardColumns+` FROM boards WHERE id = $1 AND team_id = $2`,
+		boardID,
+		teamID,
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return b, r.loadColumns(ctx, []*models.Board{b})
+}
+
+func (r *BoardRepository) loadColumns(ctx context.Context, boards []*models.Board) error {
+	if len(boards) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Board, len(boards))
+	args := make([]any, 0, len(boards))
+	for _, b := range boards {
+		byID[b.ID] = b
+		args = append(args, b.ID)
+	}
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+boardColumnColumns+` FROM board_columns
+		 WHERE board_id IN (`+placeholders(1, len(args))+`)
+		 ORDER BY position, id`,
+		args...,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c models.BoardColumn
+		if err := rows.Scan(&c.ID, &c.BoardID, &c.StateID, &c.Name, &c.Position, &c.WIPLimit, &c.WIPMode); err != nil {
+			return err
+		}
+		byID[c.BoardID].Columns = append(byID[c.BoardID].Columns, c)
+	}
+	return rows.Err()
+}
+
+func (r *BoardRepository) CreateBoard(ctx context.Context, b *models.Board) error {
+	now := time.Now()
+	b.CreatedAt = now
+	b.UpdatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO boards (id, team_id, name, swimlane_by, swimlane_field_id, created_by, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		b.ID,
+		b.TeamID,
+		b.Name,
+		string(b.SwimlaneBy),
+		b.SwimlaneFieldID,
+		b.CreatedBy,
+		now,
+		now,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	return r.SetBoardColumns(ctx, b.ID, b.Columns)
+}
+
+func (r *BoardRepository) UpdateBoard(ctx context.Context, b *models.Board) error {
+	now := time.Now()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE boards SET name = $1, swimlane_by = $2, swimlane_field_id = $3, updated_at = $4 WHERE id = $5 AND team_id = $6`,
+		b.Name,
+		string(b.SwimlaneBy),
+		b.SwimlaneFieldID,
+		now,
+		b.ID,
+		b.TeamID,
+	)
+	if err := expectAffected(res, err); err != nil {
+		return err
+	}
+	b.UpdatedAt = now
+	return nil
+}
+
+func (r *BoardRepository) SetBoardColumns(ctx context.Context, boardID uuid.UUID, columns []models.BoardColumn) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM board_columns WHERE board_id = $1`, boardID); err != nil {
+		return TranslateError(err)
+	}
+	for i := range columns {
+		c := &columns[i]
+		c.BoardID = boardID
+		c.Position = i
+		_, err := r.db.ExecContext(
+			ctx,
+			`INSERT INTO board_columns (id, board_id, state_id, name, position, wip_limit, wip_mode) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			c.ID,
+			boardID,
+			c.StateID,
+			c.Name,
+			c.Position,
+			c.WIPLimit,
+			string(c.WIPMode),
+		)
+		if err != nil {
+			return TranslateError(err)
+		}
+	}
+	return nil
+}
+
+func (r *BoardRepository) DeleteBoard(ctx context.Context, teamID uuid.UUID, boardID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM boards WHERE id = $1 AND team_id = $2`, boardID, teamID)
+	return expectAffected(res, err)
+}
+
+func (r *BoardRepository) LockBoard(ctx context.Context, teamID uuid.UUID, boardID uuid.UUID) error {
+	var locked uuid.UUID
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT id FROM boards WHERE id = $1 AND team_id = $2 FOR UPDATE`,
+		boardID,
+		teamID,
+	).Scan(&locked)
+	return TranslateError(err)
+}
+
+func (r *BoardRepository) ListColumnCards(ctx context.Context, b *models.Board, stateID uuid.UUID) ([]models.BoardCard, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT tk.id, COALESCE(bc.rank, '') FROM tasks tk
+		 LEFT JOIN board_cards bc ON bc.board_id = $1 AND bc.task_id = tk.id
+		 WHERE tk.team_id = $2 AND tk.state_id = $3 AND tk.deleted_at IS NULL `+cardOrder,
+		b.ID,
+		b.TeamID,
+		stateID,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	cards := []models.BoardCard{}
+	for rows.Next() {
+		var c models.BoardCard
+		if err := rows.Scan(&c.TaskID, &c.Rank); err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, rows.Err()
+}
+
+func (r *BoardRepository) ListColumnTasks(ctx context.Context, b *models.Board, stateID uuid.UUID, limit int) ([]*models.Task, int, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+taskColumns+` FROM tasks tk
+		 LEFT JOIN board_cards bc ON bc.board_id = $1 AND bc.task_id = tk.id
+		 WHERE tk.team_id = $2 AND tk.state_id = $3 AND tk.deleted_at IS NULL `+cardOrder+` LIMIT $4`,
+		b.ID,
+		b.TeamID,
+		stateID,
+		limit,
+	)
+	if err != nil {
+		return nil, 0, TranslateError(err)
+	}
+	defer rows.Close()
+
+	tasks := []*models.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if err := (&TaskRepository{db: r.db}).loadRelations(ctx, tasks); err != nil {
+		return nil, 0, err
+	}
+	count, err := r.CountStateTasks(ctx, b.TeamID, stateID)
+	return tasks, count, err
+}
+
+func (r *BoardRepository) CountStateTasks(ctx context.Context, teamID uuid.UUID, stateID uuid.UUID) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*) FROM tasks WHERE team_id = $1 AND state_id = $2 AND deleted_at IS NULL`,
+		teamID,
+		stateID,
+	).Scan(&n)
+	return n, TranslateError(err)
+}
+
+func (r *BoardRepository) SetCardRank(ctx context.Context, boardID uuid.UUID, taskID uuid.UUID, rank string) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO board_cards (board_id, task_id, rank) VALUES ($1, $2, $3)
+		 ON CONFLICT (board_id, task_id) DO UPDATE SET rank = excluded.rank`,
+		boardID,
+		taskID,
+		rank,
+	)
+	return TranslateError(err)
+}
@@ -0,0 +1,117 @@
+package postgress
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ChecklistRepository struct {
+	db dbx.DBTX
+}
+
+func NewChecklistRepository(db dbx.DBTX) *ChecklistRepository {
+	return &ChecklistRepository{db: db}
+}
+
+func (r *ChecklistRepository) ListChecklistItems(ctx context.Context, taskID uuid.UUID) ([]*models.ChecklistItem, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, task_id, content, done, position, created_at, updated_at
+		 FROM task_checklist_items WHERE task_id = $1 ORDER BY position, id`,
+		taskID,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	items := []*models.ChecklistItem{}
+	for rows.Next() {
+		var it models.ChecklistItem
+		if err := rows.Scan(&it.ID, &it.TaskID, &it.Content, &it.Done, &it.Position, &it.CreatedAt, &it.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &it)
+	}
+	return items, rows.Err()
+}
+
+func (r *ChecklistRepository) GetChecklistItem(ctx context.Context, taskID uuid.UUID, itemID uuid.UUID) (*models.ChecklistItem, error) {
+	var it models.ChecklistItem
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT id, task_id, content, done, position, created_at, updated_at
+		 FROM task_checklist_items WHERE id = $1 AND task_id = $2`,
+		itemID,
+		taskID,
+	).Scan(&it.ID, &it.TaskID, &it.Content, &it.Done, &it.Position, &it.CreatedAt, &it.UpdatedAt)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return &it, nil
+}
+
+func (r *ChecklistRepository) CreateChecklistItem(ctx context.Context, item *models.ChecklistItem) error {
+	now := time.Now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	err := r.db.QueryRowContext(
+		ctx,
+		`INSERT INTO task_checklist_items (id, task_id, content, done, position, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position) + 1, 0) FROM task_checklist_items WHERE task_id = $5), $6, $7)
+		 RETURNING position`,
+		item.ID,
+		item.TaskID,
+		item.Content,
+		item.Done,
+		item.TaskID,
+		now,
+		now,
+	).Scan(&item.Position)
+	return TranslateError(err)
+}
+
+func (r *ChecklistRepository) UpdateChecklistItem(ctx context.Context, item *models.ChecklistItem) error {
+	now := time.Now()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE task_checklist_items SET content = $1, done = $2, updated_at = $3 WHERE id = $4 AND task_id = $5`,
+		item.Content,
+		item.Done,
+		now,
+		item.ID,
+		item.TaskID,
+	)
+	item.UpdatedAt = now
+	return expectAffected(res, err)
+}
+
+func (r *ChecklistRepository) DeleteChecklistItem(ctx context.Context, taskID uuid.UUID, itemID uuid.UUID) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`DELETE FROM task_checklist_items WHERE id = $1 AND task_id = $2`,
+		itemID,
+		taskID,
+	)
+	return expectAffected(res, err)
+}
+
+func (r *ChecklistRepository) ReorderChecklistItems(ctx context.Context, taskID uuid.UUID, itemIDs []uuid.UUID) error {
+	for i, id := range itemIDs {
+		res, err := r.db.ExecContext(
+			ctx,
+			`UPDATE task_checklist_items SET position = $1 WHERE id = $2 AND task_id = $3`,
+			i,
+			id,
+			taskID,
+		)
+		if err := expectAffected(res, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
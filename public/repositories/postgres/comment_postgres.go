@@ -0,0 +1,235 @@
+package postgress
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories/listspec"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CommentRepository struct {
+	db dbx.DBTX
+}
+
+func NewCommentRepository(db dbx.DBTX) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+const commentColumns = `cm.id, cm.team_id, cm.task_id, cm.parent_id, cm.author_id, cm.body, cm.body_html, cm.created_at, cm.updated_at, cm.edited_at, cm.deleted_at`
+
+func scanComment(s rowScanner) (*models.Comment, error) {
+	var c models.Comment
+	if err := s.Scan(&c.ID, &c.TeamID, &c.TaskID, &c.ParentID, &c.AuthorID, &c.Body, &c.BodyHTML, &c.CreatedAt, &c.UpdatedAt, &c.EditedAt, &c.DeletedAt); err != nil {
+		return nil, err
+	}
+	c.MentionIDs = []uuid.UUID{}
+	return &c, nil
+}
+
+func (r *CommentRepository) CreateComment(ctx context.Context, c *models.Comment) error {
+	now := time.Now()
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO task_comments (id, team_id, task_id, parent_id, author_id, body, body_html, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		c.ID,
+		c.TeamID,
+		c.TaskID,
+		c.ParentID,
+		c.AuthorID,
+		c.Body,
+		c.BodyHTML,
+		now,
+		now,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	return r.saveMentions(ctx, c)
+}
+
+func (r *CommentRepository) GetComment(ctx context.Context, taskID uuid.UUID, commentID uuid.UUID) (*models.Comment, error) {
+	c, err := scanComment(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+commentColumns+` FROM task_comments cm WHERE cm.id = $1 AND cm.task_id = $2`,
+		commentID,
+		taskID,
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	if err := r.loadMentions(ctx, []*models.Comment{c}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UpdateComment saves the new body of c and keeps the previous one as a revision.
+func (r *CommentRepository) UpdateComment(ctx context.Context, c *models.Comment, editedBy uuid.UUID) error {
+	now := time.Now()
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO task_comment_revisions (id, comment_id, body, edited_by, edited_at)
+		 SELECT $1::uuid, id, body, $2::uuid, $3::timestamptz FROM task_comments WHERE id = $4 AND task_id = $5`,
+		uuid.New(),
+		editedBy,
+		now,
+		c.ID,
+		c.TaskID,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE task_comments SET body = $1, body_html = $2, edited_at = $3, updated_at = $4
+		 WHERE id = $5 AND task_id = $6 AND deleted_at IS NULL`,
+		c.Body,
+		c.BodyHTML,
+		now,
+		now,
+		c.ID,
+		c.TaskID,
+	)
+	if err := expectAffected(res, err); err != nil {
+		return err
+	}
+	c.EditedAt = &now
+	c.UpdatedAt = now
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM task_comment_mentions WHERE comment_id = $1`, c.ID); err != nil {
+		return TranslateError(err)
+	}
+	return r.saveMentions(ctx, c)
+}
+
+func (r *CommentRepository) SoftDeleteComment(ctx context.Context, taskID uuid.UUID, commentID uuid.UUID) error {
+	now := time.Now()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE task_comments SET deleted_at = $1, updated_at = $2 WHERE id = $3 AND task_id = $4 AND deleted_at IS NULL`,
+		now,
+		now,
+		commentID,
+		taskID,
+	)
+	return expectAffected(res, err)
+}
+
+// RestoreComment only finds comments whose content the purge has not erased.
+func (r *CommentRepository) RestoreComment(ctx context.Context, taskID uuid.UUID, commentID uuid.UUID) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE task_comments SET deleted_at = NULL, updated_at = $1 WHERE id = $2 AND task_id = $3 AND deleted_at IS NOT NULL AND body <> ''`,
+		time.Now(),
+		commentID,
+		taskID,
+	)
+	return expectAffected(res, err)
+}
+
+func (r *CommentRepository) ListTaskComments(ctx context.Context, taskID uuid.UUID, q listquery.Query) (listquery.Result[*models.Comment], error) {
+	const from = `FROM task_comments cm WHERE cm.task_id = $1`
+	s := q.Build(dialect, taskID)
+	rows, err := r.db.QueryContext(ctx, `SELECT `+commentColumns+` `+from+` AND `+s.Where+s.Suffix(), s.Args...)
+	if err != nil {
+		return listquery.Result[*models.Comment]{}, TranslateError(err)
+	}
+	defer rows.Close()
+
+	var comments []*models.Comment
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return listquery.Result[*models.Comment]{}, err
+		}
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return listquery.Result[*models.Comment]{}, err
+	}
+	res := listquery.Paginate(q, comments, listspec.CommentRow)
+	if err := r.loadMentions(ctx, res.Items); err != nil {
+		return listquery.Result[*models.Comment]{}, err
+	}
+	res.Total, err = listTotal(ctx, r.db, q, from, s)
+	return res, err
+}
+
+// ListCommentRevisions returns the previous bodies of the comment, oldest first.
+func (r *CommentRepository) ListCommentRevisions(ctx context.Context, commentID uuid.UUID) ([]*models.CommentRevision, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, comment_id, body, edited_by, edited_at FROM task_comment_revisions WHERE comment_id = $1 ORDER BY edited_at, id`,
+		commentID,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	revisions := []*models.CommentRevision{}
+	for rows.Next() {
+		var rev models.CommentRevision
+		if err := rows.Scan(&rev.ID, &rev.CommentID, &rev.Body, &rev.EditedBy, &rev.EditedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &rev)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *CommentRepository) saveMentions(ctx context.Context, c *models.Comment) error {
+	for _, userID := range c.MentionIDs {
+		_, err := r.db.ExecContext(
+			ctx,
+			`INSERT INTO task_comment_mentions (comment_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			c.ID,
+			userID,
+		)
+		if err != nil {
+			return TranslateError(err)
+		}
+	}
+	return nil
+}
+
+// loadMentions fills MentionIDs of comments with one query.
+func (r *CommentRepository) loadMentions(ctx context.Context, comments []*models.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Comment, len(comments))
+	args := make([]any, 0, len(comments))
+	for _, c := range comments {
+		byID[c.ID] = c
+		args = append(args, c.ID)
+	}
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT comment_id, user_id FROM task_comment_mentions
+		 WHERE comment_id IN (`+placeholders(1, len(args))+`)
+		 ORDER BY user_id`,
+		args...,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var commentID, userID uuid.UUID
+		if err := rows.Scan(&commentID, &userID); err != nil {
+			return err
+		}
+		if c, ok := byID[commentID]; ok {
+			c.MentionIDs = append(c.MentionIDs, userID)
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,257 @@
+package postgress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CustomFieldRepository struct {
+	db dbx.DBTX
+}
+
+func NewCustomFieldRepository(db dbx.DBTX) *CustomFieldRepository {
+	return &CustomFieldRepository{db: db}
+}
+
+const customFieldColumns = `id, team_id, key, name, type, required, min_value, max_value, pattern, options, position, created_at, updated_at`
+
+func scanCustomField(s rowScanner) (*models.CustomField, error) {
+	var f models.CustomField
+	var options string
+	if err := s.Scan(&f.ID, &f.TeamID, &f.Key, &f.Name, &f.Type, &f.Required, &f.Min, &f.Max, &f.Pattern, &options, &f.Position, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(options), &f.Options); err != nil {
+		return nil, fmt.Errorf("custom field %s: invalid options: %w", f.ID, err)
+	}
+	if f.Options == nil {
+		f.Options = []string{}
+	}
+	return &f, nil
+}
+
+func encodeOptions(options []string) string {
+	if options == nil {
+		options = []string{}
+	}
+	b, _ := json.Marshal(options)
+	return string(b)
+}
+
+func (r *CustomFieldRepository) ListCustomFields(ctx context.Context, teamID uuid.UUID) ([]*models.CustomField, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+customFieldColumns+` FROM custom_fields WHERE team_id = $1 ORDER BY position, created_at, id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	fields := []*models.CustomField{}
+	for rows.Next() {
+		f, err := scanCustomField(rows)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	return fields, rows.Err()
+}
+
+func (r *CustomFieldRepository) GetCustomField(ctx context.Context, teamID uuid.UUID, fieldID uuid.UUID) (*models.CustomField, error) {
+	f, err := scanCustomField(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+customFieldColumns+` FROM custom_fields WHERE id = $1 AND team_id = $2`,
+		fieldID,
+		teamID,
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return f, nil
+}
+
+func (r *CustomFieldRepository) CreateCustomField(ctx context.Context, f *models.CustomField) error {
+	now := time.Now()
+	f.CreatedAt = now
+	f.UpdatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO custom_fields (id, team_id, key, name, type, required, min_value, max_value, pattern, options, position, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		f.ID,
+		f.TeamID,
+		f.Key,
+		f.Name,
+		f.Type,
+		f.Required,
+		f.Min,
+		f.Max,
+		f.Pattern,
+		encodeOptions(f.Options),
+		f.Position,
+		now,
+		now,
+	)
+	return TranslateError(err)
+}
+
+func (r *CustomFieldRepository) UpdateCustomField(ctx context.Context, f *models.CustomField) error {
+	now := time.Now()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE custom_fields
+		 SET name = $1, required = $2, min_value = $3, max_value = $4, pattern = $5, options = $6, position = $7, updated_at = $8
+		 WHERE id = $9 AND team_id = $10`,
+		f.Name,
+		f.Required,
+		f.Min,
+		f.Max,
+		f.Pattern,
+		encodeOptions(f.Options),
+		f.Position,
+		now,
+		f.ID,
+		f.TeamID,
+	)
+	if err := expectAffected(res, err); err != nil {
+		return err
+	}
+	f.UpdatedAt = now
+	if err := r.touchTasks(ctx, f.ID, now); err != nil {
+		return err
+	}
+	switch f.Type {
+	case models.FieldSingleSelect:
+		args := []any{f.ID}
+		for _, o := range f.Options {
+			args = append(args, o)
+		}
+		_, err = r.db.ExecContext(
+			ctx,
+			`DELETE FROM task_field_values WHERE field_id = $1 AND value_text NOT IN (`+placeholders(2, len(f.Options))+`)`,
+			args...,
+		)
+	case models.FieldMultiSelect:
+		args := []any{f.ID}
+		for _, o := range f.Options {
+			args = append(args, o)
+		}
+		_, err = r.db.ExecContext(
+			ctx,
+			`DELETE FROM task_field_options WHERE field_id = $1 AND option NOT IN (`+placeholders(2, len(f.Options))+`)`,
+			args...,
+		)
+	}
+	return TranslateError(err)
+}
+
+func (r *CustomFieldRepository) DeleteCustomField(ctx context.Context, teamID uuid.UUID, fieldID uuid.UUID) error {
+	if err := r.touchTasks(ctx, fieldID, time.Now()); err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx, `DELETE FROM custom_fields WHERE id = $1 AND team_id = $2`, fieldID, teamID)
+	return expectAffected(res, err)
+}
+
+func (r *CustomFieldRepository) SetTaskFieldValues(ctx context.Context, taskID uuid.UUID, values []models.CustomFieldValue) error {
+	for _, v := range values {
+		for _, table := range []string{"task_field_values", "task_field_options"} {
+			_, err := r.db.ExecContext(ctx, `DELETE FROM `+table+` WHERE task_id = $1 AND field_id = $2`, taskID, v.Field.ID)
+			if err != nil {
+				return TranslateError(err)
+			}
+		}
+		if v.Value == nil {
+			continue
+		}
+		if options, ok := v.Value.([]string); ok {
+			for _, o := range options {
+				_, err := r.db.ExecContext(
+					ctx,
+					`INSERT INTO task_field_options (task_id, field_id, option) VALUES ($1, $2, $3)`,
+					taskID,
+					v.Field.ID,
+					o,
+				)
+				if err != nil {
+					return TranslateError(err)
+				}
+			}
+			continue
+		}
+		column, arg := fieldValueColumn(v)
+		_, err := r.db.ExecContext(
+			ctx,
+			`INSERT INTO task_field_values (task_id, field_id, `+column+`) VALUES ($1, $2, $3)`,
+			taskID,
+			v.Field.ID,
+			arg,
+		)
+		if err != nil {
+			return TranslateError(err)
+		}
+	}
+	return nil
+}
+
+func (r *CustomFieldRepository) CopyTaskFieldValues(ctx context.Context, fromTaskID uuid.UUID, toTaskID uuid.UUID) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO task_field_values (task_id, field_id, value_text, value_number, value_time, value_bool, value_user)
+		 SELECT $1::uuid, field_id, value_text, value_number, value_time, value_bool, value_user FROM task_field_values WHERE task_id = $2`,
+		toTaskID,
+		fromTaskID,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	_, err = r.db.ExecContext(
+		ctx,
+		`INSERT INTO task_field_options (task_id, field_id, option)
+		 SELECT $1::uuid, field_id, option FROM task_field_options WHERE task_id = $2`,
+		toTaskID,
+		fromTaskID,
+	)
+	return TranslateError(err)
+}
+
+// fieldValueColumn returns the task_field_values column holding values of the field type
+// and the value to bind for it.
+func fieldValueColumn(v models.CustomFieldValue) (string, any) {
+	switch val := v.Value.(type) {
+	case float64:
+		return "value_number", val
+	case time.Time:
+		return "value_time", val.UTC()
+	case bool:
+		return "value_bool", val
+	case uuid.UUID:
+		return "value_user", val
+	default:
+		return "value_text", val
+	}
+}
+
+// touchTasks bumps updated_at of the tasks holding a value of the field, so clients
+// syncing by updated_at see the change.
+func (r *CustomFieldRepository) touchTasks(ctx context.Context, fieldID uuid.UUID, now time.Time) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE tasks SET updated_at = $1 WHERE id IN (
+		 SELECT task_id FROM task_field_values WHERE field_id = $2
+		 UNION SELECT task_id FROM task_field_options WHERE field_id = $3)`,
+		now,
+		fieldID,
+		fieldID,
+	)
+	return TranslateError(err)
+}
@@ -0,0 +1,57 @@
+package postgress
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"task_manager/public/repositories/repoerr"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// SQLSTATE codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// TranslateError maps pgx errors onto the repository errors.
+// The original error is kept in the chain for logging.
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return repoerr.ErrNotFound
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return fmt.Errorf("%w: %w", repoerr.ErrConflict, err)
+	case pgForeignKeyViolation:
+		return fmt.Errorf("%w: %w", repoerr.ErrForeignKey, err)
+	case pgSerializationFailure, pgDeadlockDetected:
+		return fmt.Errorf("%w: %w", repoerr.ErrSerialization, err)
+	}
+	return err
+}
+
+// expectAffected translates err and reports ErrNotFound when the statement touched no row.
+func expectAffected(res sql.Result, err error) error {
+	if err != nil {
+		return TranslateError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repoerr.ErrNotFound
+	}
+	return nil
+}
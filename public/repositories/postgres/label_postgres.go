@@ -0,0 +1,173 @@
+package postgress
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LabelRepository struct {
+	db dbx.DBTX
+}
+
+func NewLabelRepository(db dbx.DBTX) *LabelRepository {
+	return &LabelRepository{db: db}
+}
+
+const labelColumns = `id, team_id, name, color, description, created_at, updated_at`
+
+func scanLabel(s rowScanner) (*models.Label, error) {
+	var l models.Label
+	if err := s.Scan(&l.ID, &l.TeamID, &l.Name, &l.Color, &l.Description, &l.CreatedAt, &l.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *LabelRepository) ListLabels(ctx context.Context, teamID uuid.UUID) ([]*models.Label, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+labelColumns+` FROM labels WHERE team_id = $1 ORDER BY lower(name), id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	labels := []*models.Label{}
+	for rows.Next() {
+		l, err := scanLabel(rows)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, l)
+	}
+	return labels, rows.Err()
+}
+
+func (r *LabelRepository) GetLabel(ctx context.Context, teamID uuid.UUID, labelID uuid.UUID) (*models.Label, error) {
+	l, err := scanLabel(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+labelColumns+` FROM labels WHERE id = $1 AND team_id = $2`,
+		labelID,
+		teamID,
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return l, nil
+}
+
+func (r *LabelRepository) CreateLabel(ctx context.Context, l *models.Label) error {
+	now := time.Now()
+	l.CreatedAt = now
+	l.UpdatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO labels (id, team_id, name, color, description, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		l.ID,
+		l.TeamID,
+		l.Name,
+		l.Color,
+		l.Description,
+		now,
+		now,
+	)
+	return TranslateError(err)
+}
+
+func (r *LabelRepository) UpdateLabel(ctx context.Context, l *models.Label) error {
+	now := time.Now()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE labels SET name = $1, color = $2, description = $3, updated_at = $4 WHERE id = $5 AND team_id = $6`,
+		l.Name,
+		l.Color,
+		l.Description,
+		now,
+		l.ID,
+		l.TeamID,
+	)
+	if err := expectAffected(res, err); err != nil {
+		return err
+	}
+	l.UpdatedAt = now
+	return r.touchTasks(ctx, l.ID, now)
+}
+
+func (r *LabelRepository) DeleteLabel(ctx context.Context, teamID uuid.UUID, labelID uuid.UUID) error {
+	if err := r.touchTasks(ctx, labelID, time.Now()); err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx, `DELETE FROM labels WHERE id = $1 AND team_id = $2`, labelID, teamID)
+	return expectAffected(res, err)
+}
+
+func (r *LabelRepository) MergeLabels(ctx context.Context, teamID uuid.UUID, sourceID uuid.UUID, targetID uuid.UUID) error {
+	if err := r.touchTasks(ctx, sourceID, time.Now()); err != nil {
+		return err
+	}
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO task_labels (task_id, label_id, created_at)
+		 SELECT task_id, $1::uuid, created_at FROM task_labels WHERE label_id = $2
+		 ON CONFLICT (task_id, label_id) DO NOTHING`,
+		targetID,
+		sourceID,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	return r.DeleteLabel(ctx, teamID, sourceID)
+}
+
+func (r *LabelRepository) AddTaskLabels(ctx context.Context, taskID uuid.UUID, labelIDs []uuid.UUID) error {
+	now := time.Now()
+	for _, labelID := range labelIDs {
+		_, err := r.db.ExecContext(
+			ctx,
+			`INSERT INTO task_labels (task_id, label_id, created_at) VALUES ($1, $2, $3)
+			 ON CONFLICT (task_id, label_id) DO NOTHING`,
+			taskID,
+			labelID,
+			now,
+		)
+		if err != nil {
+			return TranslateError(err)
+		}
+	}
+	return nil
+}
+
+// RemoveTaskLabels removes labelIDs from the task. Labels the task does not carry are ignored.
+func (r *LabelRepository) RemoveTaskLabels(ctx context.Context, taskID uuid.UUID, labelIDs []uuid.UUID) error {
+	if len(labelIDs) == 0 {
+		return nil
+	}
+	args := []any{taskID}
+	for _, labelID := range labelIDs {
+		args = append(args, labelID)
+	}
+	_, err := r.db.ExecContext(
+		ctx,
+		`DELETE FROM task_labels WHERE task_id = $1 AND label_id IN (`+placeholders(2, len(labelIDs))+`)`,
+		args...,
+	)
+	return TranslateError(err)
+}
+
+// touchTasks bumps updated_at of the tasks carrying the label, so clients syncing by
+// updated_at see label changes.
+func (r *LabelRepository) touchTasks(ctx context.Context, labelID uuid.UUID, now time.Time) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE tasks SET updated_at = $1 WHERE id IN (SELECT task_id FROM task_labels WHERE label_id = $2)`,
+		now,
+		labelID,
+	)
+	return TranslateError(err)
+}
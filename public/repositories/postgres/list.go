@@ -0,0 +1,43 @@
+package postgress
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/listquery"
+
+	"github.com/google/uuid"
+)
+
+const dialect = listquery.Postgres
+
+// listTotal counts the rows matching the filters of q, ignoring the cursor. from is the
+// FROM/WHERE part of the list query; it runs only when the client asked for the total.
+func listTotal(ctx context.Context, db dbx.DBTX, q listquery.Query, from string, s listquery.SQL) (*int, error) {
+	if !q.WithTotal {
+		return nil, nil
+	}
+	var n int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) `+from+` AND `+s.CountWhere, s.CountArgs...).Scan(&n); err != nil {
+		return nil, TranslateError(err)
+	}
+	return &n, nil
+}
+
+// queryIDs runs a statement returning one id per row, such as an UPDATE ... RETURNING id,
+// and collects the ids.
+func queryIDs(ctx context.Context, db dbx.DBTX, query string, args ...any) ([]uuid.UUID, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, TranslateError(rows.Err())
+}
@@ -0,0 +1,169 @@
+package postgress
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MilestoneRepository struct {
+	db dbx.DBTX
+}
+
+func NewMilestoneRepository(db dbx.DBTX) *MilestoneRepository {
+	return &MilestoneRepository{db: db}
+}
+
+const milestoneColumns = `id, team_id, project_id, name, description, due_at, completed_at, created_by, created_at, updated_at`
+
+func scanMilestone(s rowScanner) (*models.Milestone, error) {
+	var m models.Milestone
+	err := s.Scan(&m.ID, &m.TeamID, &m.ProjectID, &m.Name, &m.Description, &m.DueAt, &m.CompletedAt, &m.CreatedBy, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *MilestoneRepository) ListMilestones(ctx context.Context, teamID uuid.UUID, projectID *uuid.UUID, includeCompleted bool) ([]*models.Milestone, error) {
+	query := `SELECT ` + milestoneColumns + ` FROM milestones WHERE team_id = $1 AND ($2 OR completed_at IS NULL)`
+	args := []any{teamID, includeCompleted}
+	if projectID != nil {
+		query += ` AND project_id = $3`
+		args = append(args, projectID)
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY due_at, id`, args...)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	milestones := []*models.Milestone{}
+	for rows.Next() {
+		m, err := scanMilestone(rows)
+		if err != nil {
+			return nil, err
+		}
+		milestones = append(milestones, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return milestones, r.loadProgress(ctx, milestones)
+}
+
+func (r *MilestoneRepository) GetMilestone(ctx context.Context, teamID uuid.UUID, milestoneID uuid.UUID) (*models.Milestone, error) {
+	m, err := scanMilestone(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+milestoneColumns+` FROM milestones WHERE id = $1 AND team_id = $2`,
+		milestoneID,
+		teamID,
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return m, r.loadProgress(ctx, []*models.Milestone{m})
+}
+
+// loadProgress counts the tasks of the milestones by the category of their state.
+func (r *MilestoneRepository) loadProgress(ctx context.Context, milestones []*models.Milestone) error {
+	if len(milestones) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Milestone, len(milestones))
+	args := make([]any, 0, len(milestones))
+	for _, m := range milestones {
+		byID[m.ID] = m
+		args = append(args, m.ID)
+	}
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT tk.milestone_id, COALESCE(ws.category, ''), COUNT(*) FROM tasks tk
+		 LEFT JOIN workflow_states ws ON ws.id = tk.state_id
+		 WHERE tk.milestone_id IN (`+placeholders(1, len(args))+`) AND tk.deleted_at IS NULL
+		 GROUP BY tk.milestone_id, ws.category`,
+		args...,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var milestoneID uuid.UUID
+		var category models.WorkflowCategory
+		var n int
+		if err := rows.Scan(&milestoneID, &category, &n); err != nil {
+			return err
+		}
+		byID[milestoneID].Progress.Add(category, n)
+	}
+	return rows.Err()
+}
+
+func (r *MilestoneRepository) CreateMilestone(ctx context.Context, m *models.Milestone) error {
+	now := time.Now()
+	m.CreatedAt = now
+	m.UpdatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO milestones (id, team_id, project_id, name, description, due_at, completed_at, created_by, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		m.ID,
+		m.TeamID,
+		m.ProjectID,
+		m.Name,
+		m.Description,
+		m.DueAt,
+		m.CompletedAt,
+		m.CreatedBy,
+		now,
+		now,
+	)
+	return TranslateError(err)
+}
+
+func (r *MilestoneRepository) UpdateMilestone(ctx context.Context, m *models.Milestone) error {
+	now := time.Now()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE milestones SET project_id = $1, name = $2, description = $3, due_at = $4, completed_at = $5, updated_at = $6
+		 WHERE id = $7 AND team_id = $8`,
+		m.ProjectID,
+		m.Name,
+		m.Description,
+		m.DueAt,
+		m.CompletedAt,
+		now,
+		m.ID,
+		m.TeamID,
+	)
+	if err := expectAffected(res, err); err != nil {
+		return err
+	}
+	m.UpdatedAt = now
+	return nil
+}
+
+// DeleteMilestone detaches the tasks of the milestone before deleting it, like the sqlite
+// repository; the foreign key of tasks.milestone_id would do the same.
+func (r *MilestoneRepository) DeleteMilestone(ctx context.Context, teamID uuid.UUID, milestoneID uuid.UUID) ([]uuid.UUID, error) {
+	ids, err := queryIDs(
+		ctx,
+		r.db,
+		`UPDATE tasks SET milestone_id = NULL, updated_at = $1 WHERE milestone_id = $2 AND team_id = $3 RETURNING id`,
+		time.Now(),
+		milestoneID,
+		teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.db.ExecContext(ctx, `DELETE FROM milestones WHERE id = $1 AND team_id = $2`, milestoneID, teamID)
+	if err := expectAffected(res, err); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
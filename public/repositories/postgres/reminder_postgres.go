@@ -0,0 +1,207 @@
+package postgress
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReminderRepository struct {
+	db dbx.DBTX
+}
+
+func NewReminderRepository(db dbx.DBTX) *ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+func (r *ReminderRepository) GetUserPreferences(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error) {
+	var p models.UserPreferences
+	var reminders string
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT user_id, timezone, quiet_hours_start, quiet_hours_end, default_reminders, email_notifications, updated_at
+		 FROM user_preferences WHERE user_id = $1`,
+		userID,
+	).Scan(&p.UserID, &p.Timezone, &p.QuietHoursStart, &p.QuietHoursEnd, &reminders, &p.EmailNotifications, &p.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.DefaultUserPreferences(userID), nil
+	}
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	if err := json.Unmarshal([]byte(reminders), &p.DefaultReminders); err != nil {
+		return nil, fmt.Errorf("preferences of %s: invalid default reminders: %w", userID, err)
+	}
+	if p.DefaultReminders == nil {
+		p.DefaultReminders = []int{}
+	}
+	return &p, nil
+}
+
+func (r *ReminderRepository) SetUserPreferences(ctx context.Context, p *models.UserPreferences) error {
+	now := time.Now()
+	reminders, _ := json.Marshal(p.DefaultReminders)
+	if p.DefaultReminders == nil {
+		reminders = []byte("[]")
+	}
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO user_preferences (user_id, timezone, quiet_hours_start, quiet_hours_end, default_reminders, email_notifications, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (user_id) DO UPDATE SET
+		 timezone = excluded.timezone, quiet_hours_start = excluded.quiet_hours_start, quiet_hours_end = excluded.quiet_hours_end,
+		 default_reminders = excluded.default_reminders, email_notifications = excluded.email_notifications, updated_at = excluded.updated_at`,
+		p.UserID,
+		p.Timezone,
+		p.QuietHoursStart,
+		p.QuietHoursEnd,
+		string(reminders),
+		p.EmailNotifications,
+		now,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	p.UpdatedAt = now
+	return nil
+}
+
+func (r *ReminderRepository) ListTaskReminders(ctx context.Context, taskID uuid.UUID) ([]int, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT minutes_before FROM task_reminders WHERE task_id = $1 ORDER BY minutes_before DESC`,
+		taskID,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	minutes := []int{}
+	for rows.Next() {
+		var m int
+		if err := rows.Scan(&m); err != nil {
+			return nil, err
+		}
+		minutes = append(minutes, m)
+	}
+	return minutes, rows.Err()
+}
+
+func (r *ReminderRepository) SetTaskReminders(ctx context.Context, taskID uuid.UUID, minutesBefore []int) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM task_reminders WHERE task_id = $1`, taskID); err != nil {
+		return TranslateError(err)
+	}
+	for _, m := range minutesBefore {
+		_, err := r.db.ExecContext(
+			ctx,
+			`INSERT INTO task_reminders (task_id, minutes_before) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			taskID,
+			m,
+		)
+		if err != nil {
+			return TranslateError(err)
+		}
+	}
+	return nil
+}
+
+func (r *ReminderRepository) ListDueTasks(ctx context.Context, from time.Time, to time.Time) ([]*models.DueTask, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT tk.id, tk.team_id, tk.title, tk.due_at, tk.created_by, ta.user_id
+		 FROM tasks tk
+		 LEFT JOIN workflow_states ws ON ws.id = tk.state_id
+		 LEFT JOIN task_assignees ta ON ta.task_id = tk.id
+		 WHERE tk.due_at > $1 AND tk.due_at <= $2 AND COALESCE(ws.category, '') <> 'done'
+		   AND tk.deleted_at IS NULL AND tk.team_id NOT IN (SELECT id FROM teams WHERE deleted_at IS NOT NULL OR archived_at IS NOT NULL)
+		 ORDER BY tk.due_at, tk.id, ta.assigned_at, ta.user_id`,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	tasks := []*models.DueTask{}
+	byID := map[uuid.UUID]*models.DueTask{}
+	for rows.Next() {
+		var t models.DueTask
+		var assignee *uuid.UUID
+		if err := rows.Scan(&t.ID, &t.TeamID, &t.Title, &t.DueAt, &t.CreatedBy, &assignee); err != nil {
+			return nil, err
+		}
+		task, ok := byID[t.ID]
+		if !ok {
+			task = &t
+			task.AssigneeIDs = []uuid.UUID{}
+			task.Reminders = []int{}
+			byID[t.ID] = task
+			tasks = append(tasks, task)
+		}
+		if assignee != nil {
+			task.AssigneeIDs = append(task.AssigneeIDs, *assignee)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+
+	args := make([]any, 0, len(tasks))
+	for _, t := range tasks {
+		args = append(args, t.ID)
+	}
+	rows, err = r.db.QueryContext(
+		ctx,
+		`SELECT task_id, minutes_before FROM task_reminders
+		 WHERE task_id IN (`+placeholders(1, len(args))+`)
+		 ORDER BY task_id, minutes_before DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id uuid.UUID
+		var m int
+		if err := rows.Scan(&id, &m); err != nil {
+			return nil, err
+		}
+		byID[id].Reminders = append(byID[id].Reminders, m)
+	}
+	return tasks, rows.Err()
+}
+
+func (r *ReminderRepository) RecordDelivery(ctx context.Context, d models.ReminderDelivery) (bool, error) {
+	res, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO reminder_deliveries (task_id, user_id, kind, minutes_before, due_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT DO NOTHING`,
+		d.TaskID,
+		d.UserID,
+		string(d.Kind),
+		d.MinutesBefore,
+		d.DueAt,
+		time.Now(),
+	)
+	if err != nil {
+		return false, TranslateError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,237 @@
+package postgress
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SprintRepository struct {
+	db dbx.DBTX
+}
+
+func NewSprintRepository(db dbx.DBTX) *SprintRepository {
+	return &SprintRepository{db: db}
+}
+
+const sprintColumns = `id, team_id, project_id, name, goal, status, start_date, end_date, capacity, committed_tasks, committed_estimate, completed_tasks, completed_estimate, carried_over, started_at, closed_at, created_by, created_at, updated_at`
+
+func scanSprint(s rowScanner) (*models.Sprint, error) {
+	var sp models.Sprint
+	var committedTasks, completedTasks *int
+	var committedEstimate, completedEstimate *float64
+	err := s.Scan(
+		&sp.ID, &sp.TeamID, &sp.ProjectID, &sp.Name, &sp.Goal, &sp.Status, &sp.StartDate, &sp.EndDate, &sp.Capacity,
+		&committedTasks, &committedEstimate, &completedTasks, &completedEstimate, &sp.CarriedOver,
+		&sp.StartedAt, &sp.ClosedAt, &sp.CreatedBy, &sp.CreatedAt, &sp.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	sp.Committed = sprintTotals(committedTasks, committedEstimate)
+	sp.Completed = sprintTotals(completedTasks, completedEstimate)
+	return &sp, nil
+}
+
+func sprintTotals(tasks *int, estimate *float64) *models.SprintTotals {
+	if tasks == nil {
+		return nil
+	}
+	t := &models.SprintTotals{Tasks: *tasks}
+	if estimate != nil {
+		t.Estimate = *estimate
+	}
+	return t
+}
+
+// totalsArgs splits t into the values of its two columns.
+func totalsArgs(t *models.SprintTotals) (any, any) {
+	if t == nil {
+		return nil, nil
+	}
+	return t.Tasks, t.Estimate
+}
+
+func (r *SprintRepository) ListSprints(ctx context.Context, teamID uuid.UUID, status models.SprintStatus) ([]*models.Sprint, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+sprintColumns+` FROM sprints
+		 WHERE team_id = $1 AND ($2 = '' OR status = $2)
+		 ORDER BY start_date, id`,
+		teamID,
+		string(status),
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	sprints := []*models.Sprint{}
+	for rows.Next() {
+		sp, err := scanSprint(rows)
+		if err != nil {
+			return nil, err
+		}
+		sprints = append(sprints, sp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sprints, r.loadScope(ctx, sprints)
+}
+
+func (r *SprintRepository) GetSprint(ctx context.Context, teamID uuid.UUID, sprintID uuid.UUID) (*models.Sprint, error) {
+	sp, err := scanSprint(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+sprintColumns+` FROM sprints WHERE id = $1 AND team_id = $2`,
+		sprintID,
+		teamID,
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return sp, r.loadScope(ctx, []*models.Sprint{sp})
+}
+
+// loadScope fills in the current totals of the tasks in the sprints.
+func (r *SprintRepository) loadScope(ctx context.Context, sprints []*models.Sprint) error {
+	if len(sprints) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Sprint, len(sprints))
+	args := make([]any, 0, len(sprints))
+	for _, sp := range sprints {
+		byID[sp.ID] = sp
+		args = append(args, sp.ID)
+	}
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT tk.sprint_id, COUNT(*), COALESCE(SUM(tk.estimate), 0),
+		        COALESCE(SUM(CASE WHEN ws.category = 'done' THEN 1 ELSE 0 END), 0),
+		        COALESCE(SUM(CASE WHEN ws.category = 'done' THEN tk.estimate END), 0)
+		 FROM tasks tk
+		 LEFT JOIN workflow_states ws ON ws.id = tk.state_id
+		 WHERE tk.sprint_id IN (`+placeholders(1, len(args))+`) AND tk.deleted_at IS NULL
+		 GROUP BY tk.sprint_id`,
+		args...,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sprintID uuid.UUID
+		var scope models.SprintScope
+		if err := rows.Scan(&sprintID, &scope.Tasks, &scope.Estimate, &scope.Done.Tasks, &scope.Done.Estimate); err != nil {
+			return err
+		}
+		byID[sprintID].Scope = scope
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, sp := range sprints {
+		sp.Scope.OverCapacity = sp.Capacity != nil && sp.Scope.Estimate > *sp.Capacity
+	}
+	return nil
+}
+
+func (r *SprintRepository) CreateSprint(ctx context.Context, sp *models.Sprint) error {
+	now := time.Now()
+	sp.CreatedAt = now
+	sp.UpdatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO sprints (id, team_id, project_id, name, goal, status, start_date, end_date, capacity, created_by, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		sp.ID,
+		sp.TeamID,
+		sp.ProjectID,
+		sp.Name,
+		sp.Goal,
+		string(sp.Status),
+		sp.StartDate,
+		sp.EndDate,
+		sp.Capacity,
+		sp.CreatedBy,
+		now,
+		now,
+	)
+	return TranslateError(err)
+}
+
+func (r *SprintRepository) UpdateSprint(ctx context.Context, sp *models.Sprint, status models.SprintStatus) error {
+	now := time.Now()
+	committedTasks, committedEstimate := totalsArgs(sp.Committed)
+	completedTasks, completedEstimate := totalsArgs(sp.Completed)
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE sprints SET project_id = $1, name = $2, goal = $3, status = $4, start_date = $5, end_date = $6, capacity = $7,
+		        committed_tasks = $8, committed_estimate = $9, completed_tasks = $10, completed_estimate = $11, carried_over = $12,
+		        started_at = $13, closed_at = $14, updated_at = $15
+		 WHERE id = $16 AND team_id = $17 AND status = $18`,
+		sp.ProjectID,
+		sp.Name,
+		sp.Goal,
+		string(sp.Status),
+		sp.StartDate,
+		sp.EndDate,
+		sp.Capacity,
+		committedTasks,
+		committedEstimate,
+		completedTasks,
+		completedEstimate,
+		sp.CarriedOver,
+		sp.StartedAt,
+		sp.ClosedAt,
+		now,
+		sp.ID,
+		sp.TeamID,
+		string(status),
+	)
+	if err := expectAffected(res, err); err != nil {
+		return err
+	}
+	sp.UpdatedAt = now
+	return nil
+}
+
+// DeleteSprint moves the tasks of the sprint back to the backlog before deleting it, like
+// the sqlite repository; the foreign key of tasks.sprint_id would do the same.
+func (r *SprintRepository) DeleteSprint(ctx context.Context, teamID uuid.UUID, sprintID uuid.UUID) ([]uuid.UUID, error) {
+	ids, err := queryIDs(
+		ctx,
+		r.db,
+		`UPDATE tasks SET sprint_id = NULL, updated_at = $1 WHERE sprint_id = $2 AND team_id = $3 RETURNING id`,
+		time.Now(),
+		sprintID,
+		teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.db.ExecContext(ctx, `DELETE FROM sprints WHERE id = $1 AND team_id = $2`, sprintID, teamID)
+	if err := expectAffected(res, err); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *SprintRepository) CarryOverTasks(ctx context.Context, teamID uuid.UUID, fromID uuid.UUID, toID *uuid.UUID) ([]uuid.UUID, error) {
+	return queryIDs(
+		ctx,
+		r.db,
+		`UPDATE tasks SET sprint_id = $1, updated_at = $2
+		 WHERE team_id = $3 AND sprint_id = $4 AND deleted_at IS NULL
+		   AND (state_id IS NULL OR state_id NOT IN (SELECT id FROM workflow_states WHERE team_id = $5 AND category = 'done'))
+		 RETURNING id`,
+		toID,
+		time.Now(),
+		teamID,
+		fromID,
+		teamID,
+	)
+}
@@ -0,0 +1,183 @@
+package postgress
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaskLinkRepository struct {
+	db dbx.DBTX
+}
+
+func NewTaskLinkRepository(db dbx.DBTX) *TaskLinkRepository {
+	return &TaskLinkRepository{db: db}
+}
+
+const taskLinkColumns = `id, team_id, from_task_id, to_task_id, kind, created_by, created_at`
+
+// liveLink leaves out the links from or to tasks in the trash.
+const liveLink = `NOT EXISTS (SELECT 1 FROM tasks dt WHERE dt.id IN (from_task_id, to_task_id) AND dt.deleted_at IS NOT NULL)`
+
+func scanTaskLink(s rowScanner) (*models.TaskLink, error) {
+	var l models.TaskLink
+	if err := s.Scan(&l.ID, &l.TeamID, &l.FromTaskID, &l.ToTaskID, &l.Kind, &l.CreatedBy, &l.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *TaskLinkRepository) CreateTaskLink(ctx context.Context, l *models.TaskLink) error {
+	l.CreatedAt = time.Now()
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO task_links (id, team_id, from_task_id, to_task_id, kind, created_by, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		l.ID,
+		l.TeamID,
+		l.FromTaskID,
+		l.ToTaskID,
+		string(l.Kind),
+		l.CreatedBy,
+		l.CreatedAt,
+	)
+	return TranslateError(err)
+}
+
+func (r *TaskLinkRepository) GetTaskLink(ctx context.Context, teamID uuid.UUID, linkID uuid.UUID) (*models.TaskLink, error) {
+	l, err := scanTaskLink(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+taskLinkColumns+` FROM task_links WHERE id = $1 AND team_id = $2`,
+		linkID,
+		teamID,
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return l, nil
+}
+
+func (r *TaskLinkRepository) DeleteTaskLink(ctx context.Context, teamID uuid.UUID, linkID uuid.UUID) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`DELETE FROM task_links WHERE id = $1 AND team_id = $2`,
+		linkID,
+		teamID,
+	)
+	return expectAffected(res, err)
+}
+
+func (r *TaskLinkRepository) ListTaskLinks(ctx context.Context, taskID uuid.UUID) ([]*models.TaskLink, error) {
+	return r.list(
+		ctx,
+		`SELECT `+taskLinkColumns+` FROM task_links WHERE (from_task_id = $1 OR to_task_id = $1) AND `+liveLink+` ORDER BY created_at, id`,
+		taskID,
+	)
+}
+
+func (r *TaskLinkRepository) list(ctx context.Context, query string, args ...any) ([]*models.TaskLink, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	links := []*models.TaskLink{}
+	for rows.Next() {
+		l, err := scanTaskLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+// HasBlockingPath reports whether fromTaskID transitively blocks toTaskID. Links of
+// tasks in the trash count too, so restoring a task never closes a cycle.
+func (r *TaskLinkRepository) HasBlockingPath(ctx context.Context, fromTaskID uuid.UUID, toTaskID uuid.UUID) (bool, error) {
+	var found bool
+	err := r.db.QueryRowContext(
+		ctx,
+		`WITH RECURSIVE blocked (id) AS (
+			SELECT to_task_id FROM task_links WHERE from_task_id = $1 AND kind = 'blocks'
+			UNION
+			SELECT l.to_task_id FROM task_links l JOIN blocked b ON l.from_task_id = b.id WHERE l.kind = 'blocks'
+		)
+		SELECT EXISTS (SELECT 1 FROM blocked WHERE id = $2)`,
+		fromTaskID,
+		toTaskID,
+	).Scan(&found)
+	return found, TranslateError(err)
+}
+
+// ListOpenBlockers returns the tasks that directly block taskID and are not done yet.
+func (r *TaskLinkRepository) ListOpenBlockers(ctx context.Context, taskID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT l.from_task_id FROM task_links l
+		 JOIN tasks t ON t.id = l.from_task_id
+		 LEFT JOIN workflow_states ws ON ws.id = t.state_id
+		 WHERE l.to_task_id = $1 AND l.kind = 'blocks' AND t.deleted_at IS NULL AND COALESCE(ws.category, '') <> 'done'
+		 ORDER BY l.created_at, l.from_task_id`,
+		taskID,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetDependencyGraph returns every link of the team and the tasks they connect.
+func (r *TaskLinkRepository) GetDependencyGraph(ctx context.Context, teamID uuid.UUID) (*models.DependencyGraph, error) {
+	links, err := r.list(
+		ctx,
+		`SELECT `+taskLinkColumns+` FROM task_links WHERE team_id = $1 AND `+liveLink+` ORDER BY created_at, id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	g := &models.DependencyGraph{Nodes: []models.DependencyNode{}, Links: make([]models.TaskLink, 0, len(links))}
+	for _, l := range links {
+		g.Links = append(g.Links, *l)
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT t.id, t.title, t.state_id, COALESCE(ws.category = 'done', FALSE), t.estimate, t.due_at
+		 FROM tasks t
+		 LEFT JOIN workflow_states ws ON ws.id = t.state_id
+		 WHERE t.team_id = $1 AND t.deleted_at IS NULL AND t.id IN (
+			SELECT from_task_id FROM task_links WHERE team_id = $1 AND `+liveLink+`
+			UNION
+			SELECT to_task_id FROM task_links WHERE team_id = $1 AND `+liveLink+`
+		 )
+		 ORDER BY t.created_at, t.id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var n models.DependencyNode
+		if err := rows.Scan(&n.ID, &n.Title, &n.StateID, &n.Done, &n.Estimate, &n.DueAt); err != nil {
+			return nil, err
+		}
+		g.Nodes = append(g.Nodes, n)
+	}
+	return g, rows.Err()
+}
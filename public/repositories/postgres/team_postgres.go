@@ -2,9 +2,9 @@ package postgress
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	dbx "task_manager/public/db"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories/listspec"
 	"task_manager/public/repositories/models"
 	"time"
 
@@ -32,50 +32,66 @@ func (r *TeamRepository) CreateTeam(ctx context.Context, team *models.Team) erro
 	team.CreatedAt = now
 	team.UpdatedAt = now
 
-	return err
+	return TranslateError(err)
 }
 
 func (r *TeamRepository) GetTeamByID(ctx context.Context, teamID uuid.UUID) (*models.Team, error) {
 	var u models.Team
 	err := r.db.QueryRowContext(
 		ctx,
-		`SELECT id, name, created_at, updated_at FROM teams WHERE id = $1`,
+		`SELECT id, name, archived_at, created_at, updated_at FROM teams WHERE id = $1 AND deleted_at IS NULL`,
 		teamID,
-	).Scan(&u.ID, &u.Name, &u.CreatedAt, &u.UpdatedAt)
+	).Scan(&u.ID, &u.Name, &u.ArchivedAt, &u.CreatedAt, &u.UpdatedAt)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
+	}
+	return &u, nil
+}
+
+// LockTeam takes a shared lock on the team row, so changes to the team do not wait for
+// each other but archiving or deleting it waits for them.
+func (r *TeamRepository) LockTeam(ctx context.Context, teamID uuid.UUID) (*models.Team, error) {
+	var u models.Team
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT id, name, archived_at, created_at, updated_at FROM teams WHERE id = $1 AND deleted_at IS NULL FOR SHARE`,
+		teamID,
+	).Scan(&u.ID, &u.Name, &u.ArchivedAt, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		return nil, TranslateError(err)
 	}
 	return &u, nil
 }
 
 func (r *TeamRepository) CreateTeamUser(ctx context.Context, userTeam *models.UserTeam) error {
+	if userTeam.JoinedAt.IsZero() {
+		userTeam.JoinedAt = time.Now()
+	}
 	_, err := r.db.ExecContext(
 		ctx,
-		`INSERT INTO teams_users (id, team_id, user_id, role) VALUES ($1, $2, $3, $4)`,
+		`INSERT INTO teams_users (id, team_id, user_id, role, joined_at) VALUES ($1, $2, $3, $4, $5)`,
 		userTeam.ID,
 		userTeam.TeamID,
 		userTeam.UserID,
 		string(userTeam.Role),
+		userTeam.JoinedAt,
 	)
 
-	return err
+	return TranslateError(err)
 }
 
 func (r *TeamRepository) DeleteTeamUser(ctx context.Context, userTeamID *uuid.UUID) error {
-	_, err := r.db.ExecContext(
+	res, err := r.db.ExecContext(
 		ctx,
 		`DELETE FROM teams_users WHERE id = $1`,
 		userTeamID,
 	)
-	return err
+	return expectAffected(res, err)
 }
 
 func (r *TeamRepository) EditTeamName(ctx context.Context, team *models.Team) error {
 	now := time.Now()
-	_, err := r.db.ExecContext(
+	res, err := r.db.ExecContext(
 		ctx,
 		`UPDATE teams SET name = $1, updated_at = $2 WHERE id = $3`,
 		team.Name,
@@ -83,16 +99,64 @@ func (r *TeamRepository) EditTeamName(ctx context.Context, team *models.Team) er
 		team.ID,
 	)
 	team.UpdatedAt = now
-	return err
+	return expectAffected(res, err)
+}
+
+func (r *TeamRepository) SetTeamArchived(ctx context.Context, teamID uuid.UUID, archivedAt *time.Time) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE teams SET archived_at = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`,
+		archivedAt,
+		time.Now(),
+		teamID,
+	)
+	return expectAffected(res, err)
 }
 
 func (r *TeamRepository) DeleteTeam(ctx context.Context, teamID uuid.UUID) error {
-	_, err := r.db.ExecContext(
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE teams SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`,
+		time.Now(),
+		teamID,
+	)
+	return expectAffected(res, err)
+}
+
+func (r *TeamRepository) RestoreTeam(ctx context.Context, teamID uuid.UUID) error {
+	res, err := r.db.ExecContext(
 		ctx,
-		`DELETE FROM teams WHERE id = $1`,
+		`UPDATE teams SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`,
 		teamID,
 	)
-	return err
+	return expectAffected(res, err)
+}
+
+func (r *TeamRepository) ListDeletedTeams(ctx context.Context, userID uuid.UUID) ([]*models.TrashItem, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT t.id, t.name, t.deleted_at FROM teams t
+		 JOIN teams_users tu ON tu.team_id = t.id
+		 WHERE tu.user_id = $1 AND tu.role = $2 AND t.deleted_at IS NOT NULL
+		 ORDER BY t.deleted_at DESC, t.id`,
+		userID,
+		string(models.FounderUserRole),
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	items := []*models.TrashItem{}
+	for rows.Next() {
+		item := models.TrashItem{Kind: models.TrashTeam}
+		if err := rows.Scan(&item.ID, &item.Title, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		item.TeamID = item.ID
+		items = append(items, &item)
+	}
+	return items, rows.Err()
 }
 
 func (r *TeamRepository) RemoveTeamUser(ctx context.Context, userID uuid.UUID) error {
@@ -101,98 +165,104 @@ func (r *TeamRepository) RemoveTeamUser(ctx context.Context, userID uuid.UUID) e
 		`DELETE FROM teams_users WHERE user_id = $1`,
 		userID,
 	)
-	return err
+	return TranslateError(err)
 }
 
-func (r *TeamRepository) GetTeamsByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Team, error) {
-	rows, err := r.db.QueryContext(
-		ctx,
-		`SELECT t.id, t.name, t.created_at, t.updated_at 
-		 FROM teams t
+func (r *TeamRepository) GetTeamsByUserID(ctx context.Context, userID uuid.UUID, includeArchived bool, q listquery.Query) (listquery.Result[*models.Team], error) {
+	const from = `FROM teams t
 		 JOIN teams_users tu ON t.id = tu.team_id
-		 WHERE tu.user_id = $1
-		 ORDER BY t.created_at ASC`,
-		userID,
-	)
+		 WHERE tu.user_id = $1 AND t.deleted_at IS NULL AND ($2 OR t.archived_at IS NULL)`
+	s := q.Build(dialect, userID, includeArchived)
+	rows, err := r.db.QueryContext(ctx, `SELECT t.id, t.name, t.archived_at, t.created_at, t.updated_at `+from+` AND `+s.Where+s.Suffix(), s.Args...)
 	if err != nil {
-		return nil, err
+		return listquery.Result[*models.Team]{}, TranslateError(err)
 	}
 	defer rows.Close()
 
 	var teams []*models.Team
 	for rows.Next() {
 		var t models.Team
-		if err := rows.Scan(&t.ID, &t.Name, &t.CreatedAt, &t.UpdatedAt); err != nil {
-			return nil, err
+		if err := rows.Scan(&t.ID, &t.Name, &t.ArchivedAt, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return listquery.Result[*models.Team]{}, err
 		}
 		teams = append(teams, &t)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return listquery.Result[*models.Team]{}, err
 	}
-	return teams, nil
+	res := listquery.Paginate(q, teams, listspec.TeamRow)
+	res.Total, err = listTotal(ctx, r.db, q, from, s)
+	return res, err
 }
 
 func (r *TeamRepository) CreateTeamInvitation(ctx context.Context, invitation *models.Invitation) error {
+	if invitation.CreatedAt.IsZero() {
+		invitation.CreatedAt = time.Now()
+	}
 	_, err := r.db.ExecContext(
 		ctx,
-		`INSERT INTO team_invitations (id, team_id, user_id, accepted) VALUES ($1, $2, $3, $4)`,
+		`INSERT INTO team_invitations (id, team_id, to_user_id, from_user_id, accepted, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
 		invitation.ID,
 		invitation.TeamID,
 		invitation.UserID,
+		invitation.FromUserID,
 		invitation.Accepted,
+		invitation.CreatedAt,
 	)
-	return err
+	return TranslateError(err)
 }
 
 func (r *TeamRepository) UpdateTeamInvitationStatus(ctx context.Context, invitationID uuid.UUID, accept bool) error {
-	_, err := r.db.ExecContext(
+	res, err := r.db.ExecContext(
 		ctx,
 		`UPDATE team_invitations SET accepted = $1 WHERE id = $2`,
 		accept,
 		invitationID,
 	)
-	return err
+	return expectAffected(res, err)
 }
 
-func (r *TeamRepository) GetUserInvitations(ctx context.Context, userID uuid.UUID) ([]*models.Invitation, error) {
+func (r *TeamRepository) GetUserInvitations(ctx context.Context, userID uuid.UUID, q listquery.Query) (listquery.Result[*models.Invitation], error) {
+	const from = `FROM team_invitations ti WHERE ti.to_user_id = $1`
+	s := q.Build(dialect, userID)
 	rows, err := r.db.QueryContext(
 		ctx,
-		`SELECT id, team_id, user_id, accepted FROM team_invitations WHERE user_id = $1 ORDER BY id ASC`,
-		userID,
+		`SELECT ti.id, ti.team_id, ti.to_user_id, ti.from_user_id, ti.accepted, ti.created_at `+from+` AND `+s.Where+s.Suffix(),
+		s.Args...,
 	)
 	if err != nil {
-		return nil, err
+		return listquery.Result[*models.Invitation]{}, TranslateError(err)
 	}
 	defer rows.Close()
 
 	var invitations []*models.Invitation
 	for rows.Next() {
 		var inv models.Invitation
-		if err := rows.Scan(&inv.ID, &inv.TeamID, &inv.UserID, &inv.Accepted); err != nil {
-			return nil, err
+		if err := rows.Scan(&inv.ID, &inv.TeamID, &inv.UserID, &inv.FromUserID, &inv.Accepted, &inv.CreatedAt); err != nil {
+			return listquery.Result[*models.Invitation]{}, err
 		}
 		invitations = append(invitations, &inv)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return listquery.Result[*models.Invitation]{}, err
 	}
-	return invitations, nil
+	res := listquery.Paginate(q, invitations, listspec.InvitationRow)
+	res.Total, err = listTotal(ctx, r.db, q, from, s)
+	return res, err
 }
 
 func (r *TeamRepository) GetMemberRole(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (*models.TeamUserRole, error) {
 	var role string
 	err := r.db.QueryRowContext(
 		ctx,
-		`SELECT role FROM teams_users WHERE team_id = $1 AND user_id = $2`,
+		`SELECT tu.role FROM teams_users tu
+		 JOIN teams t ON t.id = tu.team_id
+		 WHERE tu.team_id = $1 AND tu.user_id = $2 AND t.deleted_at IS NULL`,
 		teamID,
 		userID,
 	).Scan(&role)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	result := models.TeamUserRole(role)
 	return &result, nil
@@ -208,23 +278,18 @@ func (r *TeamRepository) GetTeamFounderByTeamID(ctx context.Context, teamID uuid
 		string(models.FounderUserRole),
 	).Scan(&ut.ID, &ut.TeamID, &ut.UserID, &role)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	ut.Role = models.TeamUserRole(role)
 	return &ut, nil
 }
 
-func (r *TeamRepository) GetTeamsMembers(ctx context.Context, teamID uuid.UUID) ([]*models.UserTeam, error) {
-	rows, err := r.db.QueryContext(
-		ctx,
-		`SELECT id, team_id, user_id, role FROM teams_users WHERE team_id = $1 ORDER BY id ASC`,
-		teamID,
-	)
+func (r *TeamRepository) GetTeamsMembers(ctx context.Context, teamID uuid.UUID, q listquery.Query) (listquery.Result[*models.UserTeam], error) {
+	const from = `FROM teams_users tu WHERE tu.team_id = $1`
+	s := q.Build(dialect, teamID)
+	rows, err := r.db.QueryContext(ctx, `SELECT tu.id, tu.team_id, tu.user_id, tu.role, tu.joined_at `+from+` AND `+s.Where+s.Suffix(), s.Args...)
 	if err != nil {
-		return nil, err
+		return listquery.Result[*models.UserTeam]{}, TranslateError(err)
 	}
 	defer rows.Close()
 
@@ -232,23 +297,25 @@ func (r *TeamRepository) GetTeamsMembers(ctx context.Context, teamID uuid.UUID)
 	for rows.Next() {
 		var ut models.UserTeam
 		var role string
-		if err := rows.Scan(&ut.ID, &ut.TeamID, &ut.UserID, &role); err != nil {
-			return nil, err
+		if err := rows.Scan(&ut.ID, &ut.TeamID, &ut.UserID, &role, &ut.JoinedAt); err != nil {
+			return listquery.Result[*models.UserTeam]{}, err
 		}
 		ut.Role = models.TeamUserRole(role)
 		members = append(members, &ut)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return listquery.Result[*models.UserTeam]{}, err
 	}
-	return members, nil
+	res := listquery.Paginate(q, members, listspec.MemberRow)
+	res.Total, err = listTotal(ctx, r.db, q, from, s)
+	return res, err
 }
 
 func (r *TeamRepository) DeleteUserInvitation(ctx context.Context, invitationID uuid.UUID) error {
-	_, err := r.db.ExecContext(
+	res, err := r.db.ExecContext(
 		ctx,
 		`DELETE FROM team_invitations WHERE id = $1`,
 		invitationID,
 	)
-	return err
+	return expectAffected(res, err)
 }
@@ -0,0 +1,197 @@
+package postgress
+
+import (
+	"context"
+	"fmt"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TimeEntryRepository struct {
+	db dbx.DBTX
+}
+
+func NewTimeEntryRepository(db dbx.DBTX) *TimeEntryRepository {
+	return &TimeEntryRepository{db: db}
+}
+
+const timeEntryColumns = `id, team_id, task_id, user_id, started_at, ended_at, duration_seconds, note, billable, created_at, updated_at`
+
+func scanTimeEntry(s rowScanner) (*models.TimeEntry, error) {
+	var e models.TimeEntry
+	err := s.Scan(&e.ID, &e.TeamID, &e.TaskID, &e.UserID, &e.StartedAt, &e.EndedAt, &e.Duration, &e.Note, &e.Billable, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *TimeEntryRepository) LockUserTimeEntries(ctx context.Context, userID uuid.UUID) error {
+	var locked uuid.UUID
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&locked)
+	return TranslateError(err)
+}
+
+func (r *TimeEntryRepository) GetRunningTimer(ctx context.Context, userID uuid.UUID) (*models.TimeEntry, error) {
+	e, err := scanTimeEntry(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+timeEntryColumns+` FROM time_entries WHERE user_id = $1 AND ended_at IS NULL`,
+		userID,
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return e, nil
+}
+
+func (r *TimeEntryRepository) GetTimeEntry(ctx context.Context, teamID uuid.UUID, entryID uuid.UUID) (*models.TimeEntry, error) {
+	e, err := scanTimeEntry(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+timeEntryColumns+` FROM time_entries WHERE id = $1 AND team_id = $2`,
+		entryID,
+		teamID,
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return e, nil
+}
+
+func (r *TimeEntryRepository) FindOverlappingEntry(ctx context.Context, userID uuid.UUID, start time.Time, end time.Time, excludeID *uuid.UUID) (*models.TimeEntry, error) {
+	query := `SELECT ` + timeEntryColumns + ` FROM time_entries
+		 WHERE user_id = $1 AND started_at < $2 AND (ended_at IS NULL OR ended_at > $3)`
+	args := []any{userID, end, start}
+	if excludeID != nil {
+		query += ` AND id <> $4`
+		args = append(args, *excludeID)
+	}
+	e, err := scanTimeEntry(r.db.QueryRowContext(ctx, query+` ORDER BY started_at, id LIMIT 1`, args...))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return e, nil
+}
+
+func (r *TimeEntryRepository) ListTaskTimeEntries(ctx context.Context, taskID uuid.UUID) ([]*models.TimeEntry, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+timeEntryColumns+` FROM time_entries WHERE task_id = $1 ORDER BY started_at, id`,
+		taskID,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	entries := []*models.TimeEntry{}
+	for rows.Next() {
+		e, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *TimeEntryRepository) CreateTimeEntry(ctx context.Context, e *models.TimeEntry) error {
+	now := time.Now()
+	e.CreatedAt = now
+	e.UpdatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO time_entries (id, team_id, task_id, user_id, started_at, ended_at, duration_seconds, note, billable, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		e.ID,
+		e.TeamID,
+		e.TaskID,
+		e.UserID,
+		e.StartedAt,
+		e.EndedAt,
+		e.Duration,
+		e.Note,
+		e.Billable,
+		now,
+		now,
+	)
+	return TranslateError(err)
+}
+
+func (r *TimeEntryRepository) UpdateTimeEntry(ctx context.Context, e *models.TimeEntry) error {
+	now := time.Now()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE time_entries SET started_at = $1, ended_at = $2, duration_seconds = $3, note = $4, billable = $5, updated_at = $6
+		 WHERE id = $7 AND team_id = $8`,
+		e.StartedAt,
+		e.EndedAt,
+		e.Duration,
+		e.Note,
+		e.Billable,
+		now,
+		e.ID,
+		e.TeamID,
+	)
+	if err := expectAffected(res, err); err != nil {
+		return err
+	}
+	e.UpdatedAt = now
+	return nil
+}
+
+func (r *TimeEntryRepository) DeleteTimeEntry(ctx context.Context, teamID uuid.UUID, entryID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM time_entries WHERE id = $1 AND team_id = $2`, entryID, teamID)
+	return expectAffected(res, err)
+}
+
+func (r *TimeEntryRepository) ListTimesheet(ctx context.Context, teamID uuid.UUID, filter models.TimesheetFilter) ([]*models.TimesheetEntry, error) {
+	query := `SELECT te.id, te.team_id, te.task_id, te.user_id, te.started_at, te.ended_at, te.duration_seconds, te.note,
+		 te.billable, te.created_at, te.updated_at, TRIM(u.first_name || ' ' || u.last_name), tk.title, tk.project_id,
+		 COALESCE(p.name, ''), t.name
+		 FROM time_entries te
+		 JOIN users u ON u.id = te.user_id
+		 JOIN tasks tk ON tk.id = te.task_id
+		 JOIN teams t ON t.id = te.team_id
+		 LEFT JOIN projects p ON p.id = tk.project_id
+		 WHERE te.team_id = $1 AND tk.deleted_at IS NULL AND te.ended_at IS NOT NULL AND te.started_at >= $2 AND te.started_at < $3`
+	args := []any{teamID, filter.From, filter.To}
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		query += fmt.Sprintf(` AND te.user_id = $%d`, len(args))
+	}
+	if filter.TaskID != nil {
+		args = append(args, *filter.TaskID)
+		query += fmt.Sprintf(` AND te.task_id = $%d`, len(args))
+	}
+	if filter.ProjectID != nil {
+		args = append(args, *filter.ProjectID)
+		query += fmt.Sprintf(` AND tk.project_id = $%d`, len(args))
+	}
+	if filter.Billable != nil {
+		args = append(args, *filter.Billable)
+		query += fmt.Sprintf(` AND te.billable = $%d`, len(args))
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY te.started_at, te.id`, args...)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	entries := []*models.TimesheetEntry{}
+	for rows.Next() {
+		var e models.TimesheetEntry
+		err := rows.Scan(
+			&e.ID, &e.TeamID, &e.TaskID, &e.UserID, &e.StartedAt, &e.EndedAt, &e.Duration, &e.Note,
+			&e.Billable, &e.CreatedAt, &e.UpdatedAt, &e.UserName, &e.TaskTitle, &e.ProjectID,
+			&e.ProjectName, &e.TeamName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
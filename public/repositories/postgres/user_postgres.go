@@ -2,8 +2,8 @@ package postgress
 
 import (
 	"context"
-	"database/sql"
-	"errors"
+	"fmt"
+	"strings"
 	dbx "task_manager/public/db"
 	"task_manager/public/repositories/models"
 	"time"
@@ -29,21 +29,18 @@ func (r *UserRepository) CreateUser(ctx context.Context, u *models.User) error {
 		u.Email,
 		u.UserType,
 	)
-	return err
+	return TranslateError(err)
 }
 
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	var u models.User
 	err := r.db.QueryRowContext(
 		ctx,
-		`SELECT id, first_name, last_name, email, created_at, updated_at, user_type FROM users WHERE email = $1`,
+		`SELECT id, first_name, last_name, email, created_at, updated_at, user_type, disabled_at, password_reset_required FROM users WHERE email = $1`,
 		email,
-	).Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType)
+	).Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType, &u.DisabledAt, &u.PasswordResetRequired)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	return &u, nil
 }
@@ -52,14 +49,11 @@ func (r *UserRepository) GetUserByID(ctx context.Context, userID uuid.UUID) (*mo
 	var u models.User
 	err := r.db.QueryRowContext(
 		ctx,
-		`SELECT id, first_name, last_name, email, created_at, updated_at, user_type FROM users WHERE id = $1`,
+		`SELECT id, first_name, last_name, email, created_at, updated_at, user_type, disabled_at, password_reset_required FROM users WHERE id = $1`,
 		userID,
-	).Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType)
+	).Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType, &u.DisabledAt, &u.PasswordResetRequired)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	return &u, nil
 }
@@ -77,17 +71,14 @@ func (r *UserRepository) UpsertPassword(ctx context.Context, userID uuid.UUID, p
 		passwordHash,
 		now,
 	)
-	return err
+	return TranslateError(err)
 }
 
 func (r *UserRepository) GetPasswordHashByUserID(ctx context.Context, userID uuid.UUID) (string, error) {
 	var v string
 	err := r.db.QueryRowContext(ctx, `SELECT v FROM passwords WHERE user_id = $1`, userID).Scan(&v)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return "", nil
-		}
-		return "", err
+		return "", TranslateError(err)
 	}
 	return v, nil
 }
@@ -96,18 +87,15 @@ func (r *UserRepository) GetUserByAuthProvider(ctx context.Context, provider mod
 	var u models.User
 	err := r.db.QueryRowContext(
 		ctx,
-		`SELECT u.id, u.first_name, u.last_name, u.email, u.created_at, u.updated_at, u.user_type
+		`SELECT u.id, u.first_name, u.last_name, u.email, u.created_at, u.updated_at, u.user_type, u.disabled_at, u.password_reset_required
 		 FROM auth_providers ap
 		 JOIN users u ON u.id = ap.user_id
 		 WHERE ap.provider = $1 AND ap.provider_user_id = $2`,
 		provider,
 		providerUserID,
-	).Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType)
+	).Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType, &u.DisabledAt, &u.PasswordResetRequired)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	return &u, nil
 }
@@ -123,10 +111,7 @@ func (r *UserRepository) GetAuthProviderByUserAndProvider(ctx context.Context, u
 		provider,
 	).Scan(&ap.ID, &ap.UserID, &ap.Provider, &ap.ProviderUserID, &ap.Email, &ap.Username, &ap.DisplayName, &ap.AvatarURL, &ap.CreatedAt, &ap.UpdatedAt)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	return &ap, nil
 }
@@ -141,7 +126,7 @@ func (r *UserRepository) ListAuthProvidersByUserID(ctx context.Context, userID u
 		userID,
 	)
 	if err != nil {
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	defer rows.Close()
 
@@ -175,5 +160,133 @@ func (r *UserRepository) CreateAuthProvider(ctx context.Context, ap *models.Auth
 		ap.CreatedAt,
 		ap.UpdatedAt,
 	)
-	return err
+	return TranslateError(err)
+}
+
+func (r *UserRepository) ListUsers(ctx context.Context, filter models.UserListFilter) ([]*models.User, int, error) {
+	where := []string{"1 = 1"}
+	var args []any
+	if q := strings.ToLower(strings.TrimSpace(filter.Query)); q != "" {
+		args = append(args, "%"+q+"%")
+		n := len(args)
+		where = append(where, fmt.Sprintf("(LOWER(email) LIKE $%d OR LOWER(first_name) LIKE $%d OR LOWER(last_name) LIKE $%d)", n, n, n))
+	}
+	if filter.UserType != "" {
+		args = append(args, string(filter.UserType))
+		where = append(where, fmt.Sprintf("user_type = $%d", len(args)))
+	}
+	if filter.Disabled != nil {
+		if *filter.Disabled {
+			where = append(where, "disabled_at IS NOT NULL")
+		} else {
+			where = append(where, "disabled_at IS NULL")
+		}
+	}
+	cond := strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE `+cond, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, first_name, last_name, email, created_at, updated_at, user_type, disabled_at, password_reset_required
+		 FROM users
+		 WHERE `+cond+fmt.Sprintf(`
+		 ORDER BY created_at ASC, id ASC
+		 LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2),
+		append(args, filter.Limit, filter.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, TranslateError(err)
+	}
+	defer rows.Close()
+
+	var out []*models.User
+	for rows.Next() {
+		var u models.User
+		if err := rows.Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType, &u.DisabledAt, &u.PasswordResetRequired); err != nil {
+			return nil, 0, err
+		}
+		out = append(out, &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+func (r *UserRepository) SetUserDisabled(ctx context.Context, userID uuid.UUID, disabled bool) error {
+	now := time.Now()
+	var disabledAt *time.Time
+	if disabled {
+		disabledAt = &now
+	}
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE users SET disabled_at = $1, updated_at = $2 WHERE id = $3`,
+		disabledAt,
+		now,
+		userID,
+	)
+	return expectAffected(res, err)
+}
+
+func (r *UserRepository) UpdateUserType(ctx context.Context, userID uuid.UUID, userType models.UserType) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE users SET user_type = $1, updated_at = $2 WHERE id = $3`,
+		string(userType),
+		time.Now(),
+		userID,
+	)
+	return expectAffected(res, err)
+}
+
+func (r *UserRepository) SetPasswordResetRequired(ctx context.Context, userID uuid.UUID, required bool) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE users SET password_reset_required = $1, updated_at = $2 WHERE id = $3`,
+		required,
+		time.Now(),
+		userID,
+	)
+	return expectAffected(res, err)
+}
+
+func (r *UserRepository) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	return expectAffected(res, err)
+}
+
+func (r *UserRepository) CreatePasswordResetToken(ctx context.Context, t *models.PasswordResetToken) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4, $5)`,
+		t.ID,
+		t.UserID,
+		t.TokenHash,
+		t.ExpiresAt,
+		t.CreatedAt,
+	)
+	return TranslateError(err)
+}
+
+func (r *UserRepository) GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	var t models.PasswordResetToken
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT id, user_id, token_hash, expires_at, created_at FROM password_reset_tokens WHERE token_hash = $1`,
+		tokenHash,
+	).Scan(&t.ID, &t.UserID, &t.TokenHash, &t.ExpiresAt, &t.CreatedAt)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return &t, nil
+}
+
+func (r *UserRepository) DeletePasswordResetTokensByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM password_reset_tokens WHERE user_id = $1`, userID)
+	return TranslateError(err)
 }
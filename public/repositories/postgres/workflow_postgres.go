@@ -0,0 +1,129 @@
+package postgress
+
+import (
+	"context"
+	"encoding/json"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+
+	"github.com/google/uuid"
+)
+
+type WorkflowRepository struct {
+	db dbx.DBTX
+}
+
+func NewWorkflowRepository(db dbx.DBTX) *WorkflowRepository {
+	return &WorkflowRepository{db: db}
+}
+
+func (r *WorkflowRepository) GetWorkflow(ctx context.Context, teamID uuid.UUID) (*models.Workflow, error) {
+	w := &models.Workflow{States: []models.WorkflowState{}, Transitions: []models.WorkflowTransition{}}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, team_id, name, category, position FROM workflow_states WHERE team_id = $1 ORDER BY position, id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var s models.WorkflowState
+		if err := rows.Scan(&s.ID, &s.TeamID, &s.Name, &s.Category, &s.Position); err != nil {
+			return nil, err
+		}
+		w.States = append(w.States, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	trows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, team_id, from_state_id, to_state_id, roles FROM workflow_transitions WHERE team_id = $1 ORDER BY id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer trows.Close()
+	for trows.Next() {
+		var t models.WorkflowTransition
+		var roles string
+		if err := trows.Scan(&t.ID, &t.TeamID, &t.FromStateID, &t.ToStateID, &roles); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(roles), &t.Roles); err != nil {
+			return nil, err
+		}
+		w.Transitions = append(w.Transitions, t)
+	}
+	return w, trows.Err()
+}
+
+func (r *WorkflowRepository) SaveWorkflow(ctx context.Context, teamID uuid.UUID, w *models.Workflow) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM workflow_transitions WHERE team_id = $1`, teamID); err != nil {
+		return TranslateError(err)
+	}
+	// Free the names first so states can swap names without tripping the unique index.
+	if _, err := r.db.ExecContext(ctx, `UPDATE workflow_states SET name = id::text WHERE team_id = $1`, teamID); err != nil {
+		return TranslateError(err)
+	}
+
+	keep := []any{teamID}
+	for i := range w.States {
+		s := &w.States[i]
+		s.TeamID = teamID
+		s.Position = i
+		_, err := r.db.ExecContext(
+			ctx,
+			`INSERT INTO workflow_states (id, team_id, name, category, position) VALUES ($1, $2, $3, $4, $5)
+			 ON CONFLICT (id) DO UPDATE SET name = excluded.name, category = excluded.category, position = excluded.position
+			 WHERE workflow_states.team_id = excluded.team_id`,
+			s.ID,
+			teamID,
+			s.Name,
+			string(s.Category),
+			s.Position,
+		)
+		if err != nil {
+			return TranslateError(err)
+		}
+		keep = append(keep, s.ID)
+	}
+	_, err := r.db.ExecContext(
+		ctx,
+		`DELETE FROM workflow_states WHERE team_id = $1 AND id NOT IN (`+placeholders(2, len(keep)-1)+`)`,
+		keep...,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+
+	for i := range w.Transitions {
+		t := &w.Transitions[i]
+		t.TeamID = teamID
+		if t.Roles == nil {
+			t.Roles = []models.TeamUserRole{}
+		}
+		roles, err := json.Marshal(t.Roles)
+		if err != nil {
+			return err
+		}
+		_, err = r.db.ExecContext(
+			ctx,
+			`INSERT INTO workflow_transitions (id, team_id, from_state_id, to_state_id, roles) VALUES ($1, $2, $3, $4, $5)`,
+			t.ID,
+			teamID,
+			t.FromStateID,
+			t.ToStateID,
+			string(roles),
+		)
+		if err != nil {
+			return TranslateError(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+// Package repoerr holds the driver-independent repository errors.
+//
+// It is a leaf package so the driver implementations can produce these errors without
+// importing package repositories (which imports them). Callers should use the aliases
+// exported by package repositories.
+package repoerr
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when a lookup, update or delete matches no row.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned for unique or primary key violations.
+	ErrConflict = errors.New("conflict")
+	// ErrForeignKey is returned when a write references a row that does not exist
+	// (or deletes one that is still referenced).
+	ErrForeignKey = errors.New("foreign key violation")
+	// ErrSerialization is returned when the database aborted the statement or transaction
+	// because of concurrent access; the operation can be retried.
+	ErrSerialization = errors.New("serialization failure")
+)
@@ -0,0 +1,137 @@
+package sqlite
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AttachmentRepository struct {
+	db dbx.DBTX
+}
+
+func NewAttachmentRepository(db dbx.DBTX) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+const attachmentColumns = `id, team_id, task_id, comment_id, uploaded_by, filename, content_type, size_bytes, storage_key, created_at`
+
+func scanAttachment(s rowScanner) (*models.Attachment, error) {
+	var a models.Attachment
+	if err := s.Scan(&a.ID, &a.TeamID, &a.TaskID, &a.CommentID, &a.UploadedBy, &a.Filename, &a.ContentType, &a.SizeBytes, &a.StorageKey, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AttachmentRepository) CreateAttachment(ctx context.Context, a *models.Attachment) error {
+	a.CreatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO attachments (id, team_id, task_id, comment_id, uploaded_by, filename, content_type, size_bytes, storage_key, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.ID.String(),
+		a.TeamID.String(),
+		a.TaskID.String(),
+		nullableUUID(a.CommentID),
+		nullableUUID(a.UploadedBy),
+		a.Filename,
+		a.ContentType,
+		a.SizeBytes,
+		a.StorageKey,
+		a.CreatedAt,
+	)
+	return TranslateError(err)
+}
+
+func (r *AttachmentRepository) GetAttachment(ctx context.Context, taskID uuid.UUID, attachmentID uuid.UUID) (*models.Attachment, error) {
+	a, err := scanAttachment(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+attachmentColumns+` FROM attachments WHERE id = ? AND task_id = ?`,
+		attachmentID.String(),
+		taskID.String(),
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return a, nil
+}
+
+func (r *AttachmentRepository) ListTaskAttachments(ctx context.Context, taskID uuid.UUID) ([]*models.Attachment, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+attachmentColumns+` FROM attachments WHERE task_id = ? ORDER BY created_at, id`,
+		taskID.String(),
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	attachments := []*models.Attachment{}
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+func (r *AttachmentRepository) DeleteAttachment(ctx context.Context, taskID uuid.UUID, attachmentID uuid.UUID) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`DELETE FROM attachments WHERE id = ? AND task_id = ?`,
+		attachmentID.String(),
+		taskID.String(),
+	)
+	return expectAffected(res, err)
+}
+
+// LockTeamStorage is a no-op write on the team row; sqlite runs one writer at a time,
+// so the transaction holds the write lock until it ends.
+func (r *AttachmentRepository) LockTeamStorage(ctx context.Context, teamID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE teams SET id = id WHERE id = ?`, teamID.String())
+	return expectAffected(res, err)
+}
+
+func (r *AttachmentRepository) TeamStorageUsed(ctx context.Context, teamID uuid.UUID) (int64, error) {
+	var used int64
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT COALESCE(SUM(size_bytes), 0) FROM attachments WHERE team_id = ?`,
+		teamID.String(),
+	).Scan(&used)
+	return used, TranslateError(err)
+}
+
+func (r *AttachmentRepository) ListBlobDeletions(ctx context.Context, limit int) ([]*models.BlobDeletion, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, storage_key, created_at FROM blob_deletions ORDER BY id LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	var deletions []*models.BlobDeletion
+	for rows.Next() {
+		var d models.BlobDeletion
+		if err := rows.Scan(&d.ID, &d.StorageKey, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		deletions = append(deletions, &d)
+	}
+	return deletions, rows.Err()
+}
+
+func (r *AttachmentRepository) DeleteBlobDeletion(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM blob_deletions WHERE id = ?`, id)
+	return TranslateError(err)
+}
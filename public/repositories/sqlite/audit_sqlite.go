@@ -0,0 +1,188 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	dbx "task_manager/public/db"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories/listspec"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditRepository struct {
+	db dbx.DBTX
+}
+
+func NewAuditRepository(db dbx.DBTX) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) CreateAdminAuditLog(ctx context.Context, entry *models.AdminAuditLog) error {
+	var details *string
+	if len(entry.Details) > 0 {
+		b, err := json.Marshal(entry.Details)
+		if err != nil {
+			return err
+		}
+		s := string(b)
+		details = &s
+	}
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO admin_audit_logs (id, actor_id, action, target_user_id, details, trace_id, created_at)
+		 VALUES (?, ?, ?, ?, ?, NULLIF(?, ''), ?)`,
+		entry.ID.String(),
+		nullableUUID(entry.ActorID),
+		string(entry.Action),
+		nullableUUID(entry.TargetUserID),
+		details,
+		entry.TraceID,
+		entry.CreatedAt.UTC(),
+	)
+	return TranslateError(err)
+}
+
+func (r *AuditRepository) ListAdminAuditLogs(ctx context.Context, filter models.AdminAuditFilter) ([]*models.AdminAuditLog, int, error) {
+	where := []string{"1 = 1"}
+	var args []any
+	if filter.ActorID != nil {
+		where = append(where, "actor_id = ?")
+		args = append(args, filter.ActorID.String())
+	}
+	if filter.TargetUserID != nil {
+		where = append(where, "target_user_id = ?")
+		args = append(args, filter.TargetUserID.String())
+	}
+	if filter.Action != "" {
+		where = append(where, "action = ?")
+		args = append(args, string(filter.Action))
+	}
+	cond := strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM admin_audit_logs WHERE `+cond, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, actor_id, action, target_user_id, details, COALESCE(trace_id, ''), created_at
+		 FROM admin_audit_logs
+		 WHERE `+cond+`
+		 ORDER BY created_at DESC, id DESC
+		 LIMIT ? OFFSET ?`,
+		append(args, filter.Limit, filter.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, TranslateError(err)
+	}
+	defer rows.Close()
+
+	var out []*models.AdminAuditLog
+	for rows.Next() {
+		var e models.AdminAuditLog
+		var actorID, targetID uuid.NullUUID
+		var details sql.NullString
+		if err := rows.Scan(&e.ID, &actorID, &e.Action, &targetID, &details, &e.TraceID, &e.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		if actorID.Valid {
+			e.ActorID = &actorID.UUID
+		}
+		if targetID.Valid {
+			e.TargetUserID = &targetID.UUID
+		}
+		if details.Valid && details.String != "" {
+			if err := json.Unmarshal([]byte(details.String), &e.Details); err != nil {
+				return nil, 0, err
+			}
+		}
+		out = append(out, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+func (r *AuditRepository) CreateTeamAuditLog(ctx context.Context, entry *models.TeamAuditLog) error {
+	var details *string
+	if len(entry.Details) > 0 {
+		b, err := json.Marshal(entry.Details)
+		if err != nil {
+			return err
+		}
+		s := string(b)
+		details = &s
+	}
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO team_audit_logs (id, team_id, actor_id, action, details, ip, user_agent, trace_id, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?)`,
+		entry.ID.String(),
+		entry.TeamID.String(),
+		nullableUUID(entry.ActorID),
+		string(entry.Action),
+		details,
+		entry.IP,
+		entry.UserAgent,
+		entry.TraceID,
+		entry.CreatedAt.UTC(),
+	)
+	return TranslateError(err)
+}
+
+const teamAuditColumns = `al.id, al.team_id, al.actor_id, al.action, al.details, al.ip, al.user_agent, COALESCE(al.trace_id, ''), al.created_at`
+
+func (r *AuditRepository) ListTeamAuditLogs(ctx context.Context, teamID uuid.UUID, q listquery.Query) (listquery.Result[*models.TeamAuditLog], error) {
+	const from = `FROM team_audit_logs al WHERE al.team_id = ?`
+	s := q.Build(dialect, teamID.String())
+	rows, err := r.db.QueryContext(ctx, `SELECT `+teamAuditColumns+` `+from+` AND `+s.Where+s.Suffix(), s.Args...)
+	if err != nil {
+		return listquery.Result[*models.TeamAuditLog]{}, TranslateError(err)
+	}
+	defer rows.Close()
+
+	var entries []*models.TeamAuditLog
+	for rows.Next() {
+		var e models.TeamAuditLog
+		var details sql.NullString
+		if err := rows.Scan(&e.ID, &e.TeamID, &e.ActorID, &e.Action, &details, &e.IP, &e.UserAgent, &e.TraceID, &e.CreatedAt); err != nil {
+			return listquery.Result[*models.TeamAuditLog]{}, err
+		}
+		if details.Valid && details.String != "" {
+			if err := json.Unmarshal([]byte(details.String), &e.Details); err != nil {
+				return listquery.Result[*models.TeamAuditLog]{}, err
+			}
+		}
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return listquery.Result[*models.TeamAuditLog]{}, err
+	}
+	res := listquery.Paginate(q, entries, listspec.TeamAuditRow)
+	res.Total, err = listTotal(ctx, r.db, q, from, s)
+	return res, err
+}
+
+func (r *AuditRepository) PurgeTeamAuditLogs(ctx context.Context, before time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM team_audit_logs WHERE created_at < ?`, before.UTC())
+	if err != nil {
+		return 0, TranslateError(err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// nullableUUID converts an optional id into a value sqlite can store as NULL.
+func nullableUUID(id *uuid.UUID) any {
+	if id == nil {
+		return nil
+	}
+	return id.String()
+}
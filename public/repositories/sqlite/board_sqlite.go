@@ -0,0 +1,266 @@
+package sqlite
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BoardRepository struct {
+	db dbx.DBTX
+}
+
+func NewBoardRepository(db dbx.DBTX) *BoardRepository {
+	return &BoardRepository{db: db}
+}
+
+const boardColumns = `id, team_id, name, swimlane_by, swimlane_field_id, created_by, created_at, updated_at`
+
+const boardColumnColumns = `id, board_id, state_id, name, position, wip_limit, wip_mode`
+
+// cardOrder sorts the tasks of a column: ranked cards first, then the others oldest first.
+const cardOrder = `ORDER BY bc.rank IS NULL, bc.rank, tk.created_at, tk.id`
+
+func scanBoard(s rowScanner) (*models.Board, error) {
+	var b models.Board
+	if err := s.Scan(&b.ID, &b.TeamID, &b.Name, &b.SwimlaneBy, &b.SwimlaneFieldID, &b.CreatedBy, &b.CreatedAt, &b.UpdatedAt); err != nil {
+		return nil, err
+	}
+	b.Columns = []models.BoardColumn{}
+	return &b, nil
+}
+
+func (r *BoardRepository) ListBoards(ctx context.Context, teamID uuid.UUID) ([]*models.Board, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+boardColumns+` FROM boards WHERE team_id = ? ORDER BY name, id`,
+		teamID.String(),
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	boards := []*models.Board{}
+	for rows.Next() {
+		b, err := scanBoard(rows)
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return boards, r.loadColumns(ctx, boards)
+}
+
+func (r *BoardRepository) GetBoard(ctx context.Context, teamID uuid.UUID, boardID uuid.UUID) (*models.Board, error) {
+	b, err := scanBoard(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+boardColumns+` FROM boards WHERE id = ? AND team_id = ?`,
+		boardID.String(),
+		teamID.String(),
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return b, r.loadColumns(ctx, []*models.Board{b})
+}
+
+func (r *BoardRepository) loadColumns(ctx context.Context, boards []*models.Board) error {
+	if len(boards) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Board, len(boards))
+	args := make([]any, 0, len(boards))
+	for _, b := range boards {
+		byID[b.ID] = b
+		args = append(args, b.ID.String())
+	}
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+boardColumnColumns+` FROM board_columns
+		 WHERE board_id IN (`+placeholders(len(args))+`)
+		 ORDER BY position, id`,
+		args...,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c models.BoardColumn
+		if err := rows.Scan(&c.ID, &c.BoardID, &c.StateID, &c.Name, &c.Position, &c.WIPLimit, &c.WIPMode); err != nil {
+			return err
+		}
+		byID[c.BoardID].Columns = append(byID[c.BoardID].Columns, c)
+	}
+	return rows.Err()
+}
+
+func (r *BoardRepository) CreateBoard(ctx context.Context, b *models.Board) error {
+	now := time.Now().UTC()
+	b.CreatedAt = now
+	b.UpdatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO boards (id, team_id, name, swimlane_by, swimlane_field_id, created_by, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		b.ID.String(),
+		b.TeamID.String(),
+		b.Name,
+		string(b.SwimlaneBy),
+		nullableUUID(b.SwimlaneFieldID),
+		nullableUUID(b.CreatedBy),
+		now,
+		now,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	return r.SetBoardColumns(ctx, b.ID, b.Columns)
+}
+
+func (r *BoardRepository) UpdateBoard(ctx context.Context, b *models.Board) error {
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE boards SET name = ?, swimlane_by = ?, swimlane_field_id = ?, updated_at = ? WHERE id = ? AND team_id = ?`,
+		b.Name,
+		string(b.SwimlaneBy),
+		nullableUUID(b.SwimlaneFieldID),
+		now,
+		b.ID.String(),
+		b.TeamID.String(),
+	)
+	if err := expectAffected(res, err); err != nil {
+		return err
+	}
+	b.UpdatedAt = now
+	return nil
+}
+
+func (r *BoardRepository) SetBoardColumns(ctx context.Context, boardID uuid.UUID, columns []models.BoardColumn) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM board_columns WHERE board_id = ?`, boardID.String()); err != nil {
+		return TranslateError(err)
+	}
+	for i := range columns {
+		c := &columns[i]
+		c.BoardID = boardID
+		c.Position = i
+		_, err := r.db.ExecContext(
+			ctx,
+			`INSERT INTO board_columns (id, board_id, state_id, name, position, wip_limit, wip_mode) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			c.ID.String(),
+			boardID.String(),
+			c.StateID.String(),
+			c.Name,
+			c.Position,
+			c.WIPLimit,
+			string(c.WIPMode),
+		)
+		if err != nil {
+			return TranslateError(err)
+		}
+	}
+	return nil
+}
+
+func (r *BoardRepository) DeleteBoard(ctx context.Context, teamID uuid.UUID, boardID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM boards WHERE id = ? AND team_id = ?`, boardID.String(), teamID.String())
+	return expectAffected(res, err)
+}
+
+// LockBoard takes the database write lock with a no-op update; sqlite has no row locks,
+// but it only allows one writing transaction at a time.
+func (r *BoardRepository) LockBoard(ctx context.Context, teamID uuid.UUID, boardID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE boards SET id = id WHERE id = ? AND team_id = ?`, boardID.String(), teamID.String())
+	return expectAffected(res, err)
+}
+
+func (r *BoardRepository) ListColumnCards(ctx context.Context, b *models.Board, stateID uuid.UUID) ([]models.BoardCard, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT tk.id, COALESCE(bc.rank, '') FROM tasks tk
+		 LEFT JOIN board_cards bc ON bc.board_id = ? AND bc.task_id = tk.id
+		 WHERE tk.team_id = ? AND tk.state_id = ? AND tk.deleted_at IS NULL `+cardOrder,
+		b.ID.String(),
+		b.TeamID.String(),
+		stateID.String(),
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	cards := []models.BoardCard{}
+	for rows.Next() {
+		var c models.BoardCard
+		if err := rows.Scan(&c.TaskID, &c.Rank); err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, rows.Err()
+}
+
+func (r *BoardRepository) ListColumnTasks(ctx context.Context, b *models.Board, stateID uuid.UUID, limit int) ([]*models.Task, int, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+taskColumns+` FROM tasks tk
+		 LEFT JOIN board_cards bc ON bc.board_id = ? AND bc.task_id = tk.id
+		 WHERE tk.team_id = ? AND tk.state_id = ? AND tk.deleted_at IS NULL `+cardOrder+` LIMIT ?`,
+		b.ID.String(),
+		b.TeamID.String(),
+		stateID.String(),
+		limit,
+	)
+	if err != nil {
+		return nil, 0, TranslateError(err)
+	}
+	defer rows.Close()
+
+	tasks := []*models.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if err := (&TaskRepository{db: r.db}).loadRelations(ctx, tasks); err != nil {
+		return nil, 0, err
+	}
+	count, err := r.CountStateTasks(ctx, b.TeamID, stateID)
+	return tasks, count, err
+}
+
+func (r *BoardRepository) CountStateTasks(ctx context.Context, teamID uuid.UUID, stateID uuid.UUID) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*) FROM tasks WHERE team_id = ? AND state_id = ? AND deleted_at IS NULL`,
+		teamID.String(),
+		stateID.String(),
+	).Scan(&n)
+	return n, TranslateError(err)
+}
+
+func (r *BoardRepository) SetCardRank(ctx context.Context, boardID uuid.UUID, taskID uuid.UUID, rank string) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO board_cards (board_id, task_id, rank) VALUES (?, ?, ?)
+		 ON CONFLICT (board_id, task_id) DO UPDATE SET rank = excluded.rank`,
+		boardID.String(),
+		taskID.String(),
+		rank,
+	)
+	return TranslateError(err)
+}
@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ChecklistRepository struct {
+	db dbx.DBTX
+}
+
+func NewChecklistRepository(db dbx.DBTX) *ChecklistRepository {
+	return &ChecklistRepository{db: db}
+}
+
+func (r *ChecklistRepository) ListChecklistItems(ctx context.Context, taskID uuid.UUID) ([]*models.ChecklistItem, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, task_id, content, done, position, created_at, updated_at
+		 FROM task_checklist_items WHERE task_id = ? ORDER BY position, id`,
+		taskID.String(),
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	items := []*models.ChecklistItem{}
+	for rows.Next() {
+		var it models.ChecklistItem
+		if err := rows.Scan(&it.ID, &it.TaskID, &it.Content, &it.Done, &it.Position, &it.CreatedAt, &it.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &it)
+	}
+	return items, rows.Err()
+}
+
+func (r *ChecklistRepository) GetChecklistItem(ctx context.Context, taskID uuid.UUID, itemID uuid.UUID) (*models.ChecklistItem, error) {
+	var it models.ChecklistItem
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT id, task_id, content, done, position, created_at, updated_at
+		 FROM task_checklist_items WHERE id = ? AND task_id = ?`,
+		itemID.String(),
+		taskID.String(),
+	).Scan(&it.ID, &it.TaskID, &it.Content, &it.Done, &it.Position, &it.CreatedAt, &it.UpdatedAt)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return &it, nil
+}
+
+func (r *ChecklistRepository) CreateChecklistItem(ctx context.Context, item *models.ChecklistItem) error {
+	now := time.Now().UTC()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	err := r.db.QueryRowContext(
+		ctx,
+		`INSERT INTO task_checklist_items (id, task_id, content, done, position, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, (SELECT COALESCE(MAX(position) + 1, 0) FROM task_checklist_items WHERE task_id = ?), ?, ?)
+		 RETURNING position`,
+		item.ID.String(),
+		item.TaskID.String(),
+		item.Content,
+		item.Done,
+		item.TaskID.String(),
+		now,
+		now,
+	).Scan(&item.Position)
+	return TranslateError(err)
+}
+
+func (r *ChecklistRepository) UpdateChecklistItem(ctx context.Context, item *models.ChecklistItem) error {
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE task_checklist_items SET content = ?, done = ?, updated_at = ? WHERE id = ? AND task_id = ?`,
+		item.Content,
+		item.Done,
+		now,
+		item.ID.String(),
+		item.TaskID.String(),
+	)
+	item.UpdatedAt = now
+	return expectAffected(res, err)
+}
+
+func (r *ChecklistRepository) DeleteChecklistItem(ctx context.Context, taskID uuid.UUID, itemID uuid.UUID) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`DELETE FROM task_checklist_items WHERE id = ? AND task_id = ?`,
+		itemID.String(),
+		taskID.String(),
+	)
+	return expectAffected(res, err)
+}
+
+func (r *ChecklistRepository) ReorderChecklistItems(ctx context.Context, taskID uuid.UUID, itemIDs []uuid.UUID) error {
+	for i, id := range itemIDs {
+		res, err := r.db.ExecContext(
+			ctx,
+			`UPDATE task_checklist_items SET position = ? WHERE id = ? AND task_id = ?`,
+			i,
+			id.String(),
+			taskID.String(),
+		)
+		if err := expectAffected(res, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
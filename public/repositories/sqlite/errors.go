@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"task_manager/public/repositories/repoerr"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// TranslateError maps modernc sqlite errors onto the repository errors.
+// The original error is kept in the chain for logging.
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return repoerr.ErrNotFound
+	}
+	var se *sqlite.Error
+	if !errors.As(err, &se) {
+		return err
+	}
+	switch se.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return fmt.Errorf("%w: %w", repoerr.ErrConflict, err)
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return fmt.Errorf("%w: %w", repoerr.ErrForeignKey, err)
+	}
+	// Extended result codes carry the primary code in the low byte (e.g. SQLITE_BUSY_SNAPSHOT).
+	switch se.Code() & 0xff {
+	case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+		return fmt.Errorf("%w: %w", repoerr.ErrSerialization, err)
+	}
+	return err
+}
+
+// expectAffected translates err and reports ErrNotFound when the statement touched no row.
+func expectAffected(res sql.Result, err error) error {
+	if err != nil {
+		return TranslateError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repoerr.ErrNotFound
+	}
+	return nil
+}
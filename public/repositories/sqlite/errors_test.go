@@ -0,0 +1,61 @@
+package sqlite_test
+
+import (
+	"context"
+	"task_manager/public/repositories"
+	"task_manager/public/repositories/models"
+	"task_manager/public/repositories/sqlite"
+	"task_manager/public/testutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_TypedErrors(t *testing.T) {
+	ctx := context.Background()
+	repo := sqlite.NewUserRepository(testutil.NewSQLiteTestDB(t))
+
+	u := &models.User{ID: uuid.New(), FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", UserType: models.StandardUser}
+	require.NoError(t, repo.CreateUser(ctx, u))
+
+	now := time.Now()
+	tests := []struct {
+		name string
+		run  func() error
+		want error
+	}{
+		{"duplicate email", func() error {
+			dup := *u
+			dup.ID = uuid.New()
+			return repo.CreateUser(ctx, &dup)
+		}, repositories.ErrConflict},
+		{"missing user by id", func() error {
+			_, err := repo.GetUserByID(ctx, uuid.New())
+			return err
+		}, repositories.ErrNotFound},
+		{"missing password", func() error {
+			_, err := repo.GetPasswordHashByUserID(ctx, u.ID)
+			return err
+		}, repositories.ErrNotFound},
+		{"update missing user", func() error {
+			return repo.SetUserDisabled(ctx, uuid.New(), true)
+		}, repositories.ErrNotFound},
+		{"provider for unknown user", func() error {
+			return repo.CreateAuthProvider(ctx, &models.AuthProvider{
+				ID:             uuid.New(),
+				UserID:         uuid.New(),
+				Provider:       models.GithubProvider,
+				ProviderUserID: "42",
+				CreatedAt:      now,
+				UpdatedAt:      now,
+			})
+		}, repositories.ErrForeignKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, tt.run(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EstimationRepository struct {
+	db dbx.DBTX
+}
+
+func NewEstimationRepository(db dbx.DBTX) *EstimationRepository {
+	return &EstimationRepository{db: db}
+}
+
+func (r *EstimationRepository) GetEstimation(ctx context.Context, teamID uuid.UUID) (*models.Estimation, error) {
+	est := &models.Estimation{Members: []models.MemberCapacity{}}
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT estimate_unit, weekly_capacity FROM teams WHERE id = ?`,
+		teamID.String(),
+	).Scan(&est.Unit, &est.WeeklyCapacity)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT tu.user_id, TRIM(u.first_name || ' ' || u.last_name), tu.weekly_capacity
+		 FROM teams_users tu
+		 JOIN users u ON u.id = tu.user_id
+		 WHERE tu.team_id = ?
+		 ORDER BY u.first_name, u.last_name, tu.user_id`,
+		teamID.String(),
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m models.MemberCapacity
+		if err := rows.Scan(&m.UserID, &m.Name, &m.WeeklyCapacity); err != nil {
+			return nil, err
+		}
+		est.Members = append(est.Members, m)
+	}
+	return est, rows.Err()
+}
+
+func (r *EstimationRepository) UpdateEstimation(ctx context.Context, teamID uuid.UUID, est *models.Estimation) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE teams SET estimate_unit = ?, weekly_capacity = ?, updated_at = ? WHERE id = ?`,
+		est.Unit,
+		est.WeeklyCapacity,
+		time.Now().UTC(),
+		teamID.String(),
+	)
+	return expectAffected(res, err)
+}
+
+func (r *EstimationRepository) SetMemberCapacity(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, capacity *float64) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE teams_users SET weekly_capacity = ? WHERE team_id = ? AND user_id = ?`,
+		capacity,
+		teamID.String(),
+		userID.String(),
+	)
+	return expectAffected(res, err)
+}
+
+func (r *EstimationRepository) ListWorkloadTasks(ctx context.Context, teamID uuid.UUID) ([]*models.WorkloadTask, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT tk.id, tk.estimate, tk.due_at, sp.start_date, sp.end_date, ta.user_id
+		 FROM tasks tk
+		 JOIN task_assignees ta ON ta.task_id = tk.id
+		 LEFT JOIN workflow_states ws ON ws.id = tk.state_id
+		 LEFT JOIN sprints sp ON sp.id = tk.sprint_id AND sp.status IN ('planned', 'active')
+		 WHERE tk.team_id = ? AND tk.deleted_at IS NULL AND COALESCE(ws.category, '') <> 'done'
+		 ORDER BY tk.id, ta.user_id`,
+		teamID.String(),
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	tasks := []*models.WorkloadTask{}
+	var last *models.WorkloadTask
+	for rows.Next() {
+		var t models.WorkloadTask
+		var assignee uuid.UUID
+		if err := rows.Scan(&t.ID, &t.Estimate, &t.DueAt, &t.SprintStart, &t.SprintEnd, &assignee); err != nil {
+			return nil, err
+		}
+		if last == nil || last.ID != t.ID {
+			last = &t
+			tasks = append(tasks, last)
+		}
+		last.AssigneeIDs = append(last.AssigneeIDs, assignee)
+	}
+	return tasks, rows.Err()
+}
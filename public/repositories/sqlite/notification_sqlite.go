@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories/listspec"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepository struct {
+	db dbx.DBTX
+}
+
+func NewNotificationRepository(db dbx.DBTX) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+const notificationColumns = `n.id, n.user_id, n.team_id, n.kind, n.task_id, n.comment_id, n.actor_id, n.created_at, n.read_at`
+
+func (r *NotificationRepository) CreateNotifications(ctx context.Context, notifications []*models.Notification) error {
+	now := time.Now().UTC()
+	for _, n := range notifications {
+		n.CreatedAt = now
+		_, err := r.db.ExecContext(
+			ctx,
+			`INSERT INTO notifications (id, user_id, team_id, kind, task_id, comment_id, actor_id, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			n.ID.String(),
+			n.UserID.String(),
+			n.TeamID.String(),
+			string(n.Kind),
+			nullableUUID(n.TaskID),
+			nullableUUID(n.CommentID),
+			nullableUUID(n.ActorID),
+			now,
+		)
+		if err != nil {
+			return TranslateError(err)
+		}
+	}
+	return nil
+}
+
+func (r *NotificationRepository) ListUserNotifications(ctx context.Context, userID uuid.UUID, q listquery.Query) (listquery.Result[*models.Notification], error) {
+	const from = `FROM notifications n WHERE n.user_id = ?`
+	s := q.Build(dialect, userID.String())
+	rows, err := r.db.QueryContext(ctx, `SELECT `+notificationColumns+` `+from+` AND `+s.Where+s.Suffix(), s.Args...)
+	if err != nil {
+		return listquery.Result[*models.Notification]{}, TranslateError(err)
+	}
+	defer rows.Close()
+
+	var notifications []*models.Notification
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.TeamID, &n.Kind, &n.TaskID, &n.CommentID, &n.ActorID, &n.CreatedAt, &n.ReadAt); err != nil {
+			return listquery.Result[*models.Notification]{}, err
+		}
+		notifications = append(notifications, &n)
+	}
+	if err := rows.Err(); err != nil {
+		return listquery.Result[*models.Notification]{}, err
+	}
+	res := listquery.Paginate(q, notifications, listspec.NotificationRow)
+	res.Total, err = listTotal(ctx, r.db, q, from, s)
+	return res, err
+}
+
+// MarkNotificationRead marks a notification of the user as read; it is a no-op for
+// notifications that are already read.
+func (r *NotificationRepository) MarkNotificationRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE notifications SET read_at = COALESCE(read_at, ?) WHERE id = ? AND user_id = ?`,
+		time.Now().UTC(),
+		notificationID.String(),
+		userID.String(),
+	)
+	return expectAffected(res, err)
+}
@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxRepository struct {
+	db dbx.DBTX
+}
+
+func NewOutboxRepository(db dbx.DBTX) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) EnqueueEmail(ctx context.Context, e *models.OutboxEmail) error {
+	now := time.Now().UTC()
+	e.CreatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO email_outbox (id, recipient, subject, body, created_at) VALUES (?, ?, ?, ?, ?)`,
+		e.ID.String(),
+		e.Recipient,
+		e.Subject,
+		e.Body,
+		now,
+	)
+	return TranslateError(err)
+}
+
+// ClaimEmails relies on sqlite running one writing statement at a time: the subquery
+// and the update see the same rows.
+func (r *OutboxRepository) ClaimEmails(ctx context.Context, now time.Time, lockedUntil time.Time, maxAttempts int, limit int) ([]*models.OutboxEmail, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`UPDATE email_outbox SET locked_until = ?, attempts = attempts + 1
+		 WHERE id IN (
+		 SELECT id FROM email_outbox
+		 WHERE sent_at IS NULL AND attempts < ? AND (locked_until IS NULL OR locked_until <= ?)
+		 ORDER BY created_at, id LIMIT ?)
+		 RETURNING id, recipient, subject, body, attempts, created_at`,
+		lockedUntil.UTC(),
+		maxAttempts,
+		now.UTC(),
+		limit,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	emails := []*models.OutboxEmail{}
+	for rows.Next() {
+		var e models.OutboxEmail
+		if err := rows.Scan(&e.ID, &e.Recipient, &e.Subject, &e.Body, &e.Attempts, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		emails = append(emails, &e)
+	}
+	return emails, rows.Err()
+}
+
+func (r *OutboxRepository) MarkEmailSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE email_outbox SET sent_at = ?, locked_until = NULL WHERE id = ?`,
+		sentAt.UTC(),
+		id.String(),
+	)
+	return expectAffected(res, err)
+}
+
+func (r *OutboxRepository) MarkEmailFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE email_outbox SET last_error = ? WHERE id = ?`, lastError, id.String())
+	return expectAffected(res, err)
+}
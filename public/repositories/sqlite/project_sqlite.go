@@ -0,0 +1,264 @@
+package sqlite
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProjectRepository struct {
+	db dbx.DBTX
+}
+
+func NewProjectRepository(db dbx.DBTX) *ProjectRepository {
+	return &ProjectRepository{db: db}
+}
+
+const projectColumns = `id, team_id, name, description, lead_id, status, start_date, target_date, restricted, archived_at, created_by, created_at, updated_at`
+
+func scanProject(s rowScanner) (*models.Project, error) {
+	var p models.Project
+	err := s.Scan(&p.ID, &p.TeamID, &p.Name, &p.Description, &p.LeadID, &p.Status, &p.StartDate, &p.TargetDate, &p.Restricted, &p.ArchivedAt, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	p.MemberIDs = []uuid.UUID{}
+	return &p, nil
+}
+
+func (r *ProjectRepository) ListProjects(ctx context.Context, teamID uuid.UUID, includeArchived bool) ([]*models.Project, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+projectColumns+` FROM projects
+		 WHERE team_id = ? AND deleted_at IS NULL AND (? OR archived_at IS NULL)
+		 ORDER BY lower(name), id`,
+		teamID.String(),
+		includeArchived,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	projects := []*models.Project{}
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return projects, r.loadRelations(ctx, projects)
+}
+
+func (r *ProjectRepository) GetProject(ctx context.Context, teamID uuid.UUID, projectID uuid.UUID) (*models.Project, error) {
+	p, err := scanProject(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+projectColumns+` FROM projects WHERE id = ? AND team_id = ? AND deleted_at IS NULL`,
+		projectID.String(),
+		teamID.String(),
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return p, r.loadRelations(ctx, []*models.Project{p})
+}
+
+// loadRelations fills in the members and the progress of the projects.
+func (r *ProjectRepository) loadRelations(ctx context.Context, projects []*models.Project) error {
+	if len(projects) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Project, len(projects))
+	args := make([]any, 0, len(projects))
+	for _, p := range projects {
+		byID[p.ID] = p
+		args = append(args, p.ID.String())
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT project_id, user_id FROM project_members
+		 WHERE project_id IN (`+placeholders(len(args))+`)
+		 ORDER BY user_id`,
+		args...,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var projectID, userID uuid.UUID
+		if err := rows.Scan(&projectID, &userID); err != nil {
+			return err
+		}
+		byID[projectID].MemberIDs = append(byID[projectID].MemberIDs, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	progress, err := r.db.QueryContext(
+		ctx,
+		`SELECT tk.project_id, COALESCE(ws.category, ''), COUNT(*) FROM tasks tk
+		 LEFT JOIN workflow_states ws ON ws.id = tk.state_id
+		 WHERE tk.project_id IN (`+placeholders(len(args))+`) AND tk.deleted_at IS NULL
+		 GROUP BY tk.project_id, ws.category`,
+		args...,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	defer progress.Close()
+	for progress.Next() {
+		var projectID uuid.UUID
+		var category models.WorkflowCategory
+		var n int
+		if err := progress.Scan(&projectID, &category, &n); err != nil {
+			return err
+		}
+		byID[projectID].Progress.Add(category, n)
+	}
+	return progress.Err()
+}
+
+func (r *ProjectRepository) CreateProject(ctx context.Context, p *models.Project) error {
+	now := time.Now().UTC()
+	p.CreatedAt = now
+	p.UpdatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO projects (id, team_id, name, description, lead_id, status, start_date, target_date, restricted, archived_at, created_by, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.ID.String(),
+		p.TeamID.String(),
+		p.Name,
+		p.Description,
+		nullableUUID(p.LeadID),
+		string(p.Status),
+		utcTime(p.StartDate),
+		utcTime(p.TargetDate),
+		p.Restricted,
+		utcTime(p.ArchivedAt),
+		nullableUUID(p.CreatedBy),
+		now,
+		now,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	return r.SetProjectMembers(ctx, p.ID, p.MemberIDs)
+}
+
+func (r *ProjectRepository) UpdateProject(ctx context.Context, p *models.Project) error {
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE projects SET name = ?, description = ?, lead_id = ?, status = ?, start_date = ?, target_date = ?, restricted = ?, archived_at = ?, updated_at = ?
+		 WHERE id = ? AND team_id = ?`,
+		p.Name,
+		p.Description,
+		nullableUUID(p.LeadID),
+		string(p.Status),
+		utcTime(p.StartDate),
+		utcTime(p.TargetDate),
+		p.Restricted,
+		utcTime(p.ArchivedAt),
+		now,
+		p.ID.String(),
+		p.TeamID.String(),
+	)
+	if err := expectAffected(res, err); err != nil {
+		return err
+	}
+	p.UpdatedAt = now
+	return nil
+}
+
+func (r *ProjectRepository) SetProjectMembers(ctx context.Context, projectID uuid.UUID, userIDs []uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM project_members WHERE project_id = ?`, projectID.String()); err != nil {
+		return TranslateError(err)
+	}
+	for _, userID := range userIDs {
+		_, err := r.db.ExecContext(
+			ctx,
+			`INSERT INTO project_members (project_id, user_id) VALUES (?, ?)`,
+			projectID.String(),
+			userID.String(),
+		)
+		if err != nil {
+			return TranslateError(err)
+		}
+	}
+	return nil
+}
+
+// DeleteProject marks the project deleted, then its tasks and their subtasks that are
+// not in the trash yet with the project as their trash root.
+func (r *ProjectRepository) DeleteProject(ctx context.Context, teamID uuid.UUID, projectID uuid.UUID) ([]uuid.UUID, error) {
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE projects SET deleted_at = ? WHERE id = ? AND team_id = ? AND deleted_at IS NULL`,
+		now,
+		projectID.String(),
+		teamID.String(),
+	)
+	if err := expectAffected(res, err); err != nil {
+		return nil, err
+	}
+	return queryIDs(
+		ctx,
+		r.db,
+		`WITH RECURSIVE subtree (id, depth) AS (
+			SELECT id, 0 FROM tasks WHERE project_id = ? AND team_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id, s.depth + 1 FROM tasks t JOIN subtree s ON t.parent_id = s.id
+			WHERE t.deleted_at IS NULL AND s.depth < ?
+		)
+		UPDATE tasks SET deleted_at = ?, trash_root_id = ? WHERE id IN (SELECT id FROM subtree)
+		RETURNING id`,
+		projectID.String(),
+		teamID.String(),
+		maxTreeDepth,
+		now,
+		projectID.String(),
+	)
+}
+
+func (r *ProjectRepository) RestoreProject(ctx context.Context, teamID uuid.UUID, projectID uuid.UUID) ([]uuid.UUID, error) {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE projects SET deleted_at = NULL WHERE id = ? AND team_id = ? AND deleted_at IS NOT NULL`,
+		projectID.String(),
+		teamID.String(),
+	)
+	if err := expectAffected(res, err); err != nil {
+		return nil, err
+	}
+	return queryIDs(
+		ctx,
+		r.db,
+		`UPDATE tasks SET deleted_at = NULL, trash_root_id = NULL WHERE team_id = ? AND trash_root_id = ? RETURNING id`,
+		teamID.String(),
+		projectID.String(),
+	)
+}
+
+func (r *ProjectRepository) MoveProjectTasks(ctx context.Context, teamID uuid.UUID, fromID uuid.UUID, toID *uuid.UUID, taskIDs []uuid.UUID) ([]uuid.UUID, error) {
+	query := `UPDATE tasks SET project_id = ?, updated_at = ? WHERE team_id = ? AND project_id = ? AND deleted_at IS NULL`
+	args := []any{nullableUUID(toID), time.Now().UTC(), teamID.String(), fromID.String()}
+	if len(taskIDs) > 0 {
+		query += ` AND id IN (` + placeholders(len(taskIDs)) + `)`
+		for _, id := range taskIDs {
+			args = append(args, id.String())
+		}
+	}
+	return queryIDs(ctx, r.db, query+` RETURNING id`, args...)
+}
@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RecurrenceRepository struct {
+	db dbx.DBTX
+}
+
+func NewRecurrenceRepository(db dbx.DBTX) *RecurrenceRepository {
+	return &RecurrenceRepository{db: db}
+}
+
+const recurrenceColumns = `id, team_id, task_id, rule, timezone, mode, starts_at, occurrence_at, next_run_at, created_by, created_at, updated_at`
+
+func scanRecurrence(s rowScanner) (*models.Recurrence, error) {
+	var rec models.Recurrence
+	if err := s.Scan(&rec.ID, &rec.TeamID, &rec.TaskID, &rec.Rule, &rec.Timezone, &rec.Mode, &rec.StartsAt, &rec.OccurrenceAt, &rec.NextRunAt, &rec.CreatedBy, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (r *RecurrenceRepository) GetTaskRecurrence(ctx context.Context, taskID uuid.UUID) (*models.Recurrence, error) {
+	rec, err := scanRecurrence(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+recurrenceColumns+` FROM task_recurrences WHERE task_id = ?`,
+		taskID.String(),
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return rec, nil
+}
+
+func (r *RecurrenceRepository) SetTaskRecurrence(ctx context.Context, rec *models.Recurrence) error {
+	now := time.Now().UTC()
+	rec.CreatedAt = now
+	rec.UpdatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO task_recurrences (id, team_id, task_id, rule, timezone, mode, starts_at, occurrence_at, next_run_at, created_by, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (task_id) DO UPDATE SET
+		 rule = excluded.rule, timezone = excluded.timezone, mode = excluded.mode, starts_at = excluded.starts_at,
+		 occurrence_at = excluded.occurrence_at, next_run_at = excluded.next_run_at, updated_at = excluded.updated_at`,
+		rec.ID.String(),
+		rec.TeamID.String(),
+		rec.TaskID.String(),
+		rec.Rule,
+		rec.Timezone,
+		rec.Mode,
+		rec.StartsAt.UTC(),
+		rec.OccurrenceAt.UTC(),
+		utcTime(rec.NextRunAt),
+		nullableUUID(rec.CreatedBy),
+		now,
+		now,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	// On conflict the existing row keeps its id and creator.
+	saved, err := r.GetTaskRecurrence(ctx, rec.TaskID)
+	if err != nil {
+		return err
+	}
+	*rec = *saved
+	return nil
+}
+
+func (r *RecurrenceRepository) DeleteTaskRecurrence(ctx context.Context, taskID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM task_recurrences WHERE task_id = ?`, taskID.String())
+	return expectAffected(res, err)
+}
+
+func (r *RecurrenceRepository) ListDueRecurrences(ctx context.Context, now time.Time, limit int) ([]*models.Recurrence, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+recurrenceColumns+` FROM task_recurrences
+		 WHERE next_run_at IS NOT NULL AND next_run_at <= ?
+		   AND task_id NOT IN (SELECT tk.id FROM tasks tk JOIN teams t ON t.id = tk.team_id WHERE tk.deleted_at IS NOT NULL OR t.deleted_at IS NOT NULL OR t.archived_at IS NOT NULL)
+		 ORDER BY next_run_at, id LIMIT ?`,
+		now.UTC(),
+		limit,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	recs := []*models.Recurrence{}
+	for rows.Next() {
+		rec, err := scanRecurrence(rows)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return recs, rows.Err()
+}
+
+// LockRecurrence takes the database write lock with a no-op update; sqlite has no row
+// locks, but it only allows one writing transaction at a time.
+func (r *RecurrenceRepository) LockRecurrence(ctx context.Context, id uuid.UUID, taskID uuid.UUID) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE task_recurrences SET task_id = task_id WHERE id = ? AND task_id = ?`,
+		id.String(),
+		taskID.String(),
+	)
+	return expectAffected(res, err)
+}
+
+func (r *RecurrenceRepository) MoveRecurrence(ctx context.Context, rec *models.Recurrence) error {
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE task_recurrences SET task_id = ?, occurrence_at = ?, next_run_at = ?, updated_at = ? WHERE id = ?`,
+		rec.TaskID.String(),
+		rec.OccurrenceAt.UTC(),
+		utcTime(rec.NextRunAt),
+		now,
+		rec.ID.String(),
+	)
+	if err := expectAffected(res, err); err != nil {
+		return err
+	}
+	rec.UpdatedAt = now
+	return nil
+}
+
+// utcTime converts t to UTC so stored times compare as text in the order of time.
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
@@ -0,0 +1,238 @@
+package sqlite
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SprintRepository struct {
+	db dbx.DBTX
+}
+
+func NewSprintRepository(db dbx.DBTX) *SprintRepository {
+	return &SprintRepository{db: db}
+}
+
+const sprintColumns = `id, team_id, project_id, name, goal, status, start_date, end_date, capacity, committed_tasks, committed_estimate, completed_tasks, completed_estimate, carried_over, started_at, closed_at, created_by, created_at, updated_at`
+
+func scanSprint(s rowScanner) (*models.Sprint, error) {
+	var sp models.Sprint
+	var committedTasks, completedTasks *int
+	var committedEstimate, completedEstimate *float64
+	err := s.Scan(
+		&sp.ID, &sp.TeamID, &sp.ProjectID, &sp.Name, &sp.Goal, &sp.Status, &sp.StartDate, &sp.EndDate, &sp.Capacity,
+		&committedTasks, &committedEstimate, &completedTasks, &completedEstimate, &sp.CarriedOver,
+		&sp.StartedAt, &sp.ClosedAt, &sp.CreatedBy, &sp.CreatedAt, &sp.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	sp.Committed = sprintTotals(committedTasks, committedEstimate)
+	sp.Completed = sprintTotals(completedTasks, completedEstimate)
+	return &sp, nil
+}
+
+func sprintTotals(tasks *int, estimate *float64) *models.SprintTotals {
+	if tasks == nil {
+		return nil
+	}
+	t := &models.SprintTotals{Tasks: *tasks}
+	if estimate != nil {
+		t.Estimate = *estimate
+	}
+	return t
+}
+
+// totalsArgs splits t into the values of its two columns.
+func totalsArgs(t *models.SprintTotals) (any, any) {
+	if t == nil {
+		return nil, nil
+	}
+	return t.Tasks, t.Estimate
+}
+
+func (r *SprintRepository) ListSprints(ctx context.Context, teamID uuid.UUID, status models.SprintStatus) ([]*models.Sprint, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+sprintColumns+` FROM sprints
+		 WHERE team_id = ? AND (? = '' OR status = ?)
+		 ORDER BY start_date, id`,
+		teamID.String(),
+		string(status),
+		string(status),
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	sprints := []*models.Sprint{}
+	for rows.Next() {
+		sp, err := scanSprint(rows)
+		if err != nil {
+			return nil, err
+		}
+		sprints = append(sprints, sp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sprints, r.loadScope(ctx, sprints)
+}
+
+func (r *SprintRepository) GetSprint(ctx context.Context, teamID uuid.UUID, sprintID uuid.UUID) (*models.Sprint, error) {
+	sp, err := scanSprint(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+sprintColumns+` FROM sprints WHERE id = ? AND team_id = ?`,
+		sprintID.String(),
+		teamID.String(),
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return sp, r.loadScope(ctx, []*models.Sprint{sp})
+}
+
+// loadScope fills in the current totals of the tasks in the sprints.
+func (r *SprintRepository) loadScope(ctx context.Context, sprints []*models.Sprint) error {
+	if len(sprints) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Sprint, len(sprints))
+	args := make([]any, 0, len(sprints))
+	for _, sp := range sprints {
+		byID[sp.ID] = sp
+		args = append(args, sp.ID.String())
+	}
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT tk.sprint_id, COUNT(*), COALESCE(SUM(tk.estimate), 0),
+		        COALESCE(SUM(CASE WHEN ws.category = 'done' THEN 1 ELSE 0 END), 0),
+		        COALESCE(SUM(CASE WHEN ws.category = 'done' THEN tk.estimate END), 0)
+		 FROM tasks tk
+		 LEFT JOIN workflow_states ws ON ws.id = tk.state_id
+		 WHERE tk.sprint_id IN (`+placeholders(len(args))+`) AND tk.deleted_at IS NULL
+		 GROUP BY tk.sprint_id`,
+		args...,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sprintID uuid.UUID
+		var scope models.SprintScope
+		if err := rows.Scan(&sprintID, &scope.Tasks, &scope.Estimate, &scope.Done.Tasks, &scope.Done.Estimate); err != nil {
+			return err
+		}
+		byID[sprintID].Scope = scope
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, sp := range sprints {
+		sp.Scope.OverCapacity = sp.Capacity != nil && sp.Scope.Estimate > *sp.Capacity
+	}
+	return nil
+}
+
+func (r *SprintRepository) CreateSprint(ctx context.Context, sp *models.Sprint) error {
+	now := time.Now().UTC()
+	sp.CreatedAt = now
+	sp.UpdatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO sprints (id, team_id, project_id, name, goal, status, start_date, end_date, capacity, created_by, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sp.ID.String(),
+		sp.TeamID.String(),
+		nullableUUID(sp.ProjectID),
+		sp.Name,
+		sp.Goal,
+		string(sp.Status),
+		sp.StartDate.UTC(),
+		sp.EndDate.UTC(),
+		sp.Capacity,
+		nullableUUID(sp.CreatedBy),
+		now,
+		now,
+	)
+	return TranslateError(err)
+}
+
+func (r *SprintRepository) UpdateSprint(ctx context.Context, sp *models.Sprint, status models.SprintStatus) error {
+	now := time.Now().UTC()
+	committedTasks, committedEstimate := totalsArgs(sp.Committed)
+	completedTasks, completedEstimate := totalsArgs(sp.Completed)
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE sprints SET project_id = ?, name = ?, goal = ?, status = ?, start_date = ?, end_date = ?, capacity = ?,
+		        committed_tasks = ?, committed_estimate = ?, completed_tasks = ?, completed_estimate = ?, carried_over = ?,
+		        started_at = ?, closed_at = ?, updated_at = ?
+		 WHERE id = ? AND team_id = ? AND status = ?`,
+		nullableUUID(sp.ProjectID),
+		sp.Name,
+		sp.Goal,
+		string(sp.Status),
+		sp.StartDate.UTC(),
+		sp.EndDate.UTC(),
+		sp.Capacity,
+		committedTasks,
+		committedEstimate,
+		completedTasks,
+		completedEstimate,
+		sp.CarriedOver,
+		utcTime(sp.StartedAt),
+		utcTime(sp.ClosedAt),
+		now,
+		sp.ID.String(),
+		sp.TeamID.String(),
+		string(status),
+	)
+	if err := expectAffected(res, err); err != nil {
+		return err
+	}
+	sp.UpdatedAt = now
+	return nil
+}
+
+// DeleteSprint moves the tasks of the sprint back to the backlog before deleting it;
+// tasks.sprint_id has no foreign key on sqlite.
+func (r *SprintRepository) DeleteSprint(ctx context.Context, teamID uuid.UUID, sprintID uuid.UUID) ([]uuid.UUID, error) {
+	ids, err := queryIDs(
+		ctx,
+		r.db,
+		`UPDATE tasks SET sprint_id = NULL, updated_at = ? WHERE sprint_id = ? AND team_id = ? RETURNING id`,
+		time.Now().UTC(),
+		sprintID.String(),
+		teamID.String(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.db.ExecContext(ctx, `DELETE FROM sprints WHERE id = ? AND team_id = ?`, sprintID.String(), teamID.String())
+	if err := expectAffected(res, err); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *SprintRepository) CarryOverTasks(ctx context.Context, teamID uuid.UUID, fromID uuid.UUID, toID *uuid.UUID) ([]uuid.UUID, error) {
+	return queryIDs(
+		ctx,
+		r.db,
+		`UPDATE tasks SET sprint_id = ?, updated_at = ?
+		 WHERE team_id = ? AND sprint_id = ? AND deleted_at IS NULL
+		   AND (state_id IS NULL OR state_id NOT IN (SELECT id FROM workflow_states WHERE team_id = ? AND category = 'done'))
+		 RETURNING id`,
+		nullableUUID(toID),
+		time.Now().UTC(),
+		teamID.String(),
+		fromID.String(),
+		teamID.String(),
+	)
+}
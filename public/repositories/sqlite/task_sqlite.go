@@ -0,0 +1,464 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	dbx "task_manager/public/db"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories/listspec"
+	"task_manager/public/repositories/models"
+	"task_manager/public/repositories/repoerr"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaskRepository struct {
+	db dbx.DBTX
+}
+
+func NewTaskRepository(db dbx.DBTX) *TaskRepository {
+	return &TaskRepository{db: db}
+}
+
+const taskColumns = `tk.id, tk.team_id, tk.title, tk.description, tk.grouped, tk.state_id, tk.parent_id, tk.project_id, tk.sprint_id, tk.milestone_id, tk.created_by, tk.due_at, tk.estimate, tk.created_at, tk.updated_at`
+
+// maxTreeDepth bounds the recursive subtask queries; the handlers enforce a much lower limit.
+const maxTreeDepth = 64
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(s rowScanner) (*models.Task, error) {
+	var t models.Task
+	if err := s.Scan(&t.ID, &t.TeamID, &t.Title, &t.Description, &t.Grouped, &t.StateID, &t.ParentID, &t.ProjectID, &t.SprintID, &t.MilestoneID, &t.CreatedBy, &t.DueAt, &t.Estimate, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	t.AssigneeIDs = []uuid.UUID{}
+	t.LabelIDs = []uuid.UUID{}
+	t.CustomFields = map[string]any{}
+	return &t, nil
+}
+
+func (r *TaskRepository) CreateTask(ctx context.Context, t *models.Task) error {
+	now := time.Now().UTC()
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO tasks (id, team_id, title, description, grouped, state_id, parent_id, project_id, sprint_id, milestone_id, created_by, due_at, estimate, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.ID.String(),
+		t.TeamID.String(),
+		t.Title,
+		t.Description,
+		t.Grouped,
+		nullableUUID(t.StateID),
+		nullableUUID(t.ParentID),
+		nullableUUID(t.ProjectID),
+		nullableUUID(t.SprintID),
+		nullableUUID(t.MilestoneID),
+		nullableUUID(t.CreatedBy),
+		utcTime(t.DueAt),
+		t.Estimate,
+		now,
+		now,
+	)
+	return TranslateError(err)
+}
+
+func (r *TaskRepository) GetTaskByID(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID) (*models.Task, error) {
+	t, err := scanTask(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+taskColumns+` FROM tasks tk WHERE tk.id = ? AND tk.team_id = ? AND tk.deleted_at IS NULL`,
+		taskID.String(),
+		teamID.String(),
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	if err := r.loadRelations(ctx, []*models.Task{t}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *TaskRepository) UpdateTask(ctx context.Context, t *models.Task) error {
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE tasks SET title = ?, description = ?, grouped = ?, project_id = ?, sprint_id = ?, milestone_id = ?, due_at = ?, estimate = ?, updated_at = ? WHERE id = ? AND team_id = ?`,
+		t.Title,
+		t.Description,
+		t.Grouped,
+		nullableUUID(t.ProjectID),
+		nullableUUID(t.SprintID),
+		nullableUUID(t.MilestoneID),
+		utcTime(t.DueAt),
+		t.Estimate,
+		now,
+		t.ID.String(),
+		t.TeamID.String(),
+	)
+	t.UpdatedAt = now
+	return expectAffected(res, err)
+}
+
+func (r *TaskRepository) UpdateTaskState(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID, stateID uuid.UUID) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE tasks SET state_id = ?, updated_at = ? WHERE id = ? AND team_id = ?`,
+		stateID.String(),
+		time.Now().UTC(),
+		taskID.String(),
+		teamID.String(),
+	)
+	return expectAffected(res, err)
+}
+
+// DeleteTask marks the task and its subtasks that are not in the trash yet as deleted
+// with the task as their trash root.
+func (r *TaskRepository) DeleteTask(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`WITH RECURSIVE subtree (id, depth) AS (
+			SELECT id, 0 FROM tasks WHERE id = ? AND team_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id, s.depth + 1 FROM tasks t JOIN subtree s ON t.parent_id = s.id
+			WHERE t.deleted_at IS NULL AND s.depth < ?
+		)
+		UPDATE tasks SET deleted_at = ?, trash_root_id = ? WHERE id IN (SELECT id FROM subtree)`,
+		taskID.String(),
+		teamID.String(),
+		maxTreeDepth,
+		time.Now().UTC(),
+		taskID.String(),
+	)
+	return expectAffected(res, err)
+}
+
+func (r *TaskRepository) RestoreTask(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID) error {
+	var parentInTrash bool
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT EXISTS (SELECT 1 FROM tasks p WHERE p.id = tk.parent_id AND p.deleted_at IS NOT NULL)
+		     OR EXISTS (SELECT 1 FROM projects p WHERE p.id = tk.project_id AND p.deleted_at IS NOT NULL)
+		 FROM tasks tk WHERE tk.id = ? AND tk.team_id = ? AND tk.trash_root_id = tk.id`,
+		taskID.String(),
+		teamID.String(),
+	).Scan(&parentInTrash)
+	if err != nil {
+		return TranslateError(err)
+	}
+	if parentInTrash {
+		return repoerr.ErrConflict
+	}
+	_, err = r.db.ExecContext(
+		ctx,
+		`UPDATE tasks SET deleted_at = NULL, trash_root_id = NULL WHERE team_id = ? AND trash_root_id = ?`,
+		teamID.String(),
+		taskID.String(),
+	)
+	return TranslateError(err)
+}
+
+func (r *TaskRepository) ListTeamTasks(ctx context.Context, teamID uuid.UUID, q listquery.Query) (listquery.Result[*models.Task], error) {
+	const from = `FROM tasks tk WHERE tk.team_id = ? AND tk.deleted_at IS NULL`
+	return r.listTasks(ctx, q, from, teamID.String())
+}
+
+// ListUserTasks lists the tasks assigned to userID and the grouped tasks of every team
+// the user is a member of. Assignments in teams the user has left or that are in the
+// trash are not listed.
+func (r *TaskRepository) ListUserTasks(ctx context.Context, userID uuid.UUID, q listquery.Query) (listquery.Result[*models.Task], error) {
+	const from = `FROM tasks tk
+		 WHERE tk.deleted_at IS NULL
+		 AND tk.team_id IN (SELECT tu.team_id FROM teams_users tu JOIN teams t ON t.id = tu.team_id WHERE tu.user_id = ? AND t.deleted_at IS NULL)
+		 AND (tk.grouped OR EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = tk.id AND ta.user_id = ?))`
+	return r.listTasks(ctx, q, from, userID.String(), userID.String())
+}
+
+func (r *TaskRepository) listTasks(ctx context.Context, q listquery.Query, from string, base ...any) (listquery.Result[*models.Task], error) {
+	s := q.Build(dialect, base...)
+	rows, err := r.db.QueryContext(ctx, `SELECT `+taskColumns+` `+from+` AND `+s.Where+s.Suffix(), s.Args...)
+	if err != nil {
+		return listquery.Result[*models.Task]{}, TranslateError(err)
+	}
+	defer rows.Close()
+
+	var tasks []*models.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return listquery.Result[*models.Task]{}, err
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return listquery.Result[*models.Task]{}, err
+	}
+	if err := r.loadRelations(ctx, tasks); err != nil {
+		return listquery.Result[*models.Task]{}, err
+	}
+	res := listquery.Paginate(q, tasks, listspec.TaskRow)
+	res.Total, err = listTotal(ctx, r.db, q, from, s)
+	return res, err
+}
+
+// loadRelations fills AssigneeIDs, in assignment order, LabelIDs, by label name, and
+// CustomFields of tasks with one query each.
+func (r *TaskRepository) loadRelations(ctx context.Context, tasks []*models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Task, len(tasks))
+	args := make([]any, 0, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+		args = append(args, t.ID.String())
+	}
+	err := r.loadTaskIDs(ctx, byID,
+		`SELECT task_id, user_id FROM task_assignees
+		 WHERE task_id IN (`+placeholders(len(args))+`)
+		 ORDER BY assigned_at, user_id`,
+		args,
+		func(t *models.Task, id uuid.UUID) { t.AssigneeIDs = append(t.AssigneeIDs, id) },
+	)
+	if err != nil {
+		return err
+	}
+	err = r.loadTaskIDs(ctx, byID,
+		`SELECT tl.task_id, tl.label_id FROM task_labels tl JOIN labels l ON l.id = tl.label_id
+		 WHERE tl.task_id IN (`+placeholders(len(args))+`)
+		 ORDER BY lower(l.name), l.id`,
+		args,
+		func(t *models.Task, id uuid.UUID) { t.LabelIDs = append(t.LabelIDs, id) },
+	)
+	if err != nil {
+		return err
+	}
+	return r.loadCustomFields(ctx, byID, args)
+}
+
+// loadCustomFields fills CustomFields from task_field_values and, for multi-select
+// fields, task_field_options. Selected options are listed in the order of the field's
+// options.
+func (r *TaskRepository) loadCustomFields(ctx context.Context, byID map[uuid.UUID]*models.Task, args []any) error {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT v.task_id, f.key, f.type, v.value_text, v.value_number, v.value_time, v.value_bool, v.value_user
+		 FROM task_field_values v JOIN custom_fields f ON f.id = v.field_id
+		 WHERE v.task_id IN (`+placeholders(len(args))+`)`,
+		args...,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var taskID uuid.UUID
+		var key string
+		var v models.FieldValueColumns
+		if err := rows.Scan(&taskID, &key, &v.Type, &v.Text, &v.Number, &v.Time, &v.Bool, &v.User); err != nil {
+			return err
+		}
+		if t, ok := byID[taskID]; ok {
+			if val := v.Value(); val != nil {
+				t.CustomFields[key] = val
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	optRows, err := r.db.QueryContext(
+		ctx,
+		`SELECT o.task_id, f.key, f.options, o.option
+		 FROM task_field_options o JOIN custom_fields f ON f.id = o.field_id
+		 WHERE o.task_id IN (`+placeholders(len(args))+`)`,
+		args...,
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	defer optRows.Close()
+	selected := models.SelectedOptions{}
+	for optRows.Next() {
+		var taskID uuid.UUID
+		var key, options, option string
+		if err := optRows.Scan(&taskID, &key, &options, &option); err != nil {
+			return err
+		}
+		selected.Add(taskID, key, options, option)
+	}
+	if err := optRows.Err(); err != nil {
+		return err
+	}
+	selected.Apply(byID)
+	return nil
+}
+
+// loadTaskIDs runs a query returning (task id, related id) pairs and hands each pair to add.
+func (r *TaskRepository) loadTaskIDs(ctx context.Context, byID map[uuid.UUID]*models.Task, query string, args []any, add func(t *models.Task, id uuid.UUID)) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, id uuid.UUID
+		if err := rows.Scan(&taskID, &id); err != nil {
+			return err
+		}
+		if t, ok := byID[taskID]; ok {
+			add(t, id)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *TaskRepository) SetTaskParent(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID, parentID *uuid.UUID) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE tasks SET parent_id = ?, updated_at = ? WHERE id = ? AND team_id = ?`,
+		nullableUUID(parentID),
+		time.Now().UTC(),
+		taskID.String(),
+		teamID.String(),
+	)
+	return expectAffected(res, err)
+}
+
+func (r *TaskRepository) ListSubtasks(ctx context.Context, teamID uuid.UUID, parentID uuid.UUID) ([]*models.Task, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+taskColumns+` FROM tasks tk WHERE tk.parent_id = ? AND tk.team_id = ? AND tk.deleted_at IS NULL ORDER BY tk.created_at, tk.id`,
+		parentID.String(),
+		teamID.String(),
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	tasks := []*models.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tasks, r.loadRelations(ctx, tasks)
+}
+
+// GetTaskAncestorIDs returns the parent chain of the task, nearest first.
+func (r *TaskRepository) GetTaskAncestorIDs(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`WITH RECURSIVE ancestors (id, parent_id, depth) AS (
+			SELECT id, parent_id, 0 FROM tasks WHERE id = ? AND team_id = ?
+			UNION ALL
+			SELECT t.id, t.parent_id, a.depth + 1 FROM tasks t JOIN ancestors a ON t.id = a.parent_id
+			WHERE a.depth < ?
+		)
+		SELECT id FROM ancestors WHERE depth > 0 ORDER BY depth`,
+		taskID.String(),
+		teamID.String(),
+		maxTreeDepth,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetTaskSubtree returns every descendant of the task with its depth and whether it is done.
+func (r *TaskRepository) GetTaskSubtree(ctx context.Context, teamID uuid.UUID, taskID uuid.UUID) ([]models.TaskNode, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`WITH RECURSIVE subtree (id, parent_id, state_id, depth) AS (
+			SELECT id, parent_id, state_id, 1 FROM tasks WHERE parent_id = ? AND team_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id, t.parent_id, t.state_id, s.depth + 1 FROM tasks t JOIN subtree s ON t.parent_id = s.id
+			WHERE t.deleted_at IS NULL AND s.depth < ?
+		)
+		SELECT s.id, s.parent_id, s.depth, COALESCE(ws.category = 'done', FALSE)
+		FROM subtree s
+		LEFT JOIN workflow_states ws ON ws.id = s.state_id`,
+		taskID.String(),
+		teamID.String(),
+		maxTreeDepth,
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	nodes := []models.TaskNode{}
+	for rows.Next() {
+		var n models.TaskNode
+		if err := rows.Scan(&n.ID, &n.ParentID, &n.Depth, &n.Done); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, rows.Err()
+}
+
+// AddTaskAssignees assigns userIDs to the task. Users that are already assigned are skipped.
+func (r *TaskRepository) AddTaskAssignees(ctx context.Context, taskID uuid.UUID, userIDs []uuid.UUID, assignedBy *uuid.UUID) error {
+	now := time.Now().UTC()
+	for _, userID := range userIDs {
+		_, err := r.db.ExecContext(
+			ctx,
+			`INSERT INTO task_assignees (task_id, user_id, assigned_by, assigned_at) VALUES (?, ?, ?, ?)
+			 ON CONFLICT (task_id, user_id) DO NOTHING`,
+			taskID.String(),
+			userID.String(),
+			nullableUUID(assignedBy),
+			now,
+		)
+		if err != nil {
+			return TranslateError(err)
+		}
+	}
+	return nil
+}
+
+// RemoveTaskAssignees unassigns userIDs from the task. Users that are not assigned are ignored.
+func (r *TaskRepository) RemoveTaskAssignees(ctx context.Context, taskID uuid.UUID, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	args := []any{taskID.String()}
+	for _, userID := range userIDs {
+		args = append(args, userID.String())
+	}
+	_, err := r.db.ExecContext(
+		ctx,
+		`DELETE FROM task_assignees WHERE task_id = ? AND user_id IN (`+placeholders(len(userIDs))+`)`,
+		args...,
+	)
+	return TranslateError(err)
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
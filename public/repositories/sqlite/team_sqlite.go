@@ -2,9 +2,9 @@ package sqlite
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	dbx "task_manager/public/db"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories/listspec"
 	"task_manager/public/repositories/models"
 	"time"
 
@@ -20,7 +20,7 @@ func NewTeamRepository(db dbx.DBTX) *TeamRepository {
 }
 
 func (r *TeamRepository) CreateTeam(ctx context.Context, team *models.Team) error {
-	now := time.Now()
+	now := time.Now().UTC()
 	_, err := r.db.ExecContext(
 		ctx,
 		`INSERT INTO teams (id, name, created_at, updated_at) VALUES (?, ?, ?, ?)`,
@@ -32,7 +32,7 @@ func (r *TeamRepository) CreateTeam(ctx context.Context, team *models.Team) erro
 	team.CreatedAt = now
 	team.UpdatedAt = now
 
-	return err
+	return TranslateError(err)
 }
 
 func (r *TeamRepository) GetTeamByID(ctx context.Context, teamID uuid.UUID) (*models.Team, error) {
@@ -40,14 +40,11 @@ func (r *TeamRepository) GetTeamByID(ctx context.Context, teamID uuid.UUID) (*mo
 	var id string
 	err := r.db.QueryRowContext(
 		ctx,
-		`SELECT id, name, created_at, updated_at FROM teams WHERE id = ?`,
+		`SELECT id, name, archived_at, created_at, updated_at FROM teams WHERE id = ? AND deleted_at IS NULL`,
 		teamID.String(),
-	).Scan(&id, &u.Name, &u.CreatedAt, &u.UpdatedAt)
+	).Scan(&id, &u.Name, &u.ArchivedAt, &u.CreatedAt, &u.UpdatedAt)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	parsed, err := uuid.Parse(id)
 	if err != nil {
@@ -57,31 +54,45 @@ func (r *TeamRepository) GetTeamByID(ctx context.Context, teamID uuid.UUID) (*mo
 	return &u, nil
 }
 
+// LockTeam is a no-op write on the team row; sqlite runs one writer at a time, so the
+// transaction holds the write lock until it ends.
+func (r *TeamRepository) LockTeam(ctx context.Context, teamID uuid.UUID) (*models.Team, error) {
+	res, err := r.db.ExecContext(ctx, `UPDATE teams SET id = id WHERE id = ? AND deleted_at IS NULL`, teamID.String())
+	if err := expectAffected(res, err); err != nil {
+		return nil, err
+	}
+	return r.GetTeamByID(ctx, teamID)
+}
+
 func (r *TeamRepository) CreateTeamUser(ctx context.Context, userTeam *models.UserTeam) error {
+	if userTeam.JoinedAt.IsZero() {
+		userTeam.JoinedAt = time.Now().UTC()
+	}
 	_, err := r.db.ExecContext(
 		ctx,
-		`INSERT INTO teams_users (id, team_id, user_id, role) VALUES (?, ?, ?, ?)`,
+		`INSERT INTO teams_users (id, team_id, user_id, role, joined_at) VALUES (?, ?, ?, ?, ?)`,
 		userTeam.ID.String(),
 		userTeam.TeamID.String(),
 		userTeam.UserID.String(),
 		string(userTeam.Role),
+		userTeam.JoinedAt.UTC(),
 	)
 
-	return err
+	return TranslateError(err)
 }
 
 func (r *TeamRepository) DeleteTeamUser(ctx context.Context, userTeamID *uuid.UUID) error {
-	_, err := r.db.ExecContext(
+	res, err := r.db.ExecContext(
 		ctx,
 		`DELETE FROM teams_users WHERE id = ?`,
 		userTeamID.String(),
 	)
-	return err
+	return expectAffected(res, err)
 }
 
 func (r *TeamRepository) EditTeamName(ctx context.Context, team *models.Team) error {
-	now := time.Now()
-	_, err := r.db.ExecContext(
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(
 		ctx,
 		`UPDATE teams SET name = ?, updated_at = ? WHERE id = ?`,
 		team.Name,
@@ -89,16 +100,64 @@ func (r *TeamRepository) EditTeamName(ctx context.Context, team *models.Team) er
 		team.ID.String(),
 	)
 	team.UpdatedAt = now
-	return err
+	return expectAffected(res, err)
+}
+
+func (r *TeamRepository) SetTeamArchived(ctx context.Context, teamID uuid.UUID, archivedAt *time.Time) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE teams SET archived_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL`,
+		utcTime(archivedAt),
+		time.Now().UTC(),
+		teamID.String(),
+	)
+	return expectAffected(res, err)
 }
 
 func (r *TeamRepository) DeleteTeam(ctx context.Context, teamID uuid.UUID) error {
-	_, err := r.db.ExecContext(
+	res, err := r.db.ExecContext(
 		ctx,
-		`DELETE FROM teams WHERE id = ?`,
+		`UPDATE teams SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`,
+		time.Now().UTC(),
 		teamID.String(),
 	)
-	return err
+	return expectAffected(res, err)
+}
+
+func (r *TeamRepository) RestoreTeam(ctx context.Context, teamID uuid.UUID) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE teams SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`,
+		teamID.String(),
+	)
+	return expectAffected(res, err)
+}
+
+func (r *TeamRepository) ListDeletedTeams(ctx context.Context, userID uuid.UUID) ([]*models.TrashItem, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT t.id, t.name, t.deleted_at FROM teams t
+		 JOIN teams_users tu ON tu.team_id = t.id
+		 WHERE tu.user_id = ? AND tu.role = ? AND t.deleted_at IS NOT NULL
+		 ORDER BY t.deleted_at DESC, t.id`,
+		userID.String(),
+		string(models.FounderUserRole),
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	items := []*models.TrashItem{}
+	for rows.Next() {
+		item := models.TrashItem{Kind: models.TrashTeam}
+		if err := rows.Scan(&item.ID, &item.Title, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		item.TeamID = item.ID
+		items = append(items, &item)
+	}
+	return items, rows.Err()
 }
 
 func (r *TeamRepository) RemoveTeamUser(ctx context.Context, userID uuid.UUID) error {
@@ -107,120 +166,104 @@ func (r *TeamRepository) RemoveTeamUser(ctx context.Context, userID uuid.UUID) e
 		`DELETE FROM teams_users WHERE user_id = ?`,
 		userID.String(),
 	)
-	return err
+	return TranslateError(err)
 }
 
-func (r *TeamRepository) GetTeamsByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Team, error) {
-	rows, err := r.db.QueryContext(
-		ctx,
-		`SELECT t.id, t.name, t.created_at, t.updated_at 
-		 FROM teams t
+func (r *TeamRepository) GetTeamsByUserID(ctx context.Context, userID uuid.UUID, includeArchived bool, q listquery.Query) (listquery.Result[*models.Team], error) {
+	const from = `FROM teams t
 		 JOIN teams_users tu ON t.id = tu.team_id
-		 WHERE tu.user_id = ?
-		 ORDER BY t.created_at ASC`,
-		userID.String(),
-	)
+		 WHERE tu.user_id = ? AND t.deleted_at IS NULL AND (? OR t.archived_at IS NULL)`
+	s := q.Build(dialect, userID.String(), includeArchived)
+	rows, err := r.db.QueryContext(ctx, `SELECT t.id, t.name, t.archived_at, t.created_at, t.updated_at `+from+` AND `+s.Where+s.Suffix(), s.Args...)
 	if err != nil {
-		return nil, err
+		return listquery.Result[*models.Team]{}, TranslateError(err)
 	}
 	defer rows.Close()
 
 	var teams []*models.Team
 	for rows.Next() {
 		var t models.Team
-		var id string
-		if err := rows.Scan(&id, &t.Name, &t.CreatedAt, &t.UpdatedAt); err != nil {
-			return nil, err
-		}
-		parsed, err := uuid.Parse(id)
-		if err != nil {
-			return nil, err
+		if err := rows.Scan(&t.ID, &t.Name, &t.ArchivedAt, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return listquery.Result[*models.Team]{}, err
 		}
-		t.ID = parsed
 		teams = append(teams, &t)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return listquery.Result[*models.Team]{}, err
 	}
-	return teams, nil
+	res := listquery.Paginate(q, teams, listspec.TeamRow)
+	res.Total, err = listTotal(ctx, r.db, q, from, s)
+	return res, err
 }
 
 func (r *TeamRepository) CreateTeamInvitation(ctx context.Context, invitation *models.Invitation) error {
+	if invitation.CreatedAt.IsZero() {
+		invitation.CreatedAt = time.Now().UTC()
+	}
 	_, err := r.db.ExecContext(
 		ctx,
-		`INSERT INTO team_invitations (id, team_id, user_id, accepted) VALUES (?, ?, ?, ?)`,
+		`INSERT INTO team_invitations (id, team_id, to_user_id, from_user_id, accepted, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
 		invitation.ID.String(),
 		invitation.TeamID.String(),
 		invitation.UserID.String(),
+		invitation.FromUserID.String(),
 		invitation.Accepted,
+		invitation.CreatedAt.UTC(),
 	)
-	return err
+	return TranslateError(err)
 }
 
 func (r *TeamRepository) UpdateTeamInvitationStatus(ctx context.Context, invitationID uuid.UUID, accept bool) error {
-	_, err := r.db.ExecContext(
+	res, err := r.db.ExecContext(
 		ctx,
 		`UPDATE team_invitations SET accepted = ? WHERE id = ?`,
 		accept,
 		invitationID.String(),
 	)
-	return err
+	return expectAffected(res, err)
 }
 
-func (r *TeamRepository) GetUserInvitations(ctx context.Context, userID uuid.UUID) ([]*models.Invitation, error) {
+func (r *TeamRepository) GetUserInvitations(ctx context.Context, userID uuid.UUID, q listquery.Query) (listquery.Result[*models.Invitation], error) {
+	const from = `FROM team_invitations ti WHERE ti.to_user_id = ?`
+	s := q.Build(dialect, userID.String())
 	rows, err := r.db.QueryContext(
 		ctx,
-		`SELECT id, team_id, user_id, accepted FROM team_invitations WHERE user_id = ? ORDER BY id ASC`,
-		userID.String(),
+		`SELECT ti.id, ti.team_id, ti.to_user_id, ti.from_user_id, ti.accepted, ti.created_at `+from+` AND `+s.Where+s.Suffix(),
+		s.Args...,
 	)
 	if err != nil {
-		return nil, err
+		return listquery.Result[*models.Invitation]{}, TranslateError(err)
 	}
 	defer rows.Close()
 
 	var invitations []*models.Invitation
 	for rows.Next() {
 		var inv models.Invitation
-		var id, teamID, uID string
-		if err := rows.Scan(&id, &teamID, &uID, &inv.Accepted); err != nil {
-			return nil, err
+		if err := rows.Scan(&inv.ID, &inv.TeamID, &inv.UserID, &inv.FromUserID, &inv.Accepted, &inv.CreatedAt); err != nil {
+			return listquery.Result[*models.Invitation]{}, err
 		}
-		parsedID, err := uuid.Parse(id)
-		if err != nil {
-			return nil, err
-		}
-		parsedTeamID, err := uuid.Parse(teamID)
-		if err != nil {
-			return nil, err
-		}
-		parsedUserID, err := uuid.Parse(uID)
-		if err != nil {
-			return nil, err
-		}
-		inv.ID = parsedID
-		inv.TeamID = parsedTeamID
-		inv.UserID = parsedUserID
 		invitations = append(invitations, &inv)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return listquery.Result[*models.Invitation]{}, err
 	}
-	return invitations, nil
+	res := listquery.Paginate(q, invitations, listspec.InvitationRow)
+	res.Total, err = listTotal(ctx, r.db, q, from, s)
+	return res, err
 }
 
 func (r *TeamRepository) GetMemberRole(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (*models.TeamUserRole, error) {
 	var role string
 	err := r.db.QueryRowContext(
 		ctx,
-		`SELECT role FROM teams_users WHERE team_id = ? AND user_id = ?`,
+		`SELECT tu.role FROM teams_users tu
+		 JOIN teams t ON t.id = tu.team_id
+		 WHERE tu.team_id = ? AND tu.user_id = ? AND t.deleted_at IS NULL`,
 		teamID.String(),
 		userID.String(),
 	).Scan(&role)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	result := models.TeamUserRole(role)
 	return &result, nil
@@ -236,10 +279,7 @@ func (r *TeamRepository) GetTeamFounderByTeamID(ctx context.Context, teamID uuid
 		string(models.FounderUserRole),
 	).Scan(&id, &tID, &uID, &role)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	parsedID, err := uuid.Parse(id)
 	if err != nil {
@@ -260,53 +300,38 @@ func (r *TeamRepository) GetTeamFounderByTeamID(ctx context.Context, teamID uuid
 	return &ut, nil
 }
 
-func (r *TeamRepository) GetTeamsMembers(ctx context.Context, teamID uuid.UUID) ([]*models.UserTeam, error) {
-	rows, err := r.db.QueryContext(
-		ctx,
-		`SELECT id, team_id, user_id, role FROM teams_users WHERE team_id = ? ORDER BY id ASC`,
-		teamID.String(),
-	)
+func (r *TeamRepository) GetTeamsMembers(ctx context.Context, teamID uuid.UUID, q listquery.Query) (listquery.Result[*models.UserTeam], error) {
+	const from = `FROM teams_users tu WHERE tu.team_id = ?`
+	s := q.Build(dialect, teamID.String())
+	rows, err := r.db.QueryContext(ctx, `SELECT tu.id, tu.team_id, tu.user_id, tu.role, tu.joined_at `+from+` AND `+s.Where+s.Suffix(), s.Args...)
 	if err != nil {
-		return nil, err
+		return listquery.Result[*models.UserTeam]{}, TranslateError(err)
 	}
 	defer rows.Close()
 
 	var members []*models.UserTeam
 	for rows.Next() {
 		var ut models.UserTeam
-		var id, tID, uID, role string
-		if err := rows.Scan(&id, &tID, &uID, &role); err != nil {
-			return nil, err
-		}
-		parsedID, err := uuid.Parse(id)
-		if err != nil {
-			return nil, err
-		}
-		parsedTeamID, err := uuid.Parse(tID)
-		if err != nil {
-			return nil, err
+		var role string
+		if err := rows.Scan(&ut.ID, &ut.TeamID, &ut.UserID, &role, &ut.JoinedAt); err != nil {
+			return listquery.Result[*models.UserTeam]{}, err
 		}
-		parsedUserID, err := uuid.Parse(uID)
-		if err != nil {
-			return nil, err
-		}
-		ut.ID = parsedID
-		ut.TeamID = parsedTeamID
-		ut.UserID = parsedUserID
 		ut.Role = models.TeamUserRole(role)
 		members = append(members, &ut)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return listquery.Result[*models.UserTeam]{}, err
 	}
-	return members, nil
+	res := listquery.Paginate(q, members, listspec.MemberRow)
+	res.Total, err = listTotal(ctx, r.db, q, from, s)
+	return res, err
 }
 
 func (r *TeamRepository) DeleteUserInvitation(ctx context.Context, invitationID uuid.UUID) error {
-	_, err := r.db.ExecContext(
+	res, err := r.db.ExecContext(
 		ctx,
 		`DELETE FROM team_invitations WHERE id = ?`,
 		invitationID.String(),
 	)
-	return err
+	return expectAffected(res, err)
 }
@@ -0,0 +1,197 @@
+package sqlite
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TimeEntryRepository struct {
+	db dbx.DBTX
+}
+
+func NewTimeEntryRepository(db dbx.DBTX) *TimeEntryRepository {
+	return &TimeEntryRepository{db: db}
+}
+
+const timeEntryColumns = `id, team_id, task_id, user_id, started_at, ended_at, duration_seconds, note, billable, created_at, updated_at`
+
+func scanTimeEntry(s rowScanner) (*models.TimeEntry, error) {
+	var e models.TimeEntry
+	err := s.Scan(&e.ID, &e.TeamID, &e.TaskID, &e.UserID, &e.StartedAt, &e.EndedAt, &e.Duration, &e.Note, &e.Billable, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// LockUserTimeEntries is a no-op write on the user row; sqlite runs one writer at a time,
+// so the transaction holds the write lock until it ends.
+func (r *TimeEntryRepository) LockUserTimeEntries(ctx context.Context, userID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE users SET id = id WHERE id = ?`, userID.String())
+	return expectAffected(res, err)
+}
+
+func (r *TimeEntryRepository) GetRunningTimer(ctx context.Context, userID uuid.UUID) (*models.TimeEntry, error) {
+	e, err := scanTimeEntry(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+timeEntryColumns+` FROM time_entries WHERE user_id = ? AND ended_at IS NULL`,
+		userID.String(),
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return e, nil
+}
+
+func (r *TimeEntryRepository) GetTimeEntry(ctx context.Context, teamID uuid.UUID, entryID uuid.UUID) (*models.TimeEntry, error) {
+	e, err := scanTimeEntry(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+timeEntryColumns+` FROM time_entries WHERE id = ? AND team_id = ?`,
+		entryID.String(),
+		teamID.String(),
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return e, nil
+}
+
+func (r *TimeEntryRepository) FindOverlappingEntry(ctx context.Context, userID uuid.UUID, start time.Time, end time.Time, excludeID *uuid.UUID) (*models.TimeEntry, error) {
+	query := `SELECT ` + timeEntryColumns + ` FROM time_entries
+		 WHERE user_id = ? AND started_at < ? AND (ended_at IS NULL OR ended_at > ?)`
+	args := []any{userID.String(), end.UTC(), start.UTC()}
+	if excludeID != nil {
+		query += ` AND id <> ?`
+		args = append(args, excludeID.String())
+	}
+	e, err := scanTimeEntry(r.db.QueryRowContext(ctx, query+` ORDER BY started_at, id LIMIT 1`, args...))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return e, nil
+}
+
+func (r *TimeEntryRepository) ListTaskTimeEntries(ctx context.Context, taskID uuid.UUID) ([]*models.TimeEntry, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+timeEntryColumns+` FROM time_entries WHERE task_id = ? ORDER BY started_at, id`,
+		taskID.String(),
+	)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	entries := []*models.TimeEntry{}
+	for rows.Next() {
+		e, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *TimeEntryRepository) CreateTimeEntry(ctx context.Context, e *models.TimeEntry) error {
+	now := time.Now().UTC()
+	e.CreatedAt = now
+	e.UpdatedAt = now
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO time_entries (id, team_id, task_id, user_id, started_at, ended_at, duration_seconds, note, billable, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ID.String(),
+		e.TeamID.String(),
+		e.TaskID.String(),
+		e.UserID.String(),
+		e.StartedAt.UTC(),
+		utcTime(e.EndedAt),
+		e.Duration,
+		e.Note,
+		e.Billable,
+		now,
+		now,
+	)
+	return TranslateError(err)
+}
+
+func (r *TimeEntryRepository) UpdateTimeEntry(ctx context.Context, e *models.TimeEntry) error {
+	now := time.Now().UTC()
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE time_entries SET started_at = ?, ended_at = ?, duration_seconds = ?, note = ?, billable = ?, updated_at = ?
+		 WHERE id = ? AND team_id = ?`,
+		e.StartedAt.UTC(),
+		utcTime(e.EndedAt),
+		e.Duration,
+		e.Note,
+		e.Billable,
+		now,
+		e.ID.String(),
+		e.TeamID.String(),
+	)
+	if err := expectAffected(res, err); err != nil {
+		return err
+	}
+	e.UpdatedAt = now
+	return nil
+}
+
+func (r *TimeEntryRepository) DeleteTimeEntry(ctx context.Context, teamID uuid.UUID, entryID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM time_entries WHERE id = ? AND team_id = ?`, entryID.String(), teamID.String())
+	return expectAffected(res, err)
+}
+
+func (r *TimeEntryRepository) ListTimesheet(ctx context.Context, teamID uuid.UUID, filter models.TimesheetFilter) ([]*models.TimesheetEntry, error) {
+	query := `SELECT te.id, te.team_id, te.task_id, te.user_id, te.started_at, te.ended_at, te.duration_seconds, te.note,
+		 te.billable, te.created_at, te.updated_at, TRIM(u.first_name || ' ' || u.last_name), tk.title, tk.project_id,
+		 COALESCE(p.name, ''), t.name
+		 FROM time_entries te
+		 JOIN users u ON u.id = te.user_id
+		 JOIN tasks tk ON tk.id = te.task_id
+		 JOIN teams t ON t.id = te.team_id
+		 LEFT JOIN projects p ON p.id = tk.project_id
+		 WHERE te.team_id = ? AND tk.deleted_at IS NULL AND te.ended_at IS NOT NULL AND te.started_at >= ? AND te.started_at < ?`
+	args := []any{teamID.String(), filter.From.UTC(), filter.To.UTC()}
+	if filter.UserID != nil {
+		query += ` AND te.user_id = ?`
+		args = append(args, filter.UserID.String())
+	}
+	if filter.TaskID != nil {
+		query += ` AND te.task_id = ?`
+		args = append(args, filter.TaskID.String())
+	}
+	if filter.ProjectID != nil {
+		query += ` AND tk.project_id = ?`
+		args = append(args, filter.ProjectID.String())
+	}
+	if filter.Billable != nil {
+		query += ` AND te.billable = ?`
+		args = append(args, *filter.Billable)
+	}
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY te.started_at, te.id`, args...)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	entries := []*models.TimesheetEntry{}
+	for rows.Next() {
+		var e models.TimesheetEntry
+		err := rows.Scan(
+			&e.ID, &e.TeamID, &e.TaskID, &e.UserID, &e.StartedAt, &e.EndedAt, &e.Duration, &e.Note,
+			&e.Billable, &e.CreatedAt, &e.UpdatedAt, &e.UserName, &e.TaskTitle, &e.ProjectID,
+			&e.ProjectName, &e.TeamName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
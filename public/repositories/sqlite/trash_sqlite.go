@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"context"
+	dbx "task_manager/public/db"
+	"task_manager/public/listquery"
+	"task_manager/public/repositories/listspec"
+	"task_manager/public/repositories/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashRepository struct {
+	db dbx.DBTX
+}
+
+func NewTrashRepository(db dbx.DBTX) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// trashItems lists the restorable projects, tasks and comments of every team. Tasks are
+// listed under their trash root, and comments only while their task is not in the trash.
+const trashItems = `(
+	SELECT 'project' AS kind, p.id AS id, p.team_id AS team_id, NULL AS task_id, p.name AS title, NULL AS created_by,
+	       (SELECT COUNT(*) FROM tasks WHERE trash_root_id = p.id) AS task_count, p.deleted_at AS deleted_at
+	FROM projects p
+	WHERE p.deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'task', tk.id, tk.team_id, NULL, tk.title, tk.created_by,
+	       (SELECT COUNT(*) FROM tasks WHERE trash_root_id = tk.id), tk.deleted_at
+	FROM tasks tk
+	WHERE tk.trash_root_id = tk.id
+	UNION ALL
+	SELECT 'comment', cm.id, cm.team_id, cm.task_id, substr(cm.body, 1, 80), cm.author_id, 0, cm.deleted_at
+	FROM task_comments cm
+	JOIN tasks tk ON tk.id = cm.task_id
+	WHERE cm.deleted_at IS NOT NULL AND cm.body <> '' AND tk.deleted_at IS NULL
+) tr`
+
+const trashColumns = `tr.kind, tr.id, tr.team_id, tr.task_id, tr.title, tr.created_by, tr.task_count, tr.deleted_at`
+
+func scanTrashItem(s rowScanner) (*models.TrashItem, error) {
+	var item models.TrashItem
+	if err := s.Scan(&item.Kind, &item.ID, &item.TeamID, &item.TaskID, &item.Title, &item.CreatedBy, &item.TaskCount, &item.DeletedAt); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *TrashRepository) ListTrash(ctx context.Context, teamID uuid.UUID, q listquery.Query) (listquery.Result[*models.TrashItem], error) {
+	const from = `FROM ` + trashItems + ` WHERE tr.team_id = ?`
+	s := q.Build(dialect, teamID.String())
+	rows, err := r.db.QueryContext(ctx, `SELECT `+trashColumns+` `+from+` AND `+s.Where+s.Suffix(), s.Args...)
+	if err != nil {
+		return listquery.Result[*models.TrashItem]{}, TranslateError(err)
+	}
+	defer rows.Close()
+
+	var items []*models.TrashItem
+	for rows.Next() {
+		item, err := scanTrashItem(rows)
+		if err != nil {
+			return listquery.Result[*models.TrashItem]{}, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return listquery.Result[*models.TrashItem]{}, err
+	}
+	res := listquery.Paginate(q, items, listspec.TrashRow)
+	res.Total, err = listTotal(ctx, r.db, q, from, s)
+	return res, err
+}
+
+func (r *TrashRepository) GetTrashItem(ctx context.Context, teamID uuid.UUID, id uuid.UUID) (*models.TrashItem, error) {
+	item, err := scanTrashItem(r.db.QueryRowContext(
+		ctx,
+		`SELECT `+trashColumns+` FROM `+trashItems+` WHERE tr.team_id = ? AND tr.id = ?`,
+		teamID.String(),
+		id.String(),
+	))
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	return item, nil
+}
+
+// PurgeTrash deletes teams first, so their content goes with them, then tasks and
+// projects. Tasks deleted together share their deleted_at and are purged together.
+func (r *TrashRepository) PurgeTrash(ctx context.Context, before time.Time) (int, error) {
+	steps := []struct {
+		query string
+		count bool
+	}{
+		{`DELETE FROM teams WHERE deleted_at < ?`, true},
+		{`DELETE FROM tasks WHERE deleted_at < ?`, true},
+		{`UPDATE tasks SET project_id = NULL WHERE project_id IN (SELECT id FROM projects WHERE deleted_at < ?)`, false},
+		{`DELETE FROM projects WHERE deleted_at < ?`, true},
+		{`DELETE FROM task_comments WHERE deleted_at < ? AND NOT EXISTS (SELECT 1 FROM task_comments r WHERE r.parent_id = task_comments.id)`, true},
+		{`DELETE FROM task_comment_revisions WHERE comment_id IN (SELECT id FROM task_comments WHERE deleted_at < ? AND body <> '')`, false},
+		{`DELETE FROM task_comment_mentions WHERE comment_id IN (SELECT id FROM task_comments WHERE deleted_at < ? AND body <> '')`, false},
+		{`UPDATE task_comments SET body = '', body_html = '' WHERE deleted_at < ? AND body <> ''`, true},
+	}
+	total := 0
+	for _, step := range steps {
+		res, err := r.db.ExecContext(ctx, step.query, before.UTC())
+		if err != nil {
+			return total, TranslateError(err)
+		}
+		if !step.count {
+			continue
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += int(n)
+	}
+	return total, nil
+}
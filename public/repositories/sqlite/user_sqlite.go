@@ -2,8 +2,7 @@ package sqlite
 
 import (
 	"context"
-	"database/sql"
-	"errors"
+	"strings"
 	dbx "task_manager/public/db"
 	"task_manager/public/repositories/models"
 	"time"
@@ -29,7 +28,7 @@ func (r *UserRepository) CreateUser(ctx context.Context, u *models.User) error {
 		u.Email,
 		u.UserType,
 	)
-	return err
+	return TranslateError(err)
 }
 
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
@@ -37,14 +36,11 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 	var id string
 	err := r.db.QueryRowContext(
 		ctx,
-		`SELECT id, first_name, last_name, email, created_at, updated_at, user_type FROM users WHERE email = ?`,
+		`SELECT id, first_name, last_name, email, created_at, updated_at, user_type, disabled_at, password_reset_required FROM users WHERE email = ?`,
 		email,
-	).Scan(&id, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType)
+	).Scan(&id, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType, &u.DisabledAt, &u.PasswordResetRequired)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	parsed, err := uuid.Parse(id)
 	if err != nil {
@@ -59,14 +55,11 @@ func (r *UserRepository) GetUserByID(ctx context.Context, userID uuid.UUID) (*mo
 	var id string
 	err := r.db.QueryRowContext(
 		ctx,
-		`SELECT id, first_name, last_name, email, created_at, updated_at, user_type FROM users WHERE id = ?`,
+		`SELECT id, first_name, last_name, email, created_at, updated_at, user_type, disabled_at, password_reset_required FROM users WHERE id = ?`,
 		userID.String(),
-	).Scan(&id, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType)
+	).Scan(&id, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType, &u.DisabledAt, &u.PasswordResetRequired)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	parsed, err := uuid.Parse(id)
 	if err != nil {
@@ -77,7 +70,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, userID uuid.UUID) (*mo
 }
 
 func (r *UserRepository) UpsertPassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
-	now := time.Now()
+	now := time.Now().UTC()
 	_, err := r.db.ExecContext(
 		ctx,
 		`INSERT INTO passwords (id, user_id, v, created_at, updated_at)
@@ -91,17 +84,14 @@ func (r *UserRepository) UpsertPassword(ctx context.Context, userID uuid.UUID, p
 		now,
 		now,
 	)
-	return err
+	return TranslateError(err)
 }
 
 func (r *UserRepository) GetPasswordHashByUserID(ctx context.Context, userID uuid.UUID) (string, error) {
 	var v string
 	err := r.db.QueryRowContext(ctx, `SELECT v FROM passwords WHERE user_id = ?`, userID.String()).Scan(&v)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return "", nil
-		}
-		return "", err
+		return "", TranslateError(err)
 	}
 	return v, nil
 }
@@ -111,18 +101,15 @@ func (r *UserRepository) GetUserByAuthProvider(ctx context.Context, provider mod
 	var id string
 	err := r.db.QueryRowContext(
 		ctx,
-		`SELECT u.id, u.first_name, u.last_name, u.email, u.created_at, u.updated_at, u.user_type
+		`SELECT u.id, u.first_name, u.last_name, u.email, u.created_at, u.updated_at, u.user_type, u.disabled_at, u.password_reset_required
 		 FROM auth_providers ap
 		 JOIN users u ON u.id = ap.user_id
 		 WHERE ap.provider = ? AND ap.provider_user_id = ?`,
 		provider,
 		providerUserID,
-	).Scan(&id, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType)
+	).Scan(&id, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType, &u.DisabledAt, &u.PasswordResetRequired)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	parsed, err := uuid.Parse(id)
 	if err != nil {
@@ -147,10 +134,7 @@ func (r *UserRepository) GetAuthProviderByUserAndProvider(ctx context.Context, u
 		provider,
 	).Scan(&id, &uid, &ap.Provider, &ap.ProviderUserID, &ap.Email, &ap.Username, &ap.DisplayName, &ap.AvatarURL, &ap.CreatedAt, &ap.UpdatedAt)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	apid, err := uuid.Parse(id)
 	if err != nil {
@@ -177,7 +161,7 @@ func (r *UserRepository) ListAuthProvidersByUserID(ctx context.Context, userID u
 		userID.String(),
 	)
 	if err != nil {
-		return nil, err
+		return nil, TranslateError(err)
 	}
 	defer rows.Close()
 
@@ -220,8 +204,153 @@ func (r *UserRepository) CreateAuthProvider(ctx context.Context, ap *models.Auth
 		ap.Username,
 		ap.DisplayName,
 		ap.AvatarURL,
-		ap.CreatedAt,
-		ap.UpdatedAt,
+		ap.CreatedAt.UTC(),
+		ap.UpdatedAt.UTC(),
+	)
+	return TranslateError(err)
+}
+
+func (r *UserRepository) ListUsers(ctx context.Context, filter models.UserListFilter) ([]*models.User, int, error) {
+	where := []string{"1 = 1"}
+	var args []any
+	if q := strings.ToLower(strings.TrimSpace(filter.Query)); q != "" {
+		like := "%" + q + "%"
+		where = append(where, "(LOWER(email) LIKE ? OR LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ?)")
+		args = append(args, like, like, like)
+	}
+	if filter.UserType != "" {
+		where = append(where, "user_type = ?")
+		args = append(args, string(filter.UserType))
+	}
+	if filter.Disabled != nil {
+		if *filter.Disabled {
+			where = append(where, "disabled_at IS NOT NULL")
+		} else {
+			where = append(where, "disabled_at IS NULL")
+		}
+	}
+	cond := strings.Join(where, " AND ")
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE `+cond, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, first_name, last_name, email, created_at, updated_at, user_type, disabled_at, password_reset_required
+		 FROM users
+		 WHERE `+cond+`
+		 ORDER BY created_at ASC, id ASC
+		 LIMIT ? OFFSET ?`,
+		append(args, filter.Limit, filter.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, TranslateError(err)
+	}
+	defer rows.Close()
+
+	var out []*models.User
+	for rows.Next() {
+		var u models.User
+		var id string
+		if err := rows.Scan(&id, &u.FirstName, &u.LastName, &u.Email, &u.CreatedAt, &u.UpdatedAt, &u.UserType, &u.DisabledAt, &u.PasswordResetRequired); err != nil {
+			return nil, 0, err
+		}
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return nil, 0, err
+		}
+		u.ID = parsed
+		out = append(out, &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+func (r *UserRepository) SetUserDisabled(ctx context.Context, userID uuid.UUID, disabled bool) error {
+	now := time.Now().UTC()
+	var disabledAt *time.Time
+	if disabled {
+		disabledAt = &now
+	}
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE users SET disabled_at = ?, updated_at = ? WHERE id = ?`,
+		disabledAt,
+		now,
+		userID.String(),
+	)
+	return expectAffected(res, err)
+}
+
+func (r *UserRepository) UpdateUserType(ctx context.Context, userID uuid.UUID, userType models.UserType) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE users SET user_type = ?, updated_at = ? WHERE id = ?`,
+		string(userType),
+		time.Now().UTC(),
+		userID.String(),
+	)
+	return expectAffected(res, err)
+}
+
+func (r *UserRepository) SetPasswordResetRequired(ctx context.Context, userID uuid.UUID, required bool) error {
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE users SET password_reset_required = ?, updated_at = ? WHERE id = ?`,
+		required,
+		time.Now().UTC(),
+		userID.String(),
+	)
+	return expectAffected(res, err)
+}
+
+func (r *UserRepository) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, userID.String())
+	return expectAffected(res, err)
+}
+
+func (r *UserRepository) CreatePasswordResetToken(ctx context.Context, t *models.PasswordResetToken) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, created_at) VALUES (?, ?, ?, ?, ?)`,
+		t.ID.String(),
+		t.UserID.String(),
+		t.TokenHash,
+		t.ExpiresAt.UTC(),
+		t.CreatedAt.UTC(),
 	)
-	return err
+	return TranslateError(err)
+}
+
+func (r *UserRepository) GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	var t models.PasswordResetToken
+	var id, uid string
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT id, user_id, token_hash, expires_at, created_at FROM password_reset_tokens WHERE token_hash = ?`,
+		tokenHash,
+	).Scan(&id, &uid, &t.TokenHash, &t.ExpiresAt, &t.CreatedAt)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	tid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	tuid, err := uuid.Parse(uid)
+	if err != nil {
+		return nil, err
+	}
+	t.ID = tid
+	t.UserID = tuid
+	return &t, nil
+}
+
+func (r *UserRepository) DeletePasswordResetTokensByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM password_reset_tokens WHERE user_id = ?`, userID.String())
+	return TranslateError(err)
 }
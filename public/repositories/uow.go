@@ -11,8 +11,29 @@ import (
 // Repos groups all repositories that should share the same DB handle (DB or Tx).
 // Over time you can add more repos here (Teams, Tasks, etc).
 type Repos struct {
-	Users UserRepository
-	Teams TeamRepository
+	Users         UserRepository
+	Teams         TeamRepository
+	Tasks         TaskRepository
+	Workflows     WorkflowRepository
+	Checklists    ChecklistRepository
+	TaskLinks     TaskLinkRepository
+	Comments      CommentRepository
+	Notifications NotificationRepository
+	Attachments   AttachmentRepository
+	Labels        LabelRepository
+	CustomFields  CustomFieldRepository
+	Recurrences   RecurrenceRepository
+	Reminders     ReminderRepository
+	Outbox        OutboxRepository
+	Boards        BoardRepository
+	Projects      ProjectRepository
+	Sprints       SprintRepository
+	Milestones    MilestoneRepository
+	TimeEntries   TimeEntryRepository
+	Estimation    EstimationRepository
+	Activity      ActivityRepository
+	Trash         TrashRepository
+	Audit         AuditRepository
 }
 
 // Transaction is an explicit, manually-managed transaction scope.
@@ -32,6 +53,27 @@ type Transaction interface {
 	Repos() Repos
 	Users() UserRepository
 	Teams() TeamRepository
+	Tasks() TaskRepository
+	Workflows() WorkflowRepository
+	Checklists() ChecklistRepository
+	TaskLinks() TaskLinkRepository
+	Comments() CommentRepository
+	Notifications() NotificationRepository
+	Attachments() AttachmentRepository
+	Labels() LabelRepository
+	CustomFields() CustomFieldRepository
+	Recurrences() RecurrenceRepository
+	Reminders() ReminderRepository
+	Outbox() OutboxRepository
+	Boards() BoardRepository
+	Projects() ProjectRepository
+	Sprints() SprintRepository
+	Milestones() MilestoneRepository
+	TimeEntries() TimeEntryRepository
+	Estimation() EstimationRepository
+	Activity() ActivityRepository
+	Trash() TrashRepository
+	Audit() AuditRepository
 	Commit() error
 	Rollback() error
 	Stop() error
@@ -55,6 +97,27 @@ type Transaction interface {
 type UnitOfWork interface {
 	Users() UserRepository
 	Teams() TeamRepository
+	Tasks() TaskRepository
+	Workflows() WorkflowRepository
+	Checklists() ChecklistRepository
+	TaskLinks() TaskLinkRepository
+	Comments() CommentRepository
+	Notifications() NotificationRepository
+	Attachments() AttachmentRepository
+	Labels() LabelRepository
+	CustomFields() CustomFieldRepository
+	Recurrences() RecurrenceRepository
+	Reminders() ReminderRepository
+	Outbox() OutboxRepository
+	Boards() BoardRepository
+	Projects() ProjectRepository
+	Sprints() SprintRepository
+	Milestones() MilestoneRepository
+	TimeEntries() TimeEntryRepository
+	Estimation() EstimationRepository
+	Activity() ActivityRepository
+	Trash() TrashRepository
+	Audit() AuditRepository
 	Begin(ctx context.Context) (Transaction, error)
 	WithTransaction(ctx context.Context, fn func(ctx context.Context, r Repos) error) error
 }
@@ -65,6 +128,7 @@ type unitOfWork struct {
 }
 
 type transaction struct {
+	driver    string
 	tx        *sql.Tx
 	repos     Repos
 	committed bool
@@ -93,6 +157,132 @@ func (u *unitOfWork) Teams() TeamRepository {
 	return repos.Teams
 }
 
+func (u *unitOfWork) Tasks() TaskRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Tasks
+}
+
+func (u *unitOfWork) Workflows() WorkflowRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Workflows
+}
+
+func (u *unitOfWork) Checklists() ChecklistRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Checklists
+}
+
+func (u *unitOfWork) TaskLinks() TaskLinkRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.TaskLinks
+}
+
+func (u *unitOfWork) Comments() CommentRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Comments
+}
+
+func (u *unitOfWork) Notifications() NotificationRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Notifications
+}
+
+func (u *unitOfWork) Attachments() AttachmentRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Attachments
+}
+
+func (u *unitOfWork) Labels() LabelRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Labels
+}
+
+func (u *unitOfWork) CustomFields() CustomFieldRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.CustomFields
+}
+
+func (u *unitOfWork) Recurrences() RecurrenceRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Recurrences
+}
+
+func (u *unitOfWork) Reminders() ReminderRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Reminders
+}
+
+func (u *unitOfWork) Outbox() OutboxRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Outbox
+}
+
+func (u *unitOfWork) Boards() BoardRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Boards
+}
+
+func (u *unitOfWork) Projects() ProjectRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Projects
+}
+
+func (u *unitOfWork) Sprints() SprintRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Sprints
+}
+
+func (u *unitOfWork) Milestones() MilestoneRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Milestones
+}
+
+func (u *unitOfWork) TimeEntries() TimeEntryRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.TimeEntries
+}
+
+func (u *unitOfWork) Estimation() EstimationRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Estimation
+}
+
+func (u *unitOfWork) Activity() ActivityRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Activity
+}
+
+func (u *unitOfWork) Trash() TrashRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Trash
+}
+
+func (u *unitOfWork) Audit() AuditRepository {
+	// Driver is validated in NewUnitOfWork; ignore error here.
+	repos, _ := u.buildRepos(u.db)
+	return repos.Audit
+}
+
 func (u *unitOfWork) Begin(ctx context.Context) (Transaction, error) {
 	tx, err := u.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -105,7 +295,7 @@ func (u *unitOfWork) Begin(ctx context.Context) (Transaction, error) {
 		return nil, err
 	}
 
-	return &transaction{tx: tx, repos: repos}, nil
+	return &transaction{driver: u.driver, tx: tx, repos: repos}, nil
 }
 
 // buildRepos creates all repositories with the given DB handle.
@@ -119,7 +309,91 @@ func (u *unitOfWork) buildRepos(db dbx.DBTX) (Repos, error) {
 	if err != nil {
 		return Repos{}, err
 	}
-	return Repos{Users: users, Teams: teams}, nil
+	tasks, err := NewTaskRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	workflows, err := NewWorkflowRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	checklists, err := NewChecklistRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	taskLinks, err := NewTaskLinkRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	comments, err := NewCommentRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	notifications, err := NewNotificationRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	attachments, err := NewAttachmentRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	labels, err := NewLabelRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	customFields, err := NewCustomFieldRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	recurrences, err := NewRecurrenceRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	reminders, err := NewReminderRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	outbox, err := NewOutboxRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	boards, err := NewBoardRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	projects, err := NewProjectRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	sprints, err := NewSprintRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	milestones, err := NewMilestoneRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	timeEntries, err := NewTimeEntryRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	estimation, err := NewEstimationRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	activity, err := NewActivityRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	trash, err := NewTrashRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	audit, err := NewAuditRepositoryWithDBTX(u.driver, db)
+	if err != nil {
+		return Repos{}, err
+	}
+	return Repos{Users: users, Teams: teams, Tasks: tasks, Workflows: workflows, Checklists: checklists, TaskLinks: taskLinks, Comments: comments, Notifications: notifications, Attachments: attachments, Labels: labels, CustomFields: customFields, Recurrences: recurrences, Reminders: reminders, Outbox: outbox, Boards: boards, Projects: projects, Sprints: sprints, Milestones: milestones, TimeEntries: timeEntries, Estimation: estimation, Activity: activity, Trash: trash, Audit: audit}, nil
 }
 
 func (u *unitOfWork) WithTransaction(ctx context.Context, fn func(ctx context.Context, r Repos) error) error {
@@ -151,6 +425,90 @@ func (t *transaction) Teams() TeamRepository {
 	return t.repos.Teams
 }
 
+func (t *transaction) Tasks() TaskRepository {
+	return t.repos.Tasks
+}
+
+func (t *transaction) Workflows() WorkflowRepository {
+	return t.repos.Workflows
+}
+
+func (t *transaction) Checklists() ChecklistRepository {
+	return t.repos.Checklists
+}
+
+func (t *transaction) TaskLinks() TaskLinkRepository {
+	return t.repos.TaskLinks
+}
+
+func (t *transaction) Comments() CommentRepository {
+	return t.repos.Comments
+}
+
+func (t *transaction) Notifications() NotificationRepository {
+	return t.repos.Notifications
+}
+
+func (t *transaction) Attachments() AttachmentRepository {
+	return t.repos.Attachments
+}
+
+func (t *transaction) Labels() LabelRepository {
+	return t.repos.Labels
+}
+
+func (t *transaction) CustomFields() CustomFieldRepository {
+	return t.repos.CustomFields
+}
+
+func (t *transaction) Recurrences() RecurrenceRepository {
+	return t.repos.Recurrences
+}
+
+func (t *transaction) Reminders() ReminderRepository {
+	return t.repos.Reminders
+}
+
+func (t *transaction) Outbox() OutboxRepository {
+	return t.repos.Outbox
+}
+
+func (t *transaction) Boards() BoardRepository {
+	return t.repos.Boards
+}
+
+func (t *transaction) Projects() ProjectRepository {
+	return t.repos.Projects
+}
+
+func (t *transaction) Sprints() SprintRepository {
+	return t.repos.Sprints
+}
+
+func (t *transaction) Milestones() MilestoneRepository {
+	return t.repos.Milestones
+}
+
+func (t *transaction) TimeEntries() TimeEntryRepository {
+	return t.repos.TimeEntries
+}
+
+func (t *transaction) Estimation() EstimationRepository {
+	return t.repos.Estimation
+}
+
+func (t *transaction) Activity() ActivityRepository {
+	return t.repos.Activity
+}
+
+func (t *transaction) Trash() TrashRepository {
+	return t.repos.Trash
+}
+
+func (t *transaction) Audit() AuditRepository {
+	return t.repos.Audit
+}
+
 func (t *transaction) Commit() error {
 	if t.tx == nil {
 		return fmt.Errorf("transaction is nil")
@@ -159,7 +517,7 @@ func (t *transaction) Commit() error {
 		return nil
 	}
 	if err := t.tx.Commit(); err != nil {
-		return err
+		return translateError(t.driver, err)
 	}
 	t.committed = true
 	return nil
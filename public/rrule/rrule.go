@@ -0,0 +1,431 @@
+// Package rrule parses and expands the subset of iCalendar recurrence rules (RFC 5545,
+// section 3.3.10) used by recurring tasks:
+//
+//	FREQ=DAILY|WEEKLY|MONTHLY|YEARLY, INTERVAL, BYDAY, BYMONTHDAY, BYMONTH, COUNT, UNTIL
+//
+// Occurrences are expanded from a start time (DTSTART) in its location: they keep its
+// wall-clock time of day across daylight saving changes. Weeks start on Monday, and
+// BYDAY ordinals such as "-1FR" count within the month, also for YEARLY rules.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Freq string
+
+const (
+	Daily   Freq = "DAILY"
+	Weekly  Freq = "WEEKLY"
+	Monthly Freq = "MONTHLY"
+	Yearly  Freq = "YEARLY"
+)
+
+// Weekday is a BYDAY entry, e.g. "TU" or "2TU".
+type Weekday struct {
+	Day time.Weekday
+	// N selects the nth such weekday of the month, counted from the end when negative;
+	// 0 means every such weekday.
+	N int
+}
+
+// Rule is a parsed recurrence rule. Build it with Parse.
+type Rule struct {
+	Freq       Freq
+	Interval   int
+	ByDay      []Weekday
+	ByMonthDay []int
+	ByMonth    []time.Month
+	// Count bounds the number of occurrences; 0 means no bound.
+	Count int
+	// Until is the last possible occurrence, zero when unset. A floating UNTIL (no "Z")
+	// is read in the location of the start time.
+	Until time.Time
+
+	untilRaw      string
+	untilFloating bool
+}
+
+const (
+	maxInterval = 1000
+	maxCount    = 10000
+	// maxEmptyPeriods stops the expansion of rules that cannot produce another
+	// occurrence, e.g. February 30th. It is large enough for leap days with FREQ=DAILY.
+	maxEmptyPeriods = 5000
+)
+
+var dayNames = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=10". A leading "RRULE:"
+// is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 6 && strings.EqualFold(s[:6], "RRULE:") {
+		s = s[6:]
+	}
+	if s == "" {
+		return nil, errors.New("empty rule")
+	}
+	r := &Rule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("malformed rule part %q", part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate %s", name)
+		}
+		seen[name] = true
+		var err error
+		switch name {
+		case "FREQ":
+			switch f := Freq(value); f {
+			case Daily, Weekly, Monthly, Yearly:
+				r.Freq = f
+			default:
+				err = fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = parseInt(name, value, 1, maxInterval)
+		case "COUNT":
+			r.Count, err = parseInt(name, value, 1, maxCount)
+		case "UNTIL":
+			err = r.parseUntil(value)
+		case "BYDAY":
+			for _, v := range strings.Split(value, ",") {
+				wd, perr := parseWeekday(v)
+				if perr != nil {
+					err = perr
+					break
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				n, perr := parseInt(name, v, -31, 31)
+				if perr != nil || n == 0 {
+					err = fmt.Errorf("invalid BYMONTHDAY %q", v)
+					break
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, v := range strings.Split(value, ",") {
+				n, perr := parseInt(name, v, 1, 12)
+				if perr != nil {
+					err = perr
+					break
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(n))
+			}
+		default:
+			err = fmt.Errorf("unsupported rule part %s", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, errors.New("FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, errors.New("COUNT and UNTIL cannot be combined")
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return nil, errors.New("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+	for _, wd := range r.ByDay {
+		if wd.N != 0 && r.Freq != Monthly && r.Freq != Yearly {
+			return nil, errors.New("BYDAY ordinals need FREQ=MONTHLY or FREQ=YEARLY")
+		}
+	}
+	return r, nil
+}
+
+func parseInt(name string, v string, lo int, hi int) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n < lo || n > hi {
+		return 0, fmt.Errorf("%s must be between %d and %d", name, lo, hi)
+	}
+	return n, nil
+}
+
+func parseWeekday(v string) (Weekday, error) {
+	v = strings.TrimSpace(v)
+	if len(v) < 2 {
+		return Weekday{}, fmt.Errorf("invalid BYDAY %q", v)
+	}
+	day, ok := dayNames[v[len(v)-2:]]
+	if !ok {
+		return Weekday{}, fmt.Errorf("invalid BYDAY %q", v)
+	}
+	wd := Weekday{Day: day}
+	if prefix := v[:len(v)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return Weekday{}, fmt.Errorf("invalid BYDAY %q", v)
+		}
+		wd.N = n
+	}
+	return wd, nil
+}
+
+func (r *Rule) parseUntil(v string) error {
+	layouts := []struct {
+		layout   string
+		floating bool
+	}{
+		{"20060102T150405Z", false},
+		{"20060102T150405", true},
+		{"20060102", true},
+	}
+	for _, l := range layouts {
+		t, err := time.Parse(l.layout, v)
+		if err != nil {
+			continue
+		}
+		if l.layout == "20060102" {
+			// A date includes the whole day.
+			t = t.Add(24*time.Hour - time.Second)
+		}
+		r.Until, r.untilFloating, r.untilRaw = t, l.floating, v
+		return nil
+	}
+	return fmt.Errorf("invalid UNTIL %q", v)
+}
+
+// String returns the rule in canonical form.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = strings.ToUpper(wd.Day.String()[:2])
+			if wd.N != 0 {
+				days[i] = strconv.Itoa(wd.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = strconv.Itoa(int(m))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.untilRaw != "" {
+		parts = append(parts, "UNTIL="+r.untilRaw)
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence of the series starting at start that is after
+// after. ok is false when the series has ended before that.
+func (r *Rule) Next(start time.Time, after time.Time) (next time.Time, ok bool) {
+	r.each(start, func(t time.Time) bool {
+		if t.After(after) {
+			next, ok = t, true
+			return false
+		}
+		return true
+	})
+	return next, ok
+}
+
+// Occurrences returns up to limit occurrences after after.
+func (r *Rule) Occurrences(start time.Time, after time.Time, limit int) []time.Time {
+	out := []time.Time{}
+	if limit <= 0 {
+		return out
+	}
+	r.each(start, func(t time.Time) bool {
+		if t.After(after) {
+			out = append(out, t)
+		}
+		return len(out) < limit
+	})
+	return out
+}
+
+// each hands the occurrences of the series, in order, to yield until it returns false.
+func (r *Rule) each(start time.Time, yield func(time.Time) bool) {
+	loc := start.Location()
+	hour, minute, sec := start.Clock()
+	until := r.Until
+	if r.untilFloating {
+		y, m, d := until.Date()
+		h, mi, s := until.Clock()
+		until = time.Date(y, m, d, h, mi, s, 0, loc)
+	}
+
+	n, empty := 0, 0
+	for period := 0; empty < maxEmptyPeriods; period++ {
+		days := r.periodDays(start, period)
+		if len(days) == 0 {
+			empty++
+			continue
+		}
+		empty = 0
+		for _, d := range days {
+			y, m, day := d.Date()
+			t := time.Date(y, m, day, hour, minute, sec, start.Nanosecond(), loc)
+			if t.Before(start) {
+				continue
+			}
+			if !until.IsZero() && t.After(until) {
+				return
+			}
+			n++
+			if !yield(t) || (r.Count > 0 && n >= r.Count) {
+				return
+			}
+		}
+	}
+}
+
+// periodDays returns the days of the period-th period of the series in order, as
+// midnight UTC dates.
+func (r *Rule) periodDays(start time.Time, period int) []time.Time {
+	sy, sm, sd := start.Date()
+	first := time.Date(sy, sm, sd, 0, 0, 0, 0, time.UTC)
+	step := period * r.Interval
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		d := first.AddDate(0, 0, step)
+		if r.inMonths(d) && r.onWeekday(d) && r.onMonthDay(d) {
+			days = append(days, d)
+		}
+	case Weekly:
+		monday := first.AddDate(0, 0, -((int(first.Weekday())+6)%7)+7*step)
+		for i := range 7 {
+			d := monday.AddDate(0, 0, i)
+			if !r.inMonths(d) {
+				continue
+			}
+			if len(r.ByDay) == 0 && d.Weekday() == start.Weekday() || len(r.ByDay) > 0 && r.onWeekday(d) {
+				days = append(days, d)
+			}
+		}
+	case Monthly:
+		month := time.Date(sy, sm+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		if r.inMonths(month) {
+			days = r.monthDays(month, sd)
+		}
+	case Yearly:
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{sm}
+		}
+		months = slices.Clone(months)
+		slices.Sort(months)
+		for _, m := range slices.Compact(months) {
+			days = append(days, r.monthDays(time.Date(sy+step, m, 1, 0, 0, 0, 0, time.UTC), sd)...)
+		}
+	}
+	return days
+}
+
+// monthDays expands BYMONTHDAY and BYDAY within the month starting at first. Without
+// either, the month contributes startDay, if it has that many days.
+func (r *Rule) monthDays(first time.Time, startDay int) []time.Time {
+	n := first.AddDate(0, 1, -1).Day()
+	var set []int
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, md := range r.ByMonthDay {
+			d := md
+			if md < 0 {
+				d = n + md + 1
+			}
+			if d >= 1 && d <= n {
+				set = append(set, d)
+			}
+		}
+		if len(r.ByDay) > 0 {
+			byDay := r.monthWeekdays(first, n)
+			set = slices.DeleteFunc(set, func(d int) bool { return !slices.Contains(byDay, d) })
+		}
+	case len(r.ByDay) > 0:
+		set = r.monthWeekdays(first, n)
+	case startDay <= n:
+		set = []int{startDay}
+	}
+	slices.Sort(set)
+	set = slices.Compact(set)
+	days := make([]time.Time, len(set))
+	for i, d := range set {
+		days[i] = first.AddDate(0, 0, d-1)
+	}
+	return days
+}
+
+// monthWeekdays returns the days of the month matching BYDAY.
+func (r *Rule) monthWeekdays(first time.Time, n int) []int {
+	var out []int
+	for _, wd := range r.ByDay {
+		var matching []int
+		for d := 1; d <= n; d++ {
+			if first.AddDate(0, 0, d-1).Weekday() == wd.Day {
+				matching = append(matching, d)
+			}
+		}
+		switch {
+		case wd.N == 0:
+			out = append(out, matching...)
+		case wd.N > 0 && wd.N <= len(matching):
+			out = append(out, matching[wd.N-1])
+		case wd.N < 0 && -wd.N <= len(matching):
+			out = append(out, matching[len(matching)+wd.N])
+		}
+	}
+	return out
+}
+
+func (r *Rule) inMonths(d time.Time) bool {
+	return len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, d.Month())
+}
+
+func (r *Rule) onWeekday(d time.Time) bool {
+	return len(r.ByDay) == 0 || slices.ContainsFunc(r.ByDay, func(wd Weekday) bool { return wd.Day == d.Weekday() })
+}
+
+func (r *Rule) onMonthDay(d time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	n := d.AddDate(0, 1, -d.Day()).Day()
+	for _, md := range r.ByMonthDay {
+		if md == d.Day() || md < 0 && n+md+1 == d.Day() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package rrule_test
+
+import (
+	"task_manager/public/rrule"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+	}{
+		{"empty", ""},
+		{"no freq", "INTERVAL=2"},
+		{"unsupported freq", "FREQ=HOURLY"},
+		{"unknown part", "FREQ=DAILY;BYHOUR=9"},
+		{"duplicate part", "FREQ=DAILY;FREQ=WEEKLY"},
+		{"zero interval", "FREQ=DAILY;INTERVAL=0"},
+		{"count and until", "FREQ=DAILY;COUNT=3;UNTIL=20260101T000000Z"},
+		{"bad weekday", "FREQ=WEEKLY;BYDAY=XX"},
+		{"ordinal on weekly", "FREQ=WEEKLY;BYDAY=1MO"},
+		{"monthday on weekly", "FREQ=WEEKLY;BYMONTHDAY=1"},
+		{"zero monthday", "FREQ=MONTHLY;BYMONTHDAY=0"},
+		{"bad month", "FREQ=YEARLY;BYMONTH=13"},
+		{"bad until", "FREQ=DAILY;UNTIL=tomorrow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := rrule.Parse(tt.rule)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParse_String(t *testing.T) {
+	r, err := rrule.Parse("rrule:freq=monthly;byday=-1fr,2mo;interval=2;until=20261231")
+	require.NoError(t, err)
+	require.Equal(t, "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR,2MO;UNTIL=20261231", r.String())
+}
+
+func TestOccurrences(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	at := func(s string) time.Time {
+		v, err := time.ParseInLocation("2006-01-02 15:04", s, berlin)
+		require.NoError(t, err)
+		return v
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start string
+		after string
+		limit int
+		want  []string
+	}{
+		{"daily", "FREQ=DAILY;INTERVAL=2", "2026-03-01 09:00", "2026-03-01 09:00", 3,
+			[]string{"2026-03-03 09:00", "2026-03-05 09:00", "2026-03-07 09:00"}},
+		{"keeps the wall clock across DST", "FREQ=DAILY", "2026-03-28 09:00", "2026-03-28 09:00", 2,
+			[]string{"2026-03-29 09:00", "2026-03-30 09:00"}},
+		{"weekly by day", "FREQ=WEEKLY;BYDAY=MO,TH", "2026-03-04 08:30", "2026-03-01 00:00", 4,
+			[]string{"2026-03-05 08:30", "2026-03-09 08:30", "2026-03-12 08:30", "2026-03-16 08:30"}},
+		{"biweekly", "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR", "2026-03-06 17:00", "2026-03-06 17:00", 2,
+			[]string{"2026-03-20 17:00", "2026-04-03 17:00"}},
+		{"monthly skips short months", "FREQ=MONTHLY", "2026-01-31 10:00", "2026-01-01 00:00", 3,
+			[]string{"2026-01-31 10:00", "2026-03-31 10:00", "2026-05-31 10:00"}},
+		{"last friday of the month", "FREQ=MONTHLY;BYDAY=-1FR", "2026-01-01 12:00", "2026-01-01 00:00", 3,
+			[]string{"2026-01-30 12:00", "2026-02-27 12:00", "2026-03-27 12:00"}},
+		{"last day of the month", "FREQ=MONTHLY;BYMONTHDAY=-1", "2026-01-15 12:00", "2026-01-01 00:00", 3,
+			[]string{"2026-01-31 12:00", "2026-02-28 12:00", "2026-03-31 12:00"}},
+		{"friday the 13th", "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13", "2026-01-01 00:00", "2026-01-01 00:00", 2,
+			[]string{"2026-02-13 00:00", "2026-03-13 00:00"}},
+		{"yearly by month", "FREQ=YEARLY;BYMONTH=1,7;BYMONTHDAY=1", "2026-01-01 09:00", "2026-01-01 09:00", 3,
+			[]string{"2026-07-01 09:00", "2027-01-01 09:00", "2027-07-01 09:00"}},
+		{"leap day", "FREQ=YEARLY", "2024-02-29 09:00", "2024-02-29 09:00", 1,
+			[]string{"2028-02-29 09:00"}},
+		{"count includes past occurrences", "FREQ=DAILY;COUNT=3", "2026-03-01 09:00", "2026-03-02 00:00", 5,
+			[]string{"2026-03-02 09:00", "2026-03-03 09:00"}},
+		{"until is inclusive", "FREQ=WEEKLY;UNTIL=20260315", "2026-03-01 09:00", "2026-03-01 09:00", 5,
+			[]string{"2026-03-08 09:00", "2026-03-15 09:00"}},
+		{"impossible date", "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", "2026-01-01 09:00", "2026-01-01 09:00", 1,
+			[]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := rrule.Parse(tt.rule)
+			require.NoError(t, err)
+			got := []string{}
+			for _, o := range r.Occurrences(at(tt.start), at(tt.after), tt.limit) {
+				require.Equal(t, berlin, o.Location())
+				got = append(got, o.Format("2006-01-02 15:04"))
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNext_Ended(t *testing.T) {
+	r, err := rrule.Parse("FREQ=DAILY;COUNT=2")
+	require.NoError(t, err)
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	next, ok := r.Next(start, start)
+	require.True(t, ok)
+	require.Equal(t, start.AddDate(0, 0, 1), next)
+	_, ok = r.Next(start, next)
+	require.False(t, ok)
+}
@@ -0,0 +1,33 @@
+package fakes
+
+import (
+	"context"
+	"sync"
+	"task_manager/public/mailer"
+)
+
+var _ mailer.Mailer = (*Mailer)(nil)
+
+// Mailer records the messages it is asked to send. When Err is set, sending fails
+// with it and nothing is recorded.
+type Mailer struct {
+	mu   sync.Mutex
+	sent []mailer.Message
+	Err  error
+}
+
+func (m *Mailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *Mailer) Sent() []mailer.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]mailer.Message(nil), m.sent...)
+}
@@ -9,8 +9,7 @@ import (
 var _ repositories.UnitOfWork = (*UnitOfWork)(nil)
 
 type transaction struct {
-	users     repositories.UserRepository
-	teams     repositories.TeamRepository
+	repos     repositories.Repos
 	committed bool
 }
 
@@ -19,24 +18,112 @@ var _ repositories.Transaction = (*transaction)(nil)
 // UnitOfWork is a lightweight test double that executes "transaction" ops
 // against the same in-memory repositories without actually opening a DB transaction.
 type UnitOfWork struct {
-	users repositories.UserRepository
-	teams repositories.TeamRepository
+	repos repositories.Repos
 }
 
 func NewUnitOfWork(users repositories.UserRepository, teams repositories.TeamRepository) *UnitOfWork {
-	return &UnitOfWork{users: users, teams: teams}
+	return NewUnitOfWorkWithRepos(repositories.Repos{Users: users, Teams: teams})
+}
+
+// NewUnitOfWorkWithRepos wires any set of repositories; unset ones stay nil.
+func NewUnitOfWorkWithRepos(repos repositories.Repos) *UnitOfWork {
+	return &UnitOfWork{repos: repos}
 }
 
 func (u *UnitOfWork) Users() repositories.UserRepository {
-	return u.users
+	return u.repos.Users
 }
 
 func (u *UnitOfWork) Teams() repositories.TeamRepository {
-	return u.teams
+	return u.repos.Teams
+}
+
+func (u *UnitOfWork) Tasks() repositories.TaskRepository {
+	return u.repos.Tasks
+}
+
+func (u *UnitOfWork) Workflows() repositories.WorkflowRepository {
+	return u.repos.Workflows
+}
+
+func (u *UnitOfWork) Checklists() repositories.ChecklistRepository {
+	return u.repos.Checklists
+}
+
+func (u *UnitOfWork) TaskLinks() repositories.TaskLinkRepository {
+	return u.repos.TaskLinks
+}
+
+func (u *UnitOfWork) Comments() repositories.CommentRepository {
+	return u.repos.Comments
+}
+
+func (u *UnitOfWork) Notifications() repositories.NotificationRepository {
+	return u.repos.Notifications
+}
+
+func (u *UnitOfWork) Attachments() repositories.AttachmentRepository {
+	return u.repos.Attachments
+}
+
+func (u *UnitOfWork) Labels() repositories.LabelRepository {
+	return u.repos.Labels
+}
+
+func (u *UnitOfWork) CustomFields() repositories.CustomFieldRepository {
+	return u.repos.CustomFields
+}
+
+func (u *UnitOfWork) Recurrences() repositories.RecurrenceRepository {
+	return u.repos.Recurrences
+}
+
+func (u *UnitOfWork) Reminders() repositories.ReminderRepository {
+	return u.repos.Reminders
+}
+
+func (u *UnitOfWork) Outbox() repositories.OutboxRepository {
+	return u.repos.Outbox
+}
+
+func (u *UnitOfWork) Boards() repositories.BoardRepository {
+	return u.repos.Boards
+}
+
+func (u *UnitOfWork) Projects() repositories.ProjectRepository {
+	return u.repos.Projects
+}
+
+func (u *UnitOfWork) Sprints() repositories.SprintRepository {
+	return u.repos.Sprints
+}
+
+func (u *UnitOfWork) Milestones() repositories.MilestoneRepository {
+	return u.repos.Milestones
+}
+
+func (u *UnitOfWork) TimeEntries() repositories.TimeEntryRepository {
+	return u.repos.TimeEntries
+}
+
+func (u *UnitOfWork) Estimation() repositories.EstimationRepository {
+	return u.repos.Estimation
+}
+
+func (u *UnitOfWork) Activity() repositories.ActivityRepository {
+	return u.repos.Activity
+}
+
+func (u *UnitOfWork) Trash() repositories.TrashRepository {
+	return u.repos.Trash
+}
+
+func (u *UnitOfWork) Audit() repositories.AuditRepository {
+	return u.repos.Audit
 }
 
 func (u *UnitOfWork) Begin(_ context.Context) (repositories.Transaction, error) {
-	return &transaction{users: u.users, teams: u.teams}, nil
+	return &transaction{repos: u.repos}, nil
 }
 
 func (u *UnitOfWork) WithTransaction(ctx context.Context, fn func(ctx context.Context, r repositories.Repos) error) error {
@@ -52,15 +139,99 @@ func (u *UnitOfWork) WithTransaction(ctx context.Context, fn func(ctx context.Co
 }
 
 func (t *transaction) Repos() repositories.Repos {
-	return repositories.Repos{Users: t.users, Teams: t.teams}
+	return t.repos
 }
 
 func (t *transaction) Users() repositories.UserRepository {
-	return t.users
+	return t.repos.Users
 }
 
 func (t *transaction) Teams() repositories.TeamRepository {
-	return t.teams
+	return t.repos.Teams
+}
+
+func (t *transaction) Tasks() repositories.TaskRepository {
+	return t.repos.Tasks
+}
+
+func (t *transaction) Workflows() repositories.WorkflowRepository {
+	return t.repos.Workflows
+}
+
+func (t *transaction) Checklists() repositories.ChecklistRepository {
+	return t.repos.Checklists
+}
+
+func (t *transaction) TaskLinks() repositories.TaskLinkRepository {
+	return t.repos.TaskLinks
+}
+
+func (t *transaction) Comments() repositories.CommentRepository {
+	return t.repos.Comments
+}
+
+func (t *transaction) Notifications() repositories.NotificationRepository {
+	return t.repos.Notifications
+}
+
+func (t *transaction) Attachments() repositories.AttachmentRepository {
+	return t.repos.Attachments
+}
+
+func (t *transaction) Labels() repositories.LabelRepository {
+	return t.repos.Labels
+}
+
+func (t *transaction) CustomFields() repositories.CustomFieldRepository {
+	return t.repos.CustomFields
+}
+
+func (t *transaction) Recurrences() repositories.RecurrenceRepository {
+	return t.repos.Recurrences
+}
+
+func (t *transaction) Reminders() repositories.ReminderRepository {
+	return t.repos.Reminders
+}
+
+func (t *transaction) Outbox() repositories.OutboxRepository {
+	return t.repos.Outbox
+}
+
+func (t *transaction) Boards() repositories.BoardRepository {
+	return t.repos.Boards
+}
+
+func (t *transaction) Projects() repositories.ProjectRepository {
+	return t.repos.Projects
+}
+
+func (t *transaction) Sprints() repositories.SprintRepository {
+	return t.repos.Sprints
+}
+
+func (t *transaction) Milestones() repositories.MilestoneRepository {
+	return t.repos.Milestones
+}
+
+func (t *transaction) TimeEntries() repositories.TimeEntryRepository {
+	return t.repos.TimeEntries
+}
+
+func (t *transaction) Estimation() repositories.EstimationRepository {
+	return t.repos.Estimation
+}
+
+func (t *transaction) Activity() repositories.ActivityRepository {
+	return t.repos.Activity
+}
+
+func (t *transaction) Trash() repositories.TrashRepository {
+	return t.repos.Trash
+}
+
+func (t *transaction) Audit() repositories.AuditRepository {
+	return t.repos.Audit
 }
 
 func (t *transaction) Commit() error {
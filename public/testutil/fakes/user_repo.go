@@ -2,10 +2,13 @@ package fakes
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"task_manager/public/repositories"
 	"task_manager/public/repositories/models"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -19,6 +22,7 @@ type UserRepo struct {
 	pw      map[uuid.UUID]string
 	byProv  map[string]uuid.UUID                                  // key: string(provider) + ":" + provider_user_id
 	provs   map[uuid.UUID]map[models.Provider]models.AuthProvider // user_id -> provider -> provider
+	resets  map[string]*models.PasswordResetToken                 // key: token hash
 }
 
 func NewUserRepo() *UserRepo {
@@ -28,6 +32,7 @@ func NewUserRepo() *UserRepo {
 		pw:      make(map[uuid.UUID]string),
 		byProv:  make(map[string]uuid.UUID),
 		provs:   make(map[uuid.UUID]map[models.Provider]models.AuthProvider),
+		resets:  make(map[string]*models.PasswordResetToken),
 	}
 }
 
@@ -35,6 +40,9 @@ func (r *UserRepo) CreateUser(_ context.Context, u *models.User) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if _, ok := r.byEmail[u.Email]; ok {
+		return repositories.ErrConflict
+	}
 	clone := *u
 	r.byEmail[u.Email] = &clone
 	r.byID[u.ID] = &clone
@@ -47,7 +55,7 @@ func (r *UserRepo) GetUserByEmail(_ context.Context, email string) (*models.User
 
 	u := r.byEmail[email]
 	if u == nil {
-		return nil, nil
+		return nil, repositories.ErrNotFound
 	}
 	clone := *u
 	return &clone, nil
@@ -59,7 +67,7 @@ func (r *UserRepo) GetUserByID(_ context.Context, id uuid.UUID) (*models.User, e
 
 	u := r.byID[id]
 	if u == nil {
-		return nil, nil
+		return nil, repositories.ErrNotFound
 	}
 	clone := *u
 	return &clone, nil
@@ -75,7 +83,11 @@ func (r *UserRepo) UpsertPassword(_ context.Context, userID uuid.UUID, passwordH
 func (r *UserRepo) GetPasswordHashByUserID(_ context.Context, userID uuid.UUID) (string, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	return r.pw[userID], nil
+	v, ok := r.pw[userID]
+	if !ok {
+		return "", repositories.ErrNotFound
+	}
+	return v, nil
 }
 
 func (r *UserRepo) GetUserByAuthProvider(_ context.Context, provider models.Provider, providerUserID string) (*models.User, error) {
@@ -84,11 +96,11 @@ func (r *UserRepo) GetUserByAuthProvider(_ context.Context, provider models.Prov
 	key := string(provider) + ":" + providerUserID
 	uid, ok := r.byProv[key]
 	if !ok {
-		return nil, nil
+		return nil, repositories.ErrNotFound
 	}
 	u := r.byID[uid]
 	if u == nil {
-		return nil, nil
+		return nil, repositories.ErrNotFound
 	}
 	clone := *u
 	return &clone, nil
@@ -99,11 +111,11 @@ func (r *UserRepo) GetAuthProviderByUserAndProvider(_ context.Context, userID uu
 	defer r.mu.Unlock()
 	m := r.provs[userID]
 	if m == nil {
-		return nil, nil
+		return nil, repositories.ErrNotFound
 	}
 	ap, ok := m[provider]
 	if !ok {
-		return nil, nil
+		return nil, repositories.ErrNotFound
 	}
 	clone := ap
 	return &clone, nil
@@ -128,16 +140,141 @@ func (r *UserRepo) CreateAuthProvider(_ context.Context, ap *models.AuthProvider
 	defer r.mu.Unlock()
 	key := string(ap.Provider) + ":" + ap.ProviderUserID
 	if existingUID, ok := r.byProv[key]; ok && existingUID != ap.UserID {
-		return errors.New("unique violation: provider_user_id already linked")
+		return fmt.Errorf("%w: provider_user_id already linked", repositories.ErrConflict)
 	}
 	if r.provs[ap.UserID] == nil {
 		r.provs[ap.UserID] = make(map[models.Provider]models.AuthProvider)
 	}
 	if existing, ok := r.provs[ap.UserID][ap.Provider]; ok && existing.ProviderUserID != ap.ProviderUserID {
-		return errors.New("unique violation: user already has provider linked")
+		return fmt.Errorf("%w: user already has provider linked", repositories.ErrConflict)
 	}
 	clone := *ap
 	r.provs[ap.UserID][ap.Provider] = clone
 	r.byProv[key] = ap.UserID
 	return nil
 }
+
+func (r *UserRepo) ListUsers(_ context.Context, filter models.UserListFilter) ([]*models.User, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	q := strings.ToLower(strings.TrimSpace(filter.Query))
+	var matched []*models.User
+	for _, u := range r.byID {
+		if q != "" &&
+			!strings.Contains(strings.ToLower(u.Email), q) &&
+			!strings.Contains(strings.ToLower(u.FirstName), q) &&
+			!strings.Contains(strings.ToLower(u.LastName), q) {
+			continue
+		}
+		if filter.UserType != "" && u.UserType != filter.UserType {
+			continue
+		}
+		if filter.Disabled != nil && u.IsDisabled() != *filter.Disabled {
+			continue
+		}
+		clone := *u
+		matched = append(matched, &clone)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+			return matched[i].ID.String() < matched[j].ID.String()
+		}
+		return matched[i].CreatedAt.Before(matched[j].CreatedAt)
+	})
+
+	total := len(matched)
+	start := min(filter.Offset, total)
+	end := min(start+filter.Limit, total)
+	return matched[start:end], total, nil
+}
+
+func (r *UserRepo) SetUserDisabled(_ context.Context, userID uuid.UUID, disabled bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u := r.byID[userID]
+	if u == nil {
+		return repositories.ErrNotFound
+	}
+	if disabled {
+		now := time.Now()
+		u.DisabledAt = &now
+	} else {
+		u.DisabledAt = nil
+	}
+	return nil
+}
+
+func (r *UserRepo) UpdateUserType(_ context.Context, userID uuid.UUID, userType models.UserType) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u := r.byID[userID]
+	if u == nil {
+		return repositories.ErrNotFound
+	}
+	u.UserType = userType
+	return nil
+}
+
+func (r *UserRepo) SetPasswordResetRequired(_ context.Context, userID uuid.UUID, required bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u := r.byID[userID]
+	if u == nil {
+		return repositories.ErrNotFound
+	}
+	u.PasswordResetRequired = required
+	return nil
+}
+
+func (r *UserRepo) DeleteUser(_ context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u := r.byID[userID]
+	if u == nil {
+		return repositories.ErrNotFound
+	}
+	delete(r.byEmail, u.Email)
+	delete(r.byID, userID)
+	delete(r.pw, userID)
+	for _, ap := range r.provs[userID] {
+		delete(r.byProv, string(ap.Provider)+":"+ap.ProviderUserID)
+	}
+	delete(r.provs, userID)
+	for hash, t := range r.resets {
+		if t.UserID == userID {
+			delete(r.resets, hash)
+		}
+	}
+	return nil
+}
+
+func (r *UserRepo) CreatePasswordResetToken(_ context.Context, t *models.PasswordResetToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clone := *t
+	r.resets[t.TokenHash] = &clone
+	return nil
+}
+
+func (r *UserRepo) GetPasswordResetTokenByHash(_ context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.resets[tokenHash]
+	if t == nil {
+		return nil, repositories.ErrNotFound
+	}
+	clone := *t
+	return &clone, nil
+}
+
+func (r *UserRepo) DeletePasswordResetTokensByUserID(_ context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for hash, t := range r.resets {
+		if t.UserID == userID {
+			delete(r.resets, hash)
+		}
+	}
+	return nil
+}
@@ -1,12 +1,16 @@
 package testutil
 
 import (
+	"task_manager/public/blobstore"
+	"task_manager/public/config"
 	"task_manager/public/jwtauth"
 	"task_manager/public/repositories"
 	"testing"
 
+	adminhandler "task_manager/handlers/admin"
 	authhandler "task_manager/handlers/auth"
 	oauthhandler "task_manager/handlers/oauth"
+	teamhandler "task_manager/handlers/team"
 	mehandler "task_manager/handlers/user"
 
 	"github.com/gin-gonic/gin"
@@ -14,6 +18,13 @@ import (
 )
 
 func NewTestRouter(t *testing.T, uow repositories.UnitOfWork, jwtSecret string) *gin.Engine {
+	t.Helper()
+	return NewTestRouterWithConfig(t, uow, jwtSecret, config.Load(), blobstore.NewLocal(t.TempDir()))
+}
+
+// NewTestRouterWithConfig is NewTestRouter with the team handler built from cfg and
+// storing attachments in blobs.
+func NewTestRouterWithConfig(t *testing.T, uow repositories.UnitOfWork, jwtSecret string, cfg config.Config, blobs blobstore.Store) *gin.Engine {
 	t.Helper()
 	gin.SetMode(gin.TestMode)
 
@@ -25,6 +36,7 @@ func NewTestRouter(t *testing.T, uow repositories.UnitOfWork, jwtSecret string)
 
 	r := gin.New()
 	r.Use(gin.Recovery())
+	r.Use(jwtauth.CSRF(authMW))
 
 	v1 := r.Group("/api/v1")
 
@@ -32,8 +44,10 @@ func NewTestRouter(t *testing.T, uow repositories.UnitOfWork, jwtSecret string)
 	oauthH := oauthhandler.New(uow, authMW)
 	authGroup := v1.Group("/auth")
 	authGroup.POST("/signup", authH.Signup)
+	authGroup.POST("/password/reset", authH.ResetPassword)
 	authGroup.POST("/login", authhandler.Login)
 	authGroup.POST("/refresh", authhandler.Refresh)
+	authGroup.GET("/csrf", authMW.MiddlewareFunc(), authhandler.CSRFToken)
 	authGroup.GET("/google/login", oauthH.GoogleLogin)
 	authGroup.GET("/google/callback", oauthH.GoogleCallback)
 	authGroup.GET("/github/login", oauthH.GitHubLogin)
@@ -44,5 +58,9 @@ func NewTestRouter(t *testing.T, uow repositories.UnitOfWork, jwtSecret string)
 	protected.GET("/me", mehandler.Me)
 	protected.POST("/logout", authhandler.Logout)
 
+	mehandler.NewHandler(uow).RegisterRoutes(v1.Group("/user"), authMW.MiddlewareFunc())
+	teamhandler.NewTeamsHandlerWithConfig(uow, cfg, blobs).RegisterRoutes(v1.Group("/team"), authMW.MiddlewareFunc())
+	adminhandler.NewHandler(uow, authMW).RegisterRoutes(v1.Group("/admin"), authMW.MiddlewareFunc())
+
 	return r
 }